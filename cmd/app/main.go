@@ -2,9 +2,13 @@ package main
 
 import (
 	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/event"
 	"Fitness_REST_API/internal/handler"
-	"Fitness_REST_API/internal/repository"
-	"Fitness_REST_API/internal/repository/postgres"
+	"Fitness_REST_API/internal/idempotency"
+	"Fitness_REST_API/internal/job"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/mail"
+	"Fitness_REST_API/internal/oidc"
 	"Fitness_REST_API/internal/server"
 	"Fitness_REST_API/internal/service"
 	"context"
@@ -12,6 +16,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // runtime image has no zoneinfo, user time zones are resolved from embedded database
 )
 
 // @title Fitness REST API
@@ -32,24 +38,105 @@ func main() {
 		logrus.Fatalf("error due reading config: %s", err.Error())
 	}
 
-	db, err := postgres.InitPostgresDB(cfg)
+	store, err := openStorage(cfg)
 	if err != nil {
 		logrus.Fatalf("error due initializing database: %s", err.Error())
 	}
 	defer func() {
-		err = db.Close()
+		err = store.close()
 		if err != nil {
 			logrus.Fatalf("error due closing db: %s", err.Error())
 		}
 	}()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if store.migrator == nil {
+			logrus.Fatalf("storage backend %q has no migrations", cfg.Backend)
+		}
+		if err = runMigrate(store.migrator, os.Args[2:]); err != nil {
+			logrus.Fatalf("error due migrating: %s", err.Error())
+		}
+		return
+	}
+	if cfg.CheckOnStart && store.migrator != nil {
+		if err = store.migrator.Check(context.Background()); err != nil {
+			logrus.Fatalf("refusing to serve: %s", err.Error())
+		}
+	}
+
+	mailer, err := mail.NewMailer(cfg)
+	if err != nil {
+		logrus.Fatalf("error due initializing mailer: %s", err.Error())
+	}
+
+	attempts := store.attempts
+	if cfg.LockoutConfig.Store == "memory" {
+		attempts = lockout.NewMemoryStore()
+	}
+	lockoutWindow := time.Duration(cfg.WindowHours) * time.Hour
+	lockoutPolicy := func(freeAttempts, maxFailures int) lockout.Policy {
+		return lockout.Policy{
+			FreeAttempts:    freeAttempts,
+			MaxFailures:     maxFailures,
+			BaseDelay:       time.Duration(cfg.BaseDelaySeconds) * time.Second,
+			MaxDelay:        time.Duration(cfg.MaxDelaySeconds) * time.Second,
+			LockoutDuration: time.Duration(cfg.LockoutMinutes) * time.Minute,
+			Window:          lockoutWindow,
+		}
+	}
+
+	idempotencyKeys := store.idempotency
+	if cfg.IdempotencyConfig.Store == "memory" {
+		idempotencyKeys = idempotency.NewMemoryStore()
+	}
+	idempotencyTTL := time.Duration(cfg.IdempotencyConfig.TTLHours) * time.Hour
+	exportBuildTimeout := time.Duration(cfg.BuildTimeoutMinutes) * time.Minute
+
+	providers := make([]*oidc.Provider, 0, len(cfg.Providers))
+	for name, p := range cfg.Providers {
+		providers = append(providers, &oidc.Provider{Name: name, Issuers: p.Issuers, JWKSURL: p.JWKSURL,
+			ClientIDs: p.ClientIDs})
+	}
+
 	srv := new(server.Server)
-	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
+	services := service.NewService(store.repos, &service.Dependencies{
+		CancellationCutoff:  time.Duration(cfg.CancellationCutoffHours) * time.Hour,
+		ExportLinkTTL:       time.Duration(cfg.LinkTTLHours) * time.Hour,
+		ExportBuildTimeout:  exportBuildTimeout,
+		DeletionGrace:       time.Duration(cfg.SelfDeletionGraceDays) * 24 * time.Hour,
+		RequireVerification: cfg.RequireVerification,
+		VerificationTTL:     time.Duration(cfg.VerificationTTLHours) * time.Hour,
+		ResetTTL:            time.Duration(cfg.ResetTTLHours) * time.Hour,
+		AppURL:              cfg.AppURL,
+		Bus:                 event.NewMemoryBus(),
+		Mailer:              mailer,
+		SignInAttempts:      attempts,
+		AccountLockout:      lockoutPolicy(cfg.AccountFreeAttempts, cfg.AccountMaxFailures),
+		IPLockout:           lockoutPolicy(cfg.IPFreeAttempts, cfg.IPMaxFailures),
+		IdempotencyKeys:     idempotencyKeys,
+		IdempotencyTTL:      idempotencyTTL,
+		IdempotencyLease:    time.Duration(cfg.IdempotencyConfig.LeaseSeconds) * time.Second,
+		OIDC:                oidc.NewVerifier(providers, nil),
+	})
 	handlers := handler.NewHandler(services)
+	router, err := handlers.InitRoutes(cfg.TrustedProxies)
+	if err != nil {
+		logrus.Fatalf("invalid trusted proxies: %s", err.Error())
+	}
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	purgeInterval := time.Duration(cfg.PurgeIntervalHours) * time.Hour
+	go job.NewPurgeJob("deleted users", services.Admin.PurgeDeletedUsers, purgeInterval,
+		time.Duration(cfg.DeletedUserRetentionDays)*24*time.Hour).Run(jobCtx)
+	go job.NewPurgeJob("expired exports", services.Export.PurgeExpiredExports, purgeInterval, 0).Run(jobCtx)
+	go job.NewPurgeJob("stale exports", services.Export.FailStaleExports, purgeInterval, exportBuildTimeout).Run(jobCtx)
+	go job.NewPurgeJob("self-deleted users", services.User.AnonymizeScheduledUsers, purgeInterval, 0).Run(jobCtx)
+	go job.NewPurgeJob("sign in attempts", services.Lockout.PurgeAttempts, purgeInterval, lockoutWindow).Run(jobCtx)
+	go job.NewPurgeJob("idempotency keys", services.Idempotency.PurgeKeys, purgeInterval, idempotencyTTL).Run(jobCtx)
 
 	go func() {
-		err = srv.Run(cfg.Port, handlers.InitRoutes())
+		err = srv.Run(cfg.Port, router)
 		if err != nil {
 			logrus.Fatalf("error due running server: %s", err.Error())
 		}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"Fitness_REST_API/internal/migrate"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const migrateUsage = "usage: app migrate up | down [steps|all] | status | force <version>"
+
+// runMigrate handles migrate subcommand, output goes to stdout for operator.
+func runMigrate(m *migrate.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migrations\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 && args[1] == "all" {
+			steps = math.MaxInt32
+		} else if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.New(migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migrations\n", reverted)
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		dirty := ""
+		if status.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Printf("version %d%s, latest %d\n", status.Version, dirty, status.Latest)
+		for _, mg := range status.Pending {
+			fmt.Printf("pending %06d_%s\n", mg.Version, mg.Name)
+		}
+	case "force":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return errors.New(migrateUsage)
+		}
+		if err = m.Force(ctx, version); err != nil {
+			return err
+		}
+		fmt.Printf("forced version %d\n", version)
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
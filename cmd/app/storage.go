@@ -0,0 +1,68 @@
+package main
+
+import (
+	"Fitness_REST_API/dbschema"
+	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/idempotency"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/migrate"
+	"Fitness_REST_API/internal/repository"
+	"Fitness_REST_API/internal/repository/memory"
+	"Fitness_REST_API/internal/repository/postgres"
+	"Fitness_REST_API/internal/repository/sqlite"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// storage is database of configured backend with its migrations and repositories. Memory backend
+// has neither database nor migrations, its data lives until the process exits.
+type storage struct {
+	db          *sqlx.DB
+	migrator    *migrate.Migrator
+	repos       *repository.Repository
+	attempts    lockout.Store
+	idempotency idempotency.Store
+}
+
+func openStorage(cfg *config.Config) (*storage, error) {
+	switch cfg.Backend {
+	case "postgres":
+		db, err := postgres.InitPostgresDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		migrations, err := migrate.Load(dbschema.FS)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		return &storage{db: db, migrator: migrate.New(db.DB, migrations), repos: repository.NewRepository(db),
+			attempts:    postgres.NewLockoutRepository(postgres.NewDB(db)),
+			idempotency: postgres.NewIdempotencyRepository(postgres.NewDB(db))}, nil
+	case "sqlite":
+		db, err := sqlite.InitSQLiteDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		migrations, err := migrate.Load(dbschema.SQLiteFS)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		return &storage{db: db, migrator: migrate.NewSQLite(db.DB, migrations), repos: repository.NewSQLiteRepository(db),
+			attempts:    sqlite.NewLockoutRepository(sqlite.NewDB(db)),
+			idempotency: sqlite.NewIdempotencyRepository(sqlite.NewDB(db))}, nil
+	case "memory":
+		return &storage{repos: repository.NewMemoryRepository(memory.NewStore()),
+			attempts: lockout.NewMemoryStore(), idempotency: idempotency.NewMemoryStore()}, nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q, it has to be \"postgres\", \"sqlite\" or \"memory\"",
+		cfg.Backend)
+}
+
+func (s *storage) close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
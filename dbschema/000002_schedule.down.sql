@@ -0,0 +1,5 @@
+DROP TABLE bookings;
+
+DROP TABLE availability_exceptions;
+
+DROP TABLE availability_slots;
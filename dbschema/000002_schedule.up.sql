@@ -0,0 +1,38 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE availability_slots (
+    id serial NOT NULL PRIMARY KEY,
+    trainer_id int NOT NULL REFERENCES users(id),
+    weekday int NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time time NOT NULL,
+    end_time time NOT NULL,
+    time_zone varchar(64) NOT NULL DEFAULT 'UTC',
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE availability_exceptions (
+    id serial NOT NULL PRIMARY KEY,
+    trainer_id int NOT NULL REFERENCES users(id),
+    date date NOT NULL,
+    start_time time NOT NULL,
+    end_time time NOT NULL,
+    available boolean NOT NULL DEFAULT false,
+    time_zone varchar(64) NOT NULL DEFAULT 'UTC',
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE bookings (
+    id serial NOT NULL PRIMARY KEY,
+    trainer_id int NOT NULL REFERENCES users(id),
+    user_id int NOT NULL REFERENCES users(id),
+    workout_id int REFERENCES workouts(id) ON DELETE SET NULL,
+    starts_at timestamptz NOT NULL,
+    ends_at timestamptz NOT NULL,
+    status varchar(255) NOT NULL DEFAULT 'booked',
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    cancelled_at timestamptz,
+    cancelled_by int REFERENCES users(id),
+    CHECK (starts_at < ends_at),
+    EXCLUDE USING gist (trainer_id WITH =, tstzrange(starts_at, ends_at) WITH &&) WHERE (status = 'booked'),
+    EXCLUDE USING gist (user_id WITH =, tstzrange(starts_at, ends_at) WITH &&) WHERE (status = 'booked')
+);
@@ -0,0 +1,10 @@
+CREATE TABLE messages (
+    id serial NOT NULL PRIMARY KEY,
+    partnership_id int NOT NULL REFERENCES partnerships(id),
+    sender_id int NOT NULL REFERENCES users(id),
+    body text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    read_at timestamptz
+);
+
+CREATE INDEX messages_partnership_id_idx ON messages (partnership_id, id);
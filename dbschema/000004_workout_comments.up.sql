@@ -0,0 +1,11 @@
+CREATE TABLE workout_comments (
+    id serial NOT NULL PRIMARY KEY,
+    workout_id int NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    author_id int NOT NULL REFERENCES users(id),
+    body text NOT NULL,
+    resolved boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz
+);
+
+CREATE INDEX workout_comments_workout_id_idx ON workout_comments (workout_id, id);
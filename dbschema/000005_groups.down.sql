@@ -0,0 +1,4 @@
+DROP TABLE group_workout_participants;
+DROP TABLE group_workouts;
+DROP TABLE client_group_members;
+DROP TABLE client_groups;
@@ -0,0 +1,31 @@
+CREATE TABLE client_groups (
+    id serial NOT NULL PRIMARY KEY,
+    trainer_id int NOT NULL REFERENCES users(id),
+    name varchar(255) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE client_group_members (
+    group_id int NOT NULL REFERENCES client_groups(id) ON DELETE CASCADE,
+    user_id int NOT NULL REFERENCES users(id),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE TABLE group_workouts (
+    id serial NOT NULL PRIMARY KEY,
+    trainer_id int NOT NULL REFERENCES users(id),
+    group_id int REFERENCES client_groups(id) ON DELETE SET NULL,
+    title varchar(255) NOT NULL,
+    description varchar(255),
+    date timestamptz NOT NULL,
+    capacity int NOT NULL CHECK (capacity > 0),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE group_workout_participants (
+    group_workout_id int NOT NULL REFERENCES group_workouts(id) ON DELETE CASCADE,
+    user_id int NOT NULL REFERENCES users(id),
+    workout_id int NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'registered',
+    PRIMARY KEY (group_workout_id, user_id)
+);
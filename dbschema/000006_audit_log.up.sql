@@ -0,0 +1,26 @@
+CREATE TABLE audit_log (
+    id bigserial NOT NULL PRIMARY KEY,
+    actor_type varchar(255) NOT NULL,
+    actor_id int NOT NULL,
+    action varchar(255) NOT NULL,
+    target_type varchar(255) NOT NULL,
+    target_id int NOT NULL,
+    before jsonb,
+    after jsonb,
+    request_id varchar(255) NOT NULL DEFAULT '',
+    ip varchar(255) NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_log_actor_idx ON audit_log (actor_type, actor_id, created_at);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id, created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
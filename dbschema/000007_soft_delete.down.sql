@@ -0,0 +1,36 @@
+ALTER TABLE partnerships DROP CONSTRAINT partnerships_user_id_fkey,
+    ADD CONSTRAINT partnerships_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE partnerships DROP CONSTRAINT partnerships_trainer_id_fkey,
+    ADD CONSTRAINT partnerships_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE workouts DROP CONSTRAINT workouts_user_id_fkey,
+    ADD CONSTRAINT workouts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE workouts DROP CONSTRAINT workouts_trainer_id_fkey,
+    ADD CONSTRAINT workouts_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE availability_slots DROP CONSTRAINT availability_slots_trainer_id_fkey,
+    ADD CONSTRAINT availability_slots_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE availability_exceptions DROP CONSTRAINT availability_exceptions_trainer_id_fkey,
+    ADD CONSTRAINT availability_exceptions_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE bookings DROP CONSTRAINT bookings_trainer_id_fkey,
+    ADD CONSTRAINT bookings_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE bookings DROP CONSTRAINT bookings_user_id_fkey,
+    ADD CONSTRAINT bookings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE bookings DROP CONSTRAINT bookings_cancelled_by_fkey,
+    ADD CONSTRAINT bookings_cancelled_by_fkey FOREIGN KEY (cancelled_by) REFERENCES users(id);
+ALTER TABLE messages DROP CONSTRAINT messages_partnership_id_fkey,
+    ADD CONSTRAINT messages_partnership_id_fkey FOREIGN KEY (partnership_id) REFERENCES partnerships(id);
+ALTER TABLE messages DROP CONSTRAINT messages_sender_id_fkey,
+    ADD CONSTRAINT messages_sender_id_fkey FOREIGN KEY (sender_id) REFERENCES users(id);
+ALTER TABLE workout_comments DROP CONSTRAINT workout_comments_author_id_fkey,
+    ADD CONSTRAINT workout_comments_author_id_fkey FOREIGN KEY (author_id) REFERENCES users(id);
+ALTER TABLE client_groups DROP CONSTRAINT client_groups_trainer_id_fkey,
+    ADD CONSTRAINT client_groups_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE client_group_members DROP CONSTRAINT client_group_members_user_id_fkey,
+    ADD CONSTRAINT client_group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE group_workouts DROP CONSTRAINT group_workouts_trainer_id_fkey,
+    ADD CONSTRAINT group_workouts_trainer_id_fkey FOREIGN KEY (trainer_id) REFERENCES users(id);
+ALTER TABLE group_workout_participants DROP CONSTRAINT group_workout_participants_user_id_fkey,
+    ADD CONSTRAINT group_workout_participants_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+
+DROP INDEX users_deleted_at_idx;
+
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,13 @@
+CREATE TABLE data_exports (
+    id serial NOT NULL PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'pending',
+    error text,
+    token varchar(64) UNIQUE,
+    archive bytea,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    completed_at timestamptz,
+    expires_at timestamptz
+);
+
+CREATE INDEX data_exports_user_id_idx ON data_exports (user_id);
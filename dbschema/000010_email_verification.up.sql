@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN verified_at timestamp;
+
+-- accounts created before verification was introduced are trusted
+UPDATE users SET verified_at = created_at;
+
+CREATE TABLE user_tokens (
+    id varchar(64) NOT NULL PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose varchar(255) NOT NULL,
+    expires_at timestamp NOT NULL,
+    used_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX user_tokens_user_id_idx ON user_tokens (user_id, purpose);
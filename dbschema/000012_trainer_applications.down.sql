@@ -0,0 +1,3 @@
+DROP TABLE trainer_application_certificates;
+
+DROP TABLE trainer_applications;
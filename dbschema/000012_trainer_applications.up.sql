@@ -0,0 +1,30 @@
+CREATE TABLE trainer_applications (
+    id serial NOT NULL PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'pending',
+    bio text NOT NULL,
+    specialization varchar(255) NOT NULL,
+    experience_years int NOT NULL DEFAULT 0,
+    reviewer_id int REFERENCES admins(id) ON DELETE SET NULL,
+    reviewer_note text,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW(),
+    reviewed_at timestamp
+);
+
+-- a user can have only one application under consideration
+CREATE UNIQUE INDEX trainer_applications_open_idx ON trainer_applications (user_id)
+    WHERE status IN ('pending', 'changes_requested');
+CREATE INDEX trainer_applications_status_idx ON trainer_applications (status, created_at);
+
+CREATE TABLE trainer_application_certificates (
+    id serial NOT NULL PRIMARY KEY,
+    application_id int NOT NULL REFERENCES trainer_applications(id) ON DELETE CASCADE,
+    file_name varchar(255) NOT NULL,
+    content_type varchar(255) NOT NULL,
+    size int NOT NULL,
+    content bytea NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX trainer_application_certificates_application_id_idx ON trainer_application_certificates (application_id);
@@ -0,0 +1,26 @@
+-- factor belongs either to a user or to an admin
+CREATE TABLE mfa_factors (
+    id serial NOT NULL PRIMARY KEY,
+    user_id int UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    admin_id int UNIQUE REFERENCES admins(id) ON DELETE CASCADE,
+    secret varchar(64) NOT NULL,
+    last_step bigint NOT NULL DEFAULT 0,
+    confirmed_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    CHECK ((user_id IS NULL) <> (admin_id IS NULL))
+);
+
+CREATE TABLE mfa_recovery_codes (
+    factor_id int NOT NULL REFERENCES mfa_factors(id) ON DELETE CASCADE,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamp,
+    PRIMARY KEY (factor_id, code_hash)
+);
+
+CREATE TABLE mfa_policies (
+    role varchar(255) NOT NULL PRIMARY KEY,
+    required boolean NOT NULL DEFAULT false,
+    updated_at timestamp NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO mfa_policies (role) VALUES ('admin'), ('trainer'), ('user');
@@ -0,0 +1,10 @@
+CREATE TABLE external_identities (
+    id serial NOT NULL PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider varchar(64) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    CONSTRAINT external_identities_subject_key UNIQUE (provider, subject),
+    CONSTRAINT external_identities_user_provider_key UNIQUE (user_id, provider)
+);
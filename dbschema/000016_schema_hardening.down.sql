@@ -0,0 +1,37 @@
+ALTER TABLE external_identities ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_policies ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_recovery_codes ALTER COLUMN used_at TYPE timestamp USING used_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_factors ALTER COLUMN confirmed_at TYPE timestamp USING confirmed_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE trainer_application_certificates
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE trainer_applications ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN reviewed_at TYPE timestamp USING reviewed_at AT TIME ZONE 'UTC';
+ALTER TABLE user_tokens ALTER COLUMN expires_at TYPE timestamp USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN used_at TYPE timestamp USING used_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE workouts ALTER COLUMN date TYPE timestamp USING date AT TIME ZONE 'UTC';
+ALTER TABLE partnerships ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN ended_at TYPE timestamp USING ended_at AT TIME ZONE 'UTC';
+ALTER TABLE users ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE timestamp USING deleted_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deletion_scheduled_at TYPE timestamp USING deletion_scheduled_at AT TIME ZONE 'UTC',
+    ALTER COLUMN anonymized_at TYPE timestamp USING anonymized_at AT TIME ZONE 'UTC',
+    ALTER COLUMN verified_at TYPE timestamp USING verified_at AT TIME ZONE 'UTC';
+
+ALTER TABLE mfa_policies DROP CONSTRAINT mfa_policies_role_check;
+ALTER TABLE user_tokens DROP CONSTRAINT user_tokens_purpose_check;
+ALTER TABLE trainer_applications DROP CONSTRAINT trainer_applications_status_check;
+ALTER TABLE data_exports DROP CONSTRAINT data_exports_status_check;
+ALTER TABLE group_workout_participants DROP CONSTRAINT group_workout_participants_status_check;
+ALTER TABLE bookings DROP CONSTRAINT bookings_status_check;
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE partnerships DROP CONSTRAINT partnerships_status_check,
+    ALTER COLUMN status DROP NOT NULL;
+
+DROP INDEX partnerships_open_idx;
+DROP INDEX partnerships_trainer_id_idx;
+DROP INDEX partnerships_user_id_idx;
+DROP INDEX workouts_trainer_id_idx;
+DROP INDEX workouts_user_id_idx;
@@ -0,0 +1,56 @@
+-- foreign key actions of the initial tables are set by 000007_soft_delete
+
+CREATE INDEX workouts_user_id_idx ON workouts (user_id, date);
+CREATE INDEX workouts_trainer_id_idx ON workouts (trainer_id, date);
+CREATE INDEX partnerships_user_id_idx ON partnerships (user_id);
+CREATE INDEX partnerships_trainer_id_idx ON partnerships (trainer_id, created_at);
+
+-- concurrent requests could open several partnerships for the same pair, older ones are ended
+UPDATE partnerships p SET status = 'ended by trainer', ended_at = NOW()
+WHERE status IN ('request', 'approved') AND EXISTS (
+    SELECT 1 FROM partnerships newer
+    WHERE newer.trainer_id = p.trainer_id AND newer.user_id = p.user_id
+        AND newer.status IN ('request', 'approved') AND newer.id > p.id
+);
+
+-- ended partnerships stay as history, only one partnership per pair can be open
+CREATE UNIQUE INDEX partnerships_open_idx ON partnerships (trainer_id, user_id)
+    WHERE status IN ('request', 'approved');
+
+ALTER TABLE partnerships ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT partnerships_status_check
+        CHECK (status IN ('request', 'approved', 'ended by user', 'ended by trainer'));
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'trainer'));
+ALTER TABLE bookings ADD CONSTRAINT bookings_status_check CHECK (status IN ('booked', 'cancelled'));
+ALTER TABLE group_workout_participants ADD CONSTRAINT group_workout_participants_status_check
+    CHECK (status IN ('registered', 'attended', 'missed', 'excused'));
+ALTER TABLE data_exports ADD CONSTRAINT data_exports_status_check
+    CHECK (status IN ('pending', 'ready', 'failed'));
+ALTER TABLE trainer_applications ADD CONSTRAINT trainer_applications_status_check
+    CHECK (status IN ('pending', 'changes_requested', 'approved', 'rejected'));
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+    CHECK (purpose IN ('verify_email', 'reset_password'));
+ALTER TABLE mfa_policies ADD CONSTRAINT mfa_policies_role_check CHECK (role IN ('admin', 'trainer', 'user'));
+
+-- values without time zone were written by the server in UTC
+ALTER TABLE users ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE timestamptz USING deleted_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deletion_scheduled_at TYPE timestamptz USING deletion_scheduled_at AT TIME ZONE 'UTC',
+    ALTER COLUMN anonymized_at TYPE timestamptz USING anonymized_at AT TIME ZONE 'UTC',
+    ALTER COLUMN verified_at TYPE timestamptz USING verified_at AT TIME ZONE 'UTC';
+ALTER TABLE partnerships ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN ended_at TYPE timestamptz USING ended_at AT TIME ZONE 'UTC';
+ALTER TABLE workouts ALTER COLUMN date TYPE timestamptz USING date AT TIME ZONE 'UTC';
+ALTER TABLE user_tokens ALTER COLUMN expires_at TYPE timestamptz USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN used_at TYPE timestamptz USING used_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE trainer_applications ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN reviewed_at TYPE timestamptz USING reviewed_at AT TIME ZONE 'UTC';
+ALTER TABLE trainer_application_certificates
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_factors ALTER COLUMN confirmed_at TYPE timestamptz USING confirmed_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_recovery_codes ALTER COLUMN used_at TYPE timestamptz USING used_at AT TIME ZONE 'UTC';
+ALTER TABLE mfa_policies ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE external_identities ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
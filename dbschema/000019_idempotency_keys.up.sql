@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(300) NOT NULL PRIMARY KEY,
+    fingerprint varchar(64) NOT NULL,
+    status int NOT NULL DEFAULT 0,
+    content_type varchar(255) NOT NULL DEFAULT '',
+    body bytea,
+    created_at timestamptz NOT NULL,
+    locked_until timestamptz NOT NULL
+);
+
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
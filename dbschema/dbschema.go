@@ -0,0 +1,18 @@
+// Package dbschema embeds versioned SQL migrations into the binary.
+package dbschema
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS holds NNNNNN_name.up.sql and NNNNNN_name.down.sql migrations.
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLiteFS holds the same versions written for SQLite.
+var SQLiteFS, _ = fs.Sub(sqliteFS, "sqlite")
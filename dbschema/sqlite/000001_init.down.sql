@@ -0,0 +1,4 @@
+DROP TABLE workouts;
+DROP TABLE partnerships;
+DROP TABLE users;
+DROP TABLE admins;
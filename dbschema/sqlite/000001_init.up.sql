@@ -0,0 +1,38 @@
+-- SQLite can't alter constraints, so foreign key actions of 000007_soft_delete and checks of
+-- 000016_schema_hardening are declared by the migrations which create the tables.
+-- Times are stored as text in UTC, which keeps them comparable.
+
+CREATE TABLE admins (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    login varchar(255) NOT NULL UNIQUE,
+    password_hash varchar(255) NOT NULL
+);
+
+CREATE TABLE users (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    email varchar(255) NOT NULL UNIQUE,
+    password_hash varchar(255) NOT NULL,
+    role varchar(255) NOT NULL DEFAULT 'user' CONSTRAINT users_role_check CHECK (role IN ('user', 'trainer')),
+    name varchar(255) NOT NULL,
+    surname varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE partnerships (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL CONSTRAINT partnerships_status_check
+        CHECK (status IN ('request', 'approved', 'ended by user', 'ended by trainer')),
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    ended_at timestamp
+);
+
+CREATE TABLE workouts (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    title varchar(255) NOT NULL,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    trainer_id integer REFERENCES users(id) ON DELETE SET NULL,
+    description varchar(255),
+    date timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
@@ -0,0 +1,3 @@
+DROP TABLE bookings;
+DROP TABLE availability_exceptions;
+DROP TABLE availability_slots;
@@ -0,0 +1,46 @@
+-- times of day and dates are kept as HH:MM and YYYY-MM-DD text
+CREATE TABLE availability_slots (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    weekday integer NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time text NOT NULL,
+    end_time text NOT NULL,
+    time_zone varchar(64) NOT NULL DEFAULT 'UTC',
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE availability_exceptions (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date text NOT NULL,
+    start_time text NOT NULL,
+    end_time text NOT NULL,
+    available boolean NOT NULL DEFAULT false,
+    time_zone varchar(64) NOT NULL DEFAULT 'UTC',
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE bookings (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_id integer REFERENCES workouts(id) ON DELETE SET NULL,
+    starts_at timestamp NOT NULL,
+    ends_at timestamp NOT NULL,
+    status varchar(255) NOT NULL DEFAULT 'booked' CONSTRAINT bookings_status_check
+        CHECK (status IN ('booked', 'cancelled')),
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    cancelled_at timestamp,
+    cancelled_by integer REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (starts_at < ends_at)
+);
+
+-- replaces exclusion constraints of postgres, booked ranges of trainer or user can't overlap
+CREATE TRIGGER bookings_overlap BEFORE INSERT ON bookings WHEN NEW.status = 'booked'
+BEGIN
+    SELECT RAISE(ABORT, 'slot is already booked') WHERE EXISTS (
+        SELECT 1 FROM bookings b
+        WHERE b.status = 'booked' AND (b.trainer_id = NEW.trainer_id OR b.user_id = NEW.user_id)
+            AND b.starts_at < NEW.ends_at AND b.ends_at > NEW.starts_at
+    );
+END;
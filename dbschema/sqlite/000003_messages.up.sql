@@ -0,0 +1,10 @@
+CREATE TABLE messages (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    partnership_id integer NOT NULL REFERENCES partnerships(id) ON DELETE CASCADE,
+    sender_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    read_at timestamp
+);
+
+CREATE INDEX messages_partnership_id_idx ON messages (partnership_id, id);
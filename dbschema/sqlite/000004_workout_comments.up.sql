@@ -0,0 +1,11 @@
+CREATE TABLE workout_comments (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    workout_id integer NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    author_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body text NOT NULL,
+    resolved boolean NOT NULL DEFAULT false,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at timestamp
+);
+
+CREATE INDEX workout_comments_workout_id_idx ON workout_comments (workout_id, id);
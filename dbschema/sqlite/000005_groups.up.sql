@@ -0,0 +1,32 @@
+CREATE TABLE client_groups (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE client_group_members (
+    group_id integer NOT NULL REFERENCES client_groups(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE TABLE group_workouts (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    trainer_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    group_id integer REFERENCES client_groups(id) ON DELETE SET NULL,
+    title varchar(255) NOT NULL,
+    description varchar(255),
+    date timestamp NOT NULL,
+    capacity integer NOT NULL CHECK (capacity > 0),
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE group_workout_participants (
+    group_workout_id integer NOT NULL REFERENCES group_workouts(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_id integer NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'registered' CONSTRAINT group_workout_participants_status_check
+        CHECK (status IN ('registered', 'attended', 'missed', 'excused')),
+    PRIMARY KEY (group_workout_id, user_id)
+);
@@ -0,0 +1,26 @@
+CREATE TABLE audit_log (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    actor_type varchar(255) NOT NULL,
+    actor_id integer NOT NULL,
+    action varchar(255) NOT NULL,
+    target_type varchar(255) NOT NULL,
+    target_id integer NOT NULL,
+    before blob,
+    after blob,
+    request_id varchar(255) NOT NULL DEFAULT '',
+    ip varchar(255) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX audit_log_actor_idx ON audit_log (actor_type, actor_id, created_at);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id, created_at);
+
+CREATE TRIGGER audit_log_append_only_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER audit_log_append_only_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
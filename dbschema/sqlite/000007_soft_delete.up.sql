@@ -0,0 +1,4 @@
+-- foreign key actions which let to purge users with plain DELETE are declared by the tables
+ALTER TABLE users ADD COLUMN deleted_at timestamp;
+
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE data_exports (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'pending' CONSTRAINT data_exports_status_check
+        CHECK (status IN ('pending', 'ready', 'failed')),
+    error text,
+    token varchar(64) UNIQUE,
+    archive blob,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    completed_at timestamp,
+    expires_at timestamp
+);
+
+CREATE INDEX data_exports_user_id_idx ON data_exports (user_id);
@@ -0,0 +1,4 @@
+DROP INDEX users_deletion_scheduled_at_idx;
+
+ALTER TABLE users DROP COLUMN anonymized_at;
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
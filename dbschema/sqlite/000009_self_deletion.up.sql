@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN deletion_scheduled_at timestamp;
+ALTER TABLE users ADD COLUMN anonymized_at timestamp;
+
+CREATE INDEX users_deletion_scheduled_at_idx ON users (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN verified_at timestamp;
+
+-- accounts created before verification was introduced are trusted
+UPDATE users SET verified_at = created_at;
+
+CREATE TABLE user_tokens (
+    id varchar(64) NOT NULL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose varchar(255) NOT NULL CONSTRAINT user_tokens_purpose_check
+        CHECK (purpose IN ('verify_email', 'reset_password')),
+    expires_at timestamp NOT NULL,
+    used_at timestamp,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX user_tokens_user_id_idx ON user_tokens (user_id, purpose);
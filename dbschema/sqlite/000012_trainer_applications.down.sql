@@ -0,0 +1,2 @@
+DROP TABLE trainer_application_certificates;
+DROP TABLE trainer_applications;
@@ -0,0 +1,31 @@
+CREATE TABLE trainer_applications (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status varchar(255) NOT NULL DEFAULT 'pending' CONSTRAINT trainer_applications_status_check
+        CHECK (status IN ('pending', 'changes_requested', 'approved', 'rejected')),
+    bio text NOT NULL,
+    specialization varchar(255) NOT NULL,
+    experience_years integer NOT NULL DEFAULT 0,
+    reviewer_id integer REFERENCES admins(id) ON DELETE SET NULL,
+    reviewer_note text,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    reviewed_at timestamp
+);
+
+-- a user can have only one application under consideration
+CREATE UNIQUE INDEX trainer_applications_open_idx ON trainer_applications (user_id)
+    WHERE status IN ('pending', 'changes_requested');
+CREATE INDEX trainer_applications_status_idx ON trainer_applications (status, created_at);
+
+CREATE TABLE trainer_application_certificates (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    application_id integer NOT NULL REFERENCES trainer_applications(id) ON DELETE CASCADE,
+    file_name varchar(255) NOT NULL,
+    content_type varchar(255) NOT NULL,
+    size integer NOT NULL,
+    content blob NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX trainer_application_certificates_application_id_idx ON trainer_application_certificates (application_id);
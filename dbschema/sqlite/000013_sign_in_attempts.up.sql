@@ -0,0 +1,7 @@
+CREATE TABLE sign_in_attempts (
+    attempt_key varchar(255) NOT NULL PRIMARY KEY,
+    failures integer NOT NULL DEFAULT 0,
+    last_failure timestamp NOT NULL
+);
+
+CREATE INDEX sign_in_attempts_last_failure_idx ON sign_in_attempts (last_failure);
@@ -0,0 +1,27 @@
+-- factor belongs either to a user or to an admin
+CREATE TABLE mfa_factors (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id integer UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    admin_id integer UNIQUE REFERENCES admins(id) ON DELETE CASCADE,
+    secret varchar(64) NOT NULL,
+    last_step integer NOT NULL DEFAULT 0,
+    confirmed_at timestamp,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CHECK ((user_id IS NULL) <> (admin_id IS NULL))
+);
+
+CREATE TABLE mfa_recovery_codes (
+    factor_id integer NOT NULL REFERENCES mfa_factors(id) ON DELETE CASCADE,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamp,
+    PRIMARY KEY (factor_id, code_hash)
+);
+
+CREATE TABLE mfa_policies (
+    role varchar(255) NOT NULL PRIMARY KEY CONSTRAINT mfa_policies_role_check
+        CHECK (role IN ('admin', 'trainer', 'user')),
+    required boolean NOT NULL DEFAULT false,
+    updated_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+INSERT INTO mfa_policies (role) VALUES ('admin'), ('trainer'), ('user');
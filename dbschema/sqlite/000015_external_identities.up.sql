@@ -0,0 +1,10 @@
+CREATE TABLE external_identities (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider varchar(64) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CONSTRAINT external_identities_subject_key UNIQUE (provider, subject),
+    CONSTRAINT external_identities_user_provider_key UNIQUE (user_id, provider)
+);
@@ -0,0 +1,5 @@
+DROP INDEX partnerships_open_idx;
+DROP INDEX partnerships_trainer_id_idx;
+DROP INDEX partnerships_user_id_idx;
+DROP INDEX workouts_trainer_id_idx;
+DROP INDEX workouts_user_id_idx;
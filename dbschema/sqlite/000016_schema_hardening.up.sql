@@ -0,0 +1,19 @@
+-- checks and not null status are declared by the migrations which create the tables,
+-- time columns already hold UTC
+
+CREATE INDEX workouts_user_id_idx ON workouts (user_id, date);
+CREATE INDEX workouts_trainer_id_idx ON workouts (trainer_id, date);
+CREATE INDEX partnerships_user_id_idx ON partnerships (user_id);
+CREATE INDEX partnerships_trainer_id_idx ON partnerships (trainer_id, created_at);
+
+-- concurrent requests could open several partnerships for the same pair, older ones are ended
+UPDATE partnerships SET status = 'ended by trainer', ended_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+WHERE status IN ('request', 'approved') AND EXISTS (
+    SELECT 1 FROM partnerships newer
+    WHERE newer.trainer_id = partnerships.trainer_id AND newer.user_id = partnerships.user_id
+        AND newer.status IN ('request', 'approved') AND newer.id > partnerships.id
+);
+
+-- ended partnerships stay as history, only one partnership per pair can be open
+CREATE UNIQUE INDEX partnerships_open_idx ON partnerships (trainer_id, user_id)
+    WHERE status IN ('request', 'approved');
@@ -0,0 +1,2 @@
+ALTER TABLE workouts ADD COLUMN version bigint NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(300) NOT NULL PRIMARY KEY,
+    fingerprint varchar(64) NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    content_type varchar(255) NOT NULL DEFAULT '',
+    body blob,
+    created_at timestamp NOT NULL,
+    locked_until timestamp NOT NULL
+);
+
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
@@ -6,7 +6,26 @@ import (
 
 type Config struct {
 	Port string
+	// TrustedProxies are addresses or CIDRs whose X-Forwarded-For is believed, client IP of other
+	// requests is the remote address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	StorageConfig
 	PostgresConfig
+	SQLiteConfig
+	BookingConfig
+	RetentionConfig
+	ExportConfig
+	AuthConfig
+	MailConfig
+	LockoutConfig
+	IdempotencyConfig
+	OIDCConfig
+	MigrateConfig
+}
+
+// StorageConfig selects backend of repositories, "postgres", "sqlite" or "memory".
+type StorageConfig struct {
+	Backend string `mapstructure:"backend"`
 }
 
 type PostgresConfig struct {
@@ -17,6 +36,76 @@ type PostgresConfig struct {
 	DBPassword string
 }
 
+type SQLiteConfig struct {
+	SQLitePath string `mapstructure:"path"`
+}
+
+type BookingConfig struct {
+	CancellationCutoffHours int `mapstructure:"cancellation_cutoff_hours"`
+}
+
+type RetentionConfig struct {
+	DeletedUserRetentionDays int `mapstructure:"deleted_user_retention_days"`
+	PurgeIntervalHours       int `mapstructure:"purge_interval_hours"`
+	SelfDeletionGraceDays    int `mapstructure:"self_deletion_grace_days"`
+}
+
+type ExportConfig struct {
+	LinkTTLHours        int `mapstructure:"link_ttl_hours"`
+	BuildTimeoutMinutes int `mapstructure:"build_timeout_minutes"`
+}
+
+type AuthConfig struct {
+	RequireVerification  bool   `mapstructure:"require_verification"`
+	VerificationTTLHours int    `mapstructure:"verification_ttl_hours"`
+	ResetTTLHours        int    `mapstructure:"reset_ttl_hours"`
+	AppURL               string `mapstructure:"app_url"`
+}
+
+type MailConfig struct {
+	Transport    string `mapstructure:"transport"`
+	LogPath      string `mapstructure:"log_path"`
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     string `mapstructure:"smtp_port"`
+	SMTPUser     string `mapstructure:"smtp_user"`
+	SMTPPassword string
+	From         string `mapstructure:"from"`
+}
+
+type LockoutConfig struct {
+	Store               string `mapstructure:"store"`
+	AccountFreeAttempts int    `mapstructure:"account_free_attempts"`
+	AccountMaxFailures  int    `mapstructure:"account_max_failures"`
+	IPFreeAttempts      int    `mapstructure:"ip_free_attempts"`
+	IPMaxFailures       int    `mapstructure:"ip_max_failures"`
+	BaseDelaySeconds    int    `mapstructure:"base_delay_seconds"`
+	MaxDelaySeconds     int    `mapstructure:"max_delay_seconds"`
+	LockoutMinutes      int    `mapstructure:"lockout_minutes"`
+	WindowHours         int    `mapstructure:"window_hours"`
+}
+
+// IdempotencyConfig selects store of Idempotency-Key responses, "postgres" or "memory", how long they are kept
+// and how long key of request in progress stays reserved.
+type IdempotencyConfig struct {
+	Store        string `mapstructure:"store"`
+	TTLHours     int    `mapstructure:"ttl_hours"`
+	LeaseSeconds int    `mapstructure:"lease_seconds"`
+}
+
+type OIDCConfig struct {
+	Providers map[string]OIDCProvider `mapstructure:"providers"`
+}
+
+type OIDCProvider struct {
+	Issuers   []string `mapstructure:"issuers"`
+	JWKSURL   string   `mapstructure:"jwks_url"`
+	ClientIDs []string `mapstructure:"client_ids"`
+}
+
+type MigrateConfig struct {
+	CheckOnStart bool `mapstructure:"check_on_start"`
+}
+
 func InitConfig() (*Config, error) {
 	viper.SetConfigFile("configs/config.yml")
 
@@ -30,10 +119,54 @@ func InitConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := viper.UnmarshalKey("storage_config", &cfg.StorageConfig); err != nil {
+		return nil, err
+	}
+
 	if err := viper.UnmarshalKey("postgres_config", &cfg.PostgresConfig); err != nil {
 		return nil, err
 	}
 
+	if err := viper.UnmarshalKey("sqlite_config", &cfg.SQLiteConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("booking_config", &cfg.BookingConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("retention_config", &cfg.RetentionConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("export_config", &cfg.ExportConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("auth_config", &cfg.AuthConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("mail_config", &cfg.MailConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("lockout_config", &cfg.LockoutConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("idempotency_config", &cfg.IdempotencyConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("oidc_config", &cfg.OIDCConfig); err != nil {
+		return nil, err
+	}
+
+	if err := viper.UnmarshalKey("migrate_config", &cfg.MigrateConfig); err != nil {
+		return nil, err
+	}
+
 	if err := parseEnv(&cfg); err != nil {
 		return nil, err
 	}
@@ -51,5 +184,11 @@ func parseEnv(cfg *Config) error {
 	}
 
 	cfg.DBPassword = viper.GetString("postgres_password")
+
+	if err := viper.BindEnv("smtp_password"); err != nil {
+		return err
+	}
+
+	cfg.SMTPPassword = viper.GetString("smtp_password")
 	return nil
 }
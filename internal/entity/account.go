@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type TokenPurpose string
+
+const (
+	TokenVerifyEmail   TokenPurpose = "verify_email"
+	TokenResetPassword TokenPurpose = "reset_password"
+)
+
+// AccountToken is a record of issued single-use token, the token itself is signed and carries Id.
+type AccountToken struct {
+	Id        string       `db:"id"`
+	UserId    int64        `db:"user_id"`
+	Purpose   TokenPurpose `db:"purpose"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ApplicationStatus string
+
+const (
+	ApplicationPending          ApplicationStatus = "pending"
+	ApplicationChangesRequested ApplicationStatus = "changes_requested"
+	ApplicationApproved         ApplicationStatus = "approved"
+	ApplicationRejected         ApplicationStatus = "rejected"
+)
+
+type TrainerApplication struct {
+	Id              int64             `db:"id" json:"id"`
+	UserId          int64             `db:"user_id" json:"user_id"`
+	Status          ApplicationStatus `db:"status" json:"status"`
+	Bio             string            `db:"bio" json:"bio"`
+	Specialization  string            `db:"specialization" json:"specialization"`
+	ExperienceYears int               `db:"experience_years" json:"experience_years"`
+	ReviewerId      sql.NullInt64     `db:"reviewer_id" swaggertype:"integer" json:"reviewer_id,omitempty"`
+	ReviewerNote    sql.NullString    `db:"reviewer_note" swaggertype:"string" json:"reviewer_note,omitempty"`
+	CreatedAt       time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time         `db:"updated_at" json:"updated_at"`
+	ReviewedAt      sql.NullTime      `db:"reviewed_at" swaggertype:"string" json:"reviewed_at,omitempty"`
+	Certificates    []*Certificate    `db:"-" json:"certificates,omitempty"`
+}
+
+type TrainerApplicationInput struct {
+	Bio             string `json:"bio" binding:"required"`
+	Specialization  string `json:"specialization" binding:"required"`
+	ExperienceYears int    `json:"experience_years" binding:"min=0"`
+}
+
+type Certificate struct {
+	Id            int64     `db:"id" json:"id"`
+	ApplicationId int64     `db:"application_id" json:"application_id"`
+	FileName      string    `db:"file_name" json:"file_name"`
+	ContentType   string    `db:"content_type" json:"content_type"`
+	Size          int64     `db:"size" json:"size"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+
+	Content []byte `db:"content" json:"-"`
+}
+
+type ApplicationReview struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+type ActorType string
+
+const (
+	ActorAdmin   ActorType = "admin"
+	ActorTrainer ActorType = "trainer"
+	ActorUser    ActorType = "user"
+
+	// ActorAnonymous is a client that is not signed in, it is identified by IP only.
+	ActorAnonymous ActorType = "anonymous"
+)
+
+type AuditAction string
+
+const (
+	AuditUserCreate     AuditAction = "user.create"
+	AuditUserUpdate     AuditAction = "user.update"
+	AuditUserDelete     AuditAction = "user.delete"
+	AuditUserRestore    AuditAction = "user.restore"
+	AuditUserExport     AuditAction = "user.export"
+	AuditRequestAccept  AuditAction = "partnership.accept"
+	AuditRequestDeny    AuditAction = "partnership.deny"
+	AuditPartnershipEnd AuditAction = "partnership.end"
+
+	AuditApplicationSubmit         AuditAction = "trainer_application.submit"
+	AuditApplicationUpdate         AuditAction = "trainer_application.update"
+	AuditApplicationCertificate    AuditAction = "trainer_application.certificate"
+	AuditApplicationApprove        AuditAction = "trainer_application.approve"
+	AuditApplicationReject         AuditAction = "trainer_application.reject"
+	AuditApplicationRequestChanges AuditAction = "trainer_application.request_changes"
+
+	AuditSignInLockout AuditAction = "sign_in.lockout"
+	AuditSignInUnlock  AuditAction = "sign_in.unlock"
+
+	AuditMFAPolicy AuditAction = "mfa.policy"
+)
+
+const (
+	AuditTargetUser        = "user"
+	AuditTargetPartnership = "partnership"
+	AuditTargetApplication = "trainer_application"
+	AuditTargetSignIn      = "sign_in"
+	AuditTargetMFAPolicy   = "mfa_policy"
+)
+
+// Actor describes who performs a privileged mutation, it is recorded in audit log.
+type Actor struct {
+	Type      ActorType
+	Id        int64
+	RequestId string
+	IP        string
+}
+
+type AuditEntry struct {
+	Id         int64       `db:"id" json:"id"`
+	ActorType  ActorType   `db:"actor_type" json:"actor_type"`
+	ActorId    int64       `db:"actor_id" json:"actor_id"`
+	Action     AuditAction `db:"action" json:"action"`
+	TargetType string      `db:"target_type" json:"target_type"`
+	TargetId   int64       `db:"target_id" json:"target_id"`
+	Before     AuditData   `db:"before" swaggertype:"object" json:"before"`
+	After      AuditData   `db:"after" swaggertype:"object" json:"after"`
+	RequestId  string      `db:"request_id" json:"request_id"`
+	IP         string      `db:"ip" json:"ip"`
+	CreatedAt  time.Time   `db:"created_at" json:"created_at"`
+}
+
+type AuditFilter struct {
+	ActorType  ActorType
+	ActorId    int64
+	TargetType string
+	TargetId   int64
+	From       time.Time
+	To         time.Time
+	BeforeId   int64
+	Limit      int
+}
+
+type AuditPage struct {
+	Entries []*AuditEntry `json:"entries"`
+	HasMore bool          `json:"has_more"`
+}
+
+// AuditData holds JSON document from jsonb column as is.
+type AuditData []byte
+
+func (d AuditData) MarshalJSON() ([]byte, error) {
+	if len(d) == 0 {
+		return []byte("null"), nil
+	}
+	return d, nil
+}
+
+func (d AuditData) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+	return []byte(d), nil
+}
+
+func (d *AuditData) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = nil
+	case []byte:
+		*d = append(AuditData(nil), v...)
+	case string:
+		*d = AuditData(v)
+	default:
+		return errors.New("unsupported type of audit data")
+	}
+	return nil
+}
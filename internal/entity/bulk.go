@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// BulkMode tells whether failed item of bulk operation rolls back the other ones, atomic is default.
+type BulkMode string
+
+const (
+	BulkAtomic     BulkMode = "atomic"
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// BulkCreateInput creates workouts of trainer with his clients, trainer is set from the token.
+type BulkCreateInput struct {
+	Mode     BulkMode   `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Workouts []*Workout `json:"workouts" binding:"required,min=1,max=100,dive,required"`
+}
+
+// BulkPatchInput applies JSON Merge Patch to every workout.
+type BulkPatchInput struct {
+	Mode     BulkMode            `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Workouts []*BulkWorkoutPatch `json:"workouts" binding:"required,min=1,max=100,dive,required"`
+}
+
+// BulkWorkoutPatch is merge patch of workout with the given id, non-zero version has to match the stored one.
+type BulkWorkoutPatch struct {
+	Id      int64 `json:"id" binding:"required"`
+	Version int64 `json:"version"`
+	WorkoutPatch
+}
+
+type BulkDeleteInput struct {
+	Mode     BulkMode      `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Workouts []*WorkoutRef `json:"workouts" binding:"required,min=1,max=100,dive,required"`
+}
+
+// WorkoutRef points to workout, non-zero version has to match the stored one.
+type WorkoutRef struct {
+	Id      int64 `json:"id" binding:"required"`
+	Version int64 `json:"version"`
+}
+
+// BulkShiftInput moves workouts of trainer with user which start in [from, to) by days,
+// days are counted in time zone of user, so local time of workouts is kept over DST change.
+type BulkShiftInput struct {
+	Mode   BulkMode  `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	UserId int64     `json:"user_id" binding:"required"`
+	From   time.Time `json:"from" binding:"required"`
+	To     time.Time `json:"to" binding:"required"`
+	Days   int       `json:"days" binding:"required"`
+}
+
+// BulkItemResult is outcome of item with the same index in input, version is set for changed workouts.
+type BulkItemResult struct {
+	Index     int    `json:"index"`
+	WorkoutId int64  `json:"workout_id,omitempty"`
+	Version   int64  `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkResult reports items of bulk operation. Atomic operation with failed items isn't committed,
+// then items without error are the ones which would have been applied.
+type BulkResult struct {
+	Committed bool              `json:"committed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []*BulkItemResult `json:"items"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Comment struct {
+	Id        int64        `db:"id" json:"id"`
+	WorkoutId int64        `db:"workout_id" json:"workout_id"`
+	AuthorId  int64        `db:"author_id" json:"author_id"`
+	Body      string       `db:"body" json:"body"`
+	Resolved  bool         `db:"resolved" json:"resolved"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at" swaggertype:"string" json:"updated_at,omitempty"`
+}
+
+type CommentInput struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+type ResolveCommentInput struct {
+	Resolved bool `json:"resolved"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+type EventType string
+
+const (
+	EventPartnershipRequested EventType = "partnership.requested"
+	EventPartnershipAccepted  EventType = "partnership.accepted"
+	EventWorkoutCreated       EventType = "workout.created"
+	EventWorkoutUpdated       EventType = "workout.updated"
+	EventMessageCreated       EventType = "message.created"
+	EventCommentCreated       EventType = "comment.created"
+)
+
+type Event struct {
+	Id         int64       `json:"id"`
+	Type       EventType   `json:"type"`
+	Recipients []int64     `json:"-"`
+	Payload    interface{} `json:"payload"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type PartnershipEvent struct {
+	PartnershipId int64 `json:"partnership_id"`
+	UserId        int64 `json:"user_id"`
+	TrainerId     int64 `json:"trainer_id"`
+}
+
+func NewEvent(eventType EventType, payload interface{}, recipients ...int64) *Event {
+	return &Event{
+		Type:       eventType,
+		Recipients: recipients,
+		Payload:    payload,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending ExportStatus = "pending"
+	ExportReady   ExportStatus = "ready"
+	ExportFailed  ExportStatus = "failed"
+)
+
+type DataExport struct {
+	Id          int64          `db:"id" json:"id"`
+	UserId      int64          `db:"user_id" json:"user_id"`
+	Status      ExportStatus   `db:"status" json:"status"`
+	Error       sql.NullString `db:"error" swaggertype:"string" json:"error,omitempty"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	CompletedAt sql.NullTime   `db:"completed_at" swaggertype:"string" json:"completed_at,omitempty"`
+	ExpiresAt   sql.NullTime   `db:"expires_at" swaggertype:"string" json:"expires_at,omitempty"`
+	DownloadURL string         `db:"-" json:"download_url,omitempty"`
+
+	Token   sql.NullString `db:"token" json:"-"`
+	Archive []byte         `db:"archive" json:"-"`
+}
+
+// ExportData is everything stored about a user, it is packed into export archive.
+type ExportData struct {
+	Profile      *User          `json:"profile"`
+	Workouts     []*Workout     `json:"workouts"`
+	Partnerships []*Partnership `json:"partnerships"`
+	Messages     []*Message     `json:"messages"`
+	Comments     []*Comment     `json:"comments"`
+	Bookings     []*Booking     `json:"bookings"`
+}
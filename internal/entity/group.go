@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AttendanceStatus string
+
+const (
+	AttendanceRegistered AttendanceStatus = "registered"
+	AttendanceAttended   AttendanceStatus = "attended"
+	AttendanceMissed     AttendanceStatus = "missed"
+	AttendanceExcused    AttendanceStatus = "excused"
+)
+
+type ClientGroup struct {
+	Id        int64     `db:"id" json:"id"`
+	TrainerId int64     `db:"trainer_id" json:"trainer_id"`
+	Name      string    `db:"name" json:"name" binding:"required"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Members   []*User   `db:"-" json:"members,omitempty"`
+}
+
+type GroupWorkout struct {
+	Id           int64          `db:"id" json:"id"`
+	TrainerId    int64          `db:"trainer_id" json:"trainer_id"`
+	GroupId      sql.NullInt64  `db:"group_id" swaggertype:"integer" json:"group_id,omitempty"`
+	Title        string         `db:"title" json:"title"`
+	Description  string         `db:"description" json:"description,omitempty"`
+	Date         time.Time      `db:"date" json:"date"`
+	Capacity     int            `db:"capacity" json:"capacity"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	Participants []*Participant `db:"-" json:"participants,omitempty"`
+}
+
+type GroupWorkoutInput struct {
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date" binding:"required"`
+	Capacity    int       `json:"capacity" binding:"required,min=1"`
+	GroupId     int64     `json:"group_id"`
+	UserIds     []int64   `json:"user_ids"`
+}
+
+type Participant struct {
+	GroupWorkoutId int64            `db:"group_workout_id" json:"group_workout_id"`
+	UserId         int64            `db:"user_id" json:"user_id"`
+	WorkoutId      int64            `db:"workout_id" json:"workout_id"`
+	Status         AttendanceStatus `db:"status" json:"status"`
+	Name           string           `db:"name" json:"name"`
+	Surname        string           `db:"surname" json:"surname"`
+}
+
+type AttendanceInput struct {
+	Status AttendanceStatus `json:"status" binding:"required,oneof=registered attended missed excused"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord is response of request sent with Idempotency-Key, zero status means the request is in progress.
+// Fingerprint is hash of the request, the key can't be reused for another one. Request still in progress after
+// LockedUntil is taken as lost with its process and the key can be reserved again.
+type IdempotencyRecord struct {
+	Key         string    `db:"idempotency_key" json:"key"`
+	Fingerprint string    `db:"fingerprint" json:"fingerprint"`
+	Status      int       `db:"status" json:"status"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Body        []byte    `db:"body" json:"-"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	LockedUntil time.Time `db:"locked_until" json:"locked_until"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// ExternalIdentity links account of OIDC provider to user.
+type ExternalIdentity struct {
+	Id        int64     `db:"id" json:"-"`
+	UserId    int64     `db:"user_id" json:"-"`
+	Provider  string    `db:"provider" json:"provider"`
+	Subject   string    `db:"subject" json:"-"`
+	Email     string    `db:"email" json:"email"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// OIDCInput is ID token obtained by app from provider, nonce is the one app has passed to provider
+// for this sign in, so the token can't be replayed by another app.
+type OIDCInput struct {
+	IdToken string `json:"id_token" binding:"required"`
+	Nonce   string `json:"nonce" binding:"required"`
+}
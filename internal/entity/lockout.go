@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// SignInAttempts is history of failed sign in attempts for an account or a client IP.
+type SignInAttempts struct {
+	Key         string    `db:"attempt_key" json:"key"`
+	Failures    int       `db:"failures" json:"failures"`
+	LastFailure time.Time `db:"last_failure" json:"last_failure"`
+}
+
+// UnlockInput selects account by scope and login, client IP or both.
+type UnlockInput struct {
+	Scope ActorType `json:"scope" binding:"omitempty,oneof=admin user"`
+	Login string    `json:"login"`
+	IP    string    `json:"ip"`
+}
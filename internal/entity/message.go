@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Message struct {
+	Id            int64        `db:"id" json:"id"`
+	PartnershipId int64        `db:"partnership_id" json:"partnership_id"`
+	SenderId      int64        `db:"sender_id" json:"sender_id"`
+	Body          string       `db:"body" json:"body"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at"`
+	ReadAt        sql.NullTime `db:"read_at" swaggertype:"string" json:"read_at,omitempty"`
+}
+
+type MessageInput struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+type MessagePage struct {
+	Messages []*Message `json:"messages"`
+	HasMore  bool       `json:"has_more"`
+}
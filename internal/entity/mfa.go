@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AdminRole is role of admin accounts in MFA policy, admins are not stored among users.
+const AdminRole Role = "admin"
+
+// MFAFactor is TOTP secret of a user or an admin, it is active once ConfirmedAt is set.
+type MFAFactor struct {
+	Id          int64         `db:"id"`
+	UserId      sql.NullInt64 `db:"user_id"`
+	AdminId     sql.NullInt64 `db:"admin_id"`
+	Secret      string        `db:"secret"`
+	LastStep    int64         `db:"last_step"`
+	ConfirmedAt sql.NullTime  `db:"confirmed_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+// MFAEnrollment is secret to be added to authenticator app, URI is the QR code payload.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type MFAPolicy struct {
+	Role      Role      `db:"role" json:"role" binding:"required,oneof=admin trainer user"`
+	Required  bool      `db:"required" json:"required"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// MFACodeInput carries either TOTP code or recovery code.
+type MFACodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAChallengeInput struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+type MFAVerifyInput struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// SignInResult holds either session token or MFA challenge token which has to be verified with code.
+type SignInResult struct {
+	Token                 string
+	MFAToken              string
+	MFAEnrollmentRequired bool
+	RecoveryCodes         []string
+}
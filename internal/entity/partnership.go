@@ -21,6 +21,8 @@ type Partnership struct {
 	Status    Status       `db:"status" json:"status"`
 	CreatedAt time.Time    `db:"created_at" json:"created_at"`
 	EndedAt   sql.NullTime `db:"ended_at" swaggertype:"string" json:"ended_at,omitempty"`
+
+	UnreadMessages int64 `db:"unread_messages" json:"unread_messages,omitempty"`
 }
 
 type Request struct {
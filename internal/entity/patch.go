@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Patch is a member of JSON Merge Patch (RFC 7396) document, it tells absent member from null and from value.
+type Patch[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is called only for members present in the document, absent ones stay unset.
+func (p *Patch[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
+
+// WorkoutPatch changes members present in it, null description clears it. Title and date can't be null.
+type WorkoutPatch struct {
+	Title       Patch[string]    `json:"title" swaggertype:"string"`
+	Description Patch[string]    `json:"description" swaggertype:"string"`
+	Date        Patch[time.Time] `json:"date" swaggertype:"string" format:"date-time"`
+}
+
+// Changes returns columns set by the patch and their values.
+func (p *WorkoutPatch) Changes() ([]string, []interface{}) {
+	var columns []string
+	var values []interface{}
+	if p.Title.Set {
+		columns, values = append(columns, "title"), append(values, p.Title.Value)
+	}
+	if p.Description.Set {
+		columns, values = append(columns, "description"), append(values, p.Description.Value)
+	}
+	if p.Date.Set {
+		columns, values = append(columns, "date"), append(values, p.Date.Value)
+	}
+	return columns, values
+}
+
+// ProfilePatch changes members present in it, null time_zone resets it to UTC. Other members can't be null.
+type ProfilePatch struct {
+	Email    Patch[string] `json:"email" swaggertype:"string"`
+	Name     Patch[string] `json:"name" swaggertype:"string"`
+	Surname  Patch[string] `json:"surname" swaggertype:"string"`
+	TimeZone Patch[string] `json:"time_zone" swaggertype:"string"`
+}
+
+// Changes returns columns set by the patch and their values.
+func (p *ProfilePatch) Changes() ([]string, []interface{}) {
+	var columns []string
+	var values []interface{}
+	if p.Email.Set {
+		columns, values = append(columns, "email"), append(values, p.Email.Value)
+	}
+	if p.Name.Set {
+		columns, values = append(columns, "name"), append(values, p.Name.Value)
+	}
+	if p.Surname.Set {
+		columns, values = append(columns, "surname"), append(values, p.Surname.Value)
+	}
+	if p.TimeZone.Set {
+		columns, values = append(columns, "time_zone"), append(values, p.TimeZone.Value)
+	}
+	return columns, values
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type BookingStatus string
+
+const (
+	BookingStatusBooked    BookingStatus = "booked"
+	BookingStatusCancelled BookingStatus = "cancelled"
+)
+
+type AvailabilitySlot struct {
+	Id        int64  `db:"id" json:"id"`
+	TrainerId int64  `db:"trainer_id" json:"trainer_id"`
+	Weekday   int    `db:"weekday" json:"weekday" binding:"min=0,max=6"`
+	StartTime string `db:"start_time" json:"start_time" binding:"required"`
+	EndTime   string `db:"end_time" json:"end_time" binding:"required"`
+	TimeZone  string `db:"time_zone" json:"time_zone"`
+}
+
+type AvailabilityException struct {
+	Id        int64  `db:"id" json:"id"`
+	TrainerId int64  `db:"trainer_id" json:"trainer_id"`
+	Date      string `db:"date" json:"date" binding:"required"`
+	StartTime string `db:"start_time" json:"start_time" binding:"required"`
+	EndTime   string `db:"end_time" json:"end_time" binding:"required"`
+	Available bool   `db:"available" json:"available"`
+	TimeZone  string `db:"time_zone" json:"time_zone"`
+}
+
+type Availability struct {
+	Slots      []*AvailabilitySlot      `json:"slots"`
+	Exceptions []*AvailabilityException `json:"exceptions"`
+}
+
+type FreeSlot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type Booking struct {
+	Id          int64         `db:"id" json:"id"`
+	TrainerId   int64         `db:"trainer_id" json:"trainer_id"`
+	UserId      int64         `db:"user_id" json:"user_id"`
+	WorkoutId   sql.NullInt64 `db:"workout_id" swaggertype:"integer" json:"workout_id,omitempty"`
+	StartsAt    time.Time     `db:"starts_at" json:"starts_at"`
+	EndsAt      time.Time     `db:"ends_at" json:"ends_at"`
+	Status      BookingStatus `db:"status" json:"status"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	CancelledAt sql.NullTime  `db:"cancelled_at" swaggertype:"string" json:"cancelled_at,omitempty"`
+	CancelledBy sql.NullInt64 `db:"cancelled_by" swaggertype:"integer" json:"cancelled_by,omitempty"`
+}
+
+type BookingInput struct {
+	TrainerId   int64     `json:"trainer_id" binding:"required"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
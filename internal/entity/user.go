@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Role string
 
@@ -10,13 +13,24 @@ const (
 )
 
 type User struct {
-	Id           int64     `db:"id" json:"id"`
-	Email        string    `db:"email" json:"email" binding:"required"`
-	PasswordHash string    `db:"password_hash" json:"password_hash,omitempty" binding:"required"`
-	Role         Role      `db:"role" json:"role,omitempty"`
-	Name         string    `db:"name" json:"name" binding:"required"`
-	Surname      string    `db:"surname" json:"surname" binding:"required"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at,omitempty"`
+	Id           int64        `db:"id" json:"id"`
+	Email        string       `db:"email" json:"email" binding:"required"`
+	PasswordHash string       `db:"password_hash" json:"password_hash,omitempty" binding:"required"`
+	Role         Role         `db:"role" json:"role,omitempty"`
+	Name         string       `db:"name" json:"name" binding:"required"`
+	Surname      string       `db:"surname" json:"surname" binding:"required"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at,omitempty"`
+	TimeZone     string       `db:"time_zone" json:"time_zone,omitempty"`
+	DeletedAt    sql.NullTime `db:"deleted_at" json:"-"`
+	VerifiedAt   sql.NullTime `db:"verified_at" json:"-"`
+	TokenVersion int64        `db:"token_version" json:"-"`
+	// Version is increased by every change of profile, it is sent as ETag.
+	Version int64 `db:"version" json:"version,omitempty"`
+
+	DeletionScheduledAt sql.NullTime `db:"deletion_scheduled_at" json:"-"`
+	AnonymizedAt        sql.NullTime `db:"anonymized_at" json:"-"`
+
+	UnreadMessages int64 `db:"unread_messages" json:"unread_messages,omitempty"`
 }
 
 type UserInfo struct {
@@ -37,3 +51,21 @@ type UserUpdate struct {
 	Name     string `db:"name" json:"name"`
 	Surname  string `db:"surname" json:"surname"`
 }
+
+type AccountDeletionInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// ProfileUpdate is a self-service update, empty fields are kept as is.
+// TimeZone is IANA name, e.g. Europe/Berlin, workout times are shown in it.
+type ProfileUpdate struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+	TimeZone string `json:"time_zone"`
+}
+
+type PasswordChangeInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
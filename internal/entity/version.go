@@ -0,0 +1,7 @@
+package entity
+
+import "errors"
+
+// ErrVersionMismatch is returned by conditional changes when resource has been changed
+// since the version client has read.
+var ErrVersionMismatch = errors.New("resource has been changed, read it again")
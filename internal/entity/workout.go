@@ -2,9 +2,17 @@ package entity
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+var (
+	// ErrNoWorkoutAccess is returned for workout which is neither of the user nor of their trainer.
+	ErrNoWorkoutAccess = errors.New("no access to this workout")
+	// ErrNoWorkoutRights is returned when trainer creates workout with user they have no approved partnership with.
+	ErrNoWorkoutRights = errors.New("no rights to create workout with this user")
+)
+
 type Workout struct {
 	Id          int64         `db:"id" json:"id"`
 	Title       string        `db:"title" json:"title" binding:"required"`
@@ -12,6 +20,12 @@ type Workout struct {
 	TrainerId   sql.NullInt64 `db:"trainer_id" swaggertype:"integer" json:"trainer_id,omitempty"`
 	Description string        `db:"description" json:"description,omitempty"`
 	Date        time.Time     `db:"date" json:"date"`
+	// Version is increased by every change, it is sent as ETag.
+	Version int64 `db:"version" json:"version,omitempty"`
+
+	// LocalDate is Date in time zone of viewer, it is set when workout is read.
+	LocalDate *time.Time `db:"-" json:"local_date,omitempty"`
+	TimeZone  string     `db:"-" json:"time_zone,omitempty"`
 }
 
 type UpdateWorkout struct {
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultBufferSize = 16
+
+// Publisher is used by services to announce changes.
+type Publisher interface {
+	Publish(event *entity.Event)
+}
+
+// Subscriber delivers events addressed to a user until the returned cancel function is called.
+type Subscriber interface {
+	Subscribe(userId int64) (<-chan *entity.Event, func())
+}
+
+// Bus is the transport between services and connected clients.
+// MemoryBus works within a single process; a Redis or NATS backed
+// implementation can be plugged in for multiple replicas.
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+type MemoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan *entity.Event]struct{}
+	bufferSize  int
+	lastId      int64
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: make(map[int64]map[chan *entity.Event]struct{}),
+		bufferSize:  defaultBufferSize,
+	}
+}
+
+func (b *MemoryBus) Publish(event *entity.Event) {
+	event.Id = atomic.AddInt64(&b.lastId, 1)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, userId := range event.Recipients {
+		for ch := range b.subscribers[userId] {
+			select {
+			case ch <- event:
+			default:
+				logrus.Warnf("dropping event %s for user %d: subscriber is too slow", event.Type, userId)
+			}
+		}
+	}
+}
+
+func (b *MemoryBus) Subscribe(userId int64) (<-chan *entity.Event, func()) {
+	ch := make(chan *entity.Event, b.bufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[userId] == nil {
+		b.subscribers[userId] = make(map[chan *entity.Event]struct{})
+	}
+	b.subscribers[userId][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userId], ch)
+			if len(b.subscribers[userId]) == 0 {
+				delete(b.subscribers, userId)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
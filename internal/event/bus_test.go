@@ -0,0 +1,63 @@
+package event
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryBus_Publish(t *testing.T) {
+	bus := NewMemoryBus()
+
+	trainerEvents, cancelTrainer := bus.Subscribe(2)
+	defer cancelTrainer()
+	userEvents, cancelUser := bus.Subscribe(1)
+	defer cancelUser()
+	otherEvents, cancelOther := bus.Subscribe(3)
+	defer cancelOther()
+
+	bus.Publish(entity.NewEvent(entity.EventPartnershipRequested, &entity.PartnershipEvent{
+		PartnershipId: 5, UserId: 1, TrainerId: 2}, 2))
+	bus.Publish(entity.NewEvent(entity.EventMessageCreated, &entity.Message{Id: 1}, 1, 2))
+
+	e := <-trainerEvents
+	assert.Equal(t, int64(1), e.Id)
+	assert.Equal(t, entity.EventPartnershipRequested, e.Type)
+	e = <-trainerEvents
+	assert.Equal(t, int64(2), e.Id)
+	assert.Equal(t, entity.EventMessageCreated, e.Type)
+
+	e = <-userEvents
+	assert.Equal(t, entity.EventMessageCreated, e.Type)
+	assert.Len(t, userEvents, 0)
+	assert.Len(t, otherEvents, 0)
+}
+
+func TestMemoryBus_Subscribe(t *testing.T) {
+	bus := NewMemoryBus()
+
+	first, cancelFirst := bus.Subscribe(1)
+	second, cancelSecond := bus.Subscribe(1)
+	defer cancelSecond()
+
+	cancelFirst()
+	cancelFirst()
+	_, ok := <-first
+	assert.False(t, ok)
+
+	bus.Publish(entity.NewEvent(entity.EventWorkoutCreated, &entity.Workout{Id: 1}, 1))
+	e := <-second
+	assert.Equal(t, entity.EventWorkoutCreated, e.Type)
+}
+
+func TestMemoryBus_SlowSubscriber(t *testing.T) {
+	bus := NewMemoryBus()
+
+	events, cancel := bus.Subscribe(1)
+	defer cancel()
+
+	for i := 0; i < defaultBufferSize+5; i++ {
+		bus.Publish(entity.NewEvent(entity.EventWorkoutUpdated, &entity.Workout{Id: 1}, 1))
+	}
+	assert.Len(t, events, defaultBufferSize)
+}
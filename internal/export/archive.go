@@ -0,0 +1,150 @@
+package export
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Build packs user data into ZIP archive, each collection is written both as JSON and CSV.
+func Build(data *entity.ExportData) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	if err := writeJSON(w, "profile.json", data.Profile); err != nil {
+		return nil, err
+	}
+
+	collections := []struct {
+		name   string
+		items  interface{}
+		header []string
+		rows   [][]string
+	}{
+		{"workouts", data.Workouts, workoutsHeader, workoutRows(data.Workouts)},
+		{"partnerships", data.Partnerships, partnershipsHeader, partnershipRows(data.Partnerships)},
+		{"messages", data.Messages, messagesHeader, messageRows(data.Messages)},
+		{"comments", data.Comments, commentsHeader, commentRows(data.Comments)},
+		{"bookings", data.Bookings, bookingsHeader, bookingRows(data.Bookings)},
+	}
+	for _, c := range collections {
+		if err := writeJSON(w, c.name+".json", c.items); err != nil {
+			return nil, err
+		}
+		if err := writeCSV(w, c.name+".csv", c.header, c.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSON(w *zip.Writer, name string, v interface{}) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(w *zip.Writer, name string, header []string, rows [][]string) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if err = cw.Write(header); err != nil {
+		return err
+	}
+	if err = cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var (
+	workoutsHeader     = []string{"id", "title", "user_id", "trainer_id", "description", "date"}
+	partnershipsHeader = []string{"id", "user_id", "trainer_id", "status", "created_at", "ended_at"}
+	messagesHeader     = []string{"id", "partnership_id", "sender_id", "body", "created_at", "read_at"}
+	commentsHeader     = []string{"id", "workout_id", "author_id", "body", "resolved", "created_at", "updated_at"}
+	bookingsHeader     = []string{"id", "trainer_id", "user_id", "workout_id", "starts_at", "ends_at", "status",
+		"created_at", "cancelled_at", "cancelled_by"}
+)
+
+func workoutRows(workouts []*entity.Workout) [][]string {
+	rows := make([][]string, 0, len(workouts))
+	for _, w := range workouts {
+		rows = append(rows, []string{id(w.Id), w.Title, id(w.UserId), nullId(w.TrainerId), w.Description,
+			timestamp(w.Date)})
+	}
+	return rows
+}
+
+func partnershipRows(partnerships []*entity.Partnership) [][]string {
+	rows := make([][]string, 0, len(partnerships))
+	for _, p := range partnerships {
+		rows = append(rows, []string{id(p.Id), id(p.UserId), id(p.TrainerId), string(p.Status),
+			timestamp(p.CreatedAt), nullTimestamp(p.EndedAt)})
+	}
+	return rows
+}
+
+func messageRows(messages []*entity.Message) [][]string {
+	rows := make([][]string, 0, len(messages))
+	for _, m := range messages {
+		rows = append(rows, []string{id(m.Id), id(m.PartnershipId), id(m.SenderId), m.Body,
+			timestamp(m.CreatedAt), nullTimestamp(m.ReadAt)})
+	}
+	return rows
+}
+
+func commentRows(comments []*entity.Comment) [][]string {
+	rows := make([][]string, 0, len(comments))
+	for _, c := range comments {
+		rows = append(rows, []string{id(c.Id), id(c.WorkoutId), id(c.AuthorId), c.Body,
+			strconv.FormatBool(c.Resolved), timestamp(c.CreatedAt), nullTimestamp(c.UpdatedAt)})
+	}
+	return rows
+}
+
+func bookingRows(bookings []*entity.Booking) [][]string {
+	rows := make([][]string, 0, len(bookings))
+	for _, b := range bookings {
+		rows = append(rows, []string{id(b.Id), id(b.TrainerId), id(b.UserId), nullId(b.WorkoutId),
+			timestamp(b.StartsAt), timestamp(b.EndsAt), string(b.Status), timestamp(b.CreatedAt),
+			nullTimestamp(b.CancelledAt), nullId(b.CancelledBy)})
+	}
+	return rows
+}
+
+func id(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func nullId(v sql.NullInt64) string {
+	if !v.Valid {
+		return ""
+	}
+	return id(v.Int64)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func nullTimestamp(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return timestamp(t.Time)
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	date := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	data := &entity.ExportData{
+		Profile: &entity.User{Id: 1, Email: "test", Name: "test", Surname: "test", Role: entity.UserRole},
+		Workouts: []*entity.Workout{
+			{Id: 1, Title: "legs, arms", UserId: 1, TrainerId: sql.NullInt64{Int64: 2, Valid: true}, Date: date},
+		},
+		Partnerships: []*entity.Partnership{},
+		Messages: []*entity.Message{
+			{Id: 1, PartnershipId: 1, SenderId: 1, Body: "hello", CreatedAt: date},
+		},
+		Comments: []*entity.Comment{},
+		Bookings: []*entity.Booking{},
+	}
+
+	archive, err := Build(data)
+	assert.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Len(t, files, 11)
+
+	var profile entity.User
+	assert.NoError(t, json.Unmarshal([]byte(files["profile.json"]), &profile))
+	assert.Equal(t, "test", profile.Email)
+
+	assert.Equal(t, "id,title,user_id,trainer_id,description,date\n"+
+		"1,\"legs, arms\",1,2,,2022-03-01T10:00:00Z\n", files["workouts.csv"])
+	assert.Equal(t, "id,partnership_id,sender_id,body,created_at,read_at\n"+
+		"1,1,1,hello,2022-03-01T10:00:00Z,\n", files["messages.csv"])
+	assert.Equal(t, "id,user_id,trainer_id,status,created_at,ended_at\n", files["partnerships.csv"])
+}
@@ -107,6 +107,12 @@ func (h *Handler) getUserFullInfoByID(c *gin.Context) {
 // @Failure default {object} errorResponse
 // @Router /admin/user [post]
 func (h *Handler) createUser(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
 	var inputUser entity.User
 
 	if err := c.ShouldBindJSON(&inputUser); err != nil {
@@ -119,7 +125,7 @@ func (h *Handler) createUser(c *gin.Context) {
 		return
 	}
 
-	id, err := h.services.Admin.CreateUser(&inputUser)
+	id, err := h.services.Admin.CreateUser(newActor(c, entity.ActorAdmin, adminId), &inputUser)
 	if err != nil {
 		if id == -1 {
 			newErrorResponse(c, http.StatusBadRequest, err)
@@ -136,7 +142,7 @@ func (h *Handler) createUser(c *gin.Context) {
 // @Summary Update user
 // @Security ApiKeyAuth
 // @Tags admin
-// @Description updates user info
+// @Description updates user info, role is changed only by approving trainer application
 // @ID update-user
 // @Accept  json
 // @Produce  json
@@ -147,6 +153,11 @@ func (h *Handler) createUser(c *gin.Context) {
 // @Failure default {object} errorResponse
 // @Router /admin/user/:id [put]
 func (h *Handler) updateUser(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
 	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || userId < 1 {
 		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
@@ -165,7 +176,7 @@ func (h *Handler) updateUser(c *gin.Context) {
 		return
 	}
 
-	err = h.services.Admin.UpdateUser(userId, &update)
+	err = h.services.Admin.UpdateUser(newActor(c, entity.ActorAdmin, adminId), userId, &update)
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
@@ -176,7 +187,7 @@ func (h *Handler) updateUser(c *gin.Context) {
 // @Summary Delete user
 // @Security ApiKeyAuth
 // @Tags admin
-// @Description deletes user
+// @Description marks user as deleted, account is purged after retention period and can be restored until then
 // @ID delete-user
 // @Accept  json
 // @Produce  json
@@ -187,13 +198,50 @@ func (h *Handler) updateUser(c *gin.Context) {
 // @Failure default {object} errorResponse
 // @Router /admin/user/:id [delete]
 func (h *Handler) deleteUser(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	err = h.services.Admin.DeleteUser(newActor(c, entity.ActorAdmin, adminId), userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Restore user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description restores user deleted within retention period, ended partnerships are not resumed
+// @ID restore-user
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/user/:id/restore [post]
+func (h *Handler) restoreUser(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
 	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || userId < 1 {
 		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
 		return
 	}
 
-	err = h.services.Admin.DeleteUser(userId)
+	err = h.services.Admin.RestoreUser(newActor(c, entity.ActorAdmin, adminId), userId)
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
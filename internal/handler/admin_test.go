@@ -281,7 +281,7 @@ func TestHandler_createUser(t *testing.T) {
 				Surname:      "testSurname",
 			},
 			mockBehavior: func(r *mockService.MockAdmin, inputUser entity.User) {
-				r.EXPECT().CreateUser(&inputUser).Return(int64(1), nil)
+				r.EXPECT().CreateUser(testActor(entity.ActorAdmin, 1), &inputUser).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":1}`,
@@ -297,7 +297,7 @@ func TestHandler_createUser(t *testing.T) {
 				Surname:      "testSurname",
 			},
 			mockBehavior: func(r *mockService.MockAdmin, inputUser entity.User) {
-				r.EXPECT().CreateUser(&inputUser).Return(int64(1), nil)
+				r.EXPECT().CreateUser(testActor(entity.ActorAdmin, 1), &inputUser).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":1}`,
@@ -367,7 +367,7 @@ func TestHandler_createUser(t *testing.T) {
 				Surname:      "testSurname",
 			},
 			mockBehavior: func(r *mockService.MockAdmin, inputUser entity.User) {
-				r.EXPECT().CreateUser(&inputUser).Return(int64(0), errors.New("internal error"))
+				r.EXPECT().CreateUser(testActor(entity.ActorAdmin, 1), &inputUser).Return(int64(0), errors.New("internal error"))
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"error":"internal error"}`,
@@ -383,7 +383,7 @@ func TestHandler_createUser(t *testing.T) {
 				Surname:      "testSurname",
 			},
 			mockBehavior: func(r *mockService.MockAdmin, inputUser entity.User) {
-				r.EXPECT().CreateUser(&inputUser).Return(int64(-1), errors.New("reserved email"))
+				r.EXPECT().CreateUser(testActor(entity.ActorAdmin, 1), &inputUser).Return(int64(-1), errors.New("reserved email"))
 			},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"reserved email"}`,
@@ -406,8 +406,9 @@ func TestHandler_createUser(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, "/user",
 				bytes.NewBufferString(test.inputBody))
-
-			r.ServeHTTP(w, req)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
 
 			assert.Equal(t, w.Code, test.expectedStatusCode)
 			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
@@ -439,7 +440,7 @@ func TestHandler_updateUser(t *testing.T) {
 					Surname:  "testNew",
 				}
 				u.EXPECT().InitUpdateUser(userId, update).Return(nil)
-				r.EXPECT().UpdateUser(userId, update).Return(nil)
+				r.EXPECT().UpdateUser(testActor(entity.ActorAdmin, 1), userId, update).Return(nil)
 			},
 			expectedStatusCode: 200,
 		},
@@ -462,8 +463,9 @@ func TestHandler_updateUser(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/user/%d", test.userId),
 				bytes.NewBufferString(test.inputBody))
-
-			r.ServeHTTP(w, req)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
 
 			assert.Equal(t, w.Code, test.expectedStatusCode)
 		})
@@ -484,7 +486,7 @@ func TestHandler_deleteUser(t *testing.T) {
 			name:   "Ok",
 			userId: 1,
 			mockBehaviour: func(r *mockService.MockAdmin, userId int64) {
-				r.EXPECT().DeleteUser(userId).Return(nil)
+				r.EXPECT().DeleteUser(testActor(entity.ActorAdmin, 1), userId).Return(nil)
 			},
 			expectedStatusCode: 200,
 		},
@@ -498,7 +500,7 @@ func TestHandler_deleteUser(t *testing.T) {
 			name:   "No user to delete",
 			userId: 1,
 			mockBehaviour: func(r *mockService.MockAdmin, userId int64) {
-				r.EXPECT().DeleteUser(userId).Return(errors.New("error due deleting"))
+				r.EXPECT().DeleteUser(testActor(entity.ActorAdmin, 1), userId).Return(errors.New("error due deleting"))
 			},
 			expectedStatusCode: 400,
 		},
@@ -519,10 +521,74 @@ func TestHandler_deleteUser(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/user/%d", test.userId), nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
 
-			r.ServeHTTP(w, req)
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+		})
+	}
+}
+
+func TestHandler_restoreUser(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockAdmin, userId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockAdmin, userId int64) {
+				r.EXPECT().RestoreUser(testActor(entity.ActorAdmin, 1), userId).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:                 "invalid id parameter",
+			userId:               -1,
+			mockBehaviour:        func(r *mockService.MockAdmin, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+		{
+			name:   "Not deleted",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockAdmin, userId int64) {
+				r.EXPECT().RestoreUser(testActor(entity.ActorAdmin, 1), userId).
+					Return(errors.New("no deleted user to restore"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"no deleted user to restore"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockAdmin(c)
+			test.mockBehaviour(repo, test.userId)
+
+			services := &service.Services{Admin: repo}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/user/:id/restore", handler.restoreUser)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/user/%d/restore", test.userId), nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
 
 			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
 		})
 	}
 }
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Apply to become a trainer
+// @Security ApiKeyAuth
+// @Tags user
+// @Description submits trainer application to admin review queue, certificates are uploaded separately
+// @ID submit-trainer-application
+// @Accept  json
+// @Produce  json
+// @Param input body entity.TrainerApplicationInput true "trainer profile"
+// @Success 200 {object} applicationIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/trainer-application [post]
+func (h *Handler) submitApplication(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.TrainerApplicationInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := h.services.Application.SubmitApplication(newActor(c, entity.ActorUser, userId), userId, &input)
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, applicationIdResponse{
+		ApplicationId: id,
+	})
+}
+
+// @Summary Get trainer applications
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get your trainer applications with review status and reviewer notes
+// @ID get-user-trainer-applications
+// @Produce  json
+// @Success 200 {object} applicationsResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/trainer-application [get]
+func (h *Handler) getUserApplications(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	apps, err := h.services.Application.GetUserApplications(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, applicationsResponse{
+		Applications: apps,
+	})
+}
+
+// @Summary Update trainer application
+// @Security ApiKeyAuth
+// @Tags user
+// @Description resubmits application after reviewer requested changes
+// @ID update-trainer-application
+// @Accept  json
+// @Produce  json
+// @Param input body entity.TrainerApplicationInput true "trainer profile"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/trainer-application/:id [put]
+func (h *Handler) updateApplication(c *gin.Context) {
+	userId, appId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.TrainerApplicationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := h.services.Application.UpdateApplication(newActor(c, entity.ActorUser, userId), userId, appId, &input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Upload certificate
+// @Security ApiKeyAuth
+// @Tags user
+// @Description attaches certificate to application under consideration, pdf, png and jpeg files up to 5 MB are accepted
+// @ID upload-certificate
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "certificate file"
+// @Success 200 {object} certificateIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/trainer-application/:id/certificate [post]
+func (h *Handler) uploadCertificate(c *gin.Context) {
+	userId, appId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxCertificateSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, service.MaxCertificateSize+1))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := h.services.Application.AddCertificate(newActor(c, entity.ActorUser, userId), userId,
+		&entity.Certificate{ApplicationId: appId, FileName: header.Filename, Content: content})
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, certificateIdResponse{
+		CertificateId: id,
+	})
+}
+
+// @Summary Get trainer applications queue
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get trainer applications with status, pending ones by default, oldest first
+// @ID get-trainer-applications
+// @Produce  json
+// @Param status query string false "pending, changes_requested, approved or rejected"
+// @Success 200 {object} applicationsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application [get]
+func (h *Handler) getApplications(c *gin.Context) {
+	apps, err := h.services.Application.GetApplications(entity.ApplicationStatus(c.Query("status")))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, applicationsResponse{
+		Applications: apps,
+	})
+}
+
+// @Summary Get trainer application
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get trainer application with list of uploaded certificates
+// @ID get-trainer-application
+// @Produce  json
+// @Success 200 {object} entity.TrainerApplication
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application/:id [get]
+func (h *Handler) getApplicationById(c *gin.Context) {
+	appId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || appId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	app, err := h.services.Application.GetApplicationById(appId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, app)
+}
+
+// @Summary Download certificate
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description downloads certificate attached to trainer application
+// @ID download-certificate
+// @Produce  octet-stream
+// @Success 200 {file} binary
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application/:id/certificate/:certificate_id [get]
+func (h *Handler) downloadCertificate(c *gin.Context) {
+	appId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || appId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	certificateId, err := strconv.ParseInt(c.Param("certificate_id"), 10, 64)
+	if err != nil || certificateId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	cert, err := h.services.Application.GetCertificate(appId, certificateId)
+	if err != nil {
+		newErrorResponse(c, http.StatusNotFound, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", cert.FileName))
+	c.Data(http.StatusOK, cert.ContentType, cert.Content)
+}
+
+// @Summary Approve trainer application
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description approves pending application, applicant becomes a trainer
+// @ID approve-trainer-application
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ApplicationReview false "reviewer note"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application/:id/approve [post]
+func (h *Handler) approveApplication(c *gin.Context) {
+	h.reviewApplication(c, entity.ApplicationApproved)
+}
+
+// @Summary Reject trainer application
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description rejects pending application, reviewer note is required
+// @ID reject-trainer-application
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ApplicationReview true "reviewer note"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application/:id/reject [post]
+func (h *Handler) rejectApplication(c *gin.Context) {
+	h.reviewApplication(c, entity.ApplicationRejected)
+}
+
+// @Summary Request changes in trainer application
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description returns pending application to applicant, reviewer note is required
+// @ID request-trainer-application-changes
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ApplicationReview true "reviewer note"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/trainer-application/:id/request-changes [post]
+func (h *Handler) requestApplicationChanges(c *gin.Context) {
+	h.reviewApplication(c, entity.ApplicationChangesRequested)
+}
+
+func (h *Handler) reviewApplication(c *gin.Context, status entity.ApplicationStatus) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	appId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || appId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	var review entity.ApplicationReview
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&review); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	err = h.services.Application.ReviewApplication(newActor(c, entity.ActorAdmin, adminId), appId, status, review.Note)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
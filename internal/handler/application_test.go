@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_submitApplication(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockApplication, userId int64)
+
+	input := &entity.TrainerApplicationInput{Bio: "bio", Specialization: "yoga", ExperienceYears: 3}
+
+	table := []struct {
+		name                 string
+		userId               int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			inputBody: `{"bio":"bio", "specialization":"yoga", "experience_years":3}`,
+			mockBehaviour: func(r *mockService.MockApplication, userId int64) {
+				r.EXPECT().SubmitApplication(testActor(entity.ActorUser, userId), userId, input).Return(int64(5), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"application_id":5}`,
+		},
+		{
+			name:                 "No specialization",
+			userId:               1,
+			inputBody:            `{"bio":"bio"}`,
+			mockBehaviour:        func(r *mockService.MockApplication, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'TrainerApplicationInput.Specialization' Error:Field validation for 'Specialization' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Already under consideration",
+			userId:    1,
+			inputBody: `{"bio":"bio", "specialization":"yoga", "experience_years":3}`,
+			mockBehaviour: func(r *mockService.MockApplication, userId int64) {
+				r.EXPECT().SubmitApplication(testActor(entity.ActorUser, userId), userId, input).
+					Return(int64(-1), errors.New("application is already under consideration"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"application is already under consideration"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			app := mockService.NewMockApplication(c)
+			test.mockBehaviour(app, test.userId)
+
+			services := &service.Services{Application: app}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/trainer-application", handler.submitApplication)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/trainer-application", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_uploadCertificate(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockApplication, userId int64)
+
+	content := []byte("%PDF-1.4 certificate")
+
+	table := []struct {
+		name                 string
+		userId               int64
+		withFile             bool
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			userId:   1,
+			withFile: true,
+			mockBehaviour: func(r *mockService.MockApplication, userId int64) {
+				r.EXPECT().AddCertificate(testActor(entity.ActorUser, userId), userId,
+					&entity.Certificate{ApplicationId: 2, FileName: "cert.pdf", Content: content}).Return(int64(7), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"certificate_id":7}`,
+		},
+		{
+			name:                 "No file",
+			userId:               1,
+			mockBehaviour:        func(r *mockService.MockApplication, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"http: no such file"}`,
+		},
+		{
+			name:     "Reviewed application",
+			userId:   1,
+			withFile: true,
+			mockBehaviour: func(r *mockService.MockApplication, userId int64) {
+				r.EXPECT().AddCertificate(testActor(entity.ActorUser, userId), userId,
+					&entity.Certificate{ApplicationId: 2, FileName: "cert.pdf", Content: content}).
+					Return(int64(-1), errors.New("application is already reviewed"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"application is already reviewed"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			app := mockService.NewMockApplication(c)
+			test.mockBehaviour(app, test.userId)
+
+			services := &service.Services{Application: app}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/trainer-application/:id/certificate", handler.uploadCertificate)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			if test.withFile {
+				part, _ := mw.CreateFormFile("file", "cert.pdf")
+				_, _ = part.Write(content)
+			}
+			_ = mw.Close()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/trainer-application/2/certificate", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_getApplications(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockApplication)
+
+	table := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "",
+			mockBehaviour: func(r *mockService.MockApplication) {
+				r.EXPECT().GetApplications(entity.ApplicationStatus("")).Return([]*entity.TrainerApplication{
+					{Id: 1, UserId: 2, Status: entity.ApplicationPending, Bio: "bio", Specialization: "yoga"},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"applications":[{"id":1,"user_id":2,"status":"pending","bio":"bio","specialization":"yoga","experience_years":0,"reviewer_id":{"Int64":0,"Valid":false},"reviewer_note":{"String":"","Valid":false},"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","reviewed_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}}]}`, //nolint
+		},
+		{
+			name:  "Invalid status",
+			query: "?status=unknown",
+			mockBehaviour: func(r *mockService.MockApplication) {
+				r.EXPECT().GetApplications(entity.ApplicationStatus("unknown")).
+					Return(nil, errors.New("invalid application status"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid application status"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			app := mockService.NewMockApplication(c)
+			test.mockBehaviour(app)
+
+			services := &service.Services{Application: app}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/trainer-application", handler.getApplications)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/trainer-application"+test.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_reviewApplication(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockApplication, adminId int64)
+
+	table := []struct {
+		name                 string
+		path                 string
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Approve without note",
+			path:      "/trainer-application/3/approve",
+			inputBody: "",
+			mockBehaviour: func(r *mockService.MockApplication, adminId int64) {
+				r.EXPECT().ReviewApplication(testActor(entity.ActorAdmin, adminId), int64(3),
+					entity.ApplicationApproved, "").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:      "Reject",
+			path:      "/trainer-application/3/reject",
+			inputBody: `{"note":"no certificates"}`,
+			mockBehaviour: func(r *mockService.MockApplication, adminId int64) {
+				r.EXPECT().ReviewApplication(testActor(entity.ActorAdmin, adminId), int64(3),
+					entity.ApplicationRejected, "no certificates").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:      "Request changes without note",
+			path:      "/trainer-application/3/request-changes",
+			inputBody: `{}`,
+			mockBehaviour: func(r *mockService.MockApplication, adminId int64) {
+				r.EXPECT().ReviewApplication(testActor(entity.ActorAdmin, adminId), int64(3),
+					entity.ApplicationChangesRequested, "").Return(errors.New("reviewer note is required"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"reviewer note is required"}`,
+		},
+		{
+			name:                 "Invalid id",
+			path:                 "/trainer-application/a/approve",
+			mockBehaviour:        func(r *mockService.MockApplication, adminId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			app := mockService.NewMockApplication(c)
+			test.mockBehaviour(app, 1)
+
+			services := &service.Services{Application: app}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/trainer-application/:id/approve", handler.approveApplication)
+			r.POST("/trainer-application/:id/reject", handler.rejectApplication)
+			r.POST("/trainer-application/:id/request-changes", handler.requestApplicationChanges)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_downloadCertificate(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	app := mockService.NewMockApplication(c)
+	app.EXPECT().GetCertificate(int64(3), int64(4)).Return(&entity.Certificate{
+		Id: 4, ApplicationId: 3, FileName: "cert.pdf", ContentType: "application/pdf", Content: []byte("%PDF"),
+	}, nil)
+	app.EXPECT().GetCertificate(int64(3), int64(5)).Return(nil, errors.New("no certificate with provided id"))
+
+	handler := &Handler{services: &service.Services{Application: app}}
+	r := gin.New()
+	r.GET("/trainer-application/:id/certificate/:certificate_id", handler.downloadCertificate)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trainer-application/3/certificate/4", nil))
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, w.Header().Get("Content-Type"), "application/pdf")
+	assert.Equal(t, w.Header().Get("Content-Disposition"), `attachment; filename="cert.pdf"`)
+	assert.Equal(t, w.Body.String(), "%PDF")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trainer-application/3/certificate/5", nil))
+	assert.Equal(t, w.Code, 404)
+	assert.Equal(t, w.Body.String(), `{"error":"no certificate with provided id"}`)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary Get audit log
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get page of privileged mutations, newest first
+// @ID get-audit-log
+// @Produce  json
+// @Param actor_type query string false "admin, trainer or user"
+// @Param actor_id query int false "actor id"
+// @Param target_type query string false "user or partnership"
+// @Param target_id query int false "target id"
+// @Param from query string false "RFC3339 lower bound of created_at"
+// @Param to query string false "RFC3339 upper bound of created_at"
+// @Param before query int false "return entries with id less than provided"
+// @Param limit query int false "page size, 50 by default"
+// @Success 200 {object} entity.AuditPage
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/audit [get]
+func (h *Handler) getAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.services.Admin.GetAuditLog(filter)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func parseAuditFilter(c *gin.Context) (*entity.AuditFilter, error) {
+	filter := &entity.AuditFilter{
+		ActorType:  entity.ActorType(c.Query("actor_type")),
+		TargetType: c.Query("target_type"),
+	}
+	switch filter.ActorType {
+	case "", entity.ActorAdmin, entity.ActorTrainer, entity.ActorUser:
+	default:
+		return nil, errors.New("invalid actor_type parameter")
+	}
+
+	ids := []struct {
+		name string
+		dst  *int64
+	}{
+		{"actor_id", &filter.ActorId},
+		{"target_id", &filter.TargetId},
+		{"before", &filter.BeforeId},
+	}
+	for _, p := range ids {
+		if param := c.Query(p.name); param != "" {
+			id, err := strconv.ParseInt(param, 10, 64)
+			if err != nil || id < 1 {
+				return nil, errors.New("invalid " + p.name + " parameter")
+			}
+			*p.dst = id
+		}
+	}
+
+	times := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	}
+	for _, p := range times {
+		if param := c.Query(p.name); param != "" {
+			t, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				return nil, errors.New("invalid " + p.name + " parameter")
+			}
+			*p.dst = t
+		}
+	}
+
+	if param := c.Query("limit"); param != "" {
+		l, err := strconv.Atoi(param)
+		if err != nil || l < 1 {
+			return nil, errors.New("invalid limit parameter")
+		}
+		filter.Limit = l
+	}
+	return filter, nil
+}
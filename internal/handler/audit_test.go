@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_getAuditLog(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockAdmin)
+
+	table := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?actor_type=admin&actor_id=1&target_type=user&target_id=2&from=2022-01-01T00:00:00Z&before=10&limit=1",
+			mockBehaviour: func(r *mockService.MockAdmin) {
+				r.EXPECT().GetAuditLog(&entity.AuditFilter{
+					ActorType:  entity.ActorAdmin,
+					ActorId:    1,
+					TargetType: entity.AuditTargetUser,
+					TargetId:   2,
+					From:       time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+					BeforeId:   10,
+					Limit:      1,
+				}).Return(&entity.AuditPage{
+					Entries: []*entity.AuditEntry{
+						{
+							Id:         9,
+							ActorType:  entity.ActorAdmin,
+							ActorId:    1,
+							Action:     entity.AuditUserDelete,
+							TargetType: entity.AuditTargetUser,
+							TargetId:   2,
+							Before:     entity.AuditData(`{"email":"test"}`),
+							RequestId:  "abc",
+							IP:         "127.0.0.1",
+						},
+					},
+					HasMore: true,
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"entries":[{"id":9,"actor_type":"admin","actor_id":1,"action":"user.delete","target_type":"user","target_id":2,"before":{"email":"test"},"after":null,"request_id":"abc","ip":"127.0.0.1","created_at":"0001-01-01T00:00:00Z"}],"has_more":true}`, //nolint
+		},
+		{
+			name:                 "Invalid actor type",
+			query:                "?actor_type=robot",
+			mockBehaviour:        func(r *mockService.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid actor_type parameter"}`,
+		},
+		{
+			name:                 "Invalid target id",
+			query:                "?target_id=-1",
+			mockBehaviour:        func(r *mockService.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid target_id parameter"}`,
+		},
+		{
+			name:                 "Invalid time",
+			query:                "?to=yesterday",
+			mockBehaviour:        func(r *mockService.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid to parameter"}`,
+		},
+		{
+			name:  "Internal error",
+			query: "",
+			mockBehaviour: func(r *mockService.MockAdmin) {
+				r.EXPECT().GetAuditLog(&entity.AuditFilter{}).Return(nil, errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockAdmin(c)
+			test.mockBehaviour(repo)
+
+			services := &service.Services{Admin: repo}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/audit", handler.getAuditLog)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/audit"+test.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"Fitness_REST_API/internal/entity"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"net/http"
 )
 
@@ -16,8 +18,22 @@ type userSignInInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// signInResponse carries either session token or MFA challenge token, recovery codes
+// are shown once when MFA enrollment is confirmed during sign in.
 type signInResponse struct {
-	Token string `json:"token"`
+	Token                 string   `json:"token,omitempty"`
+	MFAToken              string   `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	RecoveryCodes         []string `json:"recovery_codes,omitempty"`
+}
+
+func newSignInResponse(result *entity.SignInResult) signInResponse {
+	return signInResponse{
+		Token:                 result.Token,
+		MFAToken:              result.MFAToken,
+		MFAEnrollmentRequired: result.MFAEnrollmentRequired,
+		RecoveryCodes:         result.RecoveryCodes,
+	}
 }
 
 // @Summary Sign Up
@@ -50,20 +66,99 @@ func (h *Handler) signUp(c *gin.Context) {
 			return
 		}
 	}
+
+	if err = h.services.Account.SendVerification(id); err != nil {
+		logrus.Errorf("can't send verification mail to user %d: %s", id, err.Error())
+	}
 	c.JSON(http.StatusOK, idResponse{
 		Id: id,
 	})
 }
 
+// @Summary Verify email
+// @Tags auth
+// @Description confirms email with token from verification mail
+// @ID verify-email
+// @Produce  json
+// @Param token query string true "verification token"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/verify [get]
+func (h *Handler) verifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		newErrorResponse(c, http.StatusBadRequest, errors.New("empty token"))
+		return
+	}
+
+	if err := h.services.Account.VerifyEmail(token); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Forgot password
+// @Tags auth
+// @Description mails password reset token if account with email exists
+// @ID forgot-password
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ForgotPasswordInput true "account email"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/forgot-password [post]
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var input entity.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.services.Account.ForgotPassword(input.Email); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Reset password
+// @Tags auth
+// @Description sets new password with token from password reset mail
+// @ID reset-password
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ResetPasswordInput true "reset token and new password"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/reset-password [post]
+func (h *Handler) resetPassword(c *gin.Context) {
+	var input entity.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.services.Account.ResetPassword(input.Token, input.Password); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
 // @Summary Sign In
 // @Tags auth
-// @Description sign-in
+// @Description sign-in, when two-factor authentication is enabled or required response has mfa_token instead of token
 // @ID sign-in
 // @Accept  json
 // @Produce  json
 // @Param input body userSignInInput true "account info"
 // @Success 200 {object} signInResponse
-// @Failure 400,401,404 {object} errorResponse
+// @Failure 400,401,404,429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-in [post]
@@ -74,26 +169,20 @@ func (h *Handler) signIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.services.User.SignIn(input.Email, input.Password, entity.UserRole)
-	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, signInResponse{
-		Token: token,
+	h.guardedSignIn(c, entity.ActorUser, input.Email, func() (*entity.SignInResult, error) {
+		return h.services.User.SignIn(input.Email, input.Password, entity.UserRole)
 	})
 }
 
 // @Summary Sign In for admin
 // @Tags auth
-// @Description sign-in as admin
+// @Description sign-in as admin, when two-factor authentication is enabled or required response has mfa_token instead of token
 // @ID admin-sign-in
 // @Accept  json
 // @Produce  json
 // @Param input body adminSignInInput true "account info"
 // @Success 200 {object} signInResponse
-// @Failure 400,401,404 {object} errorResponse
+// @Failure 400,401,404,429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /admin/auth/sign-in [post]
@@ -104,26 +193,20 @@ func (h *Handler) adminSignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.services.Admin.SignIn(input.Login, input.Password)
-	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, signInResponse{
-		Token: token,
+	h.guardedSignIn(c, entity.ActorAdmin, input.Login, func() (*entity.SignInResult, error) {
+		return h.services.Admin.SignIn(input.Login, input.Password)
 	})
 }
 
 // @Summary Sign In for trainer
 // @Tags auth
-// @Description sign-in as trainer
+// @Description sign-in as trainer, when two-factor authentication is enabled or required response has mfa_token instead of token
 // @ID trainer-sign-in
 // @Accept  json
 // @Produce  json
 // @Param input body userSignInInput true "account info"
 // @Success 200 {object} signInResponse
-// @Failure 400,401,404 {object} errorResponse
+// @Failure 400,401,404,429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /trainer/auth/sign-in [post]
@@ -134,13 +217,7 @@ func (h *Handler) trainerSignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.services.User.SignIn(input.Email, input.Password, entity.TrainerRole)
-	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, signInResponse{
-		Token: token,
+	h.guardedSignIn(c, entity.ActorUser, input.Email, func() (*entity.SignInResult, error) {
+		return h.services.User.SignIn(input.Email, input.Password, entity.TrainerRole)
 	})
 }
@@ -30,7 +30,7 @@ func TestHandler_adminSignIn(t *testing.T) {
 			inputBody:   `{"login":"testLogin", "password":"testPassword"}`,
 			signInInput: adminSignInInput{Login: "testLogin", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockAdmin, signInInput adminSignInInput) {
-				r.EXPECT().SignIn(signInInput.Login, signInInput.Password).Return("token", nil)
+				r.EXPECT().SignIn(signInInput.Login, signInInput.Password).Return(&entity.SignInResult{Token: "token"}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"token":"token"}`,
@@ -65,7 +65,7 @@ func TestHandler_adminSignIn(t *testing.T) {
 			signInInput: adminSignInInput{Login: "testLogin", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockAdmin, signInInput adminSignInInput) {
 				r.EXPECT().SignIn(signInInput.Login, signInInput.Password).
-					Return("", errors.New("invalid login or password"))
+					Return(nil, service.ErrInvalidCredentials)
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"error":"invalid login or password"}`,
@@ -79,7 +79,7 @@ func TestHandler_adminSignIn(t *testing.T) {
 			repo := mockService.NewMockAdmin(c)
 			test.mockBehavior(repo, test.signInInput)
 
-			services := &service.Services{Admin: repo}
+			services := &service.Services{Admin: repo, Lockout: allowSignIn(c)}
 			handler := &Handler{services: services}
 
 			r := gin.New()
@@ -112,7 +112,7 @@ func TestHandler_signIn(t *testing.T) {
 			inputBody:   `{"email":"testEmail", "password":"testPassword"}`,
 			signInInput: userSignInInput{Email: "testEmail", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockUser, signInInput userSignInInput) {
-				r.EXPECT().SignIn(signInInput.Email, signInInput.Password, entity.UserRole).Return("token", nil)
+				r.EXPECT().SignIn(signInInput.Email, signInInput.Password, entity.UserRole).Return(&entity.SignInResult{Token: "token"}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"token":"token"}`,
@@ -147,7 +147,7 @@ func TestHandler_signIn(t *testing.T) {
 			signInInput: userSignInInput{Email: "testEmail", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockUser, signInInput userSignInInput) {
 				r.EXPECT().SignIn(signInInput.Email, signInInput.Password, entity.UserRole).
-					Return("", errors.New("invalid email or password"))
+					Return(nil, errors.New("invalid email or password"))
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"error":"invalid email or password"}`,
@@ -161,7 +161,7 @@ func TestHandler_signIn(t *testing.T) {
 			repo := mockService.NewMockUser(c)
 			test.mockBehavior(repo, test.signInInput)
 
-			services := &service.Services{User: repo}
+			services := &service.Services{User: repo, Lockout: allowSignIn(c)}
 			handler := &Handler{services: services}
 
 			r := gin.New()
@@ -195,7 +195,7 @@ func TestHandler_trainerSignIn(t *testing.T) {
 			signInInput: userSignInInput{Email: "testEmail", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockUser, signInInput userSignInInput) {
 				r.EXPECT().SignIn(signInInput.Email, signInInput.Password, entity.TrainerRole).
-					Return("token", nil)
+					Return(&entity.SignInResult{Token: "token"}, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"token":"token"}`,
@@ -230,7 +230,7 @@ func TestHandler_trainerSignIn(t *testing.T) {
 			signInInput: userSignInInput{Email: "testEmail", Password: "testPassword"},
 			mockBehavior: func(r *mockService.MockUser, signInInput userSignInInput) {
 				r.EXPECT().SignIn(signInInput.Email, signInInput.Password, entity.TrainerRole).
-					Return("", errors.New("invalid email or password"))
+					Return(nil, errors.New("invalid email or password"))
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"error":"invalid email or password"}`,
@@ -244,7 +244,7 @@ func TestHandler_trainerSignIn(t *testing.T) {
 			repo := mockService.NewMockUser(c)
 			test.mockBehavior(repo, test.signInInput)
 
-			services := &service.Services{User: repo}
+			services := &service.Services{User: repo, Lockout: allowSignIn(c)}
 			handler := &Handler{services: services}
 
 			r := gin.New()
@@ -262,7 +262,7 @@ func TestHandler_trainerSignIn(t *testing.T) {
 }
 
 func TestHandler_signUp(t *testing.T) {
-	type mockBehavior func(r *mockService.MockUser, inputUser entity.User)
+	type mockBehavior func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User)
 
 	table := []struct {
 		name                 string
@@ -281,8 +281,25 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior: func(r *mockService.MockUser, inputUser entity.User) {
+			mockBehavior: func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {
 				r.EXPECT().SignUp(&inputUser).Return(int64(1), nil)
+				a.EXPECT().SendVerification(int64(1)).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:      "Verification mail is not sent",
+			inputBody: `{"email":"testEmail", "password_hash":"testPassword", "name":"testName", "surname":"testSurname"}`, //nolint
+			inputUser: entity.User{
+				Email:        "testEmail",
+				PasswordHash: "testPassword",
+				Name:         "testName",
+				Surname:      "testSurname",
+			},
+			mockBehavior: func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {
+				r.EXPECT().SignUp(&inputUser).Return(int64(1), nil)
+				a.EXPECT().SendVerification(int64(1)).Return(errors.New("connection refused"))
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":1}`,
@@ -296,7 +313,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior:         func(r *mockService.MockUser, inputUser entity.User) {},
+			mockBehavior:         func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"Key: 'User.Email' Error:Field validation for 'Email' failed on the 'required' tag"}`, //nolint
 		},
@@ -309,7 +326,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior:         func(r *mockService.MockUser, inputUser entity.User) {},
+			mockBehavior:         func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"Key: 'User.PasswordHash' Error:Field validation for 'PasswordHash' failed on the 'required' tag"}`, //nolint
 		},
@@ -322,7 +339,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior:         func(r *mockService.MockUser, inputUser entity.User) {},
+			mockBehavior:         func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"Key: 'User.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`, //nolint
 		},
@@ -335,7 +352,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior:         func(r *mockService.MockUser, inputUser entity.User) {},
+			mockBehavior:         func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"Key: 'User.Surname' Error:Field validation for 'Surname' failed on the 'required' tag"}`, //nolint
 		},
@@ -348,7 +365,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior: func(r *mockService.MockUser, inputUser entity.User) {
+			mockBehavior: func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {
 				r.EXPECT().SignUp(&inputUser).Return(int64(0), errors.New("internal error"))
 			},
 			expectedStatusCode:   500,
@@ -363,7 +380,7 @@ func TestHandler_signUp(t *testing.T) {
 				Name:         "testName",
 				Surname:      "testSurname",
 			},
-			mockBehavior: func(r *mockService.MockUser, inputUser entity.User) {
+			mockBehavior: func(r *mockService.MockUser, a *mockService.MockAccount, inputUser entity.User) {
 				r.EXPECT().SignUp(&inputUser).Return(int64(-1), errors.New("reserved email"))
 			},
 			expectedStatusCode:   400,
@@ -376,9 +393,10 @@ func TestHandler_signUp(t *testing.T) {
 			defer c.Finish()
 
 			repo := mockService.NewMockUser(c)
-			test.mockBehavior(repo, test.inputUser)
+			account := mockService.NewMockAccount(c)
+			test.mockBehavior(repo, account, test.inputUser)
 
-			services := &service.Services{User: repo}
+			services := &service.Services{User: repo, Account: account}
 			handler := &Handler{services: services}
 
 			r := gin.New()
@@ -395,3 +413,188 @@ func TestHandler_signUp(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_verifyEmail(t *testing.T) {
+	type mockBehavior func(r *mockService.MockAccount)
+
+	table := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?token=token",
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().VerifyEmail("token").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "Empty token",
+			query:                "",
+			mockBehavior:         func(r *mockService.MockAccount) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"empty token"}`,
+		},
+		{
+			name:  "Used token",
+			query: "?token=token",
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().VerifyEmail("token").Return(errors.New("token is invalid, expired or already used"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"token is invalid, expired or already used"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mockService.NewMockAccount(c)
+			test.mockBehavior(account)
+
+			services := &service.Services{Account: account}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/verify", handler.verifyEmail)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/verify"+test.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_forgotPassword(t *testing.T) {
+	type mockBehavior func(r *mockService.MockAccount)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"email":"testEmail"}`,
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().ForgotPassword("testEmail").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"emailA":"testEmail"}`,
+			mockBehavior:         func(r *mockService.MockAccount) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'ForgotPasswordInput.Email' Error:Field validation for 'Email' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Internal Server Error",
+			inputBody: `{"email":"testEmail"}`,
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().ForgotPassword("testEmail").Return(errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mockService.NewMockAccount(c)
+			test.mockBehavior(account)
+
+			services := &service.Services{Account: account}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/forgot-password", handler.forgotPassword)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/forgot-password",
+				bytes.NewBufferString(test.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_resetPassword(t *testing.T) {
+	type mockBehavior func(r *mockService.MockAccount)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"token":"token", "password":"newPassword"}`,
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().ResetPassword("token", "newPassword").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"token":"token"}`,
+			mockBehavior:         func(r *mockService.MockAccount) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'ResetPasswordInput.Password' Error:Field validation for 'Password' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid token",
+			inputBody: `{"token":"token", "password":"newPassword"}`,
+			mockBehavior: func(r *mockService.MockAccount) {
+				r.EXPECT().ResetPassword("token", "newPassword").Return(errors.New("token is invalid or expired"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"token is invalid or expired"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mockService.NewMockAccount(c)
+			test.mockBehavior(account)
+
+			services := &service.Services{Account: account}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/reset-password", handler.resetPassword)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/reset-password",
+				bytes.NewBufferString(test.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
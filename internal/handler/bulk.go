@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Create workouts
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description creates up to 100 workouts with clients in one transaction, atomic mode creates all or none of them
+// @ID bulk-create-workouts
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BulkCreateInput true "workouts"
+// @Success 200 {object} entity.BulkResult
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 422 {object} entity.BulkResult
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/workout/bulk [post]
+func (h *Handler) bulkCreateWorkouts(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.BulkCreateInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.services.Bulk.CreateWorkouts(trainerId, &input)
+	if err != nil {
+		newErrorResponse(c, bulkErrorStatus(err), err)
+		return
+	}
+	c.JSON(bulkStatus(result), result)
+}
+
+// @Summary Patch workouts
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description applies JSON Merge Patch to up to 100 workouts in one transaction
+// @ID bulk-patch-workouts
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BulkPatchInput true "patches"
+// @Success 200 {object} entity.BulkResult
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 422 {object} entity.BulkResult
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/workout/bulk [patch]
+func (h *Handler) bulkPatchWorkouts(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.BulkPatchInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.services.Bulk.PatchWorkouts(trainerId, &input)
+	if err != nil {
+		newErrorResponse(c, bulkErrorStatus(err), err)
+		return
+	}
+	c.JSON(bulkStatus(result), result)
+}
+
+// @Summary Delete workouts
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description deletes up to 100 workouts in one transaction
+// @ID bulk-delete-workouts
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BulkDeleteInput true "workouts"
+// @Success 200 {object} entity.BulkResult
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 422 {object} entity.BulkResult
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/workout/bulk/delete [post]
+func (h *Handler) bulkDeleteWorkouts(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.BulkDeleteInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.services.Bulk.DeleteWorkouts(trainerId, &input)
+	if err != nil {
+		newErrorResponse(c, bulkErrorStatus(err), err)
+		return
+	}
+	c.JSON(bulkStatus(result), result)
+}
+
+// @Summary Shift workouts
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description moves your workouts with client which start in [from, to) by days in time zone of client
+// @ID bulk-shift-workouts
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BulkShiftInput true "range and days"
+// @Success 200 {object} entity.BulkResult
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 422 {object} entity.BulkResult
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/workout/bulk/shift [post]
+func (h *Handler) bulkShiftWorkouts(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.BulkShiftInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.services.Bulk.ShiftWorkouts(trainerId, &input)
+	if err != nil {
+		newErrorResponse(c, bulkErrorStatus(err), err)
+		return
+	}
+	c.JSON(bulkStatus(result), result)
+}
+
+// bulkStatus reports atomic operation rolled back because of failed items as unprocessable.
+func bulkStatus(result *entity.BulkResult) int {
+	if !result.Committed {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusOK
+}
+
+// bulkErrorStatus is status of failed bulk operation, only shift range is checked before the transaction.
+func bulkErrorStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidRange) || errors.Is(err, service.ErrRangeTooLarge) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
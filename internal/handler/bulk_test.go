@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_bulkDeleteWorkouts(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockBulk, trainerId int64)
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			inputBody: `{"workouts":[{"id":2,"version":3}]}`,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64) {
+				r.EXPECT().DeleteWorkouts(trainerId, &entity.BulkDeleteInput{
+					Workouts: []*entity.WorkoutRef{{Id: 2, Version: 3}},
+				}).Return(&entity.BulkResult{Committed: true, Succeeded: 1,
+					Items: []*entity.BulkItemResult{{Index: 0, WorkoutId: 2}}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"committed":true,"succeeded":1,"failed":0,"items":[{"index":0,"workout_id":2}]}`,
+		},
+		{
+			name:      "Atomic operation is rolled back",
+			trainerId: 1,
+			inputBody: `{"mode":"atomic","workouts":[{"id":2},{"id":4}]}`,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64) {
+				r.EXPECT().DeleteWorkouts(trainerId, &entity.BulkDeleteInput{Mode: entity.BulkAtomic,
+					Workouts: []*entity.WorkoutRef{{Id: 2}, {Id: 4}},
+				}).Return(&entity.BulkResult{Succeeded: 1, Failed: 1, Items: []*entity.BulkItemResult{
+					{Index: 0, WorkoutId: 2}, {Index: 1, WorkoutId: 4, Error: "no access to this workout"}}}, nil)
+			},
+			expectedStatusCode: 422,
+			expectedResponseBody: `{"committed":false,"succeeded":1,"failed":1,"items":[{"index":0,"workout_id":2},` +
+				`{"index":1,"workout_id":4,"error":"no access to this workout"}]}`,
+		},
+		{
+			name:                 "Invalid mode",
+			trainerId:            1,
+			inputBody:            `{"mode":"partial","workouts":[{"id":2}]}`,
+			mockBehaviour:        func(r *mockService.MockBulk, trainerId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'BulkDeleteInput.Mode' Error:Field validation for 'Mode' failed on the 'oneof' tag"}`, //nolint
+		},
+		{
+			name:                 "No workouts",
+			trainerId:            1,
+			inputBody:            `{"workouts":[]}`,
+			mockBehaviour:        func(r *mockService.MockBulk, trainerId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'BulkDeleteInput.Workouts' Error:Field validation for 'Workouts' failed on the 'min' tag"}`, //nolint
+		},
+		{
+			name:      "Transaction failed",
+			trainerId: 1,
+			inputBody: `{"workouts":[{"id":2}]}`,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64) {
+				r.EXPECT().DeleteWorkouts(trainerId, &entity.BulkDeleteInput{
+					Workouts: []*entity.WorkoutRef{{Id: 2}},
+				}).Return(nil, errors.New("database is locked"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"database is locked"}`,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			bulk := mockService.NewMockBulk(c)
+			test.mockBehaviour(bulk, test.trainerId)
+
+			services := &service.Services{Bulk: bulk}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/workout/bulk/delete", handler.bulkDeleteWorkouts)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/workout/bulk/delete", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_bulkShiftWorkouts(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockBulk, trainerId int64, input *entity.BulkShiftInput)
+
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	input := &entity.BulkShiftInput{UserId: 2, From: from, To: from.AddDate(0, 0, 7), Days: 1}
+	inputBody := `{"user_id":2,"from":"2026-10-20T00:00:00Z","to":"2026-10-27T00:00:00Z","days":1}`
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64, input *entity.BulkShiftInput) {
+				r.EXPECT().ShiftWorkouts(trainerId, input).Return(&entity.BulkResult{Committed: true,
+					Items: []*entity.BulkItemResult{}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"committed":true,"succeeded":0,"failed":0,"items":[]}`,
+		},
+		{
+			name:      "Range too large",
+			trainerId: 1,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64, input *entity.BulkShiftInput) {
+				r.EXPECT().ShiftWorkouts(trainerId, input).Return(nil, service.ErrRangeTooLarge)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"range has more than 100 workouts, shift it in parts"}`,
+		},
+		{
+			name:      "Invalid range",
+			trainerId: 1,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64, input *entity.BulkShiftInput) {
+				r.EXPECT().ShiftWorkouts(trainerId, input).Return(nil, service.ErrInvalidRange)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"from must be before to"}`,
+		},
+		{
+			name:      "Service Failure",
+			trainerId: 1,
+			mockBehaviour: func(r *mockService.MockBulk, trainerId int64, input *entity.BulkShiftInput) {
+				r.EXPECT().ShiftWorkouts(trainerId, input).Return(nil, errors.New("unknown time zone"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"unknown time zone"}`,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			bulk := mockService.NewMockBulk(c)
+			test.mockBehaviour(bulk, test.trainerId, input)
+
+			services := &service.Services{Bulk: bulk}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/workout/bulk/shift", handler.bulkShiftWorkouts)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/workout/bulk/shift", bytes.NewBufferString(inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
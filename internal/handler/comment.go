@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Get workout comments
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description get comment thread of workout for its client or trainer, same route is available under /trainer
+// @ID get-workout-comments
+// @Produce  json
+// @Success 200 {object} commentsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id/comment [get]
+func (h *Handler) getWorkoutComments(c *gin.Context) {
+	userId, workoutId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	comments, err := h.services.Comment.GetComments(workoutId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, commentsResponse{
+		Comments: comments,
+	})
+}
+
+// @Summary Create workout comment
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description leaves comment on workout, same route is available under /trainer
+// @ID create-workout-comment
+// @Accept  json
+// @Produce  json
+// @Param input body entity.CommentInput true "comment"
+// @Success 200 {object} commentIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id/comment [post]
+func (h *Handler) createWorkoutComment(c *gin.Context) {
+	userId, workoutId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := h.services.Comment.CreateComment(workoutId, userId, input.Body)
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, commentIdResponse{
+		CommentId: id,
+	})
+}
+
+// @Summary Update workout comment
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description edits comment text, allowed for author only, same route is available under /trainer
+// @ID update-workout-comment
+// @Accept  json
+// @Produce  json
+// @Param input body entity.CommentInput true "comment"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id/comment/:comment_id [put]
+func (h *Handler) updateWorkoutComment(c *gin.Context) {
+	userId, workoutId, commentId, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := h.services.Comment.UpdateComment(workoutId, commentId, userId, input.Body)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Resolve workout comment
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description marks comment as resolved or unresolved by either participant, same route is available under /trainer
+// @ID resolve-workout-comment
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ResolveCommentInput true "resolved flag"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id/comment/:comment_id/resolve [put]
+func (h *Handler) resolveWorkoutComment(c *gin.Context) {
+	userId, workoutId, commentId, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.ResolveCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := h.services.Comment.ResolveComment(workoutId, commentId, userId, input.Resolved)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Delete workout comment
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description deletes comment, allowed for author only, same route is available under /trainer
+// @ID delete-workout-comment
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id/comment/:comment_id [delete]
+func (h *Handler) deleteWorkoutComment(c *gin.Context) {
+	userId, workoutId, commentId, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.services.Comment.DeleteComment(workoutId, commentId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func idParams(c *gin.Context) (int64, int64, bool) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return 0, 0, false
+	}
+	workoutId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || workoutId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return 0, 0, false
+	}
+	return userId, workoutId, true
+}
+
+func commentParams(c *gin.Context) (int64, int64, int64, bool) {
+	userId, workoutId, ok := idParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	commentId, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil || commentId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return 0, 0, 0, false
+	}
+	return userId, workoutId, commentId, true
+}
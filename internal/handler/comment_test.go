@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_createWorkoutComment(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockComment, userId, workoutId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		workoutId            int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			workoutId: 5,
+			inputBody: `{"body":"how many sets?"}`,
+			mockBehaviour: func(r *mockService.MockComment, userId, workoutId int64) {
+				r.EXPECT().CreateComment(workoutId, userId, "how many sets?").Return(int64(1), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"comment_id":1}`,
+		},
+		{
+			name:      "No access",
+			userId:    3,
+			workoutId: 5,
+			inputBody: `{"body":"hello"}`,
+			mockBehaviour: func(r *mockService.MockComment, userId, workoutId int64) {
+				r.EXPECT().CreateComment(workoutId, userId, "hello").
+					Return(int64(-1), errors.New("no access to this workout"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"no access to this workout"}`,
+		},
+		{
+			name:                 "Empty body",
+			userId:               1,
+			workoutId:            5,
+			inputBody:            `{}`,
+			mockBehaviour:        func(r *mockService.MockComment, userId, workoutId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'CommentInput.Body' Error:Field validation for 'Body' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Internal error",
+			userId:    1,
+			workoutId: 5,
+			inputBody: `{"body":"hello"}`,
+			mockBehaviour: func(r *mockService.MockComment, userId, workoutId int64) {
+				r.EXPECT().CreateComment(workoutId, userId, "hello").
+					Return(int64(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+		{
+			name:                 "Invalid workout id",
+			userId:               1,
+			workoutId:            -1,
+			inputBody:            `{"body":"hello"}`,
+			mockBehaviour:        func(r *mockService.MockComment, userId, workoutId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			comment := mockService.NewMockComment(c)
+			test.mockBehaviour(comment, test.userId, test.workoutId)
+
+			services := &service.Services{Comment: comment}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/workout/:id/comment", handler.createWorkoutComment)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/workout/%d/comment", test.workoutId),
+				bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_updateWorkoutComment(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockComment, userId, workoutId, commentId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		workoutId            int64
+		commentId            int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			workoutId: 5,
+			commentId: 2,
+			inputBody: `{"body":"edited"}`,
+			mockBehaviour: func(r *mockService.MockComment, userId, workoutId, commentId int64) {
+				r.EXPECT().UpdateComment(workoutId, commentId, userId, "edited").Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:      "Not author",
+			userId:    2,
+			workoutId: 5,
+			commentId: 2,
+			inputBody: `{"body":"edited"}`,
+			mockBehaviour: func(r *mockService.MockComment, userId, workoutId, commentId int64) {
+				r.EXPECT().UpdateComment(workoutId, commentId, userId, "edited").
+					Return(errors.New("only author can edit comment"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"only author can edit comment"}`,
+		},
+		{
+			name:                 "Invalid comment id",
+			userId:               1,
+			workoutId:            5,
+			commentId:            0,
+			inputBody:            `{"body":"edited"}`,
+			mockBehaviour:        func(r *mockService.MockComment, userId, workoutId, commentId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			comment := mockService.NewMockComment(c)
+			test.mockBehaviour(comment, test.userId, test.workoutId, test.commentId)
+
+			services := &service.Services{Comment: comment}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.PUT("/workout/:id/comment/:comment_id", handler.updateWorkoutComment)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut,
+				fmt.Sprintf("/workout/%d/comment/%d", test.workoutId, test.commentId),
+				bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
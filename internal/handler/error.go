@@ -12,6 +12,9 @@ var (
 	ErrorInvalidAuthHeader  = errors.New("invalid auth header")
 	ErrorEmptyAuthHeader    = errors.New("empty auth header")
 	ErrorForbidden          = errors.New("forbidden")
+	ErrorInvalidIfMatch     = errors.New("invalid If-Match header")
+	ErrorUnsupportedPatch   = errors.New("patch has to be application/merge-patch+json")
+	ErrorInvalidIdempotency = errors.New("invalid Idempotency-Key header")
 )
 
 type errorResponse struct {
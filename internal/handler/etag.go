@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag sends version of resource, clients return it in If-Match to change only the version they have read.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatch returns version required by If-Match header, zero when there is no header or it is "*".
+// Tags are compared strongly, so weak tags never match, and a change can require one version only,
+// a list naming different versions fails like a header no tag of which matches.
+func ifMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	var version int64
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		weak := strings.HasPrefix(tag, "W/")
+		tag = strings.TrimPrefix(tag, "W/")
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' || strings.Contains(tag[1:len(tag)-1], `"`) {
+			return 0, ErrorInvalidIfMatch
+		}
+		v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if weak || err != nil || v < 1 {
+			continue
+		}
+		if version != 0 && version != v {
+			return 0, entity.ErrVersionMismatch
+		}
+		version = v
+	}
+	if version == 0 {
+		return 0, entity.ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// changeErrorStatus is status of failed conditional change. Changes without If-Match are
+// also applied to the version their empty fields were filled from and report conflict instead.
+func changeErrorStatus(c *gin.Context, err error) int {
+	if !errors.Is(err, entity.ErrVersionMismatch) {
+		return http.StatusBadRequest
+	}
+	if c.GetHeader("If-Match") == "" {
+		return http.StatusConflict
+	}
+	return http.StatusPreconditionFailed
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/server"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	eventWriteTimeout = 10 * time.Second
+)
+
+// @Summary Stream events
+// @Security ApiKeyAuth
+// @Tags user
+// @Description streams real-time events as Server-Sent Events: partnership.requested, partnership.accepted,
+// @Description workout.created, workout.updated, message.created
+// @ID user-events
+// @Produce  text/event-stream
+// @Success 200 {object} entity.Event
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/events [get]
+func (h *Handler) getUserEvents(c *gin.Context) {
+	h.streamEvents(c)
+}
+
+// @Summary Stream events
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description streams real-time events as Server-Sent Events: partnership.requested, partnership.accepted,
+// @Description workout.created, workout.updated, message.created
+// @ID trainer-events
+// @Produce  text/event-stream
+// @Success 200 {object} entity.Event
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/events [get]
+func (h *Handler) getTrainerEvents(c *gin.Context) {
+	h.streamEvents(c)
+}
+
+func (h *Handler) streamEvents(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	events, cancel := h.services.Event.Subscribe(userId)
+	defer cancel()
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if !flushEvents(c) {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatInt(e.Id, 10),
+				Event: string(e.Type),
+				Data:  e,
+			})
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": ping\n\n")
+		}
+		if !flushEvents(c) {
+			return
+		}
+	}
+}
+
+func flushEvents(c *gin.Context) bool {
+	_ = server.SetWriteDeadline(c.Request.Context(), time.Now().Add(eventWriteTimeout))
+	if len(c.Errors) > 0 {
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
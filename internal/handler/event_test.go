@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_streamEvents(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockEvent, userId int64)
+
+	createdAt := time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			userId: 2,
+			mockBehaviour: func(r *mockService.MockEvent, userId int64) {
+				events := make(chan *entity.Event, 2)
+				events <- &entity.Event{
+					Id:   1,
+					Type: entity.EventPartnershipRequested,
+					Payload: &entity.PartnershipEvent{
+						PartnershipId: 5,
+						UserId:        1,
+						TrainerId:     2,
+					},
+					CreatedAt: createdAt,
+				}
+				events <- &entity.Event{
+					Id:        2,
+					Type:      entity.EventMessageCreated,
+					Payload:   &entity.Message{Id: 3, PartnershipId: 5, SenderId: 1, Body: "hi", CreatedAt: createdAt},
+					CreatedAt: createdAt,
+				}
+				close(events)
+				r.EXPECT().Subscribe(userId).Return(events, func() {})
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "id:1\nevent:partnership.requested\ndata:{\"id\":1,\"type\":\"partnership.requested\",\"payload\":{\"partnership_id\":5,\"user_id\":1,\"trainer_id\":2},\"created_at\":\"2023-09-01T10:00:00Z\"}\n\nid:2\nevent:message.created\ndata:{\"id\":2,\"type\":\"message.created\",\"payload\":{\"id\":3,\"partnership_id\":5,\"sender_id\":1,\"body\":\"hi\",\"created_at\":\"2023-09-01T10:00:00Z\",\"read_at\":{\"Time\":\"0001-01-01T00:00:00Z\",\"Valid\":false}},\"created_at\":\"2023-09-01T10:00:00Z\"}\n\n", //nolint
+		},
+		{
+			name:                 "Invalid user id",
+			userId:               -1,
+			mockBehaviour:        func(r *mockService.MockEvent, userId int64) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"invalid id"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			event := mockService.NewMockEvent(c)
+			test.mockBehaviour(event, test.userId)
+
+			services := &service.Services{Event: event}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/events", handler.getUserEvents)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
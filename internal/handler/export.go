@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Request data export
+// @Security ApiKeyAuth
+// @Tags user
+// @Description starts building archive with all personal data, status is available by export id
+// @ID request-user-export
+// @Produce  json
+// @Success 202 {object} exportIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/export [post]
+func (h *Handler) requestUserExport(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	h.requestExport(c, nil, userId)
+}
+
+// @Summary Get current data export
+// @Security ApiKeyAuth
+// @Tags user
+// @Description returns the latest export with download_url once archive is ready, new export is started
+// @Description when there is none or the latest one has failed or expired
+// @ID get-current-user-export
+// @Produce  json
+// @Success 200 {object} entity.DataExport
+// @Success 202 {object} entity.DataExport
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/export [get]
+func (h *Handler) getCurrentUserExport(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	e, err := h.services.Export.GetCurrentExport(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	if e.DownloadURL == "" {
+		c.JSON(http.StatusAccepted, e)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// @Summary Get data export
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get export status, download_url is set while archive is ready and link is not expired
+// @ID get-user-export
+// @Produce  json
+// @Success 200 {object} entity.DataExport
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/export/:id [get]
+func (h *Handler) getUserExport(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	exportId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || exportId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	h.respondExport(c, userId, exportId)
+}
+
+// @Summary Request user data export
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description starts building archive with all personal data of user
+// @ID request-admin-user-export
+// @Produce  json
+// @Success 202 {object} exportIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/user/:id/export [post]
+func (h *Handler) requestAdminUserExport(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	h.requestExport(c, newActor(c, entity.ActorAdmin, adminId), userId)
+}
+
+// @Summary Get user data export
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get export status, download_url is set while archive is ready and link is not expired
+// @ID get-admin-user-export
+// @Produce  json
+// @Success 200 {object} entity.DataExport
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/user/:id/export/:export_id [get]
+func (h *Handler) getAdminUserExport(c *gin.Context) {
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	exportId, err := strconv.ParseInt(c.Param("export_id"), 10, 64)
+	if err != nil || exportId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	h.respondExport(c, userId, exportId)
+}
+
+// @Summary Download data export
+// @Tags export
+// @Description downloads ZIP archive by link from export status, link works until it expires
+// @ID download-export
+// @Produce  application/zip
+// @Success 200 {file} binary
+// @Failure 404 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /export/:token [get]
+func (h *Handler) downloadExport(c *gin.Context) {
+	e, err := h.services.Export.GetExportArchive(c.Param("token"))
+	if err != nil {
+		newErrorResponse(c, http.StatusNotFound, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%d.zip"`, e.Id))
+	c.Data(http.StatusOK, "application/zip", e.Archive)
+}
+
+func (h *Handler) requestExport(c *gin.Context, actor *entity.Actor, userId int64) {
+	exportId, err := h.services.Export.RequestExport(actor, userId)
+	if err != nil {
+		if exportId == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, exportIdResponse{
+		ExportId: exportId,
+	})
+}
+
+func (h *Handler) respondExport(c *gin.Context, userId, exportId int64) {
+	e, err := h.services.Export.GetExport(userId, exportId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
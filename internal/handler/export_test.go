@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_requestUserExport(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockExport, userId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				var actor *entity.Actor
+				r.EXPECT().RequestExport(actor, userId).Return(int64(3), nil)
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: `{"export_id":3}`,
+		},
+		{
+			name:   "In progress",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				var actor *entity.Actor
+				r.EXPECT().RequestExport(actor, userId).Return(int64(-1), errors.New("export is already in progress"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"export is already in progress"}`,
+		},
+		{
+			name:   "Internal error",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				var actor *entity.Actor
+				r.EXPECT().RequestExport(actor, userId).Return(int64(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			export := mockService.NewMockExport(c)
+			test.mockBehaviour(export, test.userId)
+
+			services := &service.Services{Export: export}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/export", handler.requestUserExport)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/export", nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_requestAdminUserExport(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	export := mockService.NewMockExport(c)
+	export.EXPECT().RequestExport(testActor(entity.ActorAdmin, 1), int64(2)).Return(int64(3), nil)
+
+	services := &service.Services{Export: export}
+	handler := &Handler{services: services}
+
+	r := gin.New()
+	r.POST("/user/:id/export", handler.requestAdminUserExport)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/user/2/export", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set(userIdCtx, int64(1))
+	r.ServeHTTP(w, req.WithContext(ctx))
+
+	assert.Equal(t, w.Code, 202)
+	assert.Equal(t, w.Body.String(), `{"export_id":3}`)
+}
+
+func TestHandler_getUserExport(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockExport, userId, exportId int64)
+
+	created := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		exportId             int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			userId:   1,
+			exportId: 3,
+			mockBehaviour: func(r *mockService.MockExport, userId, exportId int64) {
+				r.EXPECT().GetExport(userId, exportId).Return(&entity.DataExport{
+					Id:        exportId,
+					UserId:    userId,
+					Status:    entity.ExportPending,
+					CreatedAt: created,
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3,"user_id":1,"status":"pending","error":{"String":"","Valid":false},"created_at":"2022-03-01T10:00:00Z","completed_at":{"Time":"0001-01-01T00:00:00Z","Valid":false},"expires_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}}`, //nolint
+		},
+		{
+			name:                 "Invalid id",
+			userId:               1,
+			exportId:             -1,
+			mockBehaviour:        func(r *mockService.MockExport, userId, exportId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+		{
+			name:     "Not found",
+			userId:   1,
+			exportId: 3,
+			mockBehaviour: func(r *mockService.MockExport, userId, exportId int64) {
+				r.EXPECT().GetExport(userId, exportId).Return(nil, errors.New("export not found"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"export not found"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			export := mockService.NewMockExport(c)
+			test.mockBehaviour(export, test.userId, test.exportId)
+
+			services := &service.Services{Export: export}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/export/:id", handler.getUserExport)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/export/%d", test.exportId), nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_getCurrentUserExport(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockExport, userId int64)
+
+	created := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ready",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				r.EXPECT().GetCurrentExport(userId).Return(&entity.DataExport{
+					Id:          3,
+					UserId:      userId,
+					Status:      entity.ExportReady,
+					CreatedAt:   created,
+					DownloadURL: "/export/abc",
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3,"user_id":1,"status":"ready","error":{"String":"","Valid":false},"created_at":"2022-03-01T10:00:00Z","completed_at":{"Time":"0001-01-01T00:00:00Z","Valid":false},"expires_at":{"Time":"0001-01-01T00:00:00Z","Valid":false},"download_url":"/export/abc"}`, //nolint
+		},
+		{
+			name:   "Pending",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				r.EXPECT().GetCurrentExport(userId).Return(&entity.DataExport{
+					Id:        3,
+					UserId:    userId,
+					Status:    entity.ExportPending,
+					CreatedAt: created,
+				}, nil)
+			},
+			expectedStatusCode:   202,
+			expectedResponseBody: `{"id":3,"user_id":1,"status":"pending","error":{"String":"","Valid":false},"created_at":"2022-03-01T10:00:00Z","completed_at":{"Time":"0001-01-01T00:00:00Z","Valid":false},"expires_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}}`, //nolint
+		},
+		{
+			name:   "Internal error",
+			userId: 1,
+			mockBehaviour: func(r *mockService.MockExport, userId int64) {
+				r.EXPECT().GetCurrentExport(userId).Return(nil, errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			export := mockService.NewMockExport(c)
+			test.mockBehaviour(export, test.userId)
+
+			services := &service.Services{Export: export}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/export", handler.getCurrentUserExport)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/export", nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_downloadExport(t *testing.T) {
+
+	type mockBehaviour func(r *mockService.MockExport, token string)
+
+	table := []struct {
+		name                 string
+		token                string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedDisposition  string
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			token: "abc",
+			mockBehaviour: func(r *mockService.MockExport, token string) {
+				r.EXPECT().GetExportArchive(token).Return(&entity.DataExport{Id: 3, Archive: []byte("zip")}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedDisposition:  `attachment; filename="export-3.zip"`,
+			expectedResponseBody: "zip",
+		},
+		{
+			name:  "Expired",
+			token: "abc",
+			mockBehaviour: func(r *mockService.MockExport, token string) {
+				r.EXPECT().GetExportArchive(token).Return(nil, errors.New("export link is invalid or expired"))
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"error":"export link is invalid or expired"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			export := mockService.NewMockExport(c)
+			test.mockBehaviour(export, test.token)
+
+			services := &service.Services{Export: export}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/export/:token", handler.downloadExport)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/export/"+test.token, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Disposition"), test.expectedDisposition)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
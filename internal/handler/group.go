@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Create client group
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description creates named group of clients
+// @ID create-client-group
+// @Accept  json
+// @Produce  json
+// @Param input body entity.ClientGroup true "group info"
+// @Success 200 {object} idResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group [post]
+func (h *Handler) createGroup(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.ClientGroup
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	input.TrainerId = trainerId
+
+	id, err := h.services.Group.CreateGroup(&input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, idResponse{
+		Id: id,
+	})
+}
+
+// @Summary Get client groups
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get all groups of trainer
+// @ID get-client-groups
+// @Produce  json
+// @Success 200 {object} groupsResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group [get]
+func (h *Handler) getGroups(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	groups, err := h.services.Group.GetTrainerGroups(trainerId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, groupsResponse{
+		Groups: groups,
+	})
+}
+
+// @Summary Get client group
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get group with its members
+// @ID get-client-group
+// @Produce  json
+// @Success 200 {object} entity.ClientGroup
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group/:id [get]
+func (h *Handler) getGroupById(c *gin.Context) {
+	trainerId, groupId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.services.Group.GetGroupById(trainerId, groupId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Delete client group
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description deletes group, group workouts created from it are kept
+// @ID delete-client-group
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group/:id [delete]
+func (h *Handler) deleteGroup(c *gin.Context) {
+	trainerId, groupId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Group.DeleteGroup(trainerId, groupId); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Add group member
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description adds client with approved partnership to group
+// @ID add-group-member
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group/:id/member/:user_id [post]
+func (h *Handler) addGroupMember(c *gin.Context) {
+	trainerId, groupId, userId, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Group.AddGroupMember(trainerId, groupId, userId); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Remove group member
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description removes client from group
+// @ID remove-group-member
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group/:id/member/:user_id [delete]
+func (h *Handler) removeGroupMember(c *gin.Context) {
+	trainerId, groupId, userId, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Group.RemoveGroupMember(trainerId, groupId, userId); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Create group workout
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description creates group workout for members of group_id and clients from user_ids,
+// @Description every participant gets own copy of workout
+// @ID create-group-workout
+// @Accept  json
+// @Produce  json
+// @Param input body entity.GroupWorkoutInput true "group workout info"
+// @Success 200 {object} idResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout [post]
+func (h *Handler) createGroupWorkout(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.GroupWorkoutInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := h.services.Group.CreateGroupWorkout(trainerId, &input)
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, idResponse{
+		Id: id,
+	})
+}
+
+// @Summary Get group workouts
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get all group workouts of trainer
+// @ID get-group-workouts
+// @Produce  json
+// @Success 200 {object} groupWorkoutsResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout [get]
+func (h *Handler) getGroupWorkouts(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	workouts, err := h.services.Group.GetTrainerGroupWorkouts(trainerId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, groupWorkoutsResponse{
+		GroupWorkouts: workouts,
+	})
+}
+
+// @Summary Get group workout
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get group workout with participants and their attendance
+// @ID get-group-workout
+// @Produce  json
+// @Success 200 {object} entity.GroupWorkout
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout/:id [get]
+func (h *Handler) getGroupWorkoutById(c *gin.Context) {
+	trainerId, groupWorkoutId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	workout, err := h.services.Group.GetGroupWorkoutById(trainerId, groupWorkoutId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, workout)
+}
+
+// @Summary Delete group workout
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description deletes group workout together with workouts of participants
+// @ID delete-group-workout
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout/:id [delete]
+func (h *Handler) deleteGroupWorkout(c *gin.Context) {
+	trainerId, groupWorkoutId, ok := idParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Group.DeleteGroupWorkout(trainerId, groupWorkoutId); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Add participant
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description adds client to group workout if participant cap is not reached
+// @ID add-group-workout-participant
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout/:id/participant/:user_id [post]
+func (h *Handler) addParticipant(c *gin.Context) {
+	trainerId, groupWorkoutId, userId, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.services.Group.AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Remove participant
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description removes client and their workout copy from group workout
+// @ID remove-group-workout-participant
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout/:id/participant/:user_id [delete]
+func (h *Handler) removeParticipant(c *gin.Context) {
+	trainerId, groupWorkoutId, userId, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.services.Group.RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Set attendance
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description sets attendance status of participant: registered, attended, missed or excused
+// @ID set-attendance
+// @Accept  json
+// @Produce  json
+// @Param input body entity.AttendanceInput true "attendance status"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/group-workout/:id/participant/:user_id [put]
+func (h *Handler) setAttendance(c *gin.Context) {
+	trainerId, groupWorkoutId, userId, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.AttendanceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := h.services.Group.SetAttendance(trainerId, groupWorkoutId, userId, input.Status)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func memberParams(c *gin.Context) (int64, int64, int64, bool) {
+	trainerId, id, ok := idParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	userId, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return 0, 0, 0, false
+	}
+	return trainerId, id, userId, true
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_createGroupWorkout(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockGroup, trainerId int64, input *entity.GroupWorkoutInput)
+
+	date := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		inputBody            string
+		input                *entity.GroupWorkoutInput
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			inputBody: `{"title":"HIIT","date":"2026-10-20T09:00:00Z","capacity":10,"group_id":3,"user_ids":[4]}`,
+			input: &entity.GroupWorkoutInput{Title: "HIIT", Date: date, Capacity: 10, GroupId: 3,
+				UserIds: []int64{4}},
+			mockBehaviour: func(r *mockService.MockGroup, trainerId int64, input *entity.GroupWorkoutInput) {
+				r.EXPECT().CreateGroupWorkout(trainerId, input).Return(int64(1), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:      "Cap exceeded",
+			trainerId: 1,
+			inputBody: `{"title":"HIIT","date":"2026-10-20T09:00:00Z","capacity":1,"user_ids":[4,5]}`,
+			input:     &entity.GroupWorkoutInput{Title: "HIIT", Date: date, Capacity: 1, UserIds: []int64{4, 5}},
+			mockBehaviour: func(r *mockService.MockGroup, trainerId int64, input *entity.GroupWorkoutInput) {
+				r.EXPECT().CreateGroupWorkout(trainerId, input).
+					Return(int64(-1), errors.New("group workout has 2 participants, cap is 1"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"group workout has 2 participants, cap is 1"}`,
+		},
+		{
+			name:      "Invalid capacity",
+			trainerId: 1,
+			inputBody: `{"title":"HIIT","date":"2026-10-20T09:00:00Z","capacity":-1}`,
+			mockBehaviour: func(r *mockService.MockGroup, trainerId int64, input *entity.GroupWorkoutInput) {
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'GroupWorkoutInput.Capacity' Error:Field validation for 'Capacity' failed on the 'min' tag"}`, //nolint
+		},
+		{
+			name:      "Internal error",
+			trainerId: 1,
+			inputBody: `{"title":"HIIT","date":"2026-10-20T09:00:00Z","capacity":10}`,
+			input:     &entity.GroupWorkoutInput{Title: "HIIT", Date: date, Capacity: 10},
+			mockBehaviour: func(r *mockService.MockGroup, trainerId int64, input *entity.GroupWorkoutInput) {
+				r.EXPECT().CreateGroupWorkout(trainerId, input).Return(int64(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			group := mockService.NewMockGroup(c)
+			test.mockBehaviour(group, test.trainerId, test.input)
+
+			services := &service.Services{Group: group}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/group-workout", handler.createGroupWorkout)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/group-workout", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_setAttendance(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockGroup, trainerId, groupWorkoutId, userId int64)
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		groupWorkoutId       int64
+		userId               int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:           "Ok",
+			trainerId:      1,
+			groupWorkoutId: 7,
+			userId:         3,
+			inputBody:      `{"status":"attended"}`,
+			mockBehaviour: func(r *mockService.MockGroup, trainerId, groupWorkoutId, userId int64) {
+				r.EXPECT().SetAttendance(trainerId, groupWorkoutId, userId, entity.AttendanceAttended).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:                 "Unknown status",
+			trainerId:            1,
+			groupWorkoutId:       7,
+			userId:               3,
+			inputBody:            `{"status":"late"}`,
+			mockBehaviour:        func(r *mockService.MockGroup, trainerId, groupWorkoutId, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'AttendanceInput.Status' Error:Field validation for 'Status' failed on the 'oneof' tag"}`, //nolint
+		},
+		{
+			name:           "Not a participant",
+			trainerId:      1,
+			groupWorkoutId: 7,
+			userId:         4,
+			inputBody:      `{"status":"missed"}`,
+			mockBehaviour: func(r *mockService.MockGroup, trainerId, groupWorkoutId, userId int64) {
+				r.EXPECT().SetAttendance(trainerId, groupWorkoutId, userId, entity.AttendanceMissed).
+					Return(errors.New("no participant with provided id"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"no participant with provided id"}`,
+		},
+		{
+			name:                 "Invalid user id",
+			trainerId:            1,
+			groupWorkoutId:       7,
+			userId:               0,
+			inputBody:            `{"status":"missed"}`,
+			mockBehaviour:        func(r *mockService.MockGroup, trainerId, groupWorkoutId, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			group := mockService.NewMockGroup(c)
+			test.mockBehaviour(group, test.trainerId, test.groupWorkoutId, test.userId)
+
+			services := &service.Services{Group: group}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.PUT("/group-workout/:id/participant/:user_id", handler.setAttendance)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut,
+				fmt.Sprintf("/group-workout/%d/participant/%d", test.groupWorkoutId, test.userId),
+				bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -16,15 +16,22 @@ func NewHandler(services *service.Services) *Handler {
 	return &Handler{services: services}
 }
 
-func (h *Handler) InitRoutes() *gin.Engine {
+// InitRoutes builds router, client IP is taken from X-Forwarded-For only for requests from trustedProxies.
+func (h *Handler) InitRoutes(trustedProxies []string) (*gin.Engine, error) {
 	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	router.Use(h.requestId)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	h.initAuthRoutes(router)
 	h.initAdminRoutes(router)
 	h.initTrainerRoutes(router)
 	h.initUserRoutes(router)
-	return router
+
+	router.GET("/export/:token", h.downloadExport)
+	return router, nil
 }
 
 func (h *Handler) initAuthRoutes(router *gin.Engine) {
@@ -34,6 +41,13 @@ func (h *Handler) initAuthRoutes(router *gin.Engine) {
 		auth.POST("/trainer/sign-in", h.trainerSignIn)
 		auth.POST("/sign-in", h.signIn)
 		auth.POST("/sign-up", h.signUp)
+		auth.GET("/verify", h.verifyEmail)
+		auth.POST("/forgot-password", h.forgotPassword)
+		auth.POST("/reset-password", h.resetPassword)
+		auth.POST("/mfa/enroll", h.enrollMFAChallenge)
+		auth.POST("/mfa/verify", h.verifyMFA)
+		auth.POST("/admin/mfa/verify", h.adminVerifyMFA)
+		auth.POST("/oidc/:provider", h.oidcSignIn)
 	}
 }
 
@@ -45,8 +59,28 @@ func (h *Handler) initAdminRoutes(router *gin.Engine) {
 		admin.POST("/user", h.createUser)
 		admin.PUT("/user/:id", h.updateUser)
 		admin.DELETE("/user/:id", h.deleteUser)
+		admin.POST("/user/:id/restore", h.restoreUser)
+		admin.POST("/user/:id/export", h.requestAdminUserExport)
+		admin.GET("/user/:id/export/:export_id", h.getAdminUserExport)
 
 		admin.GET("/trainer", h.getTrainersInfo)
+
+		admin.GET("/trainer-application", h.getApplications)
+		admin.GET("/trainer-application/:id", h.getApplicationById)
+		admin.GET("/trainer-application/:id/certificate/:certificate_id", h.downloadCertificate)
+		admin.POST("/trainer-application/:id/approve", h.approveApplication)
+		admin.POST("/trainer-application/:id/reject", h.rejectApplication)
+		admin.POST("/trainer-application/:id/request-changes", h.requestApplicationChanges)
+
+		admin.GET("/audit", h.getAuditLog)
+
+		admin.POST("/lockout/unlock", h.unlockSignIn)
+
+		admin.POST("/mfa", h.enrollAdminMFA)
+		admin.POST("/mfa/confirm", h.confirmAdminMFA)
+		admin.POST("/mfa/disable", h.disableAdminMFA)
+		admin.GET("/mfa/policy", h.getMFAPolicy)
+		admin.PUT("/mfa/policy", h.setMFAPolicy)
 	}
 }
 
@@ -57,18 +91,57 @@ func (h *Handler) initTrainerRoutes(router *gin.Engine) {
 		trainer.GET("/user/:id", h.getTrainerUserById)
 		trainer.POST("/user/:id", h.initPartnershipWithUser)
 		trainer.PUT("/user/:id", h.endPartnershipWithUser)
+		trainer.GET("/user/:id/message", h.getTrainerMessages)
+		trainer.POST("/user/:id/message", h.sendTrainerMessage)
+		trainer.PUT("/user/:id/message/read", h.readTrainerMessages)
 
 		trainer.GET("/request", h.getTrainerRequests)
 		trainer.GET("/request/:id", h.getTrainerRequestById)
 		trainer.PUT("/request/:id", h.acceptRequest)
 		trainer.DELETE("/request/:id", h.denyRequest)
 
-		trainer.POST("/workout", h.createTrainerWorkout)
+		trainer.POST("/workout", h.idempotent, h.createTrainerWorkout)
 		trainer.GET("/workout", h.getTrainerWorkouts)
 		trainer.GET("/workout/:id", h.getWorkoutByIdForTrainer)
 		trainer.GET("/workout/user/:id", h.getTrainerWorkoutsWithUser)
 		trainer.PUT("/workout/:id", h.updateWorkoutForUser)
+		trainer.PATCH("/workout/:id", h.patchWorkoutForTrainer)
+		trainer.POST("/workout/bulk", h.bulkCreateWorkouts)
+		trainer.PATCH("/workout/bulk", h.bulkPatchWorkouts)
+		trainer.POST("/workout/bulk/delete", h.bulkDeleteWorkouts)
+		trainer.POST("/workout/bulk/shift", h.bulkShiftWorkouts)
 		trainer.DELETE("/workout/:id", h.deleteWorkoutForTrainer)
+		trainer.GET("/workout/:id/comment", h.getWorkoutComments)
+		trainer.POST("/workout/:id/comment", h.createWorkoutComment)
+		trainer.PUT("/workout/:id/comment/:comment_id", h.updateWorkoutComment)
+		trainer.PUT("/workout/:id/comment/:comment_id/resolve", h.resolveWorkoutComment)
+		trainer.DELETE("/workout/:id/comment/:comment_id", h.deleteWorkoutComment)
+
+		trainer.GET("/availability", h.getAvailability)
+		trainer.POST("/availability/slot", h.createAvailabilitySlot)
+		trainer.DELETE("/availability/slot/:id", h.deleteAvailabilitySlot)
+		trainer.POST("/availability/exception", h.createAvailabilityException)
+		trainer.DELETE("/availability/exception/:id", h.deleteAvailabilityException)
+
+		trainer.GET("/booking", h.getTrainerBookings)
+		trainer.DELETE("/booking/:id", h.cancelBooking)
+
+		trainer.GET("/group", h.getGroups)
+		trainer.POST("/group", h.createGroup)
+		trainer.GET("/group/:id", h.getGroupById)
+		trainer.DELETE("/group/:id", h.deleteGroup)
+		trainer.POST("/group/:id/member/:user_id", h.addGroupMember)
+		trainer.DELETE("/group/:id/member/:user_id", h.removeGroupMember)
+
+		trainer.GET("/group-workout", h.getGroupWorkouts)
+		trainer.POST("/group-workout", h.createGroupWorkout)
+		trainer.GET("/group-workout/:id", h.getGroupWorkoutById)
+		trainer.DELETE("/group-workout/:id", h.deleteGroupWorkout)
+		trainer.POST("/group-workout/:id/participant/:user_id", h.addParticipant)
+		trainer.PUT("/group-workout/:id/participant/:user_id", h.setAttendance)
+		trainer.DELETE("/group-workout/:id/participant/:user_id", h.removeParticipant)
+
+		trainer.GET("/events", h.getTrainerEvents)
 	}
 }
 
@@ -76,18 +149,52 @@ func (h *Handler) initUserRoutes(router *gin.Engine) {
 	user := router.Group("/user", h.userIdentity)
 	{
 		user.GET("/", h.getUserInfo)
+		user.PATCH("/", h.updateProfile)
+		user.DELETE("/", h.deleteAccount)
+		user.POST("/password", h.changePassword)
+		user.POST("/mfa", h.enrollUserMFA)
+		user.POST("/mfa/confirm", h.confirmUserMFA)
+		user.POST("/mfa/disable", h.disableUserMFA)
+		user.GET("/identity", h.getIdentities)
+		user.POST("/identity/:provider", h.linkIdentity)
+		user.DELETE("/identity/:provider", h.unlinkIdentity)
 
 		user.GET("/workout", h.getUserWorkouts)
 		user.GET("/workout/:id", h.getWorkoutByIdForUser)
-		user.POST("/workout", h.createUserWorkout)
+		user.POST("/workout", h.idempotent, h.createUserWorkout)
 		user.PUT("/workout/:id", h.updateWorkoutForUser)
+		user.PATCH("/workout/:id", h.patchWorkoutForUser)
 		user.DELETE("/workout/:id", h.deleteWorkoutForTrainer)
+		user.GET("/workout/:id/comment", h.getWorkoutComments)
+		user.POST("/workout/:id/comment", h.createWorkoutComment)
+		user.PUT("/workout/:id/comment/:comment_id", h.updateWorkoutComment)
+		user.PUT("/workout/:id/comment/:comment_id/resolve", h.resolveWorkoutComment)
+		user.DELETE("/workout/:id/comment/:comment_id", h.deleteWorkoutComment)
 
 		user.GET("/trainer", h.getAllTrainers)
 		user.GET("/trainer/:id", h.getTrainerById)
+		user.GET("/trainer/:id/availability", h.getTrainerFreeSlots)
 
 		user.GET("/partnership", h.getPartnerships)
-		user.POST("/partnership/trainer/:id", h.sendRequestToTrainer)
+		user.POST("/partnership/trainer/:id", h.idempotent, h.sendRequestToTrainer)
 		user.PUT("/partnership/trainer/:id", h.endPartnershipWithTrainer)
+		user.GET("/partnership/:id/message", h.getUserMessages)
+		user.POST("/partnership/:id/message", h.sendUserMessage)
+		user.PUT("/partnership/:id/message/read", h.readUserMessages)
+
+		user.GET("/booking", h.getUserBookings)
+		user.POST("/booking", h.bookSlot)
+		user.DELETE("/booking/:id", h.cancelBooking)
+
+		user.GET("/events", h.getUserEvents)
+
+		user.GET("/export", h.getCurrentUserExport)
+		user.POST("/export", h.requestUserExport)
+		user.GET("/export/:id", h.getUserExport)
+
+		user.GET("/trainer-application", h.getUserApplications)
+		user.POST("/trainer-application", h.submitApplication)
+		user.PUT("/trainer-application/:id", h.updateApplication)
+		user.POST("/trainer-application/:id/certificate", h.uploadCertificate)
 	}
 }
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/service"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	replayedHeader       = "Idempotent-Replayed"
+	maxIdempotencyKey    = 255
+)
+
+// recordingWriter keeps copy of response body, so that it is replayed for retries with the same key.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent handles request with Idempotency-Key once: retries get stored response, the key reused for
+// another request gets 422 and retry of request in progress gets 409. Server errors aren't stored,
+// such requests can be retried with the same key. Requests without the header are passed through.
+func (h *Handler) idempotent(c *gin.Context) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		return
+	}
+	if len(key) > maxIdempotencyKey {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdempotency)
+		return
+	}
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	reservation, stored, err := h.services.Idempotency.BeginRequest(userId, key, fingerprint(c.Request, body))
+	switch {
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		newErrorResponse(c, http.StatusUnprocessableEntity, err)
+		return
+	case errors.Is(err, service.ErrIdempotencyKeyInFlight):
+		newErrorResponse(c, http.StatusConflict, err)
+		return
+	case err != nil:
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	case stored != nil:
+		c.Header(replayedHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+		return
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			if err := h.services.Idempotency.ReleaseRequest(reservation); err != nil {
+				logrus.Errorf("error due releasing idempotency key: %s", err.Error())
+			}
+		}
+	}()
+
+	w := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+
+	if w.Status() >= http.StatusInternalServerError {
+		return
+	}
+	err = h.services.Idempotency.CompleteRequest(reservation, w.Status(), w.Header().Get("Content-Type"),
+		w.body.Bytes())
+	if err != nil {
+		logrus.Errorf("error due storing idempotent response: %s", err.Error())
+		return
+	}
+	completed = true
+}
+
+// fingerprint identifies request by method, path and body, JSON body is compared regardless of formatting
+// and order of members.
+func fingerprint(r *http.Request, body []byte) string {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err == nil {
+		if canonical, err := json.Marshal(v); err == nil {
+			body = canonical
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/idempotency"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_idempotent(t *testing.T) {
+	type request struct {
+		userId               int64
+		key                  string
+		inputBody            string
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedReplayed     string
+	}
+
+	table := []struct {
+		name          string
+		mockBehaviour func(r *mockService.MockUser)
+		requests      []request
+	}{
+		{
+			name: "Replay",
+			mockBehaviour: func(r *mockService.MockUser) {
+				r.EXPECT().CreateWorkoutAsUser(&entity.Workout{Title: "test", Description: "test", UserId: 1}).
+					Return(int64(1), nil).Times(1)
+			},
+			requests: []request{
+				{userId: 1, key: "key", inputBody: `{"title":"test", "description":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+				{userId: 1, key: "key", inputBody: `{"description": "test", "title": "test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`, expectedReplayed: "true"},
+			},
+		},
+		{
+			name: "Key reused with another body",
+			mockBehaviour: func(r *mockService.MockUser) {
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(1), nil).Times(1)
+			},
+			requests: []request{
+				{userId: 1, key: "key", inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+				{userId: 1, key: "key", inputBody: `{"title":"other"}`, expectedStatusCode: 422,
+					expectedResponseBody: `{"error":"idempotency key has been used for another request"}`},
+			},
+		},
+		{
+			name: "Keys are scoped by user",
+			mockBehaviour: func(r *mockService.MockUser) {
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(1), nil)
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(2), nil)
+			},
+			requests: []request{
+				{userId: 1, key: "key", inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+				{userId: 2, key: "key", inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":2}`},
+			},
+		},
+		{
+			name: "Server error isn't stored",
+			mockBehaviour: func(r *mockService.MockUser) {
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(-1), errors.New("internal error"))
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(1), nil)
+			},
+			requests: []request{
+				{userId: 1, key: "key", inputBody: `{"title":"test"}`,
+					expectedStatusCode: 500, expectedResponseBody: `{"error":"internal error"}`},
+				{userId: 1, key: "key", inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+			},
+		},
+		{
+			name: "Without key",
+			mockBehaviour: func(r *mockService.MockUser) {
+				r.EXPECT().CreateWorkoutAsUser(gomock.Any()).Return(int64(1), nil).Times(2)
+			},
+			requests: []request{
+				{userId: 1, inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+				{userId: 1, inputBody: `{"title":"test"}`,
+					expectedStatusCode: 200, expectedResponseBody: `{"workout_id":1}`},
+			},
+		},
+		{
+			name:          "Too long key",
+			mockBehaviour: func(r *mockService.MockUser) {},
+			requests: []request{
+				{userId: 1, key: string(bytes.Repeat([]byte("k"), 256)), inputBody: `{"title":"test"}`,
+					expectedStatusCode: 400, expectedResponseBody: `{"error":"invalid Idempotency-Key header"}`},
+			},
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockUser(c)
+			test.mockBehaviour(repo)
+
+			services := &service.Services{User: repo,
+				Idempotency: service.NewIdempotencyService(idempotency.NewMemoryStore(), time.Hour, time.Minute)}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/workout", handler.idempotent, handler.createUserWorkout)
+
+			for _, input := range test.requests {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/workout",
+					bytes.NewBufferString(input.inputBody))
+				if input.key != "" {
+					req.Header.Set(idempotencyKeyHeader, input.key)
+				}
+				ctx, _ := gin.CreateTestContext(w)
+				ctx.Set(userIdCtx, input.userId)
+
+				req = req.WithContext(ctx)
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, w.Code, input.expectedStatusCode)
+				assert.Equal(t, w.Body.String(), input.expectedResponseBody)
+				assert.Equal(t, w.Header().Get(replayedHeader), input.expectedReplayed)
+			}
+		})
+	}
+}
+
+func TestHandler_idempotentInFlight(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	services := &service.Services{Idempotency: service.NewIdempotencyService(store, time.Hour, time.Minute)}
+	handler := &Handler{services: services}
+	_, _, _ = services.Idempotency.BeginRequest(1, "key", fingerprint(
+		httptest.NewRequest(http.MethodPost, "/workout", nil), []byte(`{"title":"test"}`)))
+
+	r := gin.New()
+	r.POST("/workout", handler.idempotent, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/workout", bytes.NewBufferString(`{"title":"test"}`))
+	req.Header.Set(idempotencyKeyHeader, "key")
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set(userIdCtx, int64(1))
+	r.ServeHTTP(w, req.WithContext(ctx))
+
+	assert.Equal(t, w.Code, http.StatusConflict)
+	assert.Equal(t, w.Body.String(), `{"error":"request with the idempotency key is in progress"}`)
+}
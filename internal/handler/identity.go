@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Sign In with provider
+// @Tags auth
+// @Description signs in with ID token issued to app by google or apple, account is linked or created by verified email
+// @ID oidc-sign-in
+// @Accept  json
+// @Produce  json
+// @Param input body entity.OIDCInput true "ID token"
+// @Success 200 {object} signInResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/oidc/:provider [post]
+func (h *Handler) oidcSignIn(c *gin.Context) {
+	var input entity.OIDCInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.services.Identity.SignIn(c.Param("provider"), &input)
+	if err != nil {
+		newErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+	c.JSON(http.StatusOK, newSignInResponse(result))
+}
+
+// @Summary Get linked providers
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get provider accounts which can be used to sign in
+// @ID get-identities
+// @Produce  json
+// @Success 200 {object} identitiesResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/identity [get]
+func (h *Handler) getIdentities(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	identities, err := h.services.Identity.GetIdentities(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, identitiesResponse{
+		Identities: identities,
+	})
+}
+
+// @Summary Link provider
+// @Security ApiKeyAuth
+// @Tags user
+// @Description links provider account by its ID token, email of provider account may differ
+// @ID link-identity
+// @Accept  json
+// @Produce  json
+// @Param input body entity.OIDCInput true "ID token"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/identity/:provider [post]
+func (h *Handler) linkIdentity(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.OIDCInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.services.Identity.Link(userId, c.Param("provider"), &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Unlink provider
+// @Security ApiKeyAuth
+// @Tags user
+// @Description unlinks provider account, the only sign in method of user without password can't be unlinked
+// @ID unlink-identity
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/identity/:provider [delete]
+func (h *Handler) unlinkIdentity(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = h.services.Identity.Unlink(userId, c.Param("provider")); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_oidcSignIn(t *testing.T) {
+	type mockBehavior func(r *mockService.MockIdentity)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"id_token":"id-token","nonce":"nonce"}`,
+			mockBehavior: func(r *mockService.MockIdentity) {
+				r.EXPECT().SignIn("google", &entity.OIDCInput{IdToken: "id-token", Nonce: "nonce"}).
+					Return(&entity.SignInResult{Token: "token"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:                 "Not Bindable JSON",
+			inputBody:            `{"nonce":"nonce"}`,
+			mockBehavior:         func(r *mockService.MockIdentity) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'OIDCInput.IdToken' Error:Field validation for 'IdToken' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:                 "No Nonce",
+			inputBody:            `{"id_token":"id-token"}`,
+			mockBehavior:         func(r *mockService.MockIdentity) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'OIDCInput.Nonce' Error:Field validation for 'Nonce' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid Token",
+			inputBody: `{"id_token":"id-token","nonce":"nonce"}`,
+			mockBehavior: func(r *mockService.MockIdentity) {
+				r.EXPECT().SignIn("google", &entity.OIDCInput{IdToken: "id-token", Nonce: "nonce"}).
+					Return(nil, errors.New("id token is expired"))
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"error":"id token is expired"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			identity := mockService.NewMockIdentity(c)
+			test.mockBehavior(identity)
+
+			handler := &Handler{services: &service.Services{Identity: identity}}
+
+			r := gin.New()
+			r.POST("/oidc/:provider", handler.oidcSignIn)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/oidc/google", bytes.NewBufferString(test.inputBody))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_unlinkIdentity(t *testing.T) {
+	type mockBehavior func(r *mockService.MockIdentity)
+
+	table := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mockService.MockIdentity) {
+				r.EXPECT().Unlink(int64(1), "apple").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: ``,
+		},
+		{
+			name: "Only Sign In Method",
+			mockBehavior: func(r *mockService.MockIdentity) {
+				r.EXPECT().Unlink(int64(1), "apple").
+					Return(errors.New("reset password before unlinking the only sign in method"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"reset password before unlinking the only sign in method"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			identity := mockService.NewMockIdentity(c)
+			test.mockBehavior(identity)
+
+			handler := &Handler{services: &service.Services{Identity: identity}}
+
+			r := gin.New()
+			r.DELETE("/identity/:provider", handler.unlinkIdentity)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/identity/apple", nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Unlock sign in
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description clears failed sign in attempts of account and/or client IP, scope is user by default
+// @ID unlock-sign-in
+// @Accept  json
+// @Produce  json
+// @Param input body entity.UnlockInput true "account login and/or IP"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/lockout/unlock [post]
+func (h *Handler) unlockSignIn(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.UnlockInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.services.Lockout.Unlock(newActor(c, entity.ActorAdmin, adminId), &input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// guardedSignIn runs signIn unless account or client IP is throttled. The attempt is counted before signIn,
+// it is taken back unless credentials are wrong.
+func (h *Handler) guardedSignIn(c *gin.Context, scope entity.ActorType, login string, signIn func() (*entity.SignInResult, error)) {
+	actor := newActor(c, entity.ActorAnonymous, 0)
+
+	wait, err := h.services.Lockout.BeginSignIn(actor, scope, login)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	if wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		newErrorResponse(c, http.StatusTooManyRequests,
+			fmt.Errorf("too many failed sign in attempts, retry in %s", wait.Round(1e9)))
+		return
+	}
+
+	result, err := signIn()
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			if err := h.services.Lockout.FailSignIn(actor, scope, login); err != nil {
+				logrus.Errorf("can't record failed sign in: %s", err.Error())
+			}
+		} else if err := h.services.Lockout.CancelSignIn(actor, scope, login); err != nil {
+			logrus.Errorf("can't take back sign in attempt: %s", err.Error())
+		}
+		newErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err = h.services.Lockout.SucceedSignIn(actor, scope, login); err != nil {
+		logrus.Errorf("can't reset failed sign in attempts: %s", err.Error())
+	}
+	c.JSON(http.StatusOK, newSignInResponse(result))
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// allowSignIn never throttles sign in attempts.
+func allowSignIn(c *gomock.Controller) *mockService.MockLockout {
+	l := mockService.NewMockLockout(c)
+	l.EXPECT().BeginSignIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+	l.EXPECT().FailSignIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	l.EXPECT().SucceedSignIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	l.EXPECT().CancelSignIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return l
+}
+
+func TestHandler_guardedSignIn(t *testing.T) {
+	type mockBehavior func(u *mockService.MockUser, l *mockService.MockLockout)
+
+	anonymous := testActor(entity.ActorAnonymous, 0)
+	table := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedRetryAfter   string
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(u *mockService.MockUser, l *mockService.MockLockout) {
+				l.EXPECT().BeginSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(time.Duration(0), nil)
+				u.EXPECT().SignIn("user@mail.com", "secret", entity.UserRole).Return(&entity.SignInResult{Token: "token"}, nil)
+				l.EXPECT().SucceedSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name: "Wrong Password",
+			mockBehavior: func(u *mockService.MockUser, l *mockService.MockLockout) {
+				l.EXPECT().BeginSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(time.Duration(0), nil)
+				u.EXPECT().SignIn("user@mail.com", "secret", entity.UserRole).Return(nil, service.ErrInvalidCredentials)
+				l.EXPECT().FailSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(nil)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"error":"invalid login or password"}`,
+		},
+		{
+			name: "Not Verified",
+			mockBehavior: func(u *mockService.MockUser, l *mockService.MockLockout) {
+				l.EXPECT().BeginSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(time.Duration(0), nil)
+				u.EXPECT().SignIn("user@mail.com", "secret", entity.UserRole).
+					Return(nil, errors.New("email is not verified"))
+				l.EXPECT().CancelSignIn(anonymous, entity.ActorUser, "user@mail.com").Return(nil)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"error":"email is not verified"}`,
+		},
+		{
+			name: "Throttled",
+			mockBehavior: func(u *mockService.MockUser, l *mockService.MockLockout) {
+				l.EXPECT().BeginSignIn(anonymous, entity.ActorUser, "user@mail.com").
+					Return(1500*time.Millisecond, nil)
+			},
+			expectedStatusCode:   429,
+			expectedRetryAfter:   "2",
+			expectedResponseBody: `{"error":"too many failed sign in attempts, retry in 2s"}`,
+		},
+		{
+			name: "Store Failure",
+			mockBehavior: func(u *mockService.MockUser, l *mockService.MockLockout) {
+				l.EXPECT().BeginSignIn(anonymous, entity.ActorUser, "user@mail.com").
+					Return(time.Duration(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			user := mockService.NewMockUser(c)
+			lockout := mockService.NewMockLockout(c)
+			test.mockBehavior(user, lockout)
+
+			services := &service.Services{User: user, Lockout: lockout}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/sign-in", handler.signIn)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/sign-in",
+				bytes.NewBufferString(`{"email":"user@mail.com","password":"secret"}`))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Retry-After"), test.expectedRetryAfter)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_unlockSignIn(t *testing.T) {
+	type mockBehavior func(l *mockService.MockLockout)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"scope":"admin","login":"root"}`,
+			mockBehavior: func(l *mockService.MockLockout) {
+				l.EXPECT().Unlock(testActor(entity.ActorAdmin, 1),
+					&entity.UnlockInput{Scope: entity.ActorAdmin, Login: "root"}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "Invalid Scope",
+			inputBody:            `{"scope":"trainer","login":"root"}`,
+			mockBehavior:         func(l *mockService.MockLockout) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'UnlockInput.Scope' Error:Field validation for 'Scope' failed on the 'oneof' tag"}`, //nolint
+		},
+		{
+			name:      "Nothing To Unlock",
+			inputBody: `{}`,
+			mockBehavior: func(l *mockService.MockLockout) {
+				l.EXPECT().Unlock(testActor(entity.ActorAdmin, 1), &entity.UnlockInput{}).
+					Return(errors.New("login or ip is required"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"login or ip is required"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lockout := mockService.NewMockLockout(c)
+			test.mockBehavior(lockout)
+
+			services := &service.Services{Lockout: lockout}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/lockout/unlock", handler.unlockSignIn)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/lockout/unlock", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Get messages
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get page of messages in conversation with trainer, newest first
+// @ID get-user-messages
+// @Produce  json
+// @Param before query int false "return messages with id less than provided"
+// @Param limit query int false "page size, 50 by default"
+// @Success 200 {object} entity.MessagePage
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/partnership/:id/message [get]
+func (h *Handler) getUserMessages(c *gin.Context) {
+	_, p, ok := h.userConversation(c)
+	if !ok {
+		return
+	}
+	h.respondMessages(c, p)
+}
+
+// @Summary Send message
+// @Security ApiKeyAuth
+// @Tags user
+// @Description sends message to trainer, allowed only while partnership is approved
+// @ID send-user-message
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MessageInput true "message"
+// @Success 200 {object} messageIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/partnership/:id/message [post]
+func (h *Handler) sendUserMessage(c *gin.Context) {
+	userId, p, ok := h.userConversation(c)
+	if !ok {
+		return
+	}
+	h.sendMessage(c, p, userId)
+}
+
+// @Summary Read messages
+// @Security ApiKeyAuth
+// @Tags user
+// @Description marks all messages from trainer in conversation as read
+// @ID read-user-messages
+// @Produce  json
+// @Success 200 {object} readMessagesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/partnership/:id/message/read [put]
+func (h *Handler) readUserMessages(c *gin.Context) {
+	userId, p, ok := h.userConversation(c)
+	if !ok {
+		return
+	}
+	h.readMessages(c, p, userId)
+}
+
+// @Summary Get messages
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get page of messages in conversation with client, newest first
+// @ID get-trainer-messages
+// @Produce  json
+// @Param before query int false "return messages with id less than provided"
+// @Param limit query int false "page size, 50 by default"
+// @Success 200 {object} entity.MessagePage
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/user/:id/message [get]
+func (h *Handler) getTrainerMessages(c *gin.Context) {
+	_, p, ok := h.trainerConversation(c)
+	if !ok {
+		return
+	}
+	h.respondMessages(c, p)
+}
+
+// @Summary Send message
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description sends message to client, allowed only while partnership is approved
+// @ID send-trainer-message
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MessageInput true "message"
+// @Success 200 {object} messageIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/user/:id/message [post]
+func (h *Handler) sendTrainerMessage(c *gin.Context) {
+	trainerId, p, ok := h.trainerConversation(c)
+	if !ok {
+		return
+	}
+	h.sendMessage(c, p, trainerId)
+}
+
+// @Summary Read messages
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description marks all messages from client in conversation as read
+// @ID read-trainer-messages
+// @Produce  json
+// @Success 200 {object} readMessagesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/user/:id/message/read [put]
+func (h *Handler) readTrainerMessages(c *gin.Context) {
+	trainerId, p, ok := h.trainerConversation(c)
+	if !ok {
+		return
+	}
+	h.readMessages(c, p, trainerId)
+}
+
+func (h *Handler) userConversation(c *gin.Context) (int64, *entity.Partnership, bool) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return 0, nil, false
+	}
+	partnershipId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || partnershipId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return 0, nil, false
+	}
+
+	p, err := h.services.Message.GetUserConversation(userId, partnershipId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return 0, nil, false
+	}
+	return userId, p, true
+}
+
+func (h *Handler) trainerConversation(c *gin.Context) (int64, *entity.Partnership, bool) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return 0, nil, false
+	}
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return 0, nil, false
+	}
+
+	p, err := h.services.Message.GetTrainerConversation(trainerId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return 0, nil, false
+	}
+	return trainerId, p, true
+}
+
+func (h *Handler) respondMessages(c *gin.Context, p *entity.Partnership) {
+	var beforeId int64
+	if param := c.Query("before"); param != "" {
+		id, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || id < 1 {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid before parameter"))
+			return
+		}
+		beforeId = id
+	}
+	var limit int
+	if param := c.Query("limit"); param != "" {
+		l, err := strconv.Atoi(param)
+		if err != nil || l < 1 {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid limit parameter"))
+			return
+		}
+		limit = l
+	}
+
+	page, err := h.services.Message.GetMessages(p.Id, beforeId, limit)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *Handler) sendMessage(c *gin.Context, p *entity.Partnership, senderId int64) {
+	var input entity.MessageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	messageId, err := h.services.Message.SendMessage(p, senderId, input.Body)
+	if err != nil {
+		if messageId == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, messageIdResponse{
+		MessageId: messageId,
+	})
+}
+
+func (h *Handler) readMessages(c *gin.Context, p *entity.Partnership, readerId int64) {
+	read, err := h.services.Message.MarkMessagesRead(p.Id, readerId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, readMessagesResponse{
+		Read: read,
+	})
+}
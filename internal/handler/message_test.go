@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_sendUserMessage(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockMessage, userId, partnershipId int64)
+
+	partnership := &entity.Partnership{Id: 5, UserId: 1, TrainerId: 2, Status: entity.StatusApproved}
+
+	table := []struct {
+		name                 string
+		userId               int64
+		partnershipId        int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "Ok",
+			userId:        1,
+			partnershipId: 5,
+			inputBody:     `{"body":"hello"}`,
+			mockBehaviour: func(r *mockService.MockMessage, userId, partnershipId int64) {
+				r.EXPECT().GetUserConversation(userId, partnershipId).Return(partnership, nil)
+				r.EXPECT().SendMessage(partnership, userId, "hello").Return(int64(1), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"message_id":1}`,
+		},
+		{
+			name:          "Foreign partnership",
+			userId:        3,
+			partnershipId: 5,
+			inputBody:     `{"body":"hello"}`,
+			mockBehaviour: func(r *mockService.MockMessage, userId, partnershipId int64) {
+				r.EXPECT().GetUserConversation(userId, partnershipId).
+					Return(nil, errors.New("no access to conversation"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"no access to conversation"}`,
+		},
+		{
+			name:          "Empty body",
+			userId:        1,
+			partnershipId: 5,
+			inputBody:     `{}`,
+			mockBehaviour: func(r *mockService.MockMessage, userId, partnershipId int64) {
+				r.EXPECT().GetUserConversation(userId, partnershipId).Return(partnership, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'MessageInput.Body' Error:Field validation for 'Body' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:          "Ended partnership",
+			userId:        1,
+			partnershipId: 5,
+			inputBody:     `{"body":"hello"}`,
+			mockBehaviour: func(r *mockService.MockMessage, userId, partnershipId int64) {
+				r.EXPECT().GetUserConversation(userId, partnershipId).Return(partnership, nil)
+				r.EXPECT().SendMessage(partnership, userId, "hello").
+					Return(int64(-1), errors.New("partnership is not active, conversation is read-only"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"partnership is not active, conversation is read-only"}`,
+		},
+		{
+			name:                 "Invalid partnership id",
+			userId:               1,
+			partnershipId:        -1,
+			inputBody:            `{"body":"hello"}`,
+			mockBehaviour:        func(r *mockService.MockMessage, userId, partnershipId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			message := mockService.NewMockMessage(c)
+			test.mockBehaviour(message, test.userId, test.partnershipId)
+
+			services := &service.Services{Message: message}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/partnership/:id/message", handler.sendUserMessage)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/partnership/%d/message", test.partnershipId),
+				bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_getTrainerMessages(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockMessage, trainerId, userId int64)
+
+	partnership := &entity.Partnership{Id: 5, UserId: 1, TrainerId: 2, Status: entity.StatusEndedByUser}
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		userId               int64
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 2,
+			userId:    1,
+			query:     "?before=10&limit=1",
+			mockBehaviour: func(r *mockService.MockMessage, trainerId, userId int64) {
+				r.EXPECT().GetTrainerConversation(trainerId, userId).Return(partnership, nil)
+				r.EXPECT().GetMessages(partnership.Id, int64(10), 1).Return(&entity.MessagePage{
+					Messages: []*entity.Message{{Id: 9, PartnershipId: 5, SenderId: 1, Body: "hi"}},
+					HasMore:  true,
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"messages":[{"id":9,"partnership_id":5,"sender_id":1,"body":"hi","created_at":"0001-01-01T00:00:00Z","read_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}}],"has_more":true}`, //nolint
+		},
+		{
+			name:      "Invalid before parameter",
+			trainerId: 2,
+			userId:    1,
+			query:     "?before=abc",
+			mockBehaviour: func(r *mockService.MockMessage, trainerId, userId int64) {
+				r.EXPECT().GetTrainerConversation(trainerId, userId).Return(partnership, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid before parameter"}`,
+		},
+		{
+			name:      "No partnership",
+			trainerId: 2,
+			userId:    3,
+			mockBehaviour: func(r *mockService.MockMessage, trainerId, userId int64) {
+				r.EXPECT().GetTrainerConversation(trainerId, userId).
+					Return(nil, errors.New("no partnership with user"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"no partnership with user"}`,
+		},
+		{
+			name:      "Internal error",
+			trainerId: 2,
+			userId:    1,
+			mockBehaviour: func(r *mockService.MockMessage, trainerId, userId int64) {
+				r.EXPECT().GetTrainerConversation(trainerId, userId).Return(partnership, nil)
+				r.EXPECT().GetMessages(partnership.Id, int64(0), 0).Return(nil, errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			message := mockService.NewMockMessage(c)
+			test.mockBehaviour(message, test.trainerId, test.userId)
+
+			services := &service.Services{Message: message}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/user/:id/message", handler.getTrainerMessages)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet,
+				fmt.Sprintf("/user/%d/message%s", test.userId, test.query), nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
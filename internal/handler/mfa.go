@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary Verify two-factor code
+// @Tags auth
+// @Description finishes user or trainer sign in with TOTP or recovery code, confirms enrollment required by policy
+// @ID verify-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFAVerifyInput true "challenge token and code"
+// @Success 200 {object} signInResponse
+// @Failure 400,401,429 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/mfa/verify [post]
+func (h *Handler) verifyMFA(c *gin.Context) {
+	h.verifyChallenge(c, h.services.User.VerifyMFA)
+}
+
+// @Summary Verify two-factor code for admin
+// @Tags auth
+// @Description finishes admin sign in with TOTP or recovery code, confirms enrollment required by policy
+// @ID admin-verify-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFAVerifyInput true "challenge token and code"
+// @Success 200 {object} signInResponse
+// @Failure 400,401,429 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/admin/mfa/verify [post]
+func (h *Handler) adminVerifyMFA(c *gin.Context) {
+	h.verifyChallenge(c, h.services.Admin.VerifyMFA)
+}
+
+// @Summary Enroll two-factor authentication on sign in
+// @Tags auth
+// @Description starts enrollment for account whose role requires MFA, the code is then sent to verify route
+// @ID enroll-mfa-challenge
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFAChallengeInput true "challenge token"
+// @Success 200 {object} entity.MFAEnrollment
+// @Failure 400 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/mfa/enroll [post]
+func (h *Handler) enrollMFAChallenge(c *gin.Context) {
+	var input entity.MFAChallengeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	enrollment, err := h.services.MFA.EnrollChallenge(input.MFAToken)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary Enroll two-factor authentication
+// @Security ApiKeyAuth
+// @Tags user
+// @Description generates TOTP secret, uri is payload for QR code of authenticator app
+// @ID enroll-user-mfa
+// @Produce  json
+// @Success 200 {object} entity.MFAEnrollment
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa [post]
+func (h *Handler) enrollUserMFA(c *gin.Context) {
+	h.enrollMFA(c, entity.ActorUser)
+}
+
+// @Summary Confirm two-factor authentication
+// @Security ApiKeyAuth
+// @Tags user
+// @Description enables MFA with first code from authenticator app, recovery codes are shown only once
+// @ID confirm-user-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFACodeInput true "code"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400,429 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/confirm [post]
+func (h *Handler) confirmUserMFA(c *gin.Context) {
+	h.confirmMFA(c, entity.ActorUser)
+}
+
+// @Summary Disable two-factor authentication
+// @Security ApiKeyAuth
+// @Tags user
+// @Description disables MFA with TOTP or recovery code, not allowed when policy requires MFA for role
+// @ID disable-user-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFACodeInput true "code"
+// @Success 200
+// @Failure 400,429 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/mfa/disable [post]
+func (h *Handler) disableUserMFA(c *gin.Context) {
+	h.disableMFA(c, entity.ActorUser)
+}
+
+// @Summary Enroll two-factor authentication for admin
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description generates TOTP secret, uri is payload for QR code of authenticator app
+// @ID enroll-admin-mfa
+// @Produce  json
+// @Success 200 {object} entity.MFAEnrollment
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/mfa [post]
+func (h *Handler) enrollAdminMFA(c *gin.Context) {
+	h.enrollMFA(c, entity.ActorAdmin)
+}
+
+// @Summary Confirm two-factor authentication for admin
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description enables MFA with first code from authenticator app, recovery codes are shown only once
+// @ID confirm-admin-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFACodeInput true "code"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400,429 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/mfa/confirm [post]
+func (h *Handler) confirmAdminMFA(c *gin.Context) {
+	h.confirmMFA(c, entity.ActorAdmin)
+}
+
+// @Summary Disable two-factor authentication for admin
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description disables MFA with TOTP or recovery code, not allowed when policy requires MFA for admins
+// @ID disable-admin-mfa
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFACodeInput true "code"
+// @Success 200
+// @Failure 400,429 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/mfa/disable [post]
+func (h *Handler) disableAdminMFA(c *gin.Context) {
+	h.disableMFA(c, entity.ActorAdmin)
+}
+
+// @Summary Get MFA policy
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get whether two-factor authentication is required for each role
+// @ID get-mfa-policy
+// @Produce  json
+// @Success 200 {object} mfaPoliciesResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/mfa/policy [get]
+func (h *Handler) getMFAPolicy(c *gin.Context) {
+	policies, err := h.services.MFA.GetPolicies()
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, mfaPoliciesResponse{
+		Policies: policies,
+	})
+}
+
+// @Summary Set MFA policy
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description makes two-factor authentication mandatory or optional for role, accounts without MFA enroll on next sign in
+// @ID set-mfa-policy
+// @Accept  json
+// @Produce  json
+// @Param input body entity.MFAPolicy true "role policy"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/mfa/policy [put]
+func (h *Handler) setMFAPolicy(c *gin.Context) {
+	adminId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.MFAPolicy
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.services.MFA.SetPolicy(newActor(c, entity.ActorAdmin, adminId), &input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h *Handler) verifyChallenge(c *gin.Context, verify func(mfaToken, code string) (*entity.SignInResult, error)) {
+	var input entity.MFAVerifyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := verify(input.MFAToken, input.Code)
+	if err != nil {
+		newErrorResponse(c, mfaErrorStatus(err, http.StatusUnauthorized), err)
+		return
+	}
+	c.JSON(http.StatusOK, newSignInResponse(result))
+}
+
+func (h *Handler) enrollMFA(c *gin.Context, subject entity.ActorType) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	enrollment, err := h.services.MFA.Enroll(subject, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *Handler) confirmMFA(c *gin.Context, subject entity.ActorType) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.MFACodeInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	codes, err := h.services.MFA.Confirm(subject, id, input.Code)
+	if err != nil {
+		newErrorResponse(c, mfaErrorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, recoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+func (h *Handler) disableMFA(c *gin.Context, subject entity.ActorType) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.MFACodeInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.services.MFA.Disable(subject, id, input.Code); err != nil {
+		newErrorResponse(c, mfaErrorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func mfaErrorStatus(err error, status int) int {
+	if errors.Is(err, service.ErrTooManyAttempts) {
+		return http.StatusTooManyRequests
+	}
+	return status
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_signInWithMFA(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	user := mockService.NewMockUser(c)
+	user.EXPECT().SignIn("trainer@mail.com", "secret", entity.TrainerRole).
+		Return(&entity.SignInResult{MFAToken: "challenge", MFAEnrollmentRequired: true}, nil)
+
+	handler := &Handler{services: &service.Services{User: user, Lockout: allowSignIn(c)}}
+
+	r := gin.New()
+	r.POST("/trainer/sign-in", handler.trainerSignIn)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/trainer/sign-in",
+		bytes.NewBufferString(`{"email":"trainer@mail.com","password":"secret"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, w.Body.String(), `{"mfa_token":"challenge","mfa_enrollment_required":true}`)
+}
+
+func TestHandler_verifyMFA(t *testing.T) {
+	type mockBehavior func(r *mockService.MockUser)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"mfa_token":"challenge","code":"123456"}`,
+			mockBehavior: func(r *mockService.MockUser) {
+				r.EXPECT().VerifyMFA("challenge", "123456").Return(&entity.SignInResult{Token: "token"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:      "Ok With Enrollment",
+			inputBody: `{"mfa_token":"challenge","code":"123456"}`,
+			mockBehavior: func(r *mockService.MockUser) {
+				r.EXPECT().VerifyMFA("challenge", "123456").
+					Return(&entity.SignInResult{Token: "token", RecoveryCodes: []string{"abcde-fghij"}}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token","recovery_codes":["abcde-fghij"]}`,
+		},
+		{
+			name:                 "Not Bindable JSON",
+			inputBody:            `{"mfa_token":"challenge"}`,
+			mockBehavior:         func(r *mockService.MockUser) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'MFAVerifyInput.Code' Error:Field validation for 'Code' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid Code",
+			inputBody: `{"mfa_token":"challenge","code":"000000"}`,
+			mockBehavior: func(r *mockService.MockUser) {
+				r.EXPECT().VerifyMFA("challenge", "000000").Return(nil, service.ErrInvalidMFACode)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"error":"invalid two-factor code"}`,
+		},
+		{
+			name:      "Too Many Attempts",
+			inputBody: `{"mfa_token":"challenge","code":"000000"}`,
+			mockBehavior: func(r *mockService.MockUser) {
+				r.EXPECT().VerifyMFA("challenge", "000000").Return(nil, service.ErrTooManyAttempts)
+			},
+			expectedStatusCode:   429,
+			expectedResponseBody: `{"error":"too many failed attempts, try again later"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			user := mockService.NewMockUser(c)
+			test.mockBehavior(user)
+
+			handler := &Handler{services: &service.Services{User: user}}
+
+			r := gin.New()
+			r.POST("/mfa/verify", handler.verifyMFA)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/mfa/verify", bytes.NewBufferString(test.inputBody))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_confirmAdminMFA(t *testing.T) {
+	type mockBehavior func(r *mockService.MockMFA)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(r *mockService.MockMFA) {
+				r.EXPECT().Confirm(entity.ActorAdmin, int64(1), "123456").Return([]string{"abcde-fghij"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"recovery_codes":["abcde-fghij"]}`,
+		},
+		{
+			name:      "Not Started",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(r *mockService.MockMFA) {
+				r.EXPECT().Confirm(entity.ActorAdmin, int64(1), "123456").
+					Return(nil, errors.New("two-factor enrollment is not started"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"two-factor enrollment is not started"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mfa := mockService.NewMockMFA(c)
+			test.mockBehavior(mfa)
+
+			handler := &Handler{services: &service.Services{MFA: mfa}}
+
+			r := gin.New()
+			r.POST("/mfa/confirm", handler.confirmAdminMFA)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/mfa/confirm", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_disableUserMFA(t *testing.T) {
+	type mockBehavior func(r *mockService.MockMFA)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"code":"abcde-fghij"}`,
+			mockBehavior: func(r *mockService.MockMFA) {
+				r.EXPECT().Disable(entity.ActorUser, int64(1), "abcde-fghij").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: ``,
+		},
+		{
+			name:      "Required By Policy",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(r *mockService.MockMFA) {
+				r.EXPECT().Disable(entity.ActorUser, int64(1), "123456").
+					Return(errors.New("two-factor authentication is required for trainer accounts"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"two-factor authentication is required for trainer accounts"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mfa := mockService.NewMockMFA(c)
+			test.mockBehavior(mfa)
+
+			handler := &Handler{services: &service.Services{MFA: mfa}}
+
+			r := gin.New()
+			r.POST("/mfa/disable", handler.disableUserMFA)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/mfa/disable", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_setMFAPolicy(t *testing.T) {
+	type mockBehavior func(r *mockService.MockMFA)
+
+	table := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"role":"trainer","required":true}`,
+			mockBehavior: func(r *mockService.MockMFA) {
+				r.EXPECT().SetPolicy(testActor(entity.ActorAdmin, 1),
+					&entity.MFAPolicy{Role: entity.TrainerRole, Required: true}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "Invalid Role",
+			inputBody:            `{"role":"guest","required":true}`,
+			mockBehavior:         func(r *mockService.MockMFA) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'MFAPolicy.Role' Error:Field validation for 'Role' failed on the 'oneof' tag"}`, //nolint
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mfa := mockService.NewMockMFA(c)
+			test.mockBehavior(mfa)
+
+			handler := &Handler{services: &service.Services{MFA: mfa}}
+
+			r := gin.New()
+			r.PUT("/mfa/policy", handler.setMFAPolicy)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/mfa/policy", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, int64(1))
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
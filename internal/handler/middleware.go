@@ -2,11 +2,19 @@ package handler
 
 import (
 	"Fitness_REST_API/internal/entity"
+	"crypto/rand"
+	"encoding/hex"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strings"
 )
 
+const (
+	requestIdHeader    = "X-Request-ID"
+	requestIdCtx       = "requestId"
+	maxRequestIdLength = 128
+)
+
 func validHeader(c *gin.Context) (string, bool) {
 	header := c.Request.Header.Get("Authorization")
 
@@ -36,10 +44,12 @@ func (h *Handler) adminIdentity(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Admin.ParseToken(token); err != nil {
+	id, err := h.services.Admin.ParseToken(token)
+	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, err)
 		return
 	}
+	c.Set(userIdCtx, id)
 	c.String(http.StatusOK, "ok")
 }
 
@@ -79,3 +89,23 @@ func (h *Handler) userIdentity(c *gin.Context) {
 
 	c.Set(userIdCtx, id)
 }
+
+func (h *Handler) requestId(c *gin.Context) {
+	id := c.GetHeader(requestIdHeader)
+	if id == "" || len(id) > maxRequestIdLength {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	c.Set(requestIdCtx, id)
+	c.Header(requestIdHeader, id)
+}
+
+func newActor(c *gin.Context, actorType entity.ActorType, id int64) *entity.Actor {
+	return &entity.Actor{
+		Type:      actorType,
+		Id:        id,
+		RequestId: c.GetString(requestIdCtx),
+		IP:        c.ClientIP(),
+	}
+}
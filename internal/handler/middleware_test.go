@@ -10,6 +10,7 @@ import (
 	"github.com/magiconair/properties/assert"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -31,7 +32,7 @@ func TestHandler_adminIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockAdmin, token string) {
-				r.EXPECT().ParseToken(token).Return(nil)
+				r.EXPECT().ParseToken(token).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "ok",
@@ -78,7 +79,7 @@ func TestHandler_adminIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(r *mock_service.MockAdmin, token string) {
-				r.EXPECT().ParseToken(token).Return(errors.New("some parsing error"))
+				r.EXPECT().ParseToken(token).Return(int64(-1), errors.New("some parsing error"))
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"error":"some parsing error"}`,
@@ -318,3 +319,98 @@ func TestHandler_trainerIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_requestId(t *testing.T) {
+	table := []struct {
+		name        string
+		headerValue string
+		generated   bool
+	}{
+		{
+			name:        "Provided",
+			headerValue: "abc-123",
+		},
+		{
+			name:      "Generated",
+			generated: true,
+		},
+		{
+			name:        "Too long",
+			headerValue: strings.Repeat("a", maxRequestIdLength+1),
+			generated:   true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			handler := &Handler{}
+
+			r := gin.New()
+			r.GET("/request", handler.requestId, func(c *gin.Context) {
+				c.String(200, newActor(c, entity.ActorUser, 1).RequestId)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/request", nil)
+			if test.headerValue != "" {
+				req.Header.Set(requestIdHeader, test.headerValue)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, 200)
+			assert.Equal(t, w.Header().Get(requestIdHeader), w.Body.String())
+			if test.generated {
+				assert.Equal(t, len(w.Body.String()), 32)
+			} else {
+				assert.Equal(t, w.Body.String(), test.headerValue)
+			}
+		})
+	}
+}
+
+// testActor is the actor handlers build for requests served by httptest
+// without the request id middleware.
+func testActor(actorType entity.ActorType, id int64) *entity.Actor {
+	return &entity.Actor{Type: actorType, Id: id, IP: "192.0.2.1"}
+}
+
+func TestHandler_InitRoutes_ClientIP(t *testing.T) {
+	table := []struct {
+		name           string
+		trustedProxies []string
+		expectedIP     string
+	}{
+		{
+			name:       "No Trusted Proxies",
+			expectedIP: "10.0.0.2",
+		},
+		{
+			name:           "Trusted Proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			expectedIP:     "203.0.113.7",
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewHandler(&service.Services{})
+			r, err := handler.InitRoutes(test.trustedProxies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.GET("/ip", func(c *gin.Context) {
+				c.String(200, c.ClientIP())
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/ip", nil)
+			req.RemoteAddr = "10.0.0.2:4000"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, 200)
+			assert.Equal(t, w.Body.String(), test.expectedIP)
+		})
+	}
+}
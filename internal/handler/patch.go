@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+// mergePatchType is media type of JSON Merge Patch (RFC 7396), plain JSON body is read as merge patch too.
+const mergePatchType = "application/merge-patch+json"
+
+// bindMergePatch decodes merge patch body into patch, members unknown to it are rejected instead of ignored.
+func bindMergePatch(c *gin.Context, patch interface{}) error {
+	if contentType := c.ContentType(); contentType != mergePatchType && contentType != binding.MIMEJSON {
+		return ErrorUnsupportedPatch
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(patch)
+}
+
+func patchErrorStatus(err error) int {
+	if errors.Is(err, ErrorUnsupportedPatch) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
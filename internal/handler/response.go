@@ -1,6 +1,9 @@
 package handler
 
-import "Fitness_REST_API/internal/entity"
+import (
+	"Fitness_REST_API/internal/entity"
+	"time"
+)
 
 type workoutsResponse struct {
 	Workouts []*entity.Workout `json:"workouts"`
@@ -18,6 +21,30 @@ type partnershipsResponse struct {
 	Partnerships []*entity.Partnership `json:"partnerships"`
 }
 
+type bookingsResponse struct {
+	Bookings []*entity.Booking `json:"bookings"`
+}
+
+type freeSlotsResponse struct {
+	Slots []*entity.FreeSlot `json:"slots"`
+}
+
+type commentsResponse struct {
+	Comments []*entity.Comment `json:"comments"`
+}
+
+type groupsResponse struct {
+	Groups []*entity.ClientGroup `json:"groups"`
+}
+
+type groupWorkoutsResponse struct {
+	GroupWorkouts []*entity.GroupWorkout `json:"group_workouts"`
+}
+
+type applicationsResponse struct {
+	Applications []*entity.TrainerApplication `json:"applications"`
+}
+
 type idResponse struct {
 	Id int64 `json:"id"`
 }
@@ -30,3 +57,41 @@ type workoutIdResponse struct {
 type partnershipIdResponse struct {
 	PartnershipId int64 `json:"partnership_id"`
 }
+type bookingIdResponse struct {
+	BookingId int64 `json:"booking_id"`
+}
+type messageIdResponse struct {
+	MessageId int64 `json:"message_id"`
+}
+type commentIdResponse struct {
+	CommentId int64 `json:"comment_id"`
+}
+type exportIdResponse struct {
+	ExportId int64 `json:"export_id"`
+}
+type applicationIdResponse struct {
+	ApplicationId int64 `json:"application_id"`
+}
+type certificateIdResponse struct {
+	CertificateId int64 `json:"certificate_id"`
+}
+
+type readMessagesResponse struct {
+	Read int64 `json:"read"`
+}
+
+type accountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type mfaPoliciesResponse struct {
+	Policies []*entity.MFAPolicy `json:"policies"`
+}
+
+type identitiesResponse struct {
+	Identities []*entity.ExternalIdentity `json:"identities"`
+}
@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	defaultRangeDays = 7
+	maxRangeDays     = 31
+)
+
+// @Summary Get availability
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get recurring availability slots and upcoming exceptions of trainer
+// @ID get-trainer-availability
+// @Produce  json
+// @Success 200 {object} entity.Availability
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/availability [get]
+func (h *Handler) getAvailability(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	availability, err := h.services.Schedule.GetAvailability(trainerId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, availability)
+}
+
+// @Summary Create availability slot
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description creates weekly recurring slot (weekday 0 is Sunday, times are HH:MM in time_zone)
+// @ID create-availability-slot
+// @Accept  json
+// @Produce  json
+// @Param input body entity.AvailabilitySlot true "slot info"
+// @Success 200 {object} idResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/availability/slot [post]
+func (h *Handler) createAvailabilitySlot(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.AvailabilitySlot
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	input.TrainerId = trainerId
+
+	id, err := h.services.Schedule.CreateAvailabilitySlot(&input)
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, idResponse{
+		Id: id,
+	})
+}
+
+// @Summary Delete availability slot
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description deletes weekly recurring slot
+// @ID delete-availability-slot
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/availability/slot/:id [delete]
+func (h *Handler) deleteAvailabilitySlot(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	slotId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || slotId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	err = h.services.Schedule.DeleteAvailabilitySlot(trainerId, slotId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Create availability exception
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description creates one-off exception: unavailable window or extra slot if available is true
+// @ID create-availability-exception
+// @Accept  json
+// @Produce  json
+// @Param input body entity.AvailabilityException true "exception info"
+// @Success 200 {object} idResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/availability/exception [post]
+func (h *Handler) createAvailabilityException(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.AvailabilityException
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	input.TrainerId = trainerId
+
+	id, err := h.services.Schedule.CreateAvailabilityException(&input)
+	if err != nil {
+		if id == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, idResponse{
+		Id: id,
+	})
+}
+
+// @Summary Delete availability exception
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description deletes one-off availability exception
+// @ID delete-availability-exception
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/availability/exception/:id [delete]
+func (h *Handler) deleteAvailabilityException(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	exceptionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || exceptionId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	err = h.services.Schedule.DeleteAvailabilityException(trainerId, exceptionId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Get trainer bookings
+// @Security ApiKeyAuth
+// @Tags trainer
+// @Description get active bookings of trainer in date range (from and to are YYYY-MM-DD)
+// @ID get-trainer-bookings
+// @Produce  json
+// @Param from query string false "range start"
+// @Param to query string false "range end"
+// @Success 200 {object} bookingsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/booking [get]
+func (h *Handler) getTrainerBookings(c *gin.Context) {
+	trainerId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	bookings, err := h.services.Schedule.GetTrainerBookings(trainerId, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, bookingsResponse{
+		Bookings: bookings,
+	})
+}
+
+// @Summary Get trainer free slots
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get bookable slots of trainer in date range (from and to are YYYY-MM-DD)
+// @ID get-trainer-free-slots
+// @Produce  json
+// @Param from query string false "range start"
+// @Param to query string false "range end"
+// @Success 200 {object} freeSlotsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/trainer/:id/availability [get]
+func (h *Handler) getTrainerFreeSlots(c *gin.Context) {
+	trainerId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || trainerId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	slots, err := h.services.Schedule.GetFreeSlots(trainerId, from, to)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, freeSlotsResponse{
+		Slots: slots,
+	})
+}
+
+// @Summary Book slot
+// @Security ApiKeyAuth
+// @Tags user
+// @Description books free slot of trainer with approved partnership and creates workout
+// @ID book-slot
+// @Accept  json
+// @Produce  json
+// @Param input body entity.BookingInput true "booking info"
+// @Success 200 {object} bookingIdResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/booking [post]
+func (h *Handler) bookSlot(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.BookingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	if input.TrainerId == userId {
+		newErrorResponse(c, http.StatusBadRequest, errors.New("can't book own slot"))
+		return
+	}
+
+	bookingId, err := h.services.Schedule.BookSlot(userId, &input)
+	if err != nil {
+		if bookingId == -1 {
+			newErrorResponse(c, http.StatusBadRequest, err)
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, bookingIdResponse{
+		BookingId: bookingId,
+	})
+}
+
+// @Summary Get bookings
+// @Security ApiKeyAuth
+// @Tags user
+// @Description get information about your bookings
+// @ID get-user-bookings
+// @Produce  json
+// @Success 200 {object} bookingsResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/booking [get]
+func (h *Handler) getUserBookings(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	bookings, err := h.services.Schedule.GetUserBookings(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, bookingsResponse{
+		Bookings: bookings,
+	})
+}
+
+// @Summary Cancel booking
+// @Security ApiKeyAuth
+// @Tags user, trainer
+// @Description cancels booking: client until cancellation cutoff, trainer until start of session
+// @ID cancel-booking
+// @Produce  json
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/booking/:id [delete]
+func (h *Handler) cancelBooking(c *gin.Context) {
+	userId, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	bookingId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || bookingId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	err = h.services.Schedule.CancelBooking(bookingId, userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if param := c.Query("from"); param != "" {
+		parsed, err := time.Parse(dateLayout, param)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from parameter, expected format is YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, defaultRangeDays-1)
+	if param := c.Query("to"); param != "" {
+		parsed, err := time.Parse(dateLayout, param)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to parameter, expected format is YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	if to.Before(from) || to.Sub(from) > maxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("invalid date range")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/service"
+	mockService "Fitness_REST_API/internal/service/mocks"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_createAvailabilitySlot(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockSchedule, slot entity.AvailabilitySlot)
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		inputBody            string
+		inputSlot            entity.AvailabilitySlot
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			inputBody: `{"weekday":1,"start_time":"09:00","end_time":"10:00","time_zone":"Europe/Minsk"}`,
+			inputSlot: entity.AvailabilitySlot{TrainerId: 1, Weekday: 1, StartTime: "09:00", EndTime: "10:00",
+				TimeZone: "Europe/Minsk"},
+			mockBehaviour: func(r *mockService.MockSchedule, slot entity.AvailabilitySlot) {
+				r.EXPECT().CreateAvailabilitySlot(&slot).Return(int64(1), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:                 "Invalid weekday",
+			trainerId:            1,
+			inputBody:            `{"weekday":7,"start_time":"09:00","end_time":"10:00"}`,
+			mockBehaviour:        func(r *mockService.MockSchedule, slot entity.AvailabilitySlot) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'AvailabilitySlot.Weekday' Error:Field validation for 'Weekday' failed on the 'max' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid time zone",
+			trainerId: 1,
+			inputBody: `{"weekday":1,"start_time":"09:00","end_time":"10:00","time_zone":"Mars/Olympus"}`,
+			inputSlot: entity.AvailabilitySlot{TrainerId: 1, Weekday: 1, StartTime: "09:00", EndTime: "10:00",
+				TimeZone: "Mars/Olympus"},
+			mockBehaviour: func(r *mockService.MockSchedule, slot entity.AvailabilitySlot) {
+				r.EXPECT().CreateAvailabilitySlot(&slot).Return(int64(-1), errors.New("invalid time_zone"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid time_zone"}`,
+		},
+		{
+			name:      "Internal error",
+			trainerId: 1,
+			inputBody: `{"weekday":1,"start_time":"09:00","end_time":"10:00"}`,
+			inputSlot: entity.AvailabilitySlot{TrainerId: 1, Weekday: 1, StartTime: "09:00", EndTime: "10:00"},
+			mockBehaviour: func(r *mockService.MockSchedule, slot entity.AvailabilitySlot) {
+				r.EXPECT().CreateAvailabilitySlot(&slot).Return(int64(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			schedule := mockService.NewMockSchedule(c)
+			test.mockBehaviour(schedule, test.inputSlot)
+
+			services := &service.Services{Schedule: schedule}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/availability/slot", handler.createAvailabilitySlot)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/availability/slot",
+				bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.trainerId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_getTrainerFreeSlots(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockSchedule, trainerId int64)
+
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		trainerId            int64
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			query:     "?from=2026-10-19&to=2026-10-20",
+			mockBehaviour: func(r *mockService.MockSchedule, trainerId int64) {
+				r.EXPECT().GetFreeSlots(trainerId, from, to).Return([]*entity.FreeSlot{
+					{StartsAt: time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC),
+						EndsAt: time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"slots":[{"starts_at":"2026-10-19T06:00:00Z","ends_at":"2026-10-19T07:00:00Z"}]}`,
+		},
+		{
+			name:                 "Invalid date",
+			trainerId:            1,
+			query:                "?from=19.10.2026",
+			mockBehaviour:        func(r *mockService.MockSchedule, trainerId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid from parameter, expected format is YYYY-MM-DD"}`,
+		},
+		{
+			name:                 "Too long range",
+			trainerId:            1,
+			query:                "?from=2026-10-19&to=2026-12-19",
+			mockBehaviour:        func(r *mockService.MockSchedule, trainerId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid date range"}`,
+		},
+		{
+			name:                 "Invalid trainer id",
+			trainerId:            -1,
+			mockBehaviour:        func(r *mockService.MockSchedule, trainerId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid id parameter"}`,
+		},
+		{
+			name:      "Internal error",
+			trainerId: 1,
+			query:     "?from=2026-10-19&to=2026-10-20",
+			mockBehaviour: func(r *mockService.MockSchedule, trainerId int64) {
+				r.EXPECT().GetFreeSlots(trainerId, from, to).Return(nil, errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			schedule := mockService.NewMockSchedule(c)
+			test.mockBehaviour(schedule, test.trainerId)
+
+			services := &service.Services{Schedule: schedule}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.GET("/trainer/:id/availability", handler.getTrainerFreeSlots)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet,
+				fmt.Sprintf("/trainer/%d/availability%s", test.trainerId, test.query), nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_bookSlot(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockSchedule, userId int64, input entity.BookingInput)
+
+	startsAt := time.Date(2026, 10, 20, 6, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		inputBody            string
+		input                entity.BookingInput
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    2,
+			inputBody: `{"trainer_id":1,"starts_at":"2026-10-20T06:00:00Z"}`,
+			input:     entity.BookingInput{TrainerId: 1, StartsAt: startsAt},
+			mockBehaviour: func(r *mockService.MockSchedule, userId int64, input entity.BookingInput) {
+				r.EXPECT().BookSlot(userId, &input).Return(int64(1), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"booking_id":1}`,
+		},
+		{
+			name:                 "Own slot",
+			userId:               1,
+			inputBody:            `{"trainer_id":1,"starts_at":"2026-10-20T06:00:00Z"}`,
+			mockBehaviour:        func(r *mockService.MockSchedule, userId int64, input entity.BookingInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"can't book own slot"}`,
+		},
+		{
+			name:      "Slot is not available",
+			userId:    2,
+			inputBody: `{"trainer_id":1,"starts_at":"2026-10-20T06:00:00Z"}`,
+			input:     entity.BookingInput{TrainerId: 1, StartsAt: startsAt},
+			mockBehaviour: func(r *mockService.MockSchedule, userId int64, input entity.BookingInput) {
+				r.EXPECT().BookSlot(userId, &input).
+					Return(int64(-1), errors.New("trainer is not available at provided time"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"trainer is not available at provided time"}`,
+		},
+		{
+			name:      "Internal error",
+			userId:    2,
+			inputBody: `{"trainer_id":1,"starts_at":"2026-10-20T06:00:00Z"}`,
+			input:     entity.BookingInput{TrainerId: 1, StartsAt: startsAt},
+			mockBehaviour: func(r *mockService.MockSchedule, userId int64, input entity.BookingInput) {
+				r.EXPECT().BookSlot(userId, &input).Return(int64(0), errors.New("internal error"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"error":"internal error"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			schedule := mockService.NewMockSchedule(c)
+			test.mockBehaviour(schedule, test.userId, test.input)
+
+			services := &service.Services{Schedule: schedule}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/booking", handler.bookSlot)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/booking", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_cancelBooking(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockSchedule, bookingId, userId int64)
+
+	table := []struct {
+		name               string
+		userId             int64
+		bookingId          int64
+		mockBehaviour      mockBehaviour
+		expectedStatusCode int
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			bookingId: 1,
+			mockBehaviour: func(r *mockService.MockSchedule, bookingId, userId int64) {
+				r.EXPECT().CancelBooking(bookingId, userId).Return(nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:      "Cutoff has passed",
+			userId:    1,
+			bookingId: 1,
+			mockBehaviour: func(r *mockService.MockSchedule, bookingId, userId int64) {
+				r.EXPECT().CancelBooking(bookingId, userId).
+					Return(errors.New("booking can be cancelled not later than 24h0m0s before start"))
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "Invalid userId",
+			userId:             -1,
+			bookingId:          1,
+			mockBehaviour:      func(r *mockService.MockSchedule, bookingId, userId int64) {},
+			expectedStatusCode: 500,
+		},
+		{
+			name:               "Invalid bookingId",
+			userId:             1,
+			bookingId:          -1,
+			mockBehaviour:      func(r *mockService.MockSchedule, bookingId, userId int64) {},
+			expectedStatusCode: 400,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			schedule := mockService.NewMockSchedule(c)
+			test.mockBehaviour(schedule, test.bookingId, test.userId)
+
+			services := &service.Services{Schedule: schedule}
+			handler := &Handler{services: services}
+
+			router := gin.New()
+			router.DELETE("/booking/:id", handler.cancelBooking)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/booking/%d", test.bookingId), nil)
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+		})
+	}
+}
@@ -195,9 +195,12 @@ func (h *Handler) getTrainerWorkoutsWithUser(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.Workout true "workout info"
+// @Param Idempotency-Key header string false "key which makes retries of the request safe"
 // @Success 200 {object} workoutIdResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 422 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /trainer/workout [post]
@@ -293,7 +296,7 @@ func (h *Handler) endPartnershipWithUser(c *gin.Context) {
 		return
 	}
 
-	pId, err := h.services.EndPartnershipWithUser(trainerId, userId)
+	pId, err := h.services.EndPartnershipWithUser(newActor(c, entity.ActorTrainer, trainerId), trainerId, userId)
 	if err != nil {
 		if pId == -1 {
 			newErrorResponse(c, http.StatusBadRequest, err)
@@ -333,7 +336,7 @@ func (h *Handler) acceptRequest(c *gin.Context) {
 		return
 	}
 
-	pId, err := h.services.AcceptRequest(trainerId, requestId)
+	pId, err := h.services.AcceptRequest(newActor(c, entity.ActorTrainer, trainerId), trainerId, requestId)
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
@@ -347,7 +350,7 @@ func (h *Handler) acceptRequest(c *gin.Context) {
 // @Summary Deny request
 // @Security ApiKeyAuth
 // @Tags trainer
-// @Description deny and delete request from user by provided request id
+// @Description deny request from user by provided request id, the partnership is ended by trainer
 // @ID deny-request
 // @Produce  json
 // @Success 200
@@ -368,7 +371,7 @@ func (h *Handler) denyRequest(c *gin.Context) {
 		return
 	}
 
-	err = h.services.DenyRequest(trainerId, requestId)
+	err = h.services.DenyRequest(newActor(c, entity.ActorTrainer, trainerId), trainerId, requestId)
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
@@ -383,6 +386,7 @@ func (h *Handler) denyRequest(c *gin.Context) {
 // @ID get-workout-trainer
 // @Produce  json
 // @Success 200 {object} entity.Workout
+// @Header 200 {string} ETag "version, send it in If-Match to change this version only"
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -405,6 +409,7 @@ func (h *Handler) getWorkoutByIdForTrainer(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
+	setETag(c, workout.Version)
 	c.JSON(http.StatusOK, workout)
 }
 
@@ -416,9 +421,12 @@ func (h *Handler) getWorkoutByIdForTrainer(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.UpdateWorkout true "update workout info"
+// @Param If-Match header string false "ETag of the version read"
 // @Success 200 {object} workoutIdResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 412 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /trainer/workout/:id [put]
@@ -435,23 +443,85 @@ func (h *Handler) updateWorkoutForTrainer(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
 	var input entity.UpdateWorkout
 	if err := c.ShouldBindJSON(&input); err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
 
-	err = h.services.FormatUpdateWorkout(&input, workoutId, userId)
+	version, err = h.services.FormatUpdateWorkout(&input, workoutId, userId, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
 
-	err = h.services.User.UpdateWorkout(workoutId, userId, &input)
+	err = h.services.User.UpdateWorkout(workoutId, userId, &input, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
+	setETag(c, version+1)
+	c.JSON(http.StatusOK, workoutIdResponse{
+		WorkoutId: workoutId,
+	})
+}
+
+// @Summary Patch workout
+// @Security ApiKeyAuth
+// @Description changes members present in JSON Merge Patch, null description clears it
+// @Tags trainer
+// @ID patch-workout-trainer
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param input body entity.WorkoutPatch true "merge patch of workout"
+// @Param If-Match header string false "ETag of the version read"
+// @Success 200 {object} workoutIdResponse
+// @Header 200 {string} ETag "version of patched workout"
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 412 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /trainer/workout/:id [patch]
+func (h *Handler) patchWorkoutForTrainer(c *gin.Context) {
+	workoutId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || workoutId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	userId, err := getId(c)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
+	var patch entity.WorkoutPatch
+	if err = bindMergePatch(c, &patch); err != nil {
+		newErrorResponse(c, patchErrorStatus(err), err)
+		return
+	}
+
+	version, err = h.services.PatchWorkout(workoutId, userId, &patch, version)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+	setETag(c, version)
 	c.JSON(http.StatusOK, workoutIdResponse{
 		WorkoutId: workoutId,
 	})
@@ -463,9 +533,11 @@ func (h *Handler) updateWorkoutForTrainer(c *gin.Context) {
 // @Tags trainer
 // @ID delete-workout-trainer
 // @Produce  json
+// @Param If-Match header string false "ETag of the version read"
 // @Success 200
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 412 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /trainer/workout/:id [delete]
@@ -480,9 +552,14 @@ func (h *Handler) deleteWorkoutForTrainer(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
 		return
 	}
-	err = h.services.DeleteWorkout(workoutId, userId)
+	version, err := ifMatch(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+	err = h.services.DeleteWorkout(workoutId, userId, version)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
 	c.Status(http.StatusOK)
@@ -674,7 +674,7 @@ func TestHandler_endPartnershipWithUser(t *testing.T) {
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithUser(trainerId, userId).Return(int64(1), nil)
+				r.EXPECT().EndPartnershipWithUser(testActor(entity.ActorTrainer, trainerId), trainerId, userId).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"partnership_id":1}`,
@@ -700,7 +700,7 @@ func TestHandler_endPartnershipWithUser(t *testing.T) {
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithUser(trainerId, userId).Return(int64(-1), errors.New("bad userId"))
+				r.EXPECT().EndPartnershipWithUser(testActor(entity.ActorTrainer, trainerId), trainerId, userId).Return(int64(-1), errors.New("bad userId"))
 			},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"bad userId"}`,
@@ -710,7 +710,7 @@ func TestHandler_endPartnershipWithUser(t *testing.T) {
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithUser(trainerId, userId).Return(int64(0), errors.New("internal error"))
+				r.EXPECT().EndPartnershipWithUser(testActor(entity.ActorTrainer, trainerId), trainerId, userId).Return(int64(0), errors.New("internal error"))
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"error":"internal error"}`,
@@ -758,7 +758,7 @@ func TestHandler_acceptRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, requestId int64) {
-				r.EXPECT().AcceptRequest(trainerId, requestId).Return(int64(1), nil)
+				r.EXPECT().AcceptRequest(testActor(entity.ActorTrainer, trainerId), trainerId, requestId).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"partnership_id":1}`,
@@ -784,7 +784,7 @@ func TestHandler_acceptRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, requestId int64) {
-				r.EXPECT().AcceptRequest(trainerId, requestId).Return(int64(0), errors.New("no request"))
+				r.EXPECT().AcceptRequest(testActor(entity.ActorTrainer, trainerId), trainerId, requestId).Return(int64(0), errors.New("no request"))
 			},
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"error":"no request"}`,
@@ -830,7 +830,7 @@ func TestHandler_denyRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, requestId int64) {
-				r.EXPECT().DenyRequest(trainerId, requestId).Return(nil)
+				r.EXPECT().DenyRequest(testActor(entity.ActorTrainer, trainerId), trainerId, requestId).Return(nil)
 			},
 			expectedStatusCode: 200,
 		},
@@ -853,7 +853,7 @@ func TestHandler_denyRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(r *mock_service.MockUser, trainerId, requestId int64) {
-				r.EXPECT().DenyRequest(trainerId, requestId).Return(errors.New("no request"))
+				r.EXPECT().DenyRequest(testActor(entity.ActorTrainer, trainerId), trainerId, requestId).Return(errors.New("no request"))
 			},
 			expectedStatusCode: 400,
 		},
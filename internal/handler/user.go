@@ -4,8 +4,10 @@ import (
 	"Fitness_REST_API/internal/entity"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 const (
@@ -19,6 +21,7 @@ const (
 // @ID get-user-info
 // @Produce  json
 // @Success 200 {object} entity.User
+// @Header 200 {string} ETag "version, send it in If-Match to change this version only"
 // @Failure 401 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
@@ -36,16 +39,156 @@ func (h *Handler) getUserInfo(c *gin.Context) {
 		return
 	}
 	user.PasswordHash = ""
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
+// @Summary Delete account
+// @Security ApiKeyAuth
+// @Tags user
+// @Description schedules account deletion after grace period, signing in cancels it.
+// @Description Afterwards personal data is scrubbed and email is freed, workouts are kept for trainer history.
+// @ID delete-account
+// @Accept  json
+// @Produce  json
+// @Param input body entity.AccountDeletionInput true "password confirmation"
+// @Success 200 {object} accountDeletionResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user [delete]
+func (h *Handler) deleteAccount(c *gin.Context) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.AccountDeletionInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	at, err := h.services.User.DeleteAccount(id, input.Password)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, accountDeletionResponse{
+		DeletionScheduledAt: at,
+	})
+}
+
+// @Summary Update profile
+// @Security ApiKeyAuth
+// @Tags user
+// @Description updates your name, surname, email and IANA time zone, empty fields are kept.
+// @Description Body sent as application/merge-patch+json is JSON Merge Patch of entity.ProfilePatch instead:
+// @Description absent members are kept, null time_zone resets it to UTC. Changed email has to be verified again.
+// @ID update-profile
+// @Accept  json,application/merge-patch+json
+// @Produce  json
+// @Param input body entity.ProfileUpdate true "profile info"
+// @Param If-Match header string false "ETag of the version read"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 412 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user [patch]
+func (h *Handler) updateProfile(c *gin.Context) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
+	var emailChanged bool
+	if c.ContentType() == mergePatchType {
+		var patch entity.ProfilePatch
+		if err = bindMergePatch(c, &patch); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		emailChanged, err = h.services.User.PatchProfile(id, &patch, version)
+	} else {
+		var input entity.ProfileUpdate
+		if err = c.ShouldBindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		emailChanged, err = h.services.User.UpdateProfile(id, &input, version)
+	}
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
+	if emailChanged {
+		if err = h.services.Account.SendVerification(id); err != nil {
+			logrus.Errorf("can't send verification mail to user %d: %s", id, err.Error())
+		}
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Change password
+// @Security ApiKeyAuth
+// @Tags user
+// @Description changes your password, other sessions are signed out and new token is returned
+// @ID change-password
+// @Accept  json
+// @Produce  json
+// @Param input body entity.PasswordChangeInput true "current and new password"
+// @Success 200 {object} signInResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/password [post]
+func (h *Handler) changePassword(c *gin.Context) {
+	id, err := getId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var input entity.PasswordChangeInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := h.services.User.ChangePassword(id, input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, signInResponse{
+		Token: token,
+	})
+}
+
 // @Summary Get all workouts
 // @Security ApiKeyAuth
 // @Tags user
-// @Description get information about your workouts
+// @Description get information about your workouts, date is YYYY-MM-DD calendar day in your time zone.
+// @Description Workout date is in UTC, local_date is the same moment in your time zone.
 // @ID get-user-workouts
 // @Produce  json
+// @Param date query string false "calendar day in your time zone"
 // @Success 200 {object} workoutsResponse
+// @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
@@ -56,7 +199,17 @@ func (h *Handler) getUserWorkouts(c *gin.Context) {
 		newErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
-	w, err := h.services.User.GetUserWorkouts(id)
+
+	var date time.Time
+	if param := c.Query("date"); param != "" {
+		date, err = time.Parse(dateLayout, param)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid date parameter, expected format is YYYY-MM-DD"))
+			return
+		}
+	}
+
+	w, err := h.services.User.GetUserWorkouts(id, date)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err)
 		return
@@ -73,6 +226,7 @@ func (h *Handler) getUserWorkouts(c *gin.Context) {
 // @ID get-workout-user
 // @Produce  json
 // @Success 200 {object} entity.Workout
+// @Header 200 {string} ETag "version, send it in If-Match to change this version only"
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -95,6 +249,7 @@ func (h *Handler) getWorkoutByIdForUser(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
+	setETag(c, workout.Version)
 	c.JSON(http.StatusOK, workout)
 }
 
@@ -183,9 +338,12 @@ func (h *Handler) getPartnerships(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.Workout true "workout info"
+// @Param Idempotency-Key header string false "key which makes retries of the request safe"
 // @Success 200 {object} workoutIdResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 422 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /user/workout [post]
@@ -221,9 +379,12 @@ func (h *Handler) createUserWorkout(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param input body entity.UpdateWorkout true "update workout info"
+// @Param If-Match header string false "ETag of the version read"
 // @Success 200 {object} workoutIdResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 412 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /user/workout/:id [put]
@@ -240,23 +401,85 @@ func (h *Handler) updateWorkoutForUser(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
 	var input entity.UpdateWorkout
 	if err := c.ShouldBindJSON(&input); err != nil {
 		newErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
 
-	err = h.services.FormatUpdateWorkout(&input, workoutId, userId)
+	version, err = h.services.FormatUpdateWorkout(&input, workoutId, userId, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
 
-	err = h.services.User.UpdateWorkout(workoutId, userId, &input)
+	err = h.services.User.UpdateWorkout(workoutId, userId, &input, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
+	setETag(c, version+1)
+	c.JSON(http.StatusOK, workoutIdResponse{
+		WorkoutId: workoutId,
+	})
+}
+
+// @Summary Patch workout
+// @Security ApiKeyAuth
+// @Description changes members present in JSON Merge Patch, null description clears it
+// @Tags user
+// @ID patch-workout-user
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param input body entity.WorkoutPatch true "merge patch of workout"
+// @Param If-Match header string false "ETag of the version read"
+// @Success 200 {object} workoutIdResponse
+// @Header 200 {string} ETag "version of patched workout"
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 412 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /user/workout/:id [patch]
+func (h *Handler) patchWorkoutForUser(c *gin.Context) {
+	workoutId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || workoutId < 1 {
+		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
+		return
+	}
+
+	userId, err := getId(c)
+	if err != nil || userId < 1 {
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+
+	var patch entity.WorkoutPatch
+	if err = bindMergePatch(c, &patch); err != nil {
+		newErrorResponse(c, patchErrorStatus(err), err)
+		return
+	}
+
+	version, err = h.services.PatchWorkout(workoutId, userId, &patch, version)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+	setETag(c, version)
 	c.JSON(http.StatusOK, workoutIdResponse{
 		WorkoutId: workoutId,
 	})
@@ -268,9 +491,11 @@ func (h *Handler) updateWorkoutForUser(c *gin.Context) {
 // @Tags user
 // @ID delete-workout-user
 // @Produce  json
+// @Param If-Match header string false "ETag of the version read"
 // @Success 200
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 412 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /user/workout/:id [delete]
@@ -285,9 +510,14 @@ func (h *Handler) deleteWorkoutForUser(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, ErrorInvalidIdParameter)
 		return
 	}
-	err = h.services.DeleteWorkout(workoutId, userId)
+	version, err := ifMatch(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err)
+		newErrorResponse(c, changeErrorStatus(c, err), err)
+		return
+	}
+	err = h.services.DeleteWorkout(workoutId, userId, version)
+	if err != nil {
+		newErrorResponse(c, changeErrorStatus(c, err), err)
 		return
 	}
 	c.Status(http.StatusOK)
@@ -299,9 +529,12 @@ func (h *Handler) deleteWorkoutForUser(c *gin.Context) {
 // @Tags user
 // @ID send-request
 // @Produce  json
+// @Param Idempotency-Key header string false "key which makes retries of the request safe"
 // @Success 200 {object} requestIdResponse
 // @Failure 400 {object} errorResponse
 // @Failure 401 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 422 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /user/partnership/trainer/:id [post]
@@ -357,7 +590,7 @@ func (h *Handler) endPartnershipWithTrainer(c *gin.Context) {
 		return
 	}
 
-	pId, err := h.services.EndPartnershipWithTrainer(trainerId, userId)
+	pId, err := h.services.EndPartnershipWithTrainer(newActor(c, entity.ActorUser, userId), trainerId, userId)
 	if err != nil {
 		if pId == -1 {
 			newErrorResponse(c, http.StatusBadRequest, err)
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHandler_getUserInfo(t *testing.T) {
@@ -86,12 +87,275 @@ func TestHandler_getUserInfo(t *testing.T) {
 	}
 }
 
+func TestHandler_deleteAccount(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockUser, userId int64)
+
+	at := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			inputBody: `{"password":"qwerty"}`,
+			mockBehaviour: func(r *mockService.MockUser, userId int64) {
+				r.EXPECT().DeleteAccount(userId, "qwerty").Return(at, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"deletion_scheduled_at":"2022-03-15T10:00:00Z"}`,
+		},
+		{
+			name:                 "No password",
+			userId:               1,
+			inputBody:            `{}`,
+			mockBehaviour:        func(r *mockService.MockUser, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'AccountDeletionInput.Password' Error:Field validation for 'Password' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid password",
+			userId:    1,
+			inputBody: `{"password":"wrong"}`,
+			mockBehaviour: func(r *mockService.MockUser, userId int64) {
+				r.EXPECT().DeleteAccount(userId, "wrong").Return(time.Time{}, errors.New("invalid password"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid password"}`,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockUser(c)
+			test.mockBehaviour(repo, test.userId)
+
+			services := &service.Services{User: repo}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.DELETE("/user", handler.deleteAccount)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/user", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_updateProfile(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockUser, a *mockService.MockAccount, userId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		inputBody            string
+		contentType          string
+		ifMatch              string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			inputBody: `{"name":"newName"}`,
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().UpdateProfile(userId, &entity.ProfileUpdate{Name: "newName"}, int64(0)).Return(false, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:      "Email is changed",
+			userId:    1,
+			inputBody: `{"email":"new@mail.com"}`,
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().UpdateProfile(userId, &entity.ProfileUpdate{Email: "new@mail.com"}, int64(0)).Return(true, nil)
+				a.EXPECT().SendVerification(userId).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:      "Role is ignored",
+			userId:    1,
+			inputBody: `{"surname":"newSurname", "role":"trainer"}`,
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().UpdateProfile(userId, &entity.ProfileUpdate{Surname: "newSurname"}, int64(0)).
+					Return(false, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:      "Email is reserved",
+			userId:    1,
+			inputBody: `{"email":"taken@mail.com"}`,
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().UpdateProfile(userId, &entity.ProfileUpdate{Email: "taken@mail.com"}, int64(0)).
+					Return(false, errors.New("provided email has already been reserved"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"provided email has already been reserved"}`,
+		},
+		{
+			name:      "Stale If-Match",
+			userId:    1,
+			inputBody: `{"name":"newName"}`,
+			ifMatch:   `"4"`,
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().UpdateProfile(userId, &entity.ProfileUpdate{Name: "newName"}, int64(4)).
+					Return(false, entity.ErrVersionMismatch)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+		{
+			name:        "Merge patch",
+			userId:      1,
+			inputBody:   `{"name":"newName","time_zone":null}`,
+			contentType: "application/merge-patch+json",
+			mockBehaviour: func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {
+				r.EXPECT().PatchProfile(userId, &entity.ProfilePatch{
+					Name:     entity.Patch[string]{Set: true, Value: "newName"},
+					TimeZone: entity.Patch[string]{Set: true, Null: true},
+				}, int64(0)).Return(false, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "Unknown member of merge patch",
+			userId:               1,
+			inputBody:            `{"role":"trainer"}`,
+			contentType:          "application/merge-patch+json",
+			mockBehaviour:        func(r *mockService.MockUser, a *mockService.MockAccount, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"json: unknown field \"role\""}`,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockUser(c)
+			account := mockService.NewMockAccount(c)
+			test.mockBehaviour(repo, account, test.userId)
+
+			services := &service.Services{User: repo, Account: account}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.PATCH("/user", handler.updateProfile)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/user", bytes.NewBufferString(test.inputBody))
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHandler_changePassword(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockUser, userId int64)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		inputBody            string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			inputBody: `{"current_password":"old", "new_password":"new"}`,
+			mockBehaviour: func(r *mockService.MockUser, userId int64) {
+				r.EXPECT().ChangePassword(userId, "old", "new").Return("token", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token"}`,
+		},
+		{
+			name:                 "No current password",
+			userId:               1,
+			inputBody:            `{"new_password":"new"}`,
+			mockBehaviour:        func(r *mockService.MockUser, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"Key: 'PasswordChangeInput.CurrentPassword' Error:Field validation for 'CurrentPassword' failed on the 'required' tag"}`, //nolint
+		},
+		{
+			name:      "Invalid password",
+			userId:    1,
+			inputBody: `{"current_password":"wrong", "new_password":"new"}`,
+			mockBehaviour: func(r *mockService.MockUser, userId int64) {
+				r.EXPECT().ChangePassword(userId, "wrong", "new").Return("", errors.New("invalid password"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid password"}`,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockUser(c)
+			test.mockBehaviour(repo, test.userId)
+
+			services := &service.Services{User: repo}
+			handler := &Handler{services: services}
+
+			r := gin.New()
+			r.POST("/user/password", handler.changePassword)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/user/password", bytes.NewBufferString(test.inputBody))
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			r.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
 func TestHandler_getUserWorkouts(t *testing.T) {
 	type mockBehaviour func(r *mockService.MockUser, userId int64)
 
 	table := []struct {
 		name                 string
 		userId               int64
+		query                string
 		workouts             []*entity.Workout
 		mockBehaviour        mockBehaviour
 		expectedStatusCode   int
@@ -101,7 +365,7 @@ func TestHandler_getUserWorkouts(t *testing.T) {
 			name:   "Ok",
 			userId: 1,
 			mockBehaviour: func(r *mockService.MockUser, userId int64) {
-				r.EXPECT().GetUserWorkouts(userId).Return(
+				r.EXPECT().GetUserWorkouts(userId, time.Time{}).Return(
 					[]*entity.Workout{
 						{Title: "test1", Description: "test1"},
 						{Title: "test2"},
@@ -110,11 +374,33 @@ func TestHandler_getUserWorkouts(t *testing.T) {
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"workouts":[{"id":0,"title":"test1","user_id":0,"trainer_id":{"Int64":0,"Valid":false},"description":"test1","date":"0001-01-01T00:00:00Z"},{"id":0,"title":"test2","user_id":0,"trainer_id":{"Int64":0,"Valid":false},"date":"0001-01-01T00:00:00Z"}]}`, //nolint
 		},
+		{
+			name:   "Ok by date",
+			userId: 1,
+			query:  "?date=2026-10-25",
+			mockBehaviour: func(r *mockService.MockUser, userId int64) {
+				local := time.Date(2026, 10, 25, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+				r.EXPECT().GetUserWorkouts(userId, time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)).Return(
+					[]*entity.Workout{
+						{Title: "test1", Date: local.UTC(), LocalDate: &local, TimeZone: "Europe/Berlin"},
+					}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"workouts":[{"id":0,"title":"test1","user_id":0,"trainer_id":{"Int64":0,"Valid":false},"date":"2026-10-25T08:00:00Z","local_date":"2026-10-25T09:00:00+01:00","time_zone":"Europe/Berlin"}]}`, //nolint
+		},
+		{
+			name:                 "Invalid date",
+			userId:               1,
+			query:                "?date=25.10.2026",
+			mockBehaviour:        func(r *mockService.MockUser, userId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid date parameter, expected format is YYYY-MM-DD"}`,
+		},
 		{
 			name:   "Empty workout",
 			userId: 1,
 			mockBehaviour: func(r *mockService.MockUser, userId int64) {
-				r.EXPECT().GetUserWorkouts(userId).Return(
+				r.EXPECT().GetUserWorkouts(userId, time.Time{}).Return(
 					[]*entity.Workout{}, nil)
 			},
 			expectedStatusCode:   200,
@@ -131,7 +417,7 @@ func TestHandler_getUserWorkouts(t *testing.T) {
 			name:   "Internal error",
 			userId: 1,
 			mockBehaviour: func(r *mockService.MockUser, userId int64) {
-				r.EXPECT().GetUserWorkouts(userId).Return(nil, errors.New("internal error"))
+				r.EXPECT().GetUserWorkouts(userId, time.Time{}).Return(nil, errors.New("internal error"))
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"error":"internal error"}`,
@@ -152,7 +438,7 @@ func TestHandler_getUserWorkouts(t *testing.T) {
 			router.GET("/workout", handler.getUserWorkouts)
 
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest(http.MethodGet, "/workout", nil)
+			req := httptest.NewRequest(http.MethodGet, "/workout"+test.query, nil)
 			ctx, _ := gin.CreateTestContext(w)
 			ctx.Set(userIdCtx, test.userId)
 			req = req.WithContext(ctx)
@@ -175,16 +461,19 @@ func TestHandler_getWorkoutById(t *testing.T) {
 		mockBehaviour        mockBehaviour
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedETag         string
 	}{
 		{
 			name:      "Ok",
 			userId:    1,
 			workoutId: 1,
 			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64) {
-				r.EXPECT().GetWorkoutById(workoutId, userId).Return(&entity.Workout{Title: "test", Description: "test"}, nil)
+				r.EXPECT().GetWorkoutById(workoutId, userId).
+					Return(&entity.Workout{Title: "test", Description: "test", Version: 2}, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"id":0,"title":"test","user_id":0,"trainer_id":{"Int64":0,"Valid":false},"description":"test","date":"0001-01-01T00:00:00Z"}`, //nolint
+			expectedResponseBody: `{"id":0,"title":"test","user_id":0,"trainer_id":{"Int64":0,"Valid":false},"description":"test","date":"0001-01-01T00:00:00Z","version":2}`, //nolint
+			expectedETag:         `"2"`,
 		},
 		{
 			name:                 "Invalid WorkoutId",
@@ -237,6 +526,7 @@ func TestHandler_getWorkoutById(t *testing.T) {
 
 			assert.Equal(t, w.Code, test.expectedStatusCode)
 			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+			assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
 		})
 	}
 }
@@ -527,6 +817,7 @@ func TestHandler_updateWorkout(t *testing.T) {
 		workoutId            int64
 		inputBody            string
 		updateWorkout        entity.UpdateWorkout
+		ifMatch              string
 		mockBehaviour        mockBehaviour
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -538,8 +829,8 @@ func TestHandler_updateWorkout(t *testing.T) {
 			inputBody:     `{"title":"newTitle", "description":"newDesc"}`,
 			updateWorkout: entity.UpdateWorkout{Title: "newTitle", Description: "newDesc"},
 			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
-				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId).Return(nil)
-				r.EXPECT().UpdateWorkout(workoutId, userId, &input).Return(nil)
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(0)).Return(int64(1), nil)
+				r.EXPECT().UpdateWorkout(workoutId, userId, &input, int64(1)).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"workout_id":1}`,
@@ -571,12 +862,100 @@ func TestHandler_updateWorkout(t *testing.T) {
 			inputBody:     `{}`,
 			updateWorkout: entity.UpdateWorkout{},
 			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
-				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId).Return(nil)
-				r.EXPECT().UpdateWorkout(workoutId, userId, &input).Return(nil)
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(0)).Return(int64(1), nil)
+				r.EXPECT().UpdateWorkout(workoutId, userId, &input, int64(1)).Return(nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"workout_id":1}`,
 		},
+		{
+			name:          "Matching If-Match",
+			userId:        1,
+			workoutId:     1,
+			inputBody:     `{"title":"newTitle"}`,
+			updateWorkout: entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:       `"3"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(3)).Return(int64(3), nil)
+				r.EXPECT().UpdateWorkout(workoutId, userId, &input, int64(3)).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"workout_id":1}`,
+		},
+		{
+			name:          "Stale If-Match",
+			userId:        1,
+			workoutId:     1,
+			inputBody:     `{"title":"newTitle"}`,
+			updateWorkout: entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:       `"2"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(2)).
+					Return(int64(0), entity.ErrVersionMismatch)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+		{
+			name:          "Changed after merge",
+			userId:        1,
+			workoutId:     1,
+			inputBody:     `{"title":"newTitle"}`,
+			updateWorkout: entity.UpdateWorkout{Title: "newTitle"},
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(0)).Return(int64(1), nil)
+				r.EXPECT().UpdateWorkout(workoutId, userId, &input, int64(1)).Return(entity.ErrVersionMismatch)
+			},
+			expectedStatusCode:   409,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+		{
+			name:          "If-Match list",
+			userId:        1,
+			workoutId:     1,
+			inputBody:     `{"title":"newTitle"}`,
+			updateWorkout: entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:       `W/"2", "3", "x"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {
+				r.EXPECT().FormatUpdateWorkout(&input, workoutId, userId, int64(3)).Return(int64(3), nil)
+				r.EXPECT().UpdateWorkout(workoutId, userId, &input, int64(3)).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"workout_id":1}`,
+		},
+		{
+			name:                 "Weak If-Match",
+			userId:               1,
+			workoutId:            1,
+			inputBody:            `{"title":"newTitle"}`,
+			updateWorkout:        entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:              `W/"2"`,
+			mockBehaviour:        func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+		{
+			name:                 "If-Match list of versions",
+			userId:               1,
+			workoutId:            1,
+			inputBody:            `{"title":"newTitle"}`,
+			updateWorkout:        entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:              `"2", "3"`,
+			mockBehaviour:        func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+		{
+			name:                 "Invalid If-Match",
+			userId:               1,
+			workoutId:            1,
+			inputBody:            `{"title":"newTitle"}`,
+			updateWorkout:        entity.UpdateWorkout{Title: "newTitle"},
+			ifMatch:              `2`,
+			mockBehaviour:        func(r *mockService.MockUser, workoutId, userId int64, input entity.UpdateWorkout) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"invalid If-Match header"}`,
+		},
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
@@ -595,6 +974,9 @@ func TestHandler_updateWorkout(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/workout/%d", test.workoutId),
 				bytes.NewBufferString(test.inputBody))
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
 			ctx, _ := gin.CreateTestContext(w)
 			ctx.Set(userIdCtx, test.userId)
 			req = req.WithContext(ctx)
@@ -607,6 +989,138 @@ func TestHandler_updateWorkout(t *testing.T) {
 	}
 }
 
+func TestHandler_patchWorkout(t *testing.T) {
+	type mockBehaviour func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch)
+
+	table := []struct {
+		name                 string
+		userId               int64
+		workoutId            int64
+		inputBody            string
+		contentType          string
+		patch                entity.WorkoutPatch
+		ifMatch              string
+		mockBehaviour        mockBehaviour
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:        "Ok",
+			userId:      1,
+			workoutId:   1,
+			inputBody:   `{"title":"newTitle","description":null}`,
+			contentType: "application/merge-patch+json",
+			patch: entity.WorkoutPatch{
+				Title:       entity.Patch[string]{Set: true, Value: "newTitle"},
+				Description: entity.Patch[string]{Set: true, Null: true},
+			},
+			ifMatch: `"3"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {
+				r.EXPECT().PatchWorkout(workoutId, userId, &patch, int64(3)).Return(int64(4), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"workout_id":1}`,
+			expectedETag:         `"4"`,
+		},
+		{
+			name:        "Plain JSON",
+			userId:      1,
+			workoutId:   1,
+			inputBody:   `{"description":""}`,
+			contentType: "application/json",
+			patch:       entity.WorkoutPatch{Description: entity.Patch[string]{Set: true}},
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {
+				r.EXPECT().PatchWorkout(workoutId, userId, &patch, int64(0)).Return(int64(2), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"workout_id":1}`,
+			expectedETag:         `"2"`,
+		},
+		{
+			name:                 "Unsupported media type",
+			userId:               1,
+			workoutId:            1,
+			inputBody:            `[{"op":"remove","path":"/description"}]`,
+			contentType:          "application/json-patch+json",
+			mockBehaviour:        func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {},
+			expectedStatusCode:   415,
+			expectedResponseBody: `{"error":"patch has to be application/merge-patch+json"}`,
+		},
+		{
+			name:                 "Unknown member",
+			userId:               1,
+			workoutId:            1,
+			inputBody:            `{"user_id":2}`,
+			contentType:          "application/merge-patch+json",
+			mockBehaviour:        func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"json: unknown field \"user_id\""}`,
+		},
+		{
+			name:        "Invalid member",
+			userId:      1,
+			workoutId:   1,
+			inputBody:   `{"title":null}`,
+			contentType: "application/merge-patch+json",
+			patch:       entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Null: true}},
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {
+				r.EXPECT().PatchWorkout(workoutId, userId, &patch, int64(0)).
+					Return(int64(0), errors.New("title can't be empty"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"error":"title can't be empty"}`,
+		},
+		{
+			name:        "Stale If-Match",
+			userId:      1,
+			workoutId:   1,
+			inputBody:   `{"title":"newTitle"}`,
+			contentType: "application/merge-patch+json",
+			patch:       entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Value: "newTitle"}},
+			ifMatch:     `"2"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64, patch entity.WorkoutPatch) {
+				r.EXPECT().PatchWorkout(workoutId, userId, &patch, int64(2)).
+					Return(int64(0), entity.ErrVersionMismatch)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"error":"resource has been changed, read it again"}`,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mockService.NewMockUser(c)
+			test.mockBehaviour(repo, test.workoutId, test.userId, test.patch)
+
+			services := &service.Services{User: repo}
+			handler := &Handler{services: services}
+
+			router := gin.New()
+			router.PATCH("/workout/:id", handler.patchWorkoutForUser)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/workout/%d", test.workoutId),
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Set(userIdCtx, test.userId)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+			assert.Equal(t, w.Header().Get("ETag"), test.expectedETag)
+		})
+	}
+}
+
 func TestHandler_sendRequestToTrainer(t *testing.T) {
 	type mockBehaviour func(r *mockService.MockUser, trainerId, userId int64)
 	table := []struct {
@@ -698,6 +1212,7 @@ func TestHandler_deleteWorkout(t *testing.T) {
 		name               string
 		userId             int64
 		workoutId          int64
+		ifMatch            string
 		mockBehaviour      mockBehaviour
 		expectedStatusCode int
 	}{
@@ -706,10 +1221,20 @@ func TestHandler_deleteWorkout(t *testing.T) {
 			userId:    1,
 			workoutId: 1,
 			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64) {
-				r.EXPECT().DeleteWorkout(workoutId, userId).Return(nil)
+				r.EXPECT().DeleteWorkout(workoutId, userId, int64(0)).Return(nil)
 			},
 			expectedStatusCode: 200,
 		},
+		{
+			name:      "Stale If-Match",
+			userId:    1,
+			workoutId: 1,
+			ifMatch:   `"2"`,
+			mockBehaviour: func(r *mockService.MockUser, workoutId, userId int64) {
+				r.EXPECT().DeleteWorkout(workoutId, userId, int64(2)).Return(entity.ErrVersionMismatch)
+			},
+			expectedStatusCode: 412,
+		},
 		{
 			name:               "Invalid userId",
 			userId:             -1,
@@ -741,6 +1266,9 @@ func TestHandler_deleteWorkout(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/workout/%d", test.workoutId), nil)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
 			ctx, _ := gin.CreateTestContext(w)
 			ctx.Set(userIdCtx, test.userId)
 			req = req.WithContext(ctx)
@@ -767,7 +1295,7 @@ func TestHandler_endPartnershipWithTrainer(t *testing.T) {
 			trainerId: 1,
 			userId:    1,
 			mockBehaviour: func(r *mockService.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithTrainer(trainerId, userId).Return(int64(1), nil)
+				r.EXPECT().EndPartnershipWithTrainer(testActor(entity.ActorUser, userId), trainerId, userId).Return(int64(1), nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"partnership_id":1}`,
@@ -793,7 +1321,7 @@ func TestHandler_endPartnershipWithTrainer(t *testing.T) {
 			trainerId: 1,
 			userId:    1,
 			mockBehaviour: func(r *mockService.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithTrainer(trainerId, userId).
+				r.EXPECT().EndPartnershipWithTrainer(testActor(entity.ActorUser, userId), trainerId, userId).
 					Return(int64(-1), errors.New("no partnership to end"))
 			},
 			expectedStatusCode:   400,
@@ -804,7 +1332,7 @@ func TestHandler_endPartnershipWithTrainer(t *testing.T) {
 			trainerId: 1,
 			userId:    1,
 			mockBehaviour: func(r *mockService.MockUser, trainerId, userId int64) {
-				r.EXPECT().EndPartnershipWithTrainer(trainerId, userId).
+				r.EXPECT().EndPartnershipWithTrainer(testActor(entity.ActorUser, userId), trainerId, userId).
 					Return(int64(0), errors.New("internal error"))
 			},
 			expectedStatusCode:   500,
@@ -0,0 +1,21 @@
+package idempotency
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"time"
+)
+
+// Store keeps responses of requests sent with Idempotency-Key. MemoryStore works within a single process,
+// postgres backed store shares keys between replicas.
+type Store interface {
+	// Reserve saves record for its key, records created before expiredBefore and records in progress
+	// locked until before record is created are replaced. It returns nil when key is reserved, otherwise
+	// the record already stored for key.
+	Reserve(record *entity.IdempotencyRecord, expiredBefore time.Time) (*entity.IdempotencyRecord, error)
+	// Complete saves response of key reserved at createdAt. Reservation taken over by another request
+	// after its lease is left as is.
+	Complete(key string, createdAt time.Time, status int, contentType string, body []byte) error
+	// Release forgets key reserved at createdAt which is still in progress, so that request can be retried.
+	Release(key string, createdAt time.Time) error
+	Purge(createdBefore time.Time) (int64, error)
+}
@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]entity.IdempotencyRecord)}
+}
+
+func (s *MemoryStore) Reserve(record *entity.IdempotencyRecord,
+	expiredBefore time.Time) (*entity.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[record.Key]
+	if ok && !r.CreatedAt.Before(expiredBefore) && (r.Status != 0 || !r.LockedUntil.Before(record.CreatedAt)) {
+		return &r, nil
+	}
+	s.records[record.Key] = *record
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(key string, createdAt time.Time, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[key]
+	if !ok || r.Status != 0 || !r.CreatedAt.Equal(createdAt) {
+		return nil
+	}
+	r.Status, r.ContentType, r.Body = status, contentType, body
+	s.records[key] = r
+	return nil
+}
+
+func (s *MemoryStore) Release(key string, createdAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok && r.Status == 0 && r.CreatedAt.Equal(createdAt) {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Purge(createdBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, r := range s.records {
+		if r.CreatedAt.Before(createdBefore) {
+			delete(s.records, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+	record := &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "a", CreatedAt: now,
+		LockedUntil: now.Add(time.Minute)}
+
+	r, err := s.Reserve(record, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	r, err = s.Reserve(&entity.IdempotencyRecord{Key: "1:key", Fingerprint: "b", CreatedAt: now},
+		now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", r.Fingerprint)
+	assert.Equal(t, 0, r.Status)
+
+	assert.NoError(t, s.Complete("1:key", now, 200, "application/json", []byte(`{"id":1}`)))
+	r, _ = s.Reserve(record, now.Add(-time.Hour))
+	assert.Equal(t, 200, r.Status)
+	assert.Equal(t, []byte(`{"id":1}`), r.Body)
+
+	assert.NoError(t, s.Release("1:key", now))
+	r, _ = s.Reserve(record, now.Add(-time.Hour))
+	assert.NotNil(t, r)
+
+	r, err = s.Reserve(&entity.IdempotencyRecord{Key: "1:key", Fingerprint: "b", CreatedAt: now.Add(2 * time.Hour)},
+		now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	_, _ = s.Reserve(&entity.IdempotencyRecord{Key: "2:key", CreatedAt: now}, now)
+	assert.NoError(t, s.Release("2:key", now))
+	r, _ = s.Reserve(&entity.IdempotencyRecord{Key: "2:key", CreatedAt: now}, now)
+	assert.Nil(t, r)
+
+	_, _ = s.Reserve(&entity.IdempotencyRecord{Key: "3:key", CreatedAt: now, LockedUntil: now.Add(time.Minute)},
+		now.Add(-time.Hour))
+	r, _ = s.Reserve(&entity.IdempotencyRecord{Key: "3:key", CreatedAt: now.Add(30 * time.Second)}, now.Add(-time.Hour))
+	assert.NotNil(t, r)
+	r, _ = s.Reserve(&entity.IdempotencyRecord{Key: "3:key", CreatedAt: now.Add(2 * time.Minute),
+		LockedUntil: now.Add(3 * time.Minute)}, now.Add(-time.Hour))
+	assert.Nil(t, r)
+	// request which has lost its reservation neither completes nor releases the new one
+	assert.NoError(t, s.Complete("3:key", now, 201, "application/json", []byte(`{"id":2}`)))
+	assert.NoError(t, s.Release("3:key", now))
+	r, _ = s.Reserve(&entity.IdempotencyRecord{Key: "3:key", CreatedAt: now.Add(150 * time.Second)},
+		now.Add(-time.Hour))
+	assert.Equal(t, 0, r.Status)
+	assert.True(t, now.Add(2*time.Minute).Equal(r.CreatedAt))
+
+	purged, err := s.Purge(now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// PurgeFunc removes records which became stale before provided time and returns their count.
+type PurgeFunc func(before time.Time) (int64, error)
+
+// PurgeJob periodically removes records which are older than retention period.
+type PurgeJob struct {
+	name      string
+	purge     PurgeFunc
+	interval  time.Duration
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewPurgeJob(name string, purge PurgeFunc, interval, retention time.Duration) *PurgeJob {
+	return &PurgeJob{name: name, purge: purge, interval: interval, retention: retention, now: time.Now}
+}
+
+// Run purges records right away and then on every interval until ctx is done.
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.runOnce()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) runOnce() {
+	purged, err := j.purge(j.now().Add(-j.retention))
+	if err != nil {
+		logrus.Errorf("error due purging %s: %s", j.name, err.Error())
+		return
+	}
+	if purged > 0 {
+		logrus.Infof("purged %d %s", purged, j.name)
+	}
+}
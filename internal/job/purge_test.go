@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type purgerStub struct {
+	mu    sync.Mutex
+	calls []time.Time
+	err   error
+}
+
+func (p *purgerStub) purge(deletedBefore time.Time) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, deletedBefore)
+	return 1, p.err
+}
+
+func (p *purgerStub) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+func TestPurgeJob_runOnce(t *testing.T) {
+	now := time.Date(2022, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name string
+		err  error
+	}{
+		{name: "Ok"},
+		{name: "Error", err: errors.New("internal error")},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			purger := &purgerStub{err: test.err}
+			j := NewPurgeJob("test", purger.purge, time.Hour, 30*24*time.Hour)
+			j.now = func() time.Time { return now }
+
+			j.runOnce()
+			assert.Equal(t, []time.Time{time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)}, purger.calls)
+		})
+	}
+}
+
+func TestPurgeJob_Run(t *testing.T) {
+	purger := &purgerStub{}
+	j := NewPurgeJob("test", purger.purge, 10*time.Millisecond, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		j.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return purger.count() >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+}
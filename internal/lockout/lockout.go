@@ -0,0 +1,67 @@
+package lockout
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"time"
+)
+
+// Store keeps failed sign in attempts. MemoryStore works within a single process,
+// postgres backed store shares attempts between replicas.
+type Store interface {
+	// Attempt counts attempt for key as failed unless policy delays it, then only wait is returned.
+	// Check and count are one step, so concurrent attempts can't exceed the policy. Failures older
+	// than policy window are forgotten.
+	Attempt(key string, now time.Time, policy Policy) (*entity.SignInAttempts, time.Duration, error)
+	// Refund takes back attempt counted by Attempt which turned out not to fail.
+	Refund(key string) error
+	// Get returns attempts for key or nil if there were no failures.
+	Get(key string) (*entity.SignInAttempts, error)
+	Reset(key string) error
+	Purge(lastFailureBefore time.Time) (int64, error)
+}
+
+// Policy defines exponential backoff after free attempts and lockout after MaxFailures.
+type Policy struct {
+	FreeAttempts    int
+	MaxFailures     int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// Wait returns how long next attempt has to be delayed and whether key is locked out.
+func (p Policy) Wait(a *entity.SignInAttempts, now time.Time) (time.Duration, bool) {
+	if a == nil || a.Failures <= p.FreeAttempts {
+		return 0, false
+	}
+	if now.Sub(a.LastFailure) >= p.Window {
+		return 0, false
+	}
+
+	if p.MaxFailures > 0 && a.Failures >= p.MaxFailures {
+		wait := a.LastFailure.Add(p.LockoutDuration).Sub(now)
+		return positive(wait), wait > 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < a.Failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return positive(a.LastFailure.Add(delay).Sub(now)), false
+}
+
+// LocksOut reports whether failure registered in attempts has caused lockout.
+func (p Policy) LocksOut(a *entity.SignInAttempts) bool {
+	return p.MaxFailures > 0 && a.Failures >= p.MaxFailures
+}
+
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,146 @@
+package lockout
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	FreeAttempts:    3,
+	MaxFailures:     6,
+	BaseDelay:       time.Second,
+	MaxDelay:        3 * time.Second,
+	LockoutDuration: 15 * time.Minute,
+	Window:          24 * time.Hour,
+}
+
+func TestPolicy_Wait(t *testing.T) {
+	now := time.Date(2022, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name         string
+		attempts     *entity.SignInAttempts
+		expectedWait time.Duration
+		expectedLock bool
+	}{
+		{
+			name:     "No failures",
+			attempts: nil,
+		},
+		{
+			name:     "Free attempts",
+			attempts: &entity.SignInAttempts{Failures: 3, LastFailure: now},
+		},
+		{
+			name:         "First backoff",
+			attempts:     &entity.SignInAttempts{Failures: 4, LastFailure: now},
+			expectedWait: time.Second,
+		},
+		{
+			name:         "Backoff doubles",
+			attempts:     &entity.SignInAttempts{Failures: 5, LastFailure: now.Add(-500 * time.Millisecond)},
+			expectedWait: 1500 * time.Millisecond,
+		},
+		{
+			name:     "Backoff is over",
+			attempts: &entity.SignInAttempts{Failures: 5, LastFailure: now.Add(-time.Minute)},
+		},
+		{
+			name:         "Locked out",
+			attempts:     &entity.SignInAttempts{Failures: 6, LastFailure: now.Add(-5 * time.Minute)},
+			expectedWait: 10 * time.Minute,
+			expectedLock: true,
+		},
+		{
+			name:     "Lockout is over",
+			attempts: &entity.SignInAttempts{Failures: 8, LastFailure: now.Add(-time.Hour)},
+		},
+		{
+			name:     "Failures are forgotten",
+			attempts: &entity.SignInAttempts{Failures: 40, LastFailure: now.Add(-25 * time.Hour)},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			wait, locked := testPolicy.Wait(test.attempts, now)
+			assert.Equal(t, test.expectedWait, wait)
+			assert.Equal(t, test.expectedLock, locked)
+		})
+	}
+}
+
+func TestPolicy_WaitIsCapped(t *testing.T) {
+	p := testPolicy
+	p.MaxFailures = 0
+	now := time.Now()
+
+	wait, locked := p.Wait(&entity.SignInAttempts{Failures: 50, LastFailure: now}, now)
+	assert.Equal(t, p.MaxDelay, wait)
+	assert.False(t, locked)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+	policy := testPolicy
+	policy.Window = time.Hour
+
+	a, err := s.Get("user:test")
+	assert.NoError(t, err)
+	assert.Nil(t, a)
+
+	for i := 1; i <= 4; i++ {
+		a, wait, err := s.Attempt("user:test", now, policy)
+		assert.NoError(t, err)
+		assert.Zero(t, wait)
+		assert.Equal(t, i, a.Failures)
+	}
+
+	// the fifth attempt has to wait for backoff and isn't counted
+	a, wait, err := s.Attempt("user:test", now, policy)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, wait)
+	assert.Equal(t, 4, a.Failures)
+
+	assert.NoError(t, s.Refund("user:test"))
+	a, _ = s.Get("user:test")
+	assert.Equal(t, 3, a.Failures)
+
+	a, _, err = s.Attempt("user:test", now.Add(2*time.Hour), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, a.Failures)
+
+	_, _, _ = s.Attempt("ip:192.0.2.1", now, policy)
+	purged, err := s.Purge(now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	assert.NoError(t, s.Reset("user:test"))
+	a, _ = s.Get("user:test")
+	assert.Nil(t, a)
+}
+
+func TestMemoryStore_ConcurrentAttempts(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var allowed int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, wait, err := s.Attempt("user:test", now, testPolicy); err == nil && wait == 0 {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(testPolicy.FreeAttempts+1), allowed)
+}
@@ -0,0 +1,80 @@
+package lockout
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]entity.SignInAttempts
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attempts: make(map[string]entity.SignInAttempts)}
+}
+
+func (s *MemoryStore) Attempt(key string, now time.Time, policy Policy) (*entity.SignInAttempts, time.Duration,
+	error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if ok {
+		if wait, _ := policy.Wait(&a, now); wait > 0 {
+			return &a, wait, nil
+		}
+	}
+	if !ok || a.LastFailure.Before(now.Add(-policy.Window)) {
+		a = entity.SignInAttempts{Key: key}
+	}
+	a.Failures++
+	a.LastFailure = now
+	s.attempts[key] = a
+	return &a, 0, nil
+}
+
+func (s *MemoryStore) Refund(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.attempts[key]; ok && a.Failures > 0 {
+		a.Failures--
+		s.attempts[key] = a
+	}
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) (*entity.SignInAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+func (s *MemoryStore) Purge(lastFailureBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, a := range s.attempts {
+		if a.LastFailure.Before(lastFailureBefore) {
+			delete(s.attempts, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
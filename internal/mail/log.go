@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"io"
+	"sync"
+)
+
+// LogMailer writes messages to writer instead of delivering them, it is meant for local development and tests.
+type LogMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewLogMailer(w io.Writer, from string) *LogMailer {
+	return &LogMailer{w: w, from: from}
+}
+
+func (m *LogMailer) Send(msg *Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.w.Write(format(m.from, msg)); err != nil {
+		return err
+	}
+	_, err := m.w.Write([]byte("\r\n.\r\n"))
+	return err
+}
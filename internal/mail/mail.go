@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"Fitness_REST_API/internal/config"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	TransportSMTP = "smtp"
+	TransportLog  = "log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages to users, implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewMailer creates mailer for transport from config, log transport writes to stdout
+// unless file is set.
+func NewMailer(cfg *config.Config) (Mailer, error) {
+	switch cfg.Transport {
+	case TransportSMTP:
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.From), nil
+	case TransportLog, "":
+		if cfg.LogPath == "" {
+			return NewLogMailer(os.Stdout, cfg.From), nil
+		}
+		f, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return NewLogMailer(f, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport: %s", cfg.Transport)
+	}
+}
+
+func validate(msg *Message) error {
+	if msg.To == "" {
+		return errors.New("empty recipient")
+	}
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.New("invalid mail header")
+	}
+	return nil
+}
+
+func format(from string, msg *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
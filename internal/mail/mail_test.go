@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/smtp"
+	"testing"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLogMailer(&buf, "no-reply@test")
+
+	err := m.Send(&Message{To: "user@test", Subject: "Hello", Body: "line1\nline2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "From: no-reply@test\r\nTo: user@test\r\nSubject: Hello\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n\r\nline1\r\nline2\r\n.\r\n", buf.String())
+}
+
+func TestLogMailer_SendInvalidHeader(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLogMailer(&buf, "no-reply@test")
+
+	table := []*Message{
+		{To: "", Subject: "Hello"},
+		{To: "user@test\r\nBcc: other@test", Subject: "Hello"},
+		{To: "user@test", Subject: "Hello\nBcc: other@test"},
+	}
+	for _, msg := range table {
+		assert.Error(t, m.Send(msg))
+	}
+	assert.Equal(t, 0, buf.Len())
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m := NewSMTPMailer("smtp.test", "587", "user", "secret", "no-reply@test")
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo = addr, from, to
+		assert.NotNil(t, a)
+		assert.Contains(t, string(msg), "Subject: Hello\r\n")
+		return nil
+	}
+
+	assert.NoError(t, m.Send(&Message{To: "user@test", Subject: "Hello", Body: "body"}))
+	assert.Equal(t, "smtp.test:587", gotAddr)
+	assert.Equal(t, "no-reply@test", gotFrom)
+	assert.Equal(t, []string{"user@test"}, gotTo)
+
+	m.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	assert.Error(t, m.Send(&Message{To: "user@test", Subject: "Hello"}))
+}
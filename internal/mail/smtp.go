@@ -0,0 +1,30 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+)
+
+type sendFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+	send sendFunc
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: net.JoinHostPort(host, port), from: from, auth: auth, send: smtp.SendMail}
+}
+
+func (m *SMTPMailer) Send(msg *Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	return m.send(m.addr, m.auth, m.from, []string{msg.To}, format(m.from, msg))
+}
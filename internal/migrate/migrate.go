@@ -0,0 +1,334 @@
+// Package migrate applies versioned SQL migrations. Applied version is stored in
+// schema_migrations the same way as migrate CLI does, so databases migrated by
+// the CLI are picked up as is.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+const versionTable = "schema_migrations"
+
+// lockKey is id of advisory lock which serializes migrations of concurrent replicas.
+var lockKey = int64(crc32.ChecksumIEEE([]byte("Fitness_REST_API:" + versionTable)))
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is state of database schema, Version is 0 when no migration is applied.
+type Status struct {
+	Version int64
+	Dirty   bool
+	Latest  int64
+	Pending []*Migration
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	// advisoryLock is false for SQLite, which has no advisory locks and is used by single node.
+	advisoryLock bool
+	// tableQuery tells whether table with the given name exists.
+	tableQuery string
+}
+
+// Load reads migrations from root of fsys, each version must have both up and down file.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func New(db *sql.DB, migrations []*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations, advisoryLock: true,
+		tableQuery: "SELECT to_regclass($1) IS NOT NULL"}
+}
+
+// NewSQLite creates migrator which doesn't take advisory lock, SQLite serializes
+// writers by itself and each migration still runs in its own transaction.
+func NewSQLite(db *sql.DB, migrations []*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations,
+		tableQuery: "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)"}
+}
+
+// Up applies all pending migrations and returns their count.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if mg.Version <= version {
+				continue
+			}
+			if err = m.apply(ctx, conn, mg.Up, mg.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps latest applied migrations and returns their count.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			mg := m.migrations[i]
+			if mg.Version > version {
+				continue
+			}
+			if mg.Version != version {
+				return fmt.Errorf("applied version %d is unknown", version)
+			}
+			previous := int64(0)
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err = m.apply(ctx, conn, mg.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			version = previous
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force sets version without running migrations and clears dirty flag,
+// it is used after failed migration was fixed by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		if version != 0 && m.find(version) == nil {
+			return fmt.Errorf("no migration with version %d", version)
+		}
+		return setVersion(ctx, conn, version, false)
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = m.prepare(ctx, conn); err != nil {
+		return nil, err
+	}
+	version, dirty, err := getVersion(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Version: version, Dirty: dirty, Pending: make([]*Migration, 0)}
+	for _, mg := range m.migrations {
+		status.Latest = mg.Version
+		if mg.Version > version {
+			status.Pending = append(status.Pending, mg)
+		}
+	}
+	return status, nil
+}
+
+// Check fails unless database schema is clean and at the latest version.
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("database schema is dirty at version %d", status.Version)
+	}
+	if status.Version != status.Latest {
+		return fmt.Errorf("database schema version is %d, expected %d", status.Version, status.Latest)
+	}
+	return nil
+}
+
+// locked runs fn on single connection which holds advisory lock, the lock belongs to session.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.advisoryLock {
+		if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return err
+		}
+		defer func() {
+			_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		}()
+	}
+
+	if err = m.prepare(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// prepare creates version table. Database created from 000001_init before migrations were embedded
+// has users table but no version table, it is baselined at version 1 so that init isn't applied again.
+func (m *Migrator) prepare(ctx context.Context, conn *sql.Conn) error {
+	exists, err := m.tableExists(ctx, conn, versionTable)
+	if err != nil {
+		return err
+	}
+	legacy := false
+	if !exists && m.find(1) != nil {
+		if legacy, err = m.tableExists(ctx, conn, "users"); err != nil {
+			return err
+		}
+	}
+
+	if err = ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	if legacy {
+		return setVersion(ctx, conn, 1, false)
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn, name string) (bool, error) {
+	var exists bool
+	err := conn.QueryRowContext(ctx, m.tableQuery, name).Scan(&exists)
+	return exists, err
+}
+
+func (m *Migrator) cleanVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	version, dirty, err := getVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database schema is dirty at version %d, fix it and force the version", version)
+	}
+	return version, nil
+}
+
+// apply runs migration and moves version in one transaction, so failed migration leaves no trace.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, query string, version int64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = setVersion(ctx, tx, version, false); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			return mg
+		}
+	}
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func ensureVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+versionTable+
+		" (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	return err
+}
+
+func getVersion(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM "+versionTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// setVersion keeps single row like migrate CLI, no row means that nothing is applied.
+func setVersion(ctx context.Context, db execer, version int64, dirty bool) error {
+	if _, err := db.ExecContext(ctx, "DELETE FROM "+versionTable); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, "INSERT INTO "+versionTable+" (version, dirty) VALUES ($1, $2)", version, dirty)
+	return err
+}
@@ -0,0 +1,283 @@
+package migrate
+
+import (
+	"Fitness_REST_API/dbschema"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"testing/fstest"
+)
+
+var testMigrations = []*Migration{
+	{Version: 1, Name: "init", Up: "CREATE TABLE a", Down: "DROP TABLE a"},
+	{Version: 2, Name: "more", Up: "CREATE TABLE b", Down: "DROP TABLE b"},
+}
+
+func TestLoad(t *testing.T) {
+	table := []struct {
+		name         string
+		fsys         fstest.MapFS
+		shouldFail   bool
+		shouldReturn []*Migration
+	}{
+		{
+			name: "Ok",
+			fsys: fstest.MapFS{
+				"000002_more.down.sql": {Data: []byte("DROP TABLE b")},
+				"000002_more.up.sql":   {Data: []byte("CREATE TABLE b")},
+				"000001_init.up.sql":   {Data: []byte("CREATE TABLE a")},
+				"000001_init.down.sql": {Data: []byte("DROP TABLE a")},
+				"dbschema.go":          {Data: []byte("package dbschema")},
+			},
+			shouldReturn: testMigrations,
+		},
+		{
+			name: "Missing Down",
+			fsys: fstest.MapFS{
+				"000001_init.up.sql": {Data: []byte("CREATE TABLE a")},
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Name Mismatch",
+			fsys: fstest.MapFS{
+				"000001_init.up.sql":    {Data: []byte("CREATE TABLE a")},
+				"000001_other.down.sql": {Data: []byte("DROP TABLE a")},
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Load(test.fsys)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(dbschema.FS)
+	assert.NoError(t, err)
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "versions must have no gaps")
+	}
+
+	sqlite, err := Load(dbschema.SQLiteFS)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), len(sqlite), "SQLite must mirror every version")
+	for i, m := range sqlite {
+		if i < len(migrations) {
+			assert.Equal(t, migrations[i].Version, m.Version)
+			assert.Equal(t, migrations[i].Name, m.Name)
+		}
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectPrepare(mock, "to_regclass")
+}
+
+// expectPrepare expects existing version table, which is the case for every database but a legacy one.
+func expectPrepare(mock sqlmock.Sqlmock, tableQuery string) {
+	expectTable(mock, tableQuery, versionTable, true)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectTable(mock sqlmock.Sqlmock, tableQuery, name string, exists bool) {
+	mock.ExpectQuery(tableQuery).WithArgs(name).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectVersion(mock sqlmock.Sqlmock, version int64, dirty bool) {
+	rows := sqlmock.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnRows(rows)
+}
+
+func expectApply(mock sqlmock.Sqlmock, query string, version int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(version, false).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestMigrator_Up(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  int
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				expectLock(mock)
+				expectVersion(mock, 0, false)
+				expectApply(mock, "CREATE TABLE a", 1)
+				expectApply(mock, "CREATE TABLE b", 2)
+				expectUnlock(mock)
+			},
+			shouldReturn: 2,
+		},
+		{
+			name: "Partially Applied",
+			mockBehaviour: func() {
+				expectLock(mock)
+				expectVersion(mock, 1, false)
+				expectApply(mock, "CREATE TABLE b", 2)
+				expectUnlock(mock)
+			},
+			shouldReturn: 1,
+		},
+		{
+			name: "Legacy Database",
+			mockBehaviour: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectTable(mock, "to_regclass", versionTable, false)
+				expectTable(mock, "to_regclass", "users", true)
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(1), false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectVersion(mock, 1, false)
+				expectApply(mock, "CREATE TABLE b", 2)
+				expectUnlock(mock)
+			},
+			shouldReturn: 1,
+		},
+		{
+			name: "Failed Migration",
+			mockBehaviour: func() {
+				expectLock(mock)
+				expectVersion(mock, 1, false)
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE b").WillReturnError(errors.New("syntax error"))
+				mock.ExpectRollback()
+				expectUnlock(mock)
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Dirty",
+			mockBehaviour: func() {
+				expectLock(mock)
+				expectVersion(mock, 1, true)
+				expectUnlock(mock)
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+
+			got, err := New(db, testMigrations).Up(context.Background())
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_UpSQLite(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectTable(mock, "sqlite_master", versionTable, false)
+	expectTable(mock, "sqlite_master", "users", false)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectVersion(mock, 0, false)
+	expectApply(mock, "CREATE TABLE a", 1)
+	expectApply(mock, "CREATE TABLE b", 2)
+
+	got, err := NewSQLite(db, testMigrations).Up(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 2, false)
+	expectApply(mock, "DROP TABLE b", 1)
+	expectApply(mock, "DROP TABLE a", 0)
+	expectUnlock(mock)
+
+	got, err := New(db, testMigrations).Down(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Check(t *testing.T) {
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name       string
+		version    int64
+		dirty      bool
+		shouldFail bool
+	}{
+		{name: "Ok", version: 2},
+		{name: "Behind", version: 1, shouldFail: true},
+		{name: "Dirty", version: 2, dirty: true, shouldFail: true},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			expectPrepare(mock, "to_regclass")
+			expectVersion(mock, test.version, test.dirty)
+
+			err := New(db, testMigrations).Check(context.Background())
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flag     `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+// Valid is checked by validate with provider in hand, parser skips it.
+func (c *idTokenClaims) Valid() error {
+	return nil
+}
+
+func (c *idTokenClaims) validate(p *Provider, now time.Time) error {
+	if !contains(p.Issuers, c.Issuer) {
+		return errors.New("id token is issued by untrusted issuer")
+	}
+	if !c.Audience.any(p.ClientIDs) {
+		return errors.New("id token is issued for another client")
+	}
+	if c.Subject == "" {
+		return errors.New("id token has no subject")
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(leeway)) {
+		return errors.New("id token is expired")
+	}
+	if now.Add(leeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("id token is issued in the future")
+	}
+	return nil
+}
+
+// audience is a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) any(values []string) bool {
+	for _, v := range a {
+		if contains(values, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// flag is a boolean which Apple sends as a "true" or "false" string.
+type flag bool
+
+func (f *flag) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*f = flag(v)
+	case string:
+		*f = v == "true"
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
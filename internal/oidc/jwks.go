@@ -0,0 +1,115 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	keysTTL = 24 * time.Hour
+	// minRefresh stops tokens with unknown key id from making a request each.
+	minRefresh = time.Minute
+)
+
+// keySet caches provider keys, it is refreshed when expired or when token is signed by unknown key.
+type keySet struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func newKeySet(url string, client *http.Client) *keySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &keySet{url: url, client: client}
+}
+
+func (s *keySet) key(kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[kid]
+	age := time.Since(s.fetchedAt)
+	if ok && age < keysTTL {
+		return key, nil
+	}
+	if !ok && s.keys != nil && age < minRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	if key, ok = s.keys[kid]; !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (s *keySet) refresh() error {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return fmt.Errorf("can't fetch signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("can't fetch signing keys: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("can't decode signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = key
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+func (k *jwk) publicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus of key %q", k.Kid)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent of key %q", k.Kid)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("unsupported key exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
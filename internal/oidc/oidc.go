@@ -0,0 +1,92 @@
+// Package oidc verifies ID tokens issued to native apps by OpenID Connect providers.
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"net/http"
+	"time"
+)
+
+// leeway tolerates clock drift between provider and server.
+const leeway = time.Minute
+
+// Provider describes trusted issuer, ClientIDs are accepted audiences of ID token.
+type Provider struct {
+	Name      string
+	Issuers   []string
+	JWKSURL   string
+	ClientIDs []string
+}
+
+// Identity is verified subject of ID token.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+type Verifier interface {
+	// Verify checks signature and claims of ID token, the token has to carry nonce sent by app.
+	Verify(provider, idToken, nonce string) (*Identity, error)
+}
+
+// JWKSVerifier validates tokens with signing keys published by providers.
+type JWKSVerifier struct {
+	providers map[string]*Provider
+	keys      map[string]*keySet
+	now       func() time.Time
+}
+
+func NewVerifier(providers []*Provider, client *http.Client) *JWKSVerifier {
+	v := &JWKSVerifier{
+		providers: make(map[string]*Provider, len(providers)),
+		keys:      make(map[string]*keySet, len(providers)),
+		now:       time.Now,
+	}
+	for _, p := range providers {
+		v.providers[p.Name] = p
+		v.keys[p.Name] = newKeySet(p.JWKSURL, client)
+	}
+	return v
+}
+
+func (v *JWKSVerifier) Verify(provider, idToken, nonce string) (*Identity, error) {
+	p, ok := v.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+
+	var claims idTokenClaims
+	parser := &jwt.Parser{ValidMethods: []string{"RS256"}, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys[provider].key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if err = claims.validate(p, v.now()); err != nil {
+		return nil, err
+	}
+	if nonce == "" || claims.Nonce == "" {
+		return nil, errors.New("id token nonce is required")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	return &Identity{
+		Provider:      provider,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
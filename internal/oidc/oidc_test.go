@@ -0,0 +1,221 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProvider is a local OIDC provider which publishes JWKS and signs ID tokens.
+type fakeProvider struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	requests int32
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	p := &fakeProvider{key: mustKey(t), kid: "key-1"}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&p.requests, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": p.kid,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakeProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            "https://issuer.test",
+		"sub":            "subject-1",
+		"aud":            "client-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce-1",
+		"email":          "user@mail.com",
+		"email_verified": true,
+		"given_name":     "John",
+		"family_name":    "Doe",
+	}
+}
+
+func TestJWKSVerifier_Verify(t *testing.T) {
+	provider := newFakeProvider(t)
+	verifier := NewVerifier([]*Provider{{
+		Name:      "google",
+		Issuers:   []string{"https://issuer.test"},
+		JWKSURL:   provider.URL,
+		ClientIDs: []string{"client-1", "client-2"},
+	}}, provider.Client())
+
+	table := []struct {
+		name         string
+		provider     string
+		claims       func(c jwt.MapClaims)
+		token        func(c jwt.MapClaims) string
+		nonce        string
+		shouldFail   bool
+		shouldReturn *Identity
+	}{
+		{
+			name:   "Ok",
+			claims: func(c jwt.MapClaims) {},
+			nonce:  "nonce-1",
+			shouldReturn: &Identity{Provider: "google", Subject: "subject-1", Email: "user@mail.com",
+				EmailVerified: true, GivenName: "John", FamilyName: "Doe"},
+		},
+		{
+			name: "Audience List And String Flag",
+			claims: func(c jwt.MapClaims) {
+				c["aud"] = []string{"other", "client-2"}
+				c["email_verified"] = "false"
+			},
+			nonce: "nonce-1",
+			shouldReturn: &Identity{Provider: "google", Subject: "subject-1", Email: "user@mail.com",
+				GivenName: "John", FamilyName: "Doe"},
+		},
+		{
+			name:       "Unknown Provider",
+			provider:   "apple",
+			claims:     func(c jwt.MapClaims) {},
+			shouldFail: true,
+		},
+		{
+			name:       "Untrusted Issuer",
+			claims:     func(c jwt.MapClaims) { c["iss"] = "https://evil.test" },
+			shouldFail: true,
+		},
+		{
+			name:       "Another Client",
+			claims:     func(c jwt.MapClaims) { c["aud"] = "client-3" },
+			shouldFail: true,
+		},
+		{
+			name:       "Expired",
+			claims:     func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() },
+			shouldFail: true,
+		},
+		{
+			name:       "Nonce Mismatch",
+			claims:     func(c jwt.MapClaims) {},
+			nonce:      "nonce-2",
+			shouldFail: true,
+		},
+		{
+			name:       "No Nonce",
+			claims:     func(c jwt.MapClaims) {},
+			shouldFail: true,
+		},
+		{
+			name:       "Token Without Nonce",
+			claims:     func(c jwt.MapClaims) { delete(c, "nonce") },
+			nonce:      "nonce-1",
+			shouldFail: true,
+		},
+		{
+			name:   "Foreign Key",
+			claims: func(c jwt.MapClaims) {},
+			token: func(c jwt.MapClaims) string {
+				other := &fakeProvider{key: mustKey(t), kid: provider.kid}
+				return other.sign(t, c)
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "Symmetric Algorithm",
+			claims: func(c jwt.MapClaims) {},
+			token: func(c jwt.MapClaims) string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte("secret"))
+				return signed
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			claims := validClaims()
+			test.claims(claims)
+			token := ""
+			if test.token != nil {
+				token = test.token(claims)
+			} else {
+				token = provider.sign(t, claims)
+			}
+			name := test.provider
+			if name == "" {
+				name = "google"
+			}
+
+			got, err := verifier.Verify(name, token, test.nonce)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+		})
+	}
+}
+
+func TestJWKSVerifier_KeyRotation(t *testing.T) {
+	provider := newFakeProvider(t)
+	verifier := NewVerifier([]*Provider{{
+		Name:      "apple",
+		Issuers:   []string{"https://issuer.test"},
+		JWKSURL:   provider.URL,
+		ClientIDs: []string{"client-1"},
+	}}, provider.Client())
+
+	_, err := verifier.Verify("apple", provider.sign(t, validClaims()), "nonce-1")
+	assert.NoError(t, err)
+	_, err = verifier.Verify("apple", provider.sign(t, validClaims()), "nonce-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&provider.requests))
+
+	// new key is not fetched right away, so that bogus key ids can't flood provider
+	provider.key, provider.kid = mustKey(t), "key-2"
+	_, err = verifier.Verify("apple", provider.sign(t, validClaims()), "nonce-1")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&provider.requests))
+
+	verifier.keys["apple"].fetchedAt = time.Now().Add(-minRefresh)
+	_, err = verifier.Verify("apple", provider.sign(t, validClaims()), "nonce-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&provider.requests))
+}
+
+func mustKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,142 @@
+// Package dbtx lets repositories run either on the connection pool or in the ambient
+// transaction of a unit of work, so several repository calls can be made atomic
+// without changing the repositories themselves.
+package dbtx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// savepoint names the savepoint which stands for transaction of repository method in the
+// ambient transaction, savepoints with the same name nest in postgres and SQLite.
+const savepoint = "repository"
+
+type queryer interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// DB runs queries on the pool or, when it is bound to a transaction by WithinTx, in it.
+type DB struct {
+	queryer
+	pool      *sqlx.DB
+	tx        *sqlx.Tx
+	retryable func(err error) bool
+}
+
+// New wraps pool, retryable reports errors after which transaction of WithinTx can be rerun.
+func New(pool *sqlx.DB, retryable func(err error) bool) *DB {
+	if retryable == nil {
+		retryable = func(error) bool { return false }
+	}
+	return &DB{queryer: pool, pool: pool, retryable: retryable}
+}
+
+// Beginx begins transaction of repository method. In the ambient transaction it is a
+// savepoint, so that rollback of the method doesn't abort work done before it.
+func (db *DB) Beginx() (*Tx, error) {
+	if db.tx == nil {
+		tx, err := db.pool.Beginx()
+		if err != nil {
+			return nil, err
+		}
+		return &Tx{Tx: tx}, nil
+	}
+
+	if _, err := db.tx.Exec("SAVEPOINT " + savepoint); err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: db.tx, nested: true}, nil
+}
+
+// WithinTx runs fn with db bound to a new transaction and commits it when fn returns nil.
+// Transaction failed with retryable error, e.g. serialization failure or deadlock, is rerun
+// up to retries times, so fn must not have effects outside of the database. When db is
+// already bound to a transaction fn joins it and opts and retries of the outer call apply.
+func (db *DB) WithinTx(opts *sql.TxOptions, retries int, fn func(db *DB) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := db.runTx(opts, fn)
+		if err == nil || attempt >= retries || !db.retryable(err) {
+			return err
+		}
+	}
+}
+
+// Retryable reports whether err fails transaction of WithinTx so that it is rerun.
+func (db *DB) Retryable(err error) bool {
+	return db.retryable(err)
+}
+
+func (db *DB) runTx(opts *sql.TxOptions, fn func(db *DB) error) error {
+	tx, err := db.pool.BeginTxx(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(&DB{queryer: tx, pool: db.pool, tx: tx, retryable: db.retryable}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Savepoint runs fn so that its failure rolls back only the work of fn. In the ambient transaction
+// fn runs in a savepoint of it, otherwise in a transaction of its own.
+func (db *DB) Savepoint(fn func(db *DB) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(&DB{queryer: tx.Tx, pool: db.pool, tx: tx.Tx, retryable: db.retryable}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Tx is transaction of repository method, either own or a savepoint of the ambient one.
+type Tx struct {
+	*sqlx.Tx
+	nested bool
+}
+
+func (tx *Tx) Commit() error {
+	if !tx.nested {
+		return tx.Tx.Commit()
+	}
+	_, err := tx.Tx.Exec("RELEASE SAVEPOINT " + savepoint)
+	return err
+}
+
+func (tx *Tx) Rollback() error {
+	if !tx.nested {
+		return tx.Tx.Rollback()
+	}
+	if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); err != nil {
+		return err
+	}
+	_, err := tx.Tx.Exec("RELEASE SAVEPOINT " + savepoint)
+	return err
+}
@@ -0,0 +1,202 @@
+package dbtx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var errConflict = errors.New("conflict")
+
+func isConflict(err error) bool {
+	return errors.Is(err, errConflict)
+}
+
+func TestDB_WithinTx(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func()
+
+	table := []struct {
+		name          string
+		retries       int
+		fn            func(db *DB) error
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name:    "Ok",
+			retries: 0,
+			fn: func(db *DB) error {
+				_, err := db.Exec("UPDATE users SET name = $1", "name")
+				return err
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:    "Savepoint",
+			retries: 0,
+			fn: func(db *DB) error {
+				tx, err := db.Beginx()
+				if err != nil {
+					return err
+				}
+				if _, err = tx.Exec("UPDATE users SET name = $1", "name"); err == nil {
+					return errors.New("update should fail")
+				}
+				if err = tx.Rollback(); err != nil {
+					return err
+				}
+				_, err = db.Exec("UPDATE workouts SET title = $1", "title")
+				return err
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnError(errors.New("internal error"))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE workouts").WithArgs("title").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:    "Failed savepoint",
+			retries: 0,
+			fn: func(db *DB) error {
+				err := db.Savepoint(func(db *DB) error {
+					_, err := db.Exec("UPDATE users SET name = $1", "name")
+					return err
+				})
+				if err == nil {
+					return errors.New("savepoint should fail")
+				}
+				_, err = db.Exec("UPDATE workouts SET title = $1", "title")
+				return err
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnError(errors.New("internal error"))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT repository").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE workouts").WithArgs("title").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:    "Retried",
+			retries: 1,
+			fn: func(db *DB) error {
+				_, err := db.Exec("UPDATE users SET name = $1", "name")
+				return err
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnError(errConflict)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:    "Retries exhausted",
+			retries: 1,
+			fn: func(db *DB) error {
+				_, err := db.Exec("UPDATE users SET name = $1", "name")
+				return err
+			},
+			mockBehaviour: func() {
+				for i := 0; i < 2; i++ {
+					mock.ExpectBegin()
+					mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnError(errConflict)
+					mock.ExpectRollback()
+				}
+			},
+			shouldFail: true,
+		},
+		{
+			name:    "Not retryable",
+			retries: 3,
+			fn: func(db *DB) error {
+				_, err := db.Exec("UPDATE users SET name = $1", "name")
+				return err
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:    "Joined",
+			retries: 0,
+			fn: func(db *DB) error {
+				return db.WithinTx(nil, 3, func(inner *DB) error {
+					if inner != db {
+						return errors.New("outer transaction should be joined")
+					}
+					_, err := inner.Exec("UPDATE users SET name = $1", "name")
+					return err
+				})
+			},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+
+			err := New(db, isConflict).WithinTx(&sql.TxOptions{}, test.retries, test.fn)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDB_Beginx(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WithArgs("name").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := New(db, nil).Beginx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec("UPDATE users SET name = $1", "name")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
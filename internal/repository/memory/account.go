@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type AccountRepository struct {
+	s *Store
+}
+
+func NewAccountRepository(s *Store) *AccountRepository {
+	return &AccountRepository{s: s}
+}
+
+func (r *AccountRepository) CreateToken(token *entity.AccountToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.tokens[token.Id]; ok {
+		return errors.New("token already exists")
+	}
+	if _, ok := r.s.users[token.UserId]; !ok {
+		return errors.New("invalid userId")
+	}
+	r.s.tokens[token.Id] = &entity.AccountToken{Id: token.Id, UserId: token.UserId, Purpose: token.Purpose,
+		ExpiresAt: token.ExpiresAt, CreatedAt: time.Now()}
+	return nil
+}
+
+func (r *AccountRepository) VerifyEmail(tokenId string, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	token, err := r.usableToken(tokenId, userId, entity.TokenVerifyEmail, now)
+	if err != nil {
+		return err
+	}
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("no user to verify")
+	}
+
+	token.UsedAt = sql.NullTime{Time: now, Valid: true}
+	if !u.VerifiedAt.Valid {
+		u.VerifiedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	return nil
+}
+
+// ResetPassword sets new password, revokes other reset tokens and sessions of user. Reset proves
+// ownership of email, so account becomes verified as well.
+func (r *AccountRepository) ResetPassword(tokenId string, userId int64, passwordHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	if _, err := r.usableToken(tokenId, userId, entity.TokenResetPassword, now); err != nil {
+		return err
+	}
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("no user to reset password")
+	}
+
+	u.PasswordHash = passwordHash
+	if !u.VerifiedAt.Valid {
+		u.VerifiedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	u.TokenVersion++
+	for _, t := range r.s.tokens {
+		if t.UserId == userId && t.Purpose == entity.TokenResetPassword && !t.UsedAt.Valid {
+			t.UsedAt = sql.NullTime{Time: now, Valid: true}
+		}
+	}
+	return nil
+}
+
+func (r *AccountRepository) usableToken(tokenId string, userId int64, purpose entity.TokenPurpose,
+	now time.Time) (*entity.AccountToken, error) {
+	t, ok := r.s.tokens[tokenId]
+	if !ok || t.UserId != userId || t.Purpose != purpose || t.UsedAt.Valid || !t.ExpiresAt.After(now) {
+		return nil, errors.New("token is invalid, expired or already used")
+	}
+	return t, nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"database/sql"
+)
+
+type AdminRepository struct {
+	s *Store
+}
+
+func NewAdminRepository(s *Store) *AdminRepository {
+	return &AdminRepository{s: s}
+}
+
+func (r *AdminRepository) Authorize(login, passwordHash string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range sortedIds(r.s.admins) {
+		admin := r.s.admins[id]
+		if admin.Login == login && admin.PasswordHash == passwordHash {
+			return admin.Id, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+func (r *AdminRepository) GetLogin(adminId int64) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	admin, ok := r.s.admins[adminId]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return admin.Login, nil
+}
@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+type ApplicationRepository struct {
+	s *Store
+}
+
+func NewApplicationRepository(s *Store) *ApplicationRepository {
+	return &ApplicationRepository{s: s}
+}
+
+func (r *ApplicationRepository) CreateApplication(actor *entity.Actor, app *entity.TrainerApplication) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(app.UserId)
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	if u.Role != entity.UserRole {
+		return -1, errors.New("only users can apply to become trainers")
+	}
+	for _, a := range r.s.applications {
+		if a.UserId == app.UserId && isUnderConsideration(a) {
+			return -1, errors.New("application is already under consideration")
+		}
+	}
+
+	id := r.s.lastApplicationId + 1
+	app.Status = entity.ApplicationPending
+	err := r.s.writeAudit(actor, entity.AuditApplicationSubmit, entity.AuditTargetApplication, id,
+		nil, applicationAuditState(app))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	r.s.lastApplicationId = id
+	r.s.applications[id] = &entity.TrainerApplication{Id: id, UserId: app.UserId, Status: entity.ApplicationPending,
+		Bio: app.Bio, Specialization: app.Specialization, ExperienceYears: app.ExperienceYears,
+		CreatedAt: now, UpdatedAt: now}
+	return id, nil
+}
+
+func (r *ApplicationRepository) GetUserApplications(userId int64) ([]*entity.TrainerApplication, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	apps := r.selectApplications(func(a *entity.TrainerApplication) bool { return a.UserId == userId })
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].CreatedAt.After(apps[j].CreatedAt) })
+	return apps, nil
+}
+
+// UpdateApplication resubmits application which admin asked to change.
+func (r *ApplicationRepository) UpdateApplication(actor *entity.Actor, userId, appId int64,
+	input *entity.TrainerApplicationInput) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	app, ok := r.s.applications[appId]
+	if !ok || app.UserId != userId {
+		return errors.New("no application with provided id")
+	}
+	if app.Status != entity.ApplicationChangesRequested {
+		return errors.New("application can be changed only on reviewer request")
+	}
+
+	after := *app
+	after.Status = entity.ApplicationPending
+	after.Bio, after.Specialization, after.ExperienceYears = input.Bio, input.Specialization, input.ExperienceYears
+	err := r.s.writeAudit(actor, entity.AuditApplicationUpdate, entity.AuditTargetApplication, appId,
+		applicationAuditState(app), applicationAuditState(&after))
+	if err != nil {
+		return err
+	}
+	after.UpdatedAt = time.Now()
+	*app = after
+	return nil
+}
+
+func (r *ApplicationRepository) AddCertificate(actor *entity.Actor, userId int64,
+	cert *entity.Certificate) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	app, ok := r.s.applications[cert.ApplicationId]
+	if !ok || app.UserId != userId {
+		return -1, errors.New("no application with provided id")
+	}
+	if !isUnderConsideration(app) {
+		return -1, errors.New("application is already reviewed")
+	}
+
+	id := r.s.lastCertificateId + 1
+	after := map[string]interface{}{"certificate_id": id, "file_name": cert.FileName}
+	err := r.s.writeAudit(actor, entity.AuditApplicationCertificate, entity.AuditTargetApplication,
+		cert.ApplicationId, nil, after)
+	if err != nil {
+		return 0, err
+	}
+	r.s.lastCertificateId = id
+	r.s.certificates[id] = &entity.Certificate{Id: id, ApplicationId: cert.ApplicationId, FileName: cert.FileName,
+		ContentType: cert.ContentType, Size: cert.Size, Content: cert.Content, CreatedAt: time.Now()}
+	return id, nil
+}
+
+func (r *ApplicationRepository) GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	apps := r.selectApplications(func(a *entity.TrainerApplication) bool {
+		_, active := r.s.activeUser(a.UserId)
+		return a.Status == status && active
+	})
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].UpdatedAt.Before(apps[j].UpdatedAt) })
+	return apps, nil
+}
+
+func (r *ApplicationRepository) GetApplicationById(appId int64) (*entity.TrainerApplication, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, ok := r.s.applications[appId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	app := *a
+	app.Certificates = make([]*entity.Certificate, 0)
+	for _, id := range sortedIds(r.s.certificates) {
+		if c := r.s.certificates[id]; c.ApplicationId == appId {
+			cp := *c
+			cp.Content = nil
+			app.Certificates = append(app.Certificates, &cp)
+		}
+	}
+	return &app, nil
+}
+
+func (r *ApplicationRepository) GetCertificate(appId, certificateId int64) (*entity.Certificate, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.certificates[certificateId]
+	if !ok || c.ApplicationId != appId {
+		return nil, sql.ErrNoRows
+	}
+	cp := *c
+	return &cp, nil
+}
+
+// ReviewApplication records decision of admin on pending application, approval switches role of user to trainer.
+func (r *ApplicationRepository) ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus,
+	note string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	app, ok := r.s.applications[appId]
+	if !ok {
+		return errors.New("no application with provided id")
+	}
+	if app.Status != entity.ApplicationPending {
+		return errors.New("only pending application can be reviewed")
+	}
+
+	var applicant *entity.User
+	if status == entity.ApplicationApproved {
+		u, ok := r.s.activeUser(app.UserId)
+		if !ok || u.Role != entity.UserRole {
+			return errors.New("applicant is not an active user")
+		}
+		err := r.s.writeAudit(actor, entity.AuditUserUpdate, entity.AuditTargetUser, app.UserId,
+			map[string]interface{}{"role": string(entity.UserRole)},
+			map[string]interface{}{"role": string(entity.TrainerRole)})
+		if err != nil {
+			return err
+		}
+		applicant = u
+	}
+
+	after := *app
+	after.Status, after.ReviewerNote = status, sql.NullString{String: note, Valid: note != ""}
+	err := r.s.writeAudit(actor, reviewAuditActions[status], entity.AuditTargetApplication, appId,
+		applicationAuditState(app), applicationAuditState(&after))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if actor != nil {
+		after.ReviewerId = sql.NullInt64{Int64: actor.Id, Valid: true}
+	}
+	after.ReviewedAt, after.UpdatedAt = sql.NullTime{Time: now, Valid: true}, now
+	*app = after
+	if applicant != nil {
+		applicant.Role = entity.TrainerRole
+		applicant.Version++
+	}
+	return nil
+}
+
+var reviewAuditActions = map[entity.ApplicationStatus]entity.AuditAction{
+	entity.ApplicationApproved:         entity.AuditApplicationApprove,
+	entity.ApplicationRejected:         entity.AuditApplicationReject,
+	entity.ApplicationChangesRequested: entity.AuditApplicationRequestChanges,
+}
+
+func (r *ApplicationRepository) selectApplications(
+	match func(a *entity.TrainerApplication) bool) []*entity.TrainerApplication {
+	apps := make([]*entity.TrainerApplication, 0)
+	for _, id := range sortedIds(r.s.applications) {
+		if a := r.s.applications[id]; match(a) {
+			cp := *a
+			apps = append(apps, &cp)
+		}
+	}
+	return apps
+}
+
+// isUnderConsideration reports open application, user can have only one of them.
+func isUnderConsideration(a *entity.TrainerApplication) bool {
+	return a.Status == entity.ApplicationPending || a.Status == entity.ApplicationChangesRequested
+}
+
+func applicationAuditState(a *entity.TrainerApplication) map[string]interface{} {
+	return map[string]interface{}{
+		"status":           string(a.Status),
+		"bio":              a.Bio,
+		"specialization":   a.Specialization,
+		"experience_years": a.ExperienceYears,
+		"reviewer_note":    a.ReviewerNote.String,
+	}
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+)
+
+type AuditRepository struct {
+	s *Store
+}
+
+func NewAuditRepository(s *Store) *AuditRepository {
+	return &AuditRepository{s: s}
+}
+
+// WriteAudit records event which is not a part of other mutation.
+func (r *AuditRepository) WriteAudit(actor *entity.Actor, action entity.AuditAction, targetType string, targetId int64,
+	before, after map[string]interface{}) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.writeAudit(actor, action, targetType, targetId, before, after)
+}
+
+func (r *AuditRepository) GetAuditLog(filter *entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entries := make([]*entity.AuditEntry, 0)
+	ids := sortedIds(r.s.auditLog)
+	for i := len(ids) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		e := r.s.auditLog[ids[i]]
+		if matchAudit(e, filter) {
+			cp := *e
+			entries = append(entries, &cp)
+		}
+	}
+	return entries, nil
+}
+
+func matchAudit(e *entity.AuditEntry, filter *entity.AuditFilter) bool {
+	return (filter.ActorType == "" || e.ActorType == filter.ActorType) &&
+		(filter.ActorId <= 0 || e.ActorId == filter.ActorId) &&
+		(filter.TargetType == "" || e.TargetType == filter.TargetType) &&
+		(filter.TargetId <= 0 || e.TargetId == filter.TargetId) &&
+		(filter.From.IsZero() || !e.CreatedAt.Before(filter.From)) &&
+		(filter.To.IsZero() || e.CreatedAt.Before(filter.To)) &&
+		(filter.BeforeId <= 0 || e.Id < filter.BeforeId)
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type CommentRepository struct {
+	s *Store
+}
+
+func NewCommentRepository(s *Store) *CommentRepository {
+	return &CommentRepository{s: s}
+}
+
+func (r *CommentRepository) GetWorkoutComments(workoutId int64) ([]*entity.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	comments := make([]*entity.Comment, 0)
+	for _, id := range sortedIds(r.s.comments) {
+		if c := r.s.comments[id]; c.WorkoutId == workoutId {
+			cp := *c
+			comments = append(comments, &cp)
+		}
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) GetCommentById(workoutId, commentId int64) (*entity.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.comments[commentId]
+	if !ok || c.WorkoutId != workoutId {
+		return nil, sql.ErrNoRows
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *CommentRepository) CreateComment(comment *entity.Comment) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.workouts[comment.WorkoutId]; !ok {
+		return 0, fmt.Errorf("no workout with id %d", comment.WorkoutId)
+	}
+	r.s.lastCommentId++
+	r.s.comments[r.s.lastCommentId] = &entity.Comment{Id: r.s.lastCommentId, WorkoutId: comment.WorkoutId,
+		AuthorId: comment.AuthorId, Body: comment.Body, CreatedAt: time.Now()}
+	return r.s.lastCommentId, nil
+}
+
+func (r *CommentRepository) UpdateComment(commentId, authorId int64, body string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.comments[commentId]
+	if !ok || c.AuthorId != authorId {
+		return errors.New("no comment to update")
+	}
+	c.Body, c.UpdatedAt = body, sql.NullTime{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (r *CommentRepository) SetCommentResolved(commentId int64, resolved bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.comments[commentId]
+	if !ok {
+		return errors.New("no comment to update")
+	}
+	c.Resolved = resolved
+	return nil
+}
+
+func (r *CommentRepository) DeleteComment(commentId, authorId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.comments[commentId]
+	if !ok || c.AuthorId != authorId {
+		return errors.New("no comment to delete")
+	}
+	delete(r.s.comments, commentId)
+	return nil
+}
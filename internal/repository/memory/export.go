@@ -0,0 +1,227 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+// exportTimedOut is error of pending export whose build has been lost
+const exportTimedOut = "export has timed out"
+
+type ExportRepository struct {
+	s *Store
+}
+
+func NewExportRepository(s *Store) *ExportRepository {
+	return &ExportRepository{s: s}
+}
+
+// CreateExport registers pending export unless user has one in progress, pending exports created
+// before staleBefore have lost their build and are failed first.
+func (r *ExportRepository) CreateExport(actor *entity.Actor, userId int64, staleBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userId]; !ok {
+		return 0, errors.New("invalid userId")
+	}
+	now := time.Now()
+	for _, e := range r.s.exports {
+		if e.UserId != userId || e.Status != entity.ExportPending {
+			continue
+		}
+		if !e.CreatedAt.Before(staleBefore) {
+			return -1, errors.New("export is already in progress")
+		}
+		failExport(e, exportTimedOut, now)
+	}
+
+	id := r.s.lastExportId + 1
+	after := map[string]interface{}{"export_id": id}
+	if err := r.s.writeAudit(actor, entity.AuditUserExport, entity.AuditTargetUser, userId, nil, after); err != nil {
+		return 0, err
+	}
+	r.s.lastExportId = id
+	r.s.exports[id] = &entity.DataExport{Id: id, UserId: userId, Status: entity.ExportPending, CreatedAt: now}
+	return id, nil
+}
+
+func (r *ExportRepository) GetExport(userId, exportId int64) (*entity.DataExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	e, ok := r.s.exports[exportId]
+	if !ok || e.UserId != userId {
+		return nil, sql.ErrNoRows
+	}
+	return exportInfo(e), nil
+}
+
+// GetLatestExport returns the most recent export of user.
+func (r *ExportRepository) GetLatestExport(userId int64) (*entity.DataExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := sortedIds(r.s.exports)
+	for i := len(ids) - 1; i >= 0; i-- {
+		if e := r.s.exports[ids[i]]; e.UserId == userId {
+			return exportInfo(e), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetExportArchive returns ready export by download token while the link is not expired.
+func (r *ExportRepository) GetExportArchive(token string) (*entity.DataExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range r.s.exports {
+		if e.Token.Valid && e.Token.String == token && e.Status == entity.ExportReady && e.ExpiresAt.Time.After(now) {
+			cp := *e
+			cp.Token = sql.NullString{}
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// CompleteExport stores archive of pending export, export which has timed out meanwhile stays failed.
+func (r *ExportRepository) CompleteExport(exportId int64, archive []byte, token string, expiresAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	e, ok := r.s.exports[exportId]
+	if !ok || e.Status != entity.ExportPending {
+		return errors.New("export is no longer pending")
+	}
+	e.Status, e.Archive = entity.ExportReady, archive
+	e.Token = sql.NullString{String: token, Valid: true}
+	e.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+	e.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (r *ExportRepository) FailExport(exportId int64, reason string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if e, ok := r.s.exports[exportId]; ok && e.Status == entity.ExportPending {
+		failExport(e, reason, time.Now())
+	}
+	return nil
+}
+
+// FailStaleExports fails pending exports created before createdBefore, their build has been lost.
+func (r *ExportRepository) FailStaleExports(createdBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var failed int64
+	now := time.Now()
+	for _, e := range r.s.exports {
+		if e.Status == entity.ExportPending && e.CreatedAt.Before(createdBefore) {
+			failExport(e, exportTimedOut, now)
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// PurgeExpiredExports drops archives with expired download links.
+func (r *ExportRepository) PurgeExpiredExports(expiredBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, e := range r.s.exports {
+		if e.ExpiresAt.Valid && e.ExpiresAt.Time.Before(expiredBefore) {
+			delete(r.s.exports, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// GetExportData collects everything stored about user, soft-deleted accounts are included.
+func (r *ExportRepository) GetExportData(userId int64) (*entity.ExportData, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	data := &entity.ExportData{
+		Profile: &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname, Role: u.Role,
+			CreatedAt: u.CreatedAt},
+		Workouts:     make([]*entity.Workout, 0),
+		Partnerships: make([]*entity.Partnership, 0),
+		Messages:     make([]*entity.Message, 0),
+		Comments:     make([]*entity.Comment, 0),
+		Bookings:     make([]*entity.Booking, 0),
+	}
+
+	for _, id := range sortedIds(r.s.workouts) {
+		w := r.s.workouts[id]
+		if w.UserId == userId || w.TrainerId.Valid && w.TrainerId.Int64 == userId {
+			cp := *w
+			data.Workouts = append(data.Workouts, &cp)
+		}
+	}
+	sort.SliceStable(data.Workouts, func(i, j int) bool { return data.Workouts[i].Date.Before(data.Workouts[j].Date) })
+
+	partnerships := make(map[int64]bool)
+	for _, id := range sortedIds(r.s.partnerships) {
+		if p := r.s.partnerships[id]; p.UserId == userId || p.TrainerId == userId {
+			cp := *p
+			data.Partnerships = append(data.Partnerships, &cp)
+			partnerships[id] = true
+		}
+	}
+	sort.SliceStable(data.Partnerships, func(i, j int) bool {
+		return data.Partnerships[i].CreatedAt.Before(data.Partnerships[j].CreatedAt)
+	})
+
+	for _, id := range sortedIds(r.s.messages) {
+		if m := r.s.messages[id]; partnerships[m.PartnershipId] {
+			cp := *m
+			data.Messages = append(data.Messages, &cp)
+		}
+	}
+
+	for _, id := range sortedIds(r.s.comments) {
+		if c := r.s.comments[id]; c.AuthorId == userId {
+			cp := *c
+			data.Comments = append(data.Comments, &cp)
+		}
+	}
+
+	for _, id := range sortedIds(r.s.bookings) {
+		if b := r.s.bookings[id]; b.UserId == userId || b.TrainerId == userId {
+			cp := *b
+			data.Bookings = append(data.Bookings, &cp)
+		}
+	}
+	sort.SliceStable(data.Bookings, func(i, j int) bool {
+		return data.Bookings[i].StartsAt.Before(data.Bookings[j].StartsAt)
+	})
+	return data, nil
+}
+
+func failExport(e *entity.DataExport, reason string, now time.Time) {
+	e.Status = entity.ExportFailed
+	e.Error = sql.NullString{String: reason, Valid: true}
+	e.CompletedAt = sql.NullTime{Time: now, Valid: true}
+}
+
+// exportInfo copies export without archive, archive is read only by download token.
+func exportInfo(e *entity.DataExport) *entity.DataExport {
+	cp := *e
+	cp.Archive = nil
+	return &cp
+}
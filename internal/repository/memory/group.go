@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type GroupRepository struct {
+	s *Store
+}
+
+func NewGroupRepository(s *Store) *GroupRepository {
+	return &GroupRepository{s: s}
+}
+
+func (r *GroupRepository) CreateGroup(group *entity.ClientGroup) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.lastGroupId++
+	r.s.groups[r.s.lastGroupId] = &entity.ClientGroup{Id: r.s.lastGroupId, TrainerId: group.TrainerId,
+		Name: group.Name, CreatedAt: time.Now()}
+	return r.s.lastGroupId, nil
+}
+
+func (r *GroupRepository) GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	groups := make([]*entity.ClientGroup, 0)
+	for _, id := range sortedIds(r.s.groups) {
+		if g := r.s.groups[id]; g.TrainerId == trainerId {
+			cp := *g
+			groups = append(groups, &cp)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+func (r *GroupRepository) GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	g, ok := r.trainerGroup(trainerId, groupId)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	group := *g
+	group.Members = make([]*entity.User, 0)
+	for _, id := range sortedIds(r.s.users) {
+		if !r.s.members[groupMember{groupId: groupId, userId: id}] {
+			continue
+		}
+		if u, ok := r.s.activeUser(id); ok {
+			group.Members = append(group.Members, &entity.User{Id: u.Id, Email: u.Email, Name: u.Name,
+				Surname: u.Surname})
+		}
+	}
+	sortBySurname(group.Members)
+	return &group, nil
+}
+
+func (r *GroupRepository) DeleteGroup(trainerId, groupId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.trainerGroup(trainerId, groupId); !ok {
+		return errors.New("no group to delete")
+	}
+	r.s.deleteGroup(groupId)
+	return nil
+}
+
+func (r *GroupRepository) AddGroupMember(trainerId, groupId, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.trainerGroup(trainerId, groupId); !ok || !r.s.hasApprovedPartnership(trainerId, userId) {
+		return errors.New("no group or approved partnership with user")
+	}
+	member := groupMember{groupId: groupId, userId: userId}
+	if r.s.members[member] {
+		return errors.New("user is already a member of group")
+	}
+	r.s.members[member] = true
+	return nil
+}
+
+func (r *GroupRepository) RemoveGroupMember(trainerId, groupId, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	member := groupMember{groupId: groupId, userId: userId}
+	if _, ok := r.trainerGroup(trainerId, groupId); !ok || !r.s.members[member] {
+		return errors.New("no member to remove")
+	}
+	delete(r.s.members, member)
+	return nil
+}
+
+// CreateGroupWorkout creates workout with personal workout of every participant, nothing is created
+// if any of users can't take part.
+func (r *GroupRepository) CreateGroupWorkout(workout *entity.GroupWorkout, userIds []int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	seen := make(map[int64]bool)
+	for _, userId := range userIds {
+		if !r.s.hasApprovedPartnership(workout.TrainerId, userId) {
+			return -1, fmt.Errorf("no approved partnership with user %d", userId)
+		}
+		if seen[userId] {
+			return -1, fmt.Errorf("user %d is already a participant", userId)
+		}
+		seen[userId] = true
+	}
+
+	r.s.lastGroupWorkoutId++
+	workout.Id = r.s.lastGroupWorkoutId
+	r.s.groupWorkouts[workout.Id] = &entity.GroupWorkout{Id: workout.Id, TrainerId: workout.TrainerId,
+		GroupId: workout.GroupId, Title: workout.Title, Description: workout.Description, Date: workout.Date,
+		Capacity: workout.Capacity, CreatedAt: time.Now()}
+	for _, userId := range userIds {
+		r.insertParticipant(workout, userId)
+	}
+	return workout.Id, nil
+}
+
+func (r *GroupRepository) GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	workouts := make([]*entity.GroupWorkout, 0)
+	for _, id := range sortedIds(r.s.groupWorkouts) {
+		if w := r.s.groupWorkouts[id]; w.TrainerId == trainerId {
+			cp := *w
+			workouts = append(workouts, &cp)
+		}
+	}
+	sort.SliceStable(workouts, func(i, j int) bool { return workouts[i].Date.After(workouts[j].Date) })
+	return workouts, nil
+}
+
+func (r *GroupRepository) GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	w, ok := r.trainerGroupWorkout(trainerId, groupWorkoutId)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	workout := *w
+	workout.Participants = make([]*entity.Participant, 0)
+	for _, id := range sortedIds(r.s.participants) {
+		p := r.s.participants[id]
+		if p.GroupWorkoutId != groupWorkoutId {
+			continue
+		}
+		cp := *p
+		if u, ok := r.s.users[p.UserId]; ok {
+			cp.Name, cp.Surname = u.Name, u.Surname
+		}
+		workout.Participants = append(workout.Participants, &cp)
+	}
+	sort.SliceStable(workout.Participants, func(i, j int) bool {
+		return workout.Participants[i].Surname < workout.Participants[j].Surname
+	})
+	return &workout, nil
+}
+
+// DeleteGroupWorkout removes group workout with personal workouts of participants.
+func (r *GroupRepository) DeleteGroupWorkout(trainerId, groupWorkoutId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.trainerGroupWorkout(trainerId, groupWorkoutId); !ok {
+		return errors.New("no group workout to delete")
+	}
+	for workoutId, p := range r.s.participants {
+		if p.GroupWorkoutId == groupWorkoutId {
+			r.s.deleteWorkout(workoutId)
+		}
+	}
+	r.s.deleteGroupWorkout(groupWorkoutId)
+	return nil
+}
+
+func (r *GroupRepository) AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	workout, ok := r.trainerGroupWorkout(trainerId, groupWorkoutId)
+	if !ok {
+		return errors.New("no group workout with provided id")
+	}
+	participants := 0
+	for _, p := range r.s.participants {
+		if p.GroupWorkoutId == groupWorkoutId {
+			participants++
+		}
+	}
+	if participants >= workout.Capacity {
+		return errors.New("participant cap is reached")
+	}
+	if !r.s.hasApprovedPartnership(trainerId, userId) {
+		return fmt.Errorf("no approved partnership with user %d", userId)
+	}
+	if r.participant(trainerId, groupWorkoutId, userId) != nil {
+		return fmt.Errorf("user %d is already a participant", userId)
+	}
+	r.insertParticipant(workout, userId)
+	return nil
+}
+
+// RemoveGroupWorkoutParticipant removes personal workout of participant, participation goes with it.
+func (r *GroupRepository) RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p := r.participant(trainerId, groupWorkoutId, userId)
+	if p == nil {
+		return errors.New("no participant to remove")
+	}
+	r.s.deleteWorkout(p.WorkoutId)
+	return nil
+}
+
+func (r *GroupRepository) SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p := r.participant(trainerId, groupWorkoutId, userId)
+	if p == nil {
+		return errors.New("no participant with provided id")
+	}
+	p.Status = status
+	return nil
+}
+
+func (r *GroupRepository) trainerGroup(trainerId, groupId int64) (*entity.ClientGroup, bool) {
+	g, ok := r.s.groups[groupId]
+	return g, ok && g.TrainerId == trainerId
+}
+
+func (r *GroupRepository) trainerGroupWorkout(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, bool) {
+	w, ok := r.s.groupWorkouts[groupWorkoutId]
+	return w, ok && w.TrainerId == trainerId
+}
+
+func (r *GroupRepository) participant(trainerId, groupWorkoutId, userId int64) *entity.Participant {
+	if _, ok := r.trainerGroupWorkout(trainerId, groupWorkoutId); !ok {
+		return nil
+	}
+	for _, p := range r.s.participants {
+		if p.GroupWorkoutId == groupWorkoutId && p.UserId == userId {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *GroupRepository) insertParticipant(workout *entity.GroupWorkout, userId int64) {
+	workoutId := r.s.insertWorkout(&entity.Workout{Title: workout.Title,
+		TrainerId: sql.NullInt64{Int64: workout.TrainerId, Valid: true}, UserId: userId,
+		Description: workout.Description, Date: workout.Date})
+	r.s.participants[workoutId] = &entity.Participant{GroupWorkoutId: workout.Id, UserId: userId,
+		WorkoutId: workoutId, Status: entity.AttendanceRegistered}
+}
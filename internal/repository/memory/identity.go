@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+type IdentityRepository struct {
+	s *Store
+}
+
+func NewIdentityRepository(s *Store) *IdentityRepository {
+	return &IdentityRepository{s: s}
+}
+
+// GetIdentityUser returns user linked to provider account, nil means that account is not linked.
+func (r *IdentityRepository) GetIdentityUser(provider, subject string) (*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, i := range r.s.identities {
+		if i.Provider != provider || i.Subject != subject {
+			continue
+		}
+		if u, ok := r.s.activeUser(i.UserId); ok {
+			return &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname, Role: u.Role,
+				VerifiedAt: u.VerifiedAt}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateIdentityUser creates verified user without password for provider account.
+func (r *IdentityRepository) CreateIdentityUser(user *entity.User, identity *entity.ExternalIdentity) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.emailTaken(user.Email, 0) {
+		return -1, errors.New("email has already reserved")
+	}
+	id := r.s.lastUserId + 1
+	identity.UserId = id
+	if err := r.checkIdentity(identity); err != nil {
+		return -1, err
+	}
+
+	now := time.Now()
+	r.s.lastUserId = id
+	r.s.users[id] = &entity.User{Id: id, Email: user.Email, Role: entity.UserRole, Name: user.Name,
+		Surname: user.Surname, CreatedAt: now, TimeZone: "UTC", VerifiedAt: sql.NullTime{Time: now, Valid: true},
+		Version: 1}
+	r.insertIdentity(identity)
+	return id, nil
+}
+
+// LinkIdentity links provider account to user. With takeOver password of user is
+// cleared and sessions are revoked, it is used when user had never verified email.
+func (r *IdentityRepository) LinkIdentity(userId int64, identity *entity.ExternalIdentity, takeOver bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userId]
+	if !ok {
+		return errors.New("invalid userId")
+	}
+	identity.UserId = userId
+	if err := r.checkIdentity(identity); err != nil {
+		return err
+	}
+
+	if takeOver && !u.VerifiedAt.Valid {
+		u.PasswordHash = ""
+		u.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		u.TokenVersion++
+	}
+	r.insertIdentity(identity)
+	return nil
+}
+
+func (r *IdentityRepository) GetIdentities(userId int64) ([]*entity.ExternalIdentity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	identities := make([]*entity.ExternalIdentity, 0)
+	for _, id := range sortedIds(r.s.identities) {
+		if i := r.s.identities[id]; i.UserId == userId {
+			cp := *i
+			identities = append(identities, &cp)
+		}
+	}
+	sort.SliceStable(identities, func(i, j int) bool { return identities[i].Provider < identities[j].Provider })
+	return identities, nil
+}
+
+// UnlinkIdentity refuses to remove the only way to sign in of user without password.
+func (r *IdentityRepository) UnlinkIdentity(userId int64, provider string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	var linked int
+	var unlinked int64
+	for id, i := range r.s.identities {
+		if i.UserId != userId {
+			continue
+		}
+		linked++
+		if i.Provider == provider {
+			unlinked = id
+		}
+	}
+	if u.PasswordHash == "" && linked <= 1 {
+		return errors.New("reset password before unlinking the only sign in method")
+	}
+	if unlinked == 0 {
+		return errors.New("provider is not linked")
+	}
+	delete(r.s.identities, unlinked)
+	return nil
+}
+
+// checkIdentity keeps provider account linked to one user and user linked to one account of provider.
+func (r *IdentityRepository) checkIdentity(identity *entity.ExternalIdentity) error {
+	for _, i := range r.s.identities {
+		if i.Provider != identity.Provider {
+			continue
+		}
+		if i.Subject == identity.Subject {
+			return errors.New("provider account is already linked to another user")
+		}
+		if i.UserId == identity.UserId {
+			return errors.New("provider is already linked")
+		}
+	}
+	return nil
+}
+
+func (r *IdentityRepository) insertIdentity(identity *entity.ExternalIdentity) {
+	r.s.lastIdentityId++
+	r.s.identities[r.s.lastIdentityId] = &entity.ExternalIdentity{Id: r.s.lastIdentityId, UserId: identity.UserId,
+		Provider: identity.Provider, Subject: identity.Subject, Email: identity.Email, CreatedAt: time.Now()}
+}
@@ -0,0 +1,385 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store keeps data of in-memory repositories, which run the same contract tests as database backends.
+// Repositories created on the same store see the same data.
+type Store struct {
+	mu sync.Mutex
+
+	admins        map[int64]*entity.Admin
+	users         map[int64]*entity.User
+	partnerships  map[int64]*entity.Partnership
+	workouts      map[int64]*entity.Workout
+	auditLog      map[int64]*entity.AuditEntry
+	tokens        map[string]*entity.AccountToken
+	factors       map[int64]*entity.MFAFactor
+	recoveryCodes map[int64][]*recoveryCode
+	policies      map[entity.Role]*entity.MFAPolicy
+	identities    map[int64]*entity.ExternalIdentity
+	messages      map[int64]*entity.Message
+	comments      map[int64]*entity.Comment
+	exports       map[int64]*entity.DataExport
+	applications  map[int64]*entity.TrainerApplication
+	certificates  map[int64]*entity.Certificate
+	slots         map[int64]*entity.AvailabilitySlot
+	exceptions    map[int64]*entity.AvailabilityException
+	bookings      map[int64]*entity.Booking
+	groups        map[int64]*entity.ClientGroup
+	members       map[groupMember]bool
+	groupWorkouts map[int64]*entity.GroupWorkout
+	// participants are keyed by personal workout of participant, it is removed together with the workout
+	participants map[int64]*entity.Participant
+
+	lastAdminId        int64
+	lastUserId         int64
+	lastPartnershipId  int64
+	lastWorkoutId      int64
+	lastAuditId        int64
+	lastFactorId       int64
+	lastIdentityId     int64
+	lastMessageId      int64
+	lastCommentId      int64
+	lastExportId       int64
+	lastApplicationId  int64
+	lastCertificateId  int64
+	lastSlotId         int64
+	lastExceptionId    int64
+	lastBookingId      int64
+	lastGroupId        int64
+	lastGroupWorkoutId int64
+}
+
+type recoveryCode struct {
+	hash string
+	used bool
+}
+
+type groupMember struct {
+	groupId int64
+	userId  int64
+}
+
+func NewStore() *Store {
+	now := time.Now()
+	policies := make(map[entity.Role]*entity.MFAPolicy)
+	for _, role := range []entity.Role{entity.AdminRole, entity.TrainerRole, entity.UserRole} {
+		policies[role] = &entity.MFAPolicy{Role: role, UpdatedAt: now}
+	}
+
+	return &Store{
+		admins:        make(map[int64]*entity.Admin),
+		users:         make(map[int64]*entity.User),
+		partnerships:  make(map[int64]*entity.Partnership),
+		workouts:      make(map[int64]*entity.Workout),
+		auditLog:      make(map[int64]*entity.AuditEntry),
+		tokens:        make(map[string]*entity.AccountToken),
+		factors:       make(map[int64]*entity.MFAFactor),
+		recoveryCodes: make(map[int64][]*recoveryCode),
+		policies:      policies,
+		identities:    make(map[int64]*entity.ExternalIdentity),
+		messages:      make(map[int64]*entity.Message),
+		comments:      make(map[int64]*entity.Comment),
+		exports:       make(map[int64]*entity.DataExport),
+		applications:  make(map[int64]*entity.TrainerApplication),
+		certificates:  make(map[int64]*entity.Certificate),
+		slots:         make(map[int64]*entity.AvailabilitySlot),
+		exceptions:    make(map[int64]*entity.AvailabilityException),
+		bookings:      make(map[int64]*entity.Booking),
+		groups:        make(map[int64]*entity.ClientGroup),
+		members:       make(map[groupMember]bool),
+		groupWorkouts: make(map[int64]*entity.GroupWorkout),
+		participants:  make(map[int64]*entity.Participant),
+	}
+}
+
+// AddAdmin creates admin account, admins are not created through API.
+func (s *Store) AddAdmin(login, passwordHash string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAdminId++
+	s.admins[s.lastAdminId] = &entity.Admin{Id: s.lastAdminId, Login: login, PasswordHash: passwordHash}
+	return s.lastAdminId
+}
+
+func (s *Store) activeUser(id int64) (*entity.User, bool) {
+	u, ok := s.users[id]
+	if !ok || u.DeletedAt.Valid {
+		return nil, false
+	}
+	return u, true
+}
+
+func (s *Store) isTrainer(id int64) bool {
+	u, ok := s.activeUser(id)
+	return ok && u.Role == entity.TrainerRole
+}
+
+// emailTaken also sees soft-deleted accounts, their emails stay reserved until purge.
+func (s *Store) emailTaken(email string, exceptId int64) bool {
+	for id, u := range s.users {
+		if id != exceptId && u.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// currentPartnership picks the open partnership of pair, ended ones are kept as history.
+func (s *Store) currentPartnership(trainerId, userId int64) *entity.Partnership {
+	var current *entity.Partnership
+	for _, id := range sortedIds(s.partnerships) {
+		p := s.partnerships[id]
+		if p.TrainerId != trainerId || p.UserId != userId {
+			continue
+		}
+		if current == nil || isOpen(p) || !isOpen(current) {
+			current = p
+		}
+	}
+	return current
+}
+
+func (s *Store) hasApprovedPartnership(trainerId, userId int64) bool {
+	p := s.currentPartnership(trainerId, userId)
+	return p != nil && p.Status == entity.StatusApproved
+}
+
+func (s *Store) insertWorkout(workout *entity.Workout) int64 {
+	s.lastWorkoutId++
+	s.workouts[s.lastWorkoutId] = &entity.Workout{Id: s.lastWorkoutId, Title: workout.Title,
+		UserId: workout.UserId, TrainerId: workout.TrainerId, Description: workout.Description, Date: workout.Date,
+		Version: 1}
+	return s.lastWorkoutId
+}
+
+// deleteWorkout removes workout with its comments and group participation, bookings keep the slot.
+func (s *Store) deleteWorkout(workoutId int64) {
+	delete(s.workouts, workoutId)
+	delete(s.participants, workoutId)
+	for id, c := range s.comments {
+		if c.WorkoutId == workoutId {
+			delete(s.comments, id)
+		}
+	}
+	for _, b := range s.bookings {
+		if b.WorkoutId.Valid && b.WorkoutId.Int64 == workoutId {
+			b.WorkoutId = sql.NullInt64{}
+		}
+	}
+}
+
+// purgeUser removes user with everything referencing the account, like foreign keys do in postgres.
+func (s *Store) purgeUser(userId int64) {
+	delete(s.users, userId)
+	for id, p := range s.partnerships {
+		if p.UserId != userId && p.TrainerId != userId {
+			continue
+		}
+		delete(s.partnerships, id)
+		for mId, m := range s.messages {
+			if m.PartnershipId == id {
+				delete(s.messages, mId)
+			}
+		}
+	}
+	for id, w := range s.workouts {
+		if w.UserId == userId {
+			s.deleteWorkout(id)
+		} else if w.TrainerId.Valid && w.TrainerId.Int64 == userId {
+			w.TrainerId = sql.NullInt64{}
+		}
+	}
+	for id, m := range s.messages {
+		if m.SenderId == userId {
+			delete(s.messages, id)
+		}
+	}
+	for id, c := range s.comments {
+		if c.AuthorId == userId {
+			delete(s.comments, id)
+		}
+	}
+	for id, slot := range s.slots {
+		if slot.TrainerId == userId {
+			delete(s.slots, id)
+		}
+	}
+	for id, e := range s.exceptions {
+		if e.TrainerId == userId {
+			delete(s.exceptions, id)
+		}
+	}
+	for id, b := range s.bookings {
+		if b.UserId == userId || b.TrainerId == userId {
+			delete(s.bookings, id)
+		} else if b.CancelledBy.Valid && b.CancelledBy.Int64 == userId {
+			b.CancelledBy = sql.NullInt64{}
+		}
+	}
+	for id, g := range s.groups {
+		if g.TrainerId == userId {
+			s.deleteGroup(id)
+		}
+	}
+	for m := range s.members {
+		if m.userId == userId {
+			delete(s.members, m)
+		}
+	}
+	for id, w := range s.groupWorkouts {
+		if w.TrainerId == userId {
+			s.deleteGroupWorkout(id)
+		}
+	}
+	for id, p := range s.participants {
+		if p.UserId == userId {
+			delete(s.participants, id)
+		}
+	}
+	for id, e := range s.exports {
+		if e.UserId == userId {
+			delete(s.exports, id)
+		}
+	}
+	for id, t := range s.tokens {
+		if t.UserId == userId {
+			delete(s.tokens, id)
+		}
+	}
+	for id, a := range s.applications {
+		if a.UserId == userId {
+			s.deleteApplication(id)
+		}
+	}
+	for id, f := range s.factors {
+		if f.UserId.Valid && f.UserId.Int64 == userId {
+			s.deleteFactor(id)
+		}
+	}
+	for id, i := range s.identities {
+		if i.UserId == userId {
+			delete(s.identities, id)
+		}
+	}
+}
+
+func (s *Store) deleteGroup(groupId int64) {
+	delete(s.groups, groupId)
+	for m := range s.members {
+		if m.groupId == groupId {
+			delete(s.members, m)
+		}
+	}
+	for _, w := range s.groupWorkouts {
+		if w.GroupId.Valid && w.GroupId.Int64 == groupId {
+			w.GroupId = sql.NullInt64{}
+		}
+	}
+}
+
+func (s *Store) deleteGroupWorkout(groupWorkoutId int64) {
+	delete(s.groupWorkouts, groupWorkoutId)
+	for id, p := range s.participants {
+		if p.GroupWorkoutId == groupWorkoutId {
+			delete(s.participants, id)
+		}
+	}
+}
+
+func (s *Store) deleteApplication(appId int64) {
+	delete(s.applications, appId)
+	for id, c := range s.certificates {
+		if c.ApplicationId == appId {
+			delete(s.certificates, id)
+		}
+	}
+}
+
+func (s *Store) deleteFactor(factorId int64) {
+	delete(s.factors, factorId)
+	delete(s.recoveryCodes, factorId)
+}
+
+// writeAudit stores changed fields of target together with mutation.
+// Nil actor means that mutation is not privileged and is not recorded.
+func (s *Store) writeAudit(actor *entity.Actor, action entity.AuditAction, targetType string, targetId int64,
+	before, after map[string]interface{}) error {
+	if actor == nil {
+		return nil
+	}
+
+	before, after = auditDiff(before, after)
+	beforeData, err := marshalAudit(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := marshalAudit(after)
+	if err != nil {
+		return err
+	}
+
+	s.lastAuditId++
+	s.auditLog[s.lastAuditId] = &entity.AuditEntry{Id: s.lastAuditId, ActorType: actor.Type, ActorId: actor.Id,
+		Action: action, TargetType: targetType, TargetId: targetId, Before: beforeData, After: afterData,
+		RequestId: actor.RequestId, IP: actor.IP, CreatedAt: time.Now()}
+	return nil
+}
+
+func auditDiff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+func marshalAudit(data map[string]interface{}) (entity.AuditData, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+func userAuditState(user *entity.User) map[string]interface{} {
+	return map[string]interface{}{
+		"email":   user.Email,
+		"role":    string(user.Role),
+		"name":    user.Name,
+		"surname": user.Surname,
+	}
+}
+
+func partnershipAuditState(p *entity.Partnership) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    p.UserId,
+		"trainer_id": p.TrainerId,
+		"status":     string(p.Status),
+	}
+}
+
+// sortedIds returns keys in ascending order, ids grow with insertion, so ties in other orderings keep it.
+func sortedIds[T any](rows map[int64]T) []int64 {
+	ids := make([]int64, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,24 @@
+package memory_test
+
+import (
+	"Fitness_REST_API/internal/repository/memory"
+	"Fitness_REST_API/internal/repository/repotest"
+	"testing"
+)
+
+func TestContract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) *repotest.Backend {
+		store := memory.NewStore()
+		return &repotest.Backend{
+			Admin:    memory.NewAdminRepository(store),
+			User:     memory.NewUserRepository(store),
+			Account:  memory.NewAccountRepository(store),
+			Audit:    memory.NewAuditRepository(store),
+			MFA:      memory.NewMFARepository(store),
+			Identity: memory.NewIdentityRepository(store),
+			AddAdmin: func(t *testing.T, login, passwordHash string) int64 {
+				return store.AddAdmin(login, passwordHash)
+			},
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type MessageRepository struct {
+	s *Store
+}
+
+func NewMessageRepository(s *Store) *MessageRepository {
+	return &MessageRepository{s: s}
+}
+
+func (r *MessageRepository) GetMessages(partnershipId, beforeId int64, limit int) ([]*entity.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	messages := make([]*entity.Message, 0)
+	ids := sortedIds(r.s.messages)
+	for i := len(ids) - 1; i >= 0 && len(messages) < limit; i-- {
+		m := r.s.messages[ids[i]]
+		if m.PartnershipId == partnershipId && (beforeId == 0 || m.Id < beforeId) {
+			cp := *m
+			messages = append(messages, &cp)
+		}
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) CreateMessage(message *entity.Message) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.partnerships[message.PartnershipId]
+	if !ok || p.Status != entity.StatusApproved {
+		return -1, errors.New("partnership is not active, conversation is read-only")
+	}
+	r.s.lastMessageId++
+	r.s.messages[r.s.lastMessageId] = &entity.Message{Id: r.s.lastMessageId, PartnershipId: message.PartnershipId,
+		SenderId: message.SenderId, Body: message.Body, CreatedAt: time.Now()}
+	return r.s.lastMessageId, nil
+}
+
+func (r *MessageRepository) MarkMessagesRead(partnershipId, readerId int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var read int64
+	now := time.Now()
+	for _, m := range r.s.messages {
+		if m.PartnershipId == partnershipId && m.SenderId != readerId && !m.ReadAt.Valid {
+			m.ReadAt = sql.NullTime{Time: now, Valid: true}
+			read++
+		}
+	}
+	return read, nil
+}
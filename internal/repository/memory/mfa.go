@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+type MFARepository struct {
+	s *Store
+}
+
+func NewMFARepository(s *Store) *MFARepository {
+	return &MFARepository{s: s}
+}
+
+func (r *MFARepository) GetFactor(subject entity.ActorType, subjectId int64) (*entity.MFAFactor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	f := r.factor(subject, subjectId)
+	if f == nil {
+		return nil, nil
+	}
+	cp := *f
+	return &cp, nil
+}
+
+// SaveFactor stores secret of pending enrollment, secret of confirmed factor can't be replaced.
+func (r *MFARepository) SaveFactor(subject entity.ActorType, subjectId int64, secret string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	f := r.factor(subject, subjectId)
+	if f == nil {
+		r.s.lastFactorId++
+		f = &entity.MFAFactor{Id: r.s.lastFactorId}
+		subjectRef := sql.NullInt64{Int64: subjectId, Valid: true}
+		if subject == entity.ActorAdmin {
+			f.AdminId = subjectRef
+		} else {
+			f.UserId = subjectRef
+		}
+		r.s.factors[f.Id] = f
+	} else if f.ConfirmedAt.Valid {
+		return errors.New("two-factor authentication is already enabled")
+	}
+	f.Secret, f.LastStep, f.CreatedAt = secret, 0, time.Now()
+	return nil
+}
+
+// ConfirmFactor activates pending factor and replaces its recovery codes.
+func (r *MFARepository) ConfirmFactor(subject entity.ActorType, subjectId, step int64, codeHashes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	f := r.factor(subject, subjectId)
+	if f == nil || f.ConfirmedAt.Valid {
+		return errors.New("two-factor enrollment is not started")
+	}
+	f.ConfirmedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	f.LastStep = step
+
+	codes := make([]*recoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &recoveryCode{hash: hash})
+	}
+	r.s.recoveryCodes[f.Id] = codes
+	return nil
+}
+
+// UseStep consumes time step of accepted code, so that the code can't be replayed.
+func (r *MFARepository) UseStep(subject entity.ActorType, subjectId, step int64) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	f := r.factor(subject, subjectId)
+	if f == nil || !f.ConfirmedAt.Valid || f.LastStep >= step {
+		return false, nil
+	}
+	f.LastStep = step
+	return true, nil
+}
+
+func (r *MFARepository) UseRecoveryCode(subject entity.ActorType, subjectId int64, codeHash string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	f := r.factor(subject, subjectId)
+	if f == nil || !f.ConfirmedAt.Valid {
+		return false, nil
+	}
+	for _, code := range r.s.recoveryCodes[f.Id] {
+		if code.hash == codeHash && !code.used {
+			code.used = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MFARepository) DeleteFactor(subject entity.ActorType, subjectId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if f := r.factor(subject, subjectId); f != nil {
+		r.s.deleteFactor(f.Id)
+	}
+	return nil
+}
+
+func (r *MFARepository) GetPolicies() ([]*entity.MFAPolicy, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	policies := make([]*entity.MFAPolicy, 0, len(r.s.policies))
+	for _, p := range r.s.policies {
+		cp := *p
+		policies = append(policies, &cp)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Role < policies[j].Role })
+	return policies, nil
+}
+
+func (r *MFARepository) IsRequired(role entity.Role) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.policies[role]
+	return ok && p.Required, nil
+}
+
+func (r *MFARepository) SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.policies[policy.Role]
+	if !ok {
+		return errors.New("no policy for provided role")
+	}
+	err := r.s.writeAudit(actor, entity.AuditMFAPolicy, entity.AuditTargetMFAPolicy, 0,
+		mfaPolicyAuditState(p), mfaPolicyAuditState(policy))
+	if err != nil {
+		return err
+	}
+	p.Required, p.UpdatedAt = policy.Required, time.Now()
+	return nil
+}
+
+func (r *MFARepository) factor(subject entity.ActorType, subjectId int64) *entity.MFAFactor {
+	for _, f := range r.s.factors {
+		ref := f.UserId
+		if subject == entity.ActorAdmin {
+			ref = f.AdminId
+		}
+		if ref.Valid && ref.Int64 == subjectId {
+			return f
+		}
+	}
+	return nil
+}
+
+// mfaPolicyAuditState is keyed by role, so that diff still names the role whose policy is changed.
+func mfaPolicyAuditState(p *entity.MFAPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		string(p.Role): p.Required,
+	}
+}
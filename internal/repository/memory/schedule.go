@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	clockLayout = "15:04"
+	dateLayout  = "2006-01-02"
+)
+
+type ScheduleRepository struct {
+	s *Store
+}
+
+func NewScheduleRepository(s *Store) *ScheduleRepository {
+	return &ScheduleRepository{s: s}
+}
+
+func (r *ScheduleRepository) CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	startTime, endTime, err := clockRange(slot.StartTime, slot.EndTime)
+	if err != nil {
+		return 0, err
+	}
+	r.s.lastSlotId++
+	r.s.slots[r.s.lastSlotId] = &entity.AvailabilitySlot{Id: r.s.lastSlotId, TrainerId: slot.TrainerId,
+		Weekday: slot.Weekday, StartTime: startTime, EndTime: endTime, TimeZone: slot.TimeZone}
+	return r.s.lastSlotId, nil
+}
+
+func (r *ScheduleRepository) GetAvailabilitySlots(trainerId int64) ([]*entity.AvailabilitySlot, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	slots := make([]*entity.AvailabilitySlot, 0)
+	for _, id := range sortedIds(r.s.slots) {
+		if slot := r.s.slots[id]; slot.TrainerId == trainerId {
+			cp := *slot
+			slots = append(slots, &cp)
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Weekday != slots[j].Weekday {
+			return slots[i].Weekday < slots[j].Weekday
+		}
+		return slots[i].StartTime < slots[j].StartTime
+	})
+	return slots, nil
+}
+
+func (r *ScheduleRepository) DeleteAvailabilitySlot(trainerId, slotId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	slot, ok := r.s.slots[slotId]
+	if !ok || slot.TrainerId != trainerId {
+		return errors.New("no slot to delete")
+	}
+	delete(r.s.slots, slotId)
+	return nil
+}
+
+func (r *ScheduleRepository) CreateAvailabilityException(e *entity.AvailabilityException) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	date, err := time.Parse(dateLayout, e.Date)
+	if err != nil {
+		return 0, fmt.Errorf("invalid date %q", e.Date)
+	}
+	startTime, endTime, err := clockRange(e.StartTime, e.EndTime)
+	if err != nil {
+		return 0, err
+	}
+	r.s.lastExceptionId++
+	r.s.exceptions[r.s.lastExceptionId] = &entity.AvailabilityException{Id: r.s.lastExceptionId,
+		TrainerId: e.TrainerId, Date: date.Format(dateLayout), StartTime: startTime, EndTime: endTime,
+		Available: e.Available, TimeZone: e.TimeZone}
+	return r.s.lastExceptionId, nil
+}
+
+func (r *ScheduleRepository) GetAvailabilityExceptions(trainerId int64, from, to time.Time) (
+	[]*entity.AvailabilityException, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	fromDate, toDate := from.Format(dateLayout), to.Format(dateLayout)
+	exceptions := make([]*entity.AvailabilityException, 0)
+	for _, id := range sortedIds(r.s.exceptions) {
+		e := r.s.exceptions[id]
+		if e.TrainerId == trainerId && e.Date >= fromDate && e.Date <= toDate {
+			cp := *e
+			exceptions = append(exceptions, &cp)
+		}
+	}
+	sort.SliceStable(exceptions, func(i, j int) bool {
+		if exceptions[i].Date != exceptions[j].Date {
+			return exceptions[i].Date < exceptions[j].Date
+		}
+		return exceptions[i].StartTime < exceptions[j].StartTime
+	})
+	return exceptions, nil
+}
+
+func (r *ScheduleRepository) DeleteAvailabilityException(trainerId, exceptionId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	e, ok := r.s.exceptions[exceptionId]
+	if !ok || e.TrainerId != trainerId {
+		return errors.New("no exception to delete")
+	}
+	delete(r.s.exceptions, exceptionId)
+	return nil
+}
+
+func (r *ScheduleRepository) GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	bookings := r.selectBookings(func(b *entity.Booking) bool {
+		return b.TrainerId == trainerId && b.Status == entity.BookingStatusBooked &&
+			b.StartsAt.Before(to) && b.EndsAt.After(from)
+	})
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].StartsAt.Before(bookings[j].StartsAt) })
+	return bookings, nil
+}
+
+func (r *ScheduleRepository) GetUserBookings(userId int64) ([]*entity.Booking, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	bookings := r.selectBookings(func(b *entity.Booking) bool { return b.UserId == userId })
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].StartsAt.After(bookings[j].StartsAt) })
+	return bookings, nil
+}
+
+func (r *ScheduleRepository) GetBookingById(bookingId int64) (*entity.Booking, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	b, ok := r.s.bookings[bookingId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *b
+	return &cp, nil
+}
+
+// CreateBooking books range of trainer together with workout, booked ranges of trainer or user can't overlap.
+func (r *ScheduleRepository) CreateBooking(booking *entity.Booking, workout *entity.Workout) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.hasApprovedPartnership(booking.TrainerId, booking.UserId) {
+		return -1, errors.New("no approved partnership with trainer")
+	}
+	for _, b := range r.s.bookings {
+		if b.Status != entity.BookingStatusBooked || (b.TrainerId != booking.TrainerId && b.UserId != booking.UserId) {
+			continue
+		}
+		if b.StartsAt.Before(booking.EndsAt) && booking.StartsAt.Before(b.EndsAt) {
+			return -1, errors.New("slot is already booked")
+		}
+	}
+
+	workout.Id = r.s.insertWorkout(workout)
+	r.s.lastBookingId++
+	r.s.bookings[r.s.lastBookingId] = &entity.Booking{Id: r.s.lastBookingId, TrainerId: booking.TrainerId,
+		UserId: booking.UserId, WorkoutId: sql.NullInt64{Int64: workout.Id, Valid: true},
+		StartsAt: booking.StartsAt, EndsAt: booking.EndsAt, Status: entity.BookingStatusBooked, CreatedAt: time.Now()}
+	return r.s.lastBookingId, nil
+}
+
+// CancelBooking frees the range and removes workout created for it.
+func (r *ScheduleRepository) CancelBooking(bookingId, cancelledBy int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	b, ok := r.s.bookings[bookingId]
+	if !ok || b.Status != entity.BookingStatusBooked {
+		return errors.New("no booking to cancel")
+	}
+	b.Status = entity.BookingStatusCancelled
+	b.CancelledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	b.CancelledBy = sql.NullInt64{Int64: cancelledBy, Valid: true}
+	if b.WorkoutId.Valid {
+		r.s.deleteWorkout(b.WorkoutId.Int64)
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) selectBookings(match func(b *entity.Booking) bool) []*entity.Booking {
+	bookings := make([]*entity.Booking, 0)
+	for _, id := range sortedIds(r.s.bookings) {
+		if b := r.s.bookings[id]; match(b) {
+			cp := *b
+			bookings = append(bookings, &cp)
+		}
+	}
+	return bookings
+}
+
+// clockRange normalizes time of day to HH:MM, like databases return it, so values compare in time order.
+func clockRange(start, end string) (string, string, error) {
+	startTime, err := parseClock(start)
+	if err != nil {
+		return "", "", err
+	}
+	endTime, err := parseClock(end)
+	if err != nil {
+		return "", "", err
+	}
+	if endTime <= startTime {
+		return "", "", errors.New("end time has to be after start time")
+	}
+	return startTime, endTime, nil
+}
+
+func parseClock(value string) (string, error) {
+	for _, layout := range []string{"15:04:05", clockLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(clockLayout), nil
+		}
+	}
+	return "", fmt.Errorf("invalid time of day %q", value)
+}
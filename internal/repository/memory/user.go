@@ -0,0 +1,834 @@
+package memory
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// UserRepository follows semantics of postgres implementation, missing rows are reported with sql.ErrNoRows.
+type UserRepository struct {
+	s *Store
+}
+
+func NewUserRepository(s *Store) *UserRepository {
+	return &UserRepository{s: s}
+}
+
+func (r *UserRepository) Authorize(email, passwordHash string, role entity.Role) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range sortedIds(r.s.users) {
+		u := r.s.users[id]
+		if u.Email == email && u.PasswordHash == passwordHash && u.Role == role && !u.DeletedAt.Valid {
+			return u.Id, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+func (r *UserRepository) CreateUser(actor *entity.Actor, user *entity.User, role entity.Role) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.emailTaken(user.Email, 0) {
+		return -1, errors.New("email has already reserved")
+	}
+	id := r.s.lastUserId + 1
+	after := userAuditState(user)
+	after["role"] = string(role)
+	if err := r.s.writeAudit(actor, entity.AuditUserCreate, entity.AuditTargetUser, id, nil, after); err != nil {
+		return 0, err
+	}
+
+	r.s.lastUserId = id
+	r.s.users[id] = &entity.User{
+		Id:           id,
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Role:         role,
+		Name:         user.Name,
+		Surname:      user.Surname,
+		CreatedAt:    time.Now(),
+		TimeZone:     "UTC",
+		VerifiedAt:   user.VerifiedAt,
+		Version:      1,
+	}
+	return id, nil
+}
+
+func (r *UserRepository) GetUserInfoById(id int64) (*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(id)
+	if !ok {
+		return &entity.User{}, sql.ErrNoRows
+	}
+	return &entity.User{Id: u.Id, Email: u.Email, PasswordHash: u.PasswordHash, Name: u.Name, Surname: u.Surname,
+		Role: u.Role, CreatedAt: u.CreatedAt, TimeZone: u.TimeZone, Version: u.Version}, nil
+}
+
+func (r *UserRepository) GetUserByEmail(email string) (*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range sortedIds(r.s.users) {
+		u := r.s.users[id]
+		if u.Email == email && !u.DeletedAt.Valid {
+			return &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname, Role: u.Role,
+				VerifiedAt: u.VerifiedAt}, nil
+		}
+	}
+	return &entity.User{}, sql.ErrNoRows
+}
+
+func (r *UserRepository) IsVerified(userId int64) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return false, sql.ErrNoRows
+	}
+	return u.VerifiedAt.Valid, nil
+}
+
+func (r *UserRepository) GetTimeZone(userId int64) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return u.TimeZone, nil
+}
+
+func (r *UserRepository) GetTokenVersion(userId int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return u.TokenVersion, nil
+}
+
+func (r *UserRepository) UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.emailTaken(update.Email, userId) {
+		return errors.New("provided email has already been reserved")
+	}
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("invalid userId")
+	}
+	if version != 0 && u.Version != version {
+		return entity.ErrVersionMismatch
+	}
+	if u.Email != update.Email {
+		u.VerifiedAt = sql.NullTime{}
+		r.revokeVerification(userId)
+	}
+	u.Email, u.Name, u.Surname, u.TimeZone = update.Email, update.Name, update.Surname, update.TimeZone
+	u.Version++
+	return nil
+}
+
+func (r *UserRepository) PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if patch.Email.Set && r.s.emailTaken(patch.Email.Value, userId) {
+		return errors.New("provided email has already been reserved")
+	}
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("invalid userId")
+	}
+	if version != 0 && u.Version != version {
+		return entity.ErrVersionMismatch
+	}
+	if patch.Email.Set && u.Email != patch.Email.Value {
+		u.Email, u.VerifiedAt = patch.Email.Value, sql.NullTime{}
+		r.revokeVerification(userId)
+	}
+	if patch.Name.Set {
+		u.Name = patch.Name.Value
+	}
+	if patch.Surname.Set {
+		u.Surname = patch.Surname.Value
+	}
+	if patch.TimeZone.Set {
+		u.TimeZone = patch.TimeZone.Value
+	}
+	u.Version++
+	return nil
+}
+
+func (r *UserRepository) ChangePassword(userId int64, passwordHash string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	u.PasswordHash = passwordHash
+	u.TokenVersion++
+	return u.TokenVersion, nil
+}
+
+func (r *UserRepository) CreateWorkoutAsUser(workout *entity.Workout) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if workout.TrainerId.Int64 > 0 && !r.s.isTrainer(workout.TrainerId.Int64) {
+		return -1, errors.New("can't set common user as a trainer")
+	}
+	if _, ok := r.s.users[workout.UserId]; !ok {
+		return -1, fmt.Errorf("no user with id %d", workout.UserId)
+	}
+	return r.s.insertWorkout(workout), nil
+}
+
+func (r *UserRepository) CheckAccessToWorkout(workoutId, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.checkAccess(workoutId, userId)
+}
+
+func (r *UserRepository) GetUserWorkouts(userId int64, from, to time.Time) ([]*entity.Workout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.selectWorkouts(func(w *entity.Workout) bool {
+		return w.UserId == userId && (from.IsZero() || !w.Date.Before(from)) && (to.IsZero() || w.Date.Before(to))
+	}), nil
+}
+
+func (r *UserRepository) GetWorkoutById(workoutId, userId int64) (*entity.Workout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.checkAccess(workoutId, userId); err != nil {
+		return nil, err
+	}
+	w := *r.s.workouts[workoutId]
+	return &w, nil
+}
+
+func (r *UserRepository) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.checkAccess(workoutId, userId); err != nil {
+		return err
+	}
+	w := r.s.workouts[workoutId]
+	if version != 0 && w.Version != version {
+		return entity.ErrVersionMismatch
+	}
+	w.Title, w.Description, w.Date = update.Title, update.Description, update.Date
+	w.Version++
+	return nil
+}
+
+func (r *UserRepository) PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch,
+	version int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.checkAccess(workoutId, userId); err != nil {
+		return 0, err
+	}
+	w := r.s.workouts[workoutId]
+	if version != 0 && w.Version != version {
+		return 0, entity.ErrVersionMismatch
+	}
+	if patch.Title.Set {
+		w.Title = patch.Title.Value
+	}
+	if patch.Description.Set {
+		w.Description = patch.Description.Value
+	}
+	if patch.Date.Set {
+		w.Date = patch.Date.Value
+	}
+	w.Version++
+	return w.Version, nil
+}
+
+func (r *UserRepository) DeleteWorkout(workoutId, userId, version int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.checkAccess(workoutId, userId); err != nil {
+		return err
+	}
+	if version != 0 && r.s.workouts[workoutId].Version != version {
+		return entity.ErrVersionMismatch
+	}
+	r.s.deleteWorkout(workoutId)
+	return nil
+}
+
+func (r *UserRepository) GetTrainers() ([]*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	trainers := make([]*entity.User, 0)
+	for _, id := range sortedIds(r.s.users) {
+		u := r.s.users[id]
+		if u.Role == entity.TrainerRole && !u.DeletedAt.Valid {
+			trainers = append(trainers, &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname})
+		}
+	}
+	sortBySurname(trainers)
+	return trainers, nil
+}
+
+func (r *UserRepository) GetTrainerById(id int64) (*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.isTrainer(id) {
+		return nil, sql.ErrNoRows
+	}
+	u := r.s.users[id]
+	return &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname}, nil
+}
+
+func (r *UserRepository) GetUserPartnerships(userId int64) ([]*entity.Partnership, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.selectPartnerships(func(p *entity.Partnership) bool { return p.UserId == userId }), nil
+}
+
+func (r *UserRepository) GetPartnershipById(partnershipId int64) (*entity.Partnership, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.partnerships[partnershipId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *UserRepository) GetPartnership(trainerId, userId int64) (*entity.Partnership, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p := r.s.currentPartnership(trainerId, userId)
+	if p == nil {
+		return &entity.Partnership{}, sql.ErrNoRows
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *UserRepository) SendRequestToTrainer(trainerId, userId int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.isTrainer(trainerId) {
+		return -1, errors.New("can't send request not to trainer")
+	}
+
+	p := r.s.currentPartnership(trainerId, userId)
+	if p == nil {
+		return r.insertPartnership(trainerId, userId, entity.StatusRequest), nil
+	}
+	switch p.Status {
+	case entity.StatusApproved:
+		return -1, errors.New("there is already approved partnership with trainer")
+	case entity.StatusRequest:
+		return p.Id, nil
+	case entity.StatusEndedByTrainer, entity.StatusEndedByUser:
+		p.Status = entity.StatusRequest
+		return p.Id, nil
+	}
+	return 0, errors.New("undefined partnership on provided id")
+}
+
+func (r *UserRepository) EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	return r.endPartnership(actor, trainerId, userId, entity.StatusEndedByUser)
+}
+
+func (r *UserRepository) GetTrainerUsers(trainerId int64) ([]*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.isTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+
+	users := make([]*entity.User, 0)
+	for _, id := range sortedIds(r.s.partnerships) {
+		p := r.s.partnerships[id]
+		if p.TrainerId != trainerId || p.Status != entity.StatusApproved {
+			continue
+		}
+		if u, ok := r.s.activeUser(p.UserId); ok {
+			users = append(users, &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname,
+				CreatedAt: p.CreatedAt})
+		}
+	}
+	sortBySurname(users)
+	return users, nil
+}
+
+func (r *UserRepository) GetTrainerRequests(trainerId int64) ([]*entity.Request, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.isTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+
+	requests := make([]*entity.Request, 0)
+	for _, id := range sortedIds(r.s.partnerships) {
+		p := r.s.partnerships[id]
+		if p.TrainerId != trainerId || p.Status != entity.StatusRequest {
+			continue
+		}
+		if u, ok := r.s.activeUser(p.UserId); ok {
+			requests = append(requests, newRequest(u, p))
+		}
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].SendAt.After(requests[j].SendAt) })
+	return requests, nil
+}
+
+func (r *UserRepository) GetTrainerUserById(trainerId, userId int64) (*entity.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.isTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+	if p := r.s.currentPartnership(trainerId, userId); p == nil || p.Status != entity.StatusApproved {
+		return nil, errors.New("approved partnership with user was not found")
+	}
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &entity.User{Id: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname}, nil
+}
+
+func (r *UserRepository) GetTrainerRequestById(trainerId, requestId int64) (*entity.Request, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.partnerships[requestId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if p.Status != entity.StatusRequest {
+		return nil, errors.New("no request for you on that id")
+	}
+	if p.TrainerId != trainerId {
+		return nil, errors.New("no access to request")
+	}
+	u, ok := r.s.users[p.UserId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return newRequest(u, p), nil
+}
+
+func (r *UserRepository) InitPartnershipWithUser(trainerId, userId int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.activeUser(userId); !ok {
+		return -1, errors.New("invalid userId")
+	}
+
+	p := r.s.currentPartnership(trainerId, userId)
+	if p == nil {
+		return r.insertPartnership(trainerId, userId, entity.StatusApproved), nil
+	}
+	switch p.Status {
+	case entity.StatusEndedByUser:
+		return p.Id, errors.New("partnership was ended by user, it can be resumed only by request from user")
+	case entity.StatusApproved:
+		return p.Id, nil
+	case entity.StatusEndedByTrainer, entity.StatusRequest:
+		p.Status = entity.StatusApproved
+		p.EndedAt = sql.NullTime{}
+		return p.Id, nil
+	}
+	return 0, errors.New("undefined status of partnership")
+}
+
+func (r *UserRepository) EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	return r.endPartnership(actor, trainerId, userId, entity.StatusEndedByTrainer)
+}
+
+func (r *UserRepository) AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.partnerships[requestId]
+	if !ok || p.TrainerId != trainerId || p.Status != entity.StatusRequest {
+		return -1, errors.New("no request to accept")
+	}
+
+	after := partnershipAuditState(p)
+	after["status"] = string(entity.StatusApproved)
+	err := r.s.writeAudit(actor, entity.AuditRequestAccept, entity.AuditTargetPartnership, p.Id,
+		partnershipAuditState(p), after)
+	if err != nil {
+		return 0, err
+	}
+	p.Status = entity.StatusApproved
+	return p.Id, nil
+}
+
+func (r *UserRepository) DenyRequest(actor *entity.Actor, trainerId, requestId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p, ok := r.s.partnerships[requestId]
+	if !ok || p.TrainerId != trainerId || p.Status != entity.StatusRequest {
+		return errors.New("no request to deny")
+	}
+
+	err := r.s.writeAudit(actor, entity.AuditRequestDeny, entity.AuditTargetPartnership, requestId,
+		map[string]interface{}{"status": string(entity.StatusRequest)},
+		map[string]interface{}{"status": string(entity.StatusEndedByTrainer)})
+	if err != nil {
+		return err
+	}
+	p.Status = entity.StatusEndedByTrainer
+	p.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (r *UserRepository) CreateWorkoutAsTrainer(workout *entity.Workout) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p := r.s.currentPartnership(workout.TrainerId.Int64, workout.UserId)
+	if p == nil || p.Status != entity.StatusApproved {
+		return -1, entity.ErrNoWorkoutRights
+	}
+	return r.s.insertWorkout(workout), nil
+}
+
+func (r *UserRepository) GetTrainerWorkouts(trainerId int64) ([]*entity.Workout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.selectWorkouts(func(w *entity.Workout) bool {
+		return w.TrainerId.Valid && w.TrainerId.Int64 == trainerId
+	}), nil
+}
+
+func (r *UserRepository) GetTrainerWorkoutsWithUser(trainerId, userId int64) ([]*entity.Workout, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.selectWorkouts(func(w *entity.Workout) bool {
+		return w.TrainerId.Valid && w.TrainerId.Int64 == trainerId && w.UserId == userId
+	}), nil
+}
+
+func (r *UserRepository) GetUsersId(role entity.Role) ([]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]int64, 0)
+	for _, id := range sortedIds(r.s.users) {
+		u := r.s.users[id]
+		if u.Role == role && !u.DeletedAt.Valid {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *UserRepository) GetUserFullInfoById(userId int64) (*entity.UserInfo, error) {
+	user, err := r.GetUserInfoById(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &entity.UserInfo{Id: user.Id, Email: user.Email, Role: user.Role, Name: user.Name,
+		Surname: user.Surname, CreatedAt: user.CreatedAt}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	switch user.Role {
+	case entity.UserRole:
+		info.Partnerships = r.selectPartnerships(func(p *entity.Partnership) bool { return p.UserId == userId })
+		info.Workouts = r.selectWorkouts(func(w *entity.Workout) bool { return w.UserId == userId })
+	case entity.TrainerRole:
+		info.Partnerships = r.selectPartnerships(func(p *entity.Partnership) bool { return p.TrainerId == userId })
+		info.Workouts = r.selectWorkouts(func(w *entity.Workout) bool {
+			return w.TrainerId.Valid && w.TrainerId.Int64 == userId
+		})
+	default:
+		return nil, errors.New("undefined user role")
+	}
+	return info, nil
+}
+
+func (r *UserRepository) UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("invalid userId")
+	}
+	if update.Role != u.Role {
+		return errors.New("role can be changed only by approving trainer application")
+	}
+	if r.s.emailTaken(update.Email, userId) {
+		return errors.New("provided email has already been reserved")
+	}
+
+	after := userAuditState(&entity.User{Email: update.Email, Role: update.Role, Name: update.Name,
+		Surname: update.Surname})
+	if update.Password != u.PasswordHash {
+		after["password"] = "changed"
+	}
+	if err := r.s.writeAudit(actor, entity.AuditUserUpdate, entity.AuditTargetUser, userId, userAuditState(u),
+		after); err != nil {
+		return err
+	}
+	u.Email, u.PasswordHash, u.Name, u.Surname = update.Email, update.Password, update.Name, update.Surname
+	u.Version++
+	return nil
+}
+
+func (r *UserRepository) DeleteUser(actor *entity.Actor, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("no user to delete")
+	}
+	err := r.s.writeAudit(actor, entity.AuditUserDelete, entity.AuditTargetUser, userId, userAuditState(u), nil)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.endOpenPartnerships(u, now)
+	if u.Role == entity.TrainerRole {
+		for _, w := range r.s.workouts {
+			if w.TrainerId.Valid && w.TrainerId.Int64 == userId {
+				w.TrainerId = sql.NullInt64{}
+				w.Version++
+			}
+		}
+	}
+	u.DeletedAt = sql.NullTime{Time: now, Valid: true}
+	return nil
+}
+
+func (r *UserRepository) RestoreUser(actor *entity.Actor, userId int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userId]
+	if !ok || !u.DeletedAt.Valid || u.AnonymizedAt.Valid {
+		return errors.New("no deleted user to restore")
+	}
+	err := r.s.writeAudit(actor, entity.AuditUserRestore, entity.AuditTargetUser, userId, nil, userAuditState(u))
+	if err != nil {
+		return err
+	}
+	u.DeletedAt = sql.NullTime{}
+	return nil
+}
+
+// PurgeDeletedUsers removes users with everything referencing them.
+func (r *UserRepository) PurgeDeletedUsers(deletedBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, u := range r.s.users {
+		if !u.DeletedAt.Valid || !u.DeletedAt.Time.Before(deletedBefore) || u.AnonymizedAt.Valid {
+			continue
+		}
+		r.s.purgeUser(id)
+		purged++
+	}
+	return purged, nil
+}
+
+func (r *UserRepository) ScheduleDeletion(userId int64, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.activeUser(userId)
+	if !ok {
+		return errors.New("no user to delete")
+	}
+	u.DeletionScheduledAt = sql.NullTime{Time: at, Valid: true}
+	return nil
+}
+
+func (r *UserRepository) CancelDeletion(userId int64) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userId]
+	if !ok || !u.DeletionScheduledAt.Valid {
+		return false, nil
+	}
+	u.DeletionScheduledAt = sql.NullTime{}
+	return true, nil
+}
+
+func (r *UserRepository) AnonymizeScheduledUsers(dueBefore time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var anonymized int64
+	now := time.Now()
+	for _, id := range sortedIds(r.s.users) {
+		u := r.s.users[id]
+		if !u.DeletionScheduledAt.Valid || u.DeletionScheduledAt.Time.After(dueBefore) || u.DeletedAt.Valid {
+			continue
+		}
+		u.Email = fmt.Sprintf("deleted-%d@anonymized.invalid", id)
+		u.PasswordHash, u.Name, u.Surname = "", "Deleted", "User"
+		u.DeletedAt = sql.NullTime{Time: now, Valid: true}
+		u.AnonymizedAt = sql.NullTime{Time: now, Valid: true}
+		u.DeletionScheduledAt = sql.NullTime{}
+		r.endOpenPartnerships(u, now)
+		anonymized++
+	}
+	return anonymized, nil
+}
+
+func (r *UserRepository) endPartnership(actor *entity.Actor, trainerId, userId int64,
+	status entity.Status) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p := r.s.currentPartnership(trainerId, userId)
+	if p == nil {
+		return -1, sql.ErrNoRows
+	}
+	if p.Status != entity.StatusApproved {
+		return -1, errors.New("no approved partnership to end")
+	}
+
+	after := partnershipAuditState(p)
+	after["status"] = string(status)
+	err := r.s.writeAudit(actor, entity.AuditPartnershipEnd, entity.AuditTargetPartnership, p.Id,
+		partnershipAuditState(p), after)
+	if err != nil {
+		return -1, err
+	}
+	p.Status = status
+	p.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return p.Id, nil
+}
+
+// endOpenPartnerships ends requests and approved partnerships of leaving user on their side.
+func (r *UserRepository) endOpenPartnerships(u *entity.User, now time.Time) {
+	for _, p := range r.s.partnerships {
+		if p.Status != entity.StatusApproved && p.Status != entity.StatusRequest {
+			continue
+		}
+		if p.UserId == u.Id {
+			p.Status = entity.StatusEndedByUser
+		} else if p.TrainerId == u.Id {
+			p.Status = entity.StatusEndedByTrainer
+		} else {
+			continue
+		}
+		p.EndedAt = sql.NullTime{Time: now, Valid: true}
+	}
+}
+
+// revokeVerification marks pending verification tokens used, they were sent to previous email.
+func (r *UserRepository) revokeVerification(userId int64) {
+	now := time.Now()
+	for _, t := range r.s.tokens {
+		if t.UserId == userId && t.Purpose == entity.TokenVerifyEmail && !t.UsedAt.Valid {
+			t.UsedAt = sql.NullTime{Time: now, Valid: true}
+		}
+	}
+}
+
+func (r *UserRepository) checkAccess(workoutId, userId int64) error {
+	w, ok := r.s.workouts[workoutId]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if w.UserId != userId && (!w.TrainerId.Valid || w.TrainerId.Int64 != userId) {
+		return entity.ErrNoWorkoutAccess
+	}
+	return nil
+}
+
+func (r *UserRepository) insertPartnership(trainerId, userId int64, status entity.Status) int64 {
+	r.s.lastPartnershipId++
+	r.s.partnerships[r.s.lastPartnershipId] = &entity.Partnership{Id: r.s.lastPartnershipId, UserId: userId,
+		TrainerId: trainerId, Status: status, CreatedAt: time.Now()}
+	return r.s.lastPartnershipId
+}
+
+// selectWorkouts returns copies of matching workouts, latest first.
+func (r *UserRepository) selectWorkouts(match func(w *entity.Workout) bool) []*entity.Workout {
+	workouts := make([]*entity.Workout, 0)
+	for _, id := range sortedIds(r.s.workouts) {
+		if w := r.s.workouts[id]; match(w) {
+			cp := *w
+			workouts = append(workouts, &cp)
+		}
+	}
+	sort.SliceStable(workouts, func(i, j int) bool { return workouts[i].Date.After(workouts[j].Date) })
+	return workouts
+}
+
+// selectPartnerships returns copies of matching partnerships, latest first.
+func (r *UserRepository) selectPartnerships(match func(p *entity.Partnership) bool) []*entity.Partnership {
+	partnerships := make([]*entity.Partnership, 0)
+	for _, id := range sortedIds(r.s.partnerships) {
+		if p := r.s.partnerships[id]; match(p) {
+			cp := *p
+			partnerships = append(partnerships, &cp)
+		}
+	}
+	sort.SliceStable(partnerships, func(i, j int) bool {
+		return partnerships[i].CreatedAt.After(partnerships[j].CreatedAt)
+	})
+	return partnerships
+}
+
+func isOpen(p *entity.Partnership) bool {
+	return p.Status == entity.StatusRequest || p.Status == entity.StatusApproved
+}
+
+func newRequest(u *entity.User, p *entity.Partnership) *entity.Request {
+	return &entity.Request{RequestId: p.Id, UserId: u.Id, Email: u.Email, Name: u.Name, Surname: u.Surname,
+		SendAt: p.CreatedAt}
+}
+
+func sortBySurname(users []*entity.User) {
+	sort.SliceStable(users, func(i, j int) bool { return users[i].Surname < users[j].Surname })
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestAccountRepository_CreateToken(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	token := &entity.AccountToken{Id: "jti", UserId: 1, Purpose: entity.TokenVerifyEmail, ExpiresAt: time.Now()}
+	mock.ExpectExec("INSERT INTO user_tokens").
+		WithArgs(token.Id, token.UserId, token.Purpose, token.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := NewAccountRepository(NewDB(db))
+	assert.NoError(t, r.CreateToken(token))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountRepository_VerifyEmail(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenVerifyEmail).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET verified_at").
+					WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Token is already used",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenVerifyEmail).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "User is deleted",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenVerifyEmail).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET verified_at").
+					WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewAccountRepository(NewDB(db))
+
+			err := r.VerifyEmail("jti", 1)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountRepository_ResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenResetPassword).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET password_hash").
+					WithArgs(int64(1), "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs(int64(1), entity.TokenResetPassword).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Token is expired",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenResetPassword).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Internal error",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_tokens SET used_at").
+					WithArgs("jti", int64(1), entity.TokenResetPassword).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET password_hash").
+					WithArgs(int64(1), "hash").WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewAccountRepository(NewDB(db))
+
+			err := r.ResetPassword("jti", 1, "hash")
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -54,15 +54,16 @@ func TestAdminRepository_Authorize(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehavior(test.admin)
 
-			r := NewAdminRepository(db)
+			r := NewAdminRepository(NewDB(db))
 
-			err := r.Authorize(test.args.login, test.args.password)
+			id, err := r.Authorize(test.args.login, test.args.password)
 			if test.shouldFail {
 				assert.Error(t, err)
 				t.Skip("OK")
 			}
 
 			assert.NoError(t, err)
+			assert.Equal(t, id, test.admin.Id)
 		})
 	}
 }
@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var applicationColumns = []string{"id", "user_id", "status", "bio", "specialization", "experience_years",
+	"reviewer_id", "reviewer_note", "created_at", "updated_at", "reviewed_at"}
+
+func applicationRow(id, userId int64, status entity.ApplicationStatus) *sqlmock.Rows {
+	return sqlmock.NewRows(applicationColumns).AddRow(id, userId, status, "bio", "yoga", 3,
+		nil, nil, time.Unix(0, 1), time.Unix(0, 1), nil)
+}
+
+func TestApplicationRepository_CreateApplication(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM users").WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(entity.UserRole))
+				mock.ExpectQuery("INSERT INTO trainer_applications").
+					WithArgs(int64(2), entity.ApplicationPending, "bio", "yoga", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(5)))
+				expectAudit(mock, entity.AuditApplicationSubmit, entity.AuditTargetApplication, 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 5,
+		},
+		{
+			name: "Trainer applies",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM users").WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(entity.TrainerRole))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name: "Already under consideration",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM users").WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(entity.UserRole))
+				mock.ExpectQuery("INSERT INTO trainer_applications").
+					WillReturnError(&pq.Error{Code: uniqueViolation})
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewApplicationRepository(NewDB(db))
+
+			got, err := r.CreateApplication(testActor, &entity.TrainerApplication{
+				UserId: 2, Bio: "bio", Specialization: "yoga", ExperienceYears: 3})
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplicationRepository_AddCertificate(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cert := &entity.Certificate{ApplicationId: 5, FileName: "cert.pdf", ContentType: "application/pdf",
+		Size: 4, Content: []byte("%PDF")}
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationPending))
+				mock.ExpectQuery("INSERT INTO trainer_application_certificates").
+					WithArgs(int64(5), "cert.pdf", "application/pdf", int64(4), []byte("%PDF")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+				expectAudit(mock, entity.AuditApplicationCertificate, entity.AuditTargetApplication, 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 7,
+		},
+		{
+			name: "Application of other user",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 3, entity.ApplicationPending))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name: "Reviewed application",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationRejected))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewApplicationRepository(NewDB(db))
+
+			got, err := r.AddCertificate(testActor, 2, cert)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplicationRepository_ReviewApplication(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	reviewer := sql.NullInt64{Int64: testActor.Id, Valid: true}
+
+	table := []struct {
+		name          string
+		status        entity.ApplicationStatus
+		note          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name:   "Approve",
+			status: entity.ApplicationApproved,
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationPending))
+				mock.ExpectExec("UPDATE trainer_applications SET status").
+					WithArgs(int64(5), entity.ApplicationApproved, reviewer, sql.NullString{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE users SET role = \$2, version = version \+ 1`).
+					WithArgs(int64(2), entity.TrainerRole, entity.UserRole).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditUserUpdate, entity.AuditTargetUser, 2).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAudit(mock, entity.AuditApplicationApprove, entity.AuditTargetApplication, 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Request changes",
+			status: entity.ApplicationChangesRequested,
+			note:   "upload certificate",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationPending))
+				mock.ExpectExec("UPDATE trainer_applications SET status").
+					WithArgs(int64(5), entity.ApplicationChangesRequested, reviewer,
+						sql.NullString{String: "upload certificate", Valid: true}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditApplicationRequestChanges, entity.AuditTargetApplication, 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Applicant is deleted",
+			status: entity.ApplicationApproved,
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationPending))
+				mock.ExpectExec("UPDATE trainer_applications SET status").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET role").
+					WithArgs(int64(2), entity.TrainerRole, entity.UserRole).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "Not pending",
+			status: entity.ApplicationRejected,
+			note:   "note",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnRows(applicationRow(5, 2, entity.ApplicationChangesRequested))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "No application",
+			status: entity.ApplicationApproved,
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM trainer_applications WHERE id = (.+) FOR UPDATE").
+					WithArgs(int64(5)).WillReturnError(errors.New("sql: no rows in result set"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewApplicationRepository(NewDB(db))
+
+			err := r.ReviewApplication(testActor, 5, test.status, test.note)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
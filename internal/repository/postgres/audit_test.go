@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+var testActor = &entity.Actor{Type: entity.ActorAdmin, Id: 1, RequestId: "req", IP: "127.0.0.1"}
+
+func expectAudit(mock sqlmock.Sqlmock, action entity.AuditAction, targetType string,
+	targetId int64) *sqlmock.ExpectedExec {
+	return mock.ExpectExec("INSERT INTO audit_log").WithArgs(string(testActor.Type), testActor.Id, string(action),
+		targetType, targetId, sqlmock.AnyArg(), sqlmock.AnyArg(), testActor.RequestId, testActor.IP)
+}
+
+func TestAuditRepository_GetAuditLog(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(filter *entity.AuditFilter)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "actor_type", "actor_id", "action", "target_type", "target_id", "before", "after",
+		"request_id", "ip", "created_at"}
+
+	table := []struct {
+		name          string
+		filter        *entity.AuditFilter
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  []*entity.AuditEntry
+	}{
+		{
+			name: "Ok",
+			filter: &entity.AuditFilter{ActorType: entity.ActorAdmin, TargetType: entity.AuditTargetUser,
+				TargetId: 2, From: from, BeforeId: 10, Limit: 3},
+			mockBehaviour: func(filter *entity.AuditFilter) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(9, "admin", 1, "user.delete", "user", 2, []byte(`{"email":"test"}`), nil,
+						"req", "127.0.0.1", from)
+				mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE actor_type = (.+) AND target_type = (.+) "+
+					"AND target_id = (.+) AND created_at >= (.+) AND id < (.+) ORDER BY id DESC LIMIT").
+					WithArgs("admin", "user", int64(2), from, int64(10), 3).WillReturnRows(rows)
+			},
+			shouldReturn: []*entity.AuditEntry{
+				{
+					Id:         9,
+					ActorType:  entity.ActorAdmin,
+					ActorId:    1,
+					Action:     entity.AuditUserDelete,
+					TargetType: entity.AuditTargetUser,
+					TargetId:   2,
+					Before:     entity.AuditData(`{"email":"test"}`),
+					RequestId:  "req",
+					IP:         "127.0.0.1",
+					CreatedAt:  from,
+				},
+			},
+		},
+		{
+			name:   "Without filters",
+			filter: &entity.AuditFilter{Limit: 3},
+			mockBehaviour: func(filter *entity.AuditFilter) {
+				mock.ExpectQuery("SELECT (.+) FROM audit_log ORDER BY id DESC LIMIT").
+					WithArgs(3).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			shouldReturn: []*entity.AuditEntry{},
+		},
+		{
+			name:   "Internal error",
+			filter: &entity.AuditFilter{Limit: 3},
+			mockBehaviour: func(filter *entity.AuditFilter) {
+				mock.ExpectQuery("SELECT (.+) FROM audit_log").
+					WithArgs(3).WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewAuditRepository(NewDB(db))
+			test.mockBehaviour(test.filter)
+
+			got, err := r.GetAuditLog(test.filter)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.shouldReturn, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestCommentRepository_GetWorkoutComments(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(workoutId int64)
+
+	table := []struct {
+		name          string
+		workoutId     int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  []*entity.Comment
+	}{
+		{
+			name:      "Ok",
+			workoutId: 1,
+			mockBehaviour: func(workoutId int64) {
+				rows := sqlmock.NewRows([]string{"id", "workout_id", "author_id", "body", "resolved", "created_at"}).
+					AddRow(1, 1, 2, "how did it go?", true, time.Time{}).
+					AddRow(2, 1, 3, "fine", false, time.Time{})
+				mock.ExpectQuery("SELECT (.+) FROM workout_comments").
+					WithArgs(workoutId).WillReturnRows(rows)
+			},
+			shouldReturn: []*entity.Comment{
+				{Id: 1, WorkoutId: 1, AuthorId: 2, Body: "how did it go?", Resolved: true},
+				{Id: 2, WorkoutId: 1, AuthorId: 3, Body: "fine"},
+			},
+		},
+		{
+			name:      "Internal error",
+			workoutId: 1,
+			mockBehaviour: func(workoutId int64) {
+				mock.ExpectQuery("SELECT (.+) FROM workout_comments").
+					WithArgs(workoutId).WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewCommentRepository(NewDB(db))
+			test.mockBehaviour(test.workoutId)
+
+			got, err := r.GetWorkoutComments(test.workoutId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, got, test.shouldReturn)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentRepository_CreateComment(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(comment *entity.Comment, id int64)
+
+	table := []struct {
+		name          string
+		comment       *entity.Comment
+		mockBehaviour mockBehaviour
+		id            int64
+		shouldFail    bool
+	}{
+		{
+			name:    "Ok",
+			comment: &entity.Comment{WorkoutId: 1, AuthorId: 2, Body: "test"},
+			id:      1,
+			mockBehaviour: func(comment *entity.Comment, id int64) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO workout_comments").
+					WithArgs(comment.WorkoutId, comment.AuthorId, comment.Body).WillReturnRows(rows)
+			},
+		},
+		{
+			name:    "Internal error",
+			comment: &entity.Comment{WorkoutId: 1, AuthorId: 2, Body: "test"},
+			mockBehaviour: func(comment *entity.Comment, id int64) {
+				mock.ExpectQuery("INSERT INTO workout_comments").
+					WithArgs(comment.WorkoutId, comment.AuthorId, comment.Body).
+					WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewCommentRepository(NewDB(db))
+			test.mockBehaviour(test.comment, test.id)
+
+			got, err := r.CreateComment(test.comment)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, got, test.id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentRepository_DeleteComment(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type args struct {
+		commentId int64
+		authorId  int64
+	}
+
+	type mockBehaviour func(args args)
+
+	table := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			args: args{commentId: 1, authorId: 2},
+			mockBehaviour: func(args args) {
+				mock.ExpectExec("DELETE FROM workout_comments").
+					WithArgs(args.commentId, args.authorId).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not author",
+			args: args{commentId: 1, authorId: 3},
+			mockBehaviour: func(args args) {
+				mock.ExpectExec("DELETE FROM workout_comments").
+					WithArgs(args.commentId, args.authorId).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewCommentRepository(NewDB(db))
+			test.mockBehaviour(test.args)
+
+			err := r.DeleteComment(test.args.commentId, test.args.authorId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package postgres_test
+
+import (
+	"Fitness_REST_API/dbschema"
+	"Fitness_REST_API/internal/migrate"
+	"Fitness_REST_API/internal/repository/postgres"
+	"Fitness_REST_API/internal/repository/repotest"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContract needs disposable database, its public schema is recreated for every test.
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrations, err := migrate.Load(dbschema.FS)
+	require.NoError(t, err)
+
+	repotest.RunContract(t, func(t *testing.T) *repotest.Backend {
+		_, err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+		require.NoError(t, err)
+		_, err = migrate.New(db.DB, migrations).Up(context.Background())
+		require.NoError(t, err)
+
+		return &repotest.Backend{
+			Admin: postgres.NewAdminRepository(postgres.NewDB(db)),
+			User:  postgres.NewUserRepository(postgres.NewDB(db)),
+			AddAdmin: func(t *testing.T, login, passwordHash string) int64 {
+				var id int64
+				err := db.Get(&id, "INSERT INTO admins (login, password_hash) values ($1, $2) RETURNING id",
+					login, passwordHash)
+				require.NoError(t, err)
+				return id
+			},
+		}
+	})
+}
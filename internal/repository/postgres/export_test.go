@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestExportRepository_CreateExport(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	staleBefore := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	expectStale := func(userId int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE data_exports SET status = 'failed'(.+) AND status = 'pending' AND created_at < ").
+			WithArgs(userId, staleBefore, "export has timed out").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	type mockBehaviour func(userId int64)
+
+	table := []struct {
+		name          string
+		actor         *entity.Actor
+		userId        int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name:   "Ok by user",
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				expectStale(userId)
+				mock.ExpectQuery("INSERT INTO data_exports").
+					WithArgs(userId).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 3,
+		},
+		{
+			name:   "Ok by admin",
+			actor:  testActor,
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				expectStale(userId)
+				mock.ExpectQuery("INSERT INTO data_exports").
+					WithArgs(userId).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				expectAudit(mock, entity.AuditUserExport, entity.AuditTargetUser, userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 3,
+		},
+		{
+			name:   "In progress",
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				expectStale(userId)
+				mock.ExpectQuery("INSERT INTO data_exports").
+					WithArgs(userId).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewExportRepository(NewDB(db))
+			test.mockBehaviour(test.userId)
+
+			got, err := r.CreateExport(test.actor, test.userId, staleBefore)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExportRepository_CompleteExport(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expiresAt := time.Date(2022, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE data_exports SET status = 'ready'(.+) AND status = 'pending'").
+					WithArgs(3, []byte("zip"), "abc", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Timed out",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE data_exports SET status = 'ready'").
+					WithArgs(3, []byte("zip"), "abc", expiresAt).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewExportRepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.CompleteExport(3, []byte("zip"), "abc", expiresAt)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExportRepository_FailStaleExports(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	before := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE data_exports SET status = 'failed'(.+) WHERE status = 'pending' AND created_at < ").
+		WithArgs(before, "export has timed out").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	got, err := NewExportRepository(NewDB(db)).FailStaleExports(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepository_GetExportArchive(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "user_id", "status", "error", "created_at", "completed_at", "expires_at", "archive"}
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(3, 1, "ready", nil, time.Time{}, time.Time{}, time.Time{}, []byte("zip"))
+				mock.ExpectQuery("SELECT (.+), archive FROM data_exports WHERE token = (.+) AND expires_at > NOW()").
+					WithArgs("abc").WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Expired",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM data_exports").
+					WithArgs("abc").WillReturnError(sql.ErrNoRows)
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewExportRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.GetExportArchive("abc")
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("zip"), got.Archive)
+				assert.Equal(t, entity.ExportReady, got.Status)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExportRepository_GetExportData(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "test"))
+				mock.ExpectQuery("SELECT (.+) FROM workouts").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(2, "test"))
+				mock.ExpectQuery("SELECT (.+) FROM partnerships").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("SELECT (.+) FROM messages").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("SELECT (.+) FROM workout_comments").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("SELECT (.+) FROM bookings").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "Internal error",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "test"))
+				mock.ExpectQuery("SELECT (.+) FROM workouts").WithArgs(1).
+					WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewExportRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.GetExportData(1)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "test", got.Profile.Email)
+				assert.Len(t, got.Workouts, 1)
+				assert.Len(t, got.Bookings, 0)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
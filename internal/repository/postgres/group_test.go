@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestGroupRepository_CreateGroupWorkout(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	date := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	workout := entity.GroupWorkout{TrainerId: 1, Title: "test", Date: date, Capacity: 2}
+
+	type mockBehaviour func()
+
+	table := []struct {
+		name          string
+		userIds       []int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name:    "Ok",
+			userIds: []int64{2, 3},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO group_workouts").
+					WithArgs(workout.TrainerId, workout.GroupId, workout.Title, workout.Description,
+						workout.Date, workout.Capacity).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				for i, userId := range []int64{2, 3} {
+					mock.ExpectQuery("SELECT status FROM partnerships").
+						WithArgs(workout.TrainerId, userId).
+						WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+					mock.ExpectQuery("INSERT INTO workouts").
+						WithArgs(workout.Title, workout.TrainerId, userId, workout.Description, workout.Date).
+						WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10 + i))
+					mock.ExpectExec("INSERT INTO group_workout_participants").
+						WithArgs(7, userId, 10+i, entity.AttendanceRegistered).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+			shouldReturn: 7,
+		},
+		{
+			name:    "No partnership with participant",
+			userIds: []int64{4},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO group_workouts").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(workout.TrainerId, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusRequest))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name:    "Insert error",
+			userIds: []int64{2},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO group_workouts").
+					WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: 0,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewGroupRepository(NewDB(db))
+			test.mockBehaviour()
+
+			input := workout
+			got, err := r.CreateGroupWorkout(&input, test.userIds)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, got, test.shouldReturn)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGroupRepository_AddGroupWorkoutParticipant(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	date := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	columns := []string{"id", "trainer_id", "title", "date", "capacity"}
+
+	type args struct {
+		trainerId      int64
+		groupWorkoutId int64
+		userId         int64
+	}
+
+	type mockBehaviour func(args args)
+
+	table := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			args: args{trainerId: 1, groupWorkoutId: 7, userId: 3},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM group_workouts (.+) FOR UPDATE").
+					WithArgs(args.trainerId, args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(7, 1, "test", date, 2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM group_workout_participants").
+					WithArgs(args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(args.trainerId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+				mock.ExpectQuery("INSERT INTO workouts").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mock.ExpectExec("INSERT INTO group_workout_participants").
+					WithArgs(args.groupWorkoutId, args.userId, 12, entity.AttendanceRegistered).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Cap is reached",
+			args: args{trainerId: 1, groupWorkoutId: 7, userId: 3},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM group_workouts (.+) FOR UPDATE").
+					WithArgs(args.trainerId, args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(7, 1, "test", date, 2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM group_workout_participants").
+					WithArgs(args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Already participant",
+			args: args{trainerId: 1, groupWorkoutId: 7, userId: 3},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM group_workouts (.+) FOR UPDATE").
+					WithArgs(args.trainerId, args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(7, 1, "test", date, 2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM group_workout_participants").
+					WithArgs(args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(args.trainerId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+				mock.ExpectQuery("INSERT INTO workouts").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mock.ExpectExec("INSERT INTO group_workout_participants").
+					WillReturnError(&pq.Error{Code: uniqueViolation})
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Foreign group workout",
+			args: args{trainerId: 2, groupWorkoutId: 7, userId: 3},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM group_workouts (.+) FOR UPDATE").
+					WithArgs(args.trainerId, args.groupWorkoutId).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewGroupRepository(NewDB(db))
+			test.mockBehaviour(test.args)
+
+			err := r.AddGroupWorkoutParticipant(test.args.trainerId, test.args.groupWorkoutId, test.args.userId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
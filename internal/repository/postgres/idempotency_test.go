@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-24 * time.Hour)
+	lockedUntil := now.Add(time.Minute)
+	record := &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "abc", CreatedAt: now, LockedUntil: lockedUntil}
+	columns := []string{"idempotency_key", "fingerprint", "status", "content_type", "body", "created_at",
+		"locked_until"}
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  *entity.IdempotencyRecord
+	}{
+		{
+			name: "Reserved",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys (.+) ON CONFLICT").
+					WithArgs("1:key", "abc", now, expired, lockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Stored",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys (.+) ON CONFLICT").
+					WithArgs("1:key", "abc", now, expired, lockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE").
+					WithArgs("1:key").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("1:key", "abc", 200, "application/json", []byte(`{"id":1}`), now, lockedUntil))
+			},
+			shouldReturn: &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "abc", Status: 200,
+				ContentType: "application/json", Body: []byte(`{"id":1}`), CreatedAt: now, LockedUntil: lockedUntil},
+		},
+		{
+			name: "Released in between",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys (.+) ON CONFLICT").
+					WithArgs("1:key", "abc", now, expired, lockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE").
+					WithArgs("1:key").
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectExec("INSERT INTO idempotency_keys (.+) ON CONFLICT").
+					WithArgs("1:key", "abc", now, expired, lockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "DB Failure",
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO idempotency_keys").
+					WithArgs("1:key", "abc", now, expired, lockedUntil).
+					WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewIdempotencyRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.Reserve(record, expired)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestIdentityRepository_GetIdentityUser(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldReturn  *entity.User
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users u JOIN external_identities i").
+					WithArgs("google", "subject-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "surname", "role"}).
+						AddRow(1, "user@mail.com", "John", "Doe", "user"))
+			},
+			shouldReturn: &entity.User{Id: 1, Email: "user@mail.com", Name: "John", Surname: "Doe",
+				Role: entity.UserRole},
+		},
+		{
+			name: "Not Linked",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users u JOIN external_identities i").
+					WithArgs("google", "subject-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "surname", "role"}))
+			},
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewIdentityRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.GetIdentityUser("google", "subject-1")
+			assert.NoError(t, err)
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdentityRepository_LinkIdentity(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		takeOver      bool
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO external_identities").
+					WithArgs(1, "google", "subject-1", "user@mail.com").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "Take Over Unverified",
+			takeOver: true,
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET password_hash = '', verified_at = NOW\\(\\), " +
+					"token_version = token_version \\+ 1 WHERE id = (.+) AND verified_at IS NULL").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO external_identities").
+					WithArgs(1, "google", "subject-1", "user@mail.com").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Linked To Another User",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO external_identities").
+					WithArgs(1, "google", "subject-1", "user@mail.com").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "external_identities_subject_key"})
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewIdentityRepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.LinkIdentity(1, &entity.ExternalIdentity{Provider: "google", Subject: "subject-1",
+				Email: "user@mail.com"}, test.takeOver)
+			if test.shouldFail {
+				assert.EqualError(t, err, "provider account is already linked to another user")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdentityRepository_UnlinkIdentity(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT password_hash FROM users (.+) FOR UPDATE").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow(""))
+				mock.ExpectQuery("SELECT COUNT(.+) FROM external_identities").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec("DELETE FROM external_identities").
+					WithArgs(1, "google").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Only Sign In Method",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT password_hash FROM users").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow(""))
+				mock.ExpectQuery("SELECT COUNT(.+) FROM external_identities").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name: "Not Linked",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT password_hash FROM users").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow("hash"))
+				mock.ExpectQuery("SELECT COUNT(.+) FROM external_identities").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("DELETE FROM external_identities").
+					WithArgs(1, "google").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewIdentityRepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.UnlinkIdentity(1, "google")
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
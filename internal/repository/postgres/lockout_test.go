@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/lockout"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestLockoutRepository_Attempt(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := lockout.Policy{FreeAttempts: 3, MaxFailures: 10, BaseDelay: time.Second, MaxDelay: time.Minute,
+		LockoutDuration: time.Hour, Window: time.Hour}
+	expectRow := func(failures int, lastFailure time.Time) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO sign_in_attempts (.+) ON CONFLICT (.+) DO NOTHING").
+			WithArgs("user:user@mail.com", now).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT (.+) FROM sign_in_attempts WHERE (.+) FOR UPDATE").
+			WithArgs("user:user@mail.com").
+			WillReturnRows(sqlmock.NewRows([]string{"attempt_key", "failures", "last_failure"}).
+				AddRow("user:user@mail.com", failures, lastFailure))
+	}
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  *entity.SignInAttempts
+		shouldWait    time.Duration
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				expectRow(3, now.Add(-time.Minute))
+				mock.ExpectExec("UPDATE sign_in_attempts SET failures").
+					WithArgs("user:user@mail.com", 4, now).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldReturn: &entity.SignInAttempts{Key: "user:user@mail.com", Failures: 4, LastFailure: now},
+		},
+		{
+			name: "Window Has Passed",
+			mockBehaviour: func() {
+				expectRow(3, now.Add(-2*time.Hour))
+				mock.ExpectExec("UPDATE sign_in_attempts SET failures").
+					WithArgs("user:user@mail.com", 1, now).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			shouldReturn: &entity.SignInAttempts{Key: "user:user@mail.com", Failures: 1, LastFailure: now},
+		},
+		{
+			name: "Throttled",
+			mockBehaviour: func() {
+				expectRow(4, now)
+				mock.ExpectRollback()
+			},
+			shouldReturn: &entity.SignInAttempts{Key: "user:user@mail.com", Failures: 4, LastFailure: now},
+			shouldWait:   time.Second,
+		},
+		{
+			name: "DB Failure",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO sign_in_attempts").
+					WithArgs("user:user@mail.com", now).
+					WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewLockoutRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, wait, err := r.Attempt("user:user@mail.com", now, policy)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.Equal(t, test.shouldWait, wait)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLockoutRepository_Refund(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE sign_in_attempts SET failures = failures - 1 WHERE (.+) AND failures > 0").
+		WithArgs("ip:192.0.2.1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewLockoutRepository(NewDB(db)).Refund("ip:192.0.2.1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockoutRepository_Get(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldReturn  *entity.SignInAttempts
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM sign_in_attempts WHERE").
+					WithArgs("ip:192.0.2.1").
+					WillReturnRows(sqlmock.NewRows([]string{"attempt_key", "failures", "last_failure"}).
+						AddRow("ip:192.0.2.1", 2, now))
+			},
+			shouldReturn: &entity.SignInAttempts{Key: "ip:192.0.2.1", Failures: 2, LastFailure: now},
+		},
+		{
+			name: "No Failures",
+			mockBehaviour: func() {
+				mock.ExpectQuery("SELECT (.+) FROM sign_in_attempts WHERE").
+					WithArgs("ip:192.0.2.1").
+					WillReturnRows(sqlmock.NewRows([]string{"attempt_key", "failures", "last_failure"}))
+			},
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewLockoutRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.Get("ip:192.0.2.1")
+			assert.NoError(t, err)
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLockoutRepository_Purge(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	before := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec("DELETE FROM sign_in_attempts WHERE last_failure").
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 5))
+
+	got, err := NewLockoutRepository(NewDB(db)).Purge(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestMessageRepository_GetMessages(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type args struct {
+		partnershipId int64
+		beforeId      int64
+		limit         int
+	}
+
+	type mockBehaviour func(args args)
+
+	table := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  []*entity.Message
+	}{
+		{
+			name: "Ok",
+			args: args{partnershipId: 1, beforeId: 10, limit: 2},
+			mockBehaviour: func(args args) {
+				rows := sqlmock.NewRows([]string{"id", "partnership_id", "sender_id", "body", "created_at"}).
+					AddRow(9, 1, 2, "test2", time.Time{}).
+					AddRow(8, 1, 3, "test1", time.Time{})
+				mock.ExpectQuery("SELECT (.+) FROM messages").
+					WithArgs(args.partnershipId, args.beforeId, args.limit).WillReturnRows(rows)
+			},
+			shouldReturn: []*entity.Message{
+				{Id: 9, PartnershipId: 1, SenderId: 2, Body: "test2"},
+				{Id: 8, PartnershipId: 1, SenderId: 3, Body: "test1"},
+			},
+		},
+		{
+			name: "Internal error",
+			args: args{partnershipId: 1, limit: 2},
+			mockBehaviour: func(args args) {
+				mock.ExpectQuery("SELECT (.+) FROM messages").
+					WithArgs(args.partnershipId, args.beforeId, args.limit).
+					WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMessageRepository(NewDB(db))
+			test.mockBehaviour(test.args)
+
+			got, err := r.GetMessages(test.args.partnershipId, test.args.beforeId, test.args.limit)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, got, test.shouldReturn)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMessageRepository_CreateMessage(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(message *entity.Message)
+
+	table := []struct {
+		name          string
+		message       entity.Message
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name:    "Ok",
+			message: entity.Message{PartnershipId: 1, SenderId: 2, Body: "test"},
+			mockBehaviour: func(message *entity.Message) {
+				mock.ExpectQuery("INSERT INTO messages").
+					WithArgs(message.PartnershipId, message.SenderId, message.Body).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			shouldReturn: 1,
+		},
+		{
+			name:    "Partnership is not active",
+			message: entity.Message{PartnershipId: 1, SenderId: 2, Body: "test"},
+			mockBehaviour: func(message *entity.Message) {
+				mock.ExpectQuery("INSERT INTO messages").
+					WithArgs(message.PartnershipId, message.SenderId, message.Body).
+					WillReturnError(sql.ErrNoRows)
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name:    "Internal error",
+			message: entity.Message{PartnershipId: 1, SenderId: 2, Body: "test"},
+			mockBehaviour: func(message *entity.Message) {
+				mock.ExpectQuery("INSERT INTO messages").
+					WithArgs(message.PartnershipId, message.SenderId, message.Body).
+					WillReturnError(errors.New("internal error"))
+			},
+			shouldFail:   true,
+			shouldReturn: 0,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMessageRepository(NewDB(db))
+			test.mockBehaviour(&test.message)
+
+			got, err := r.CreateMessage(&test.message)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, got, test.shouldReturn)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMessageRepository_MarkMessagesRead(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewMessageRepository(NewDB(db))
+
+	mock.ExpectExec("UPDATE messages SET read_at").
+		WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	got, err := r.MarkMessagesRead(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, got, int64(3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestMFARepository_SaveFactor(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		subject       entity.ActorType
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name:    "Ok for user",
+			subject: entity.ActorUser,
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO mfa_factors \\(user_id, secret\\) (.+) ON CONFLICT \\(user_id\\)").
+					WithArgs(1, "SECRET").WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name:    "Ok for admin",
+			subject: entity.ActorAdmin,
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO mfa_factors \\(admin_id, secret\\) (.+) ON CONFLICT \\(admin_id\\)").
+					WithArgs(1, "SECRET").WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name:    "Already Enabled",
+			subject: entity.ActorUser,
+			mockBehaviour: func() {
+				mock.ExpectExec("INSERT INTO mfa_factors").
+					WithArgs(1, "SECRET").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMFARepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.SaveFactor(test.subject, 1, "SECRET")
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_ConfirmFactor(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE mfa_factors SET confirmed_at").
+					WithArgs(1, 100).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec("DELETE FROM mfa_recovery_codes").
+					WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO mfa_recovery_codes").
+					WithArgs(5, "hash1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO mfa_recovery_codes").
+					WithArgs(5, "hash2").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Started",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE mfa_factors SET confirmed_at").
+					WithArgs(1, 100).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMFARepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.ConfirmFactor(entity.ActorUser, 1, 100, []string{"hash1", "hash2"})
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_UseStep(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE mfa_factors SET last_step (.+) AND last_step <").
+					WithArgs(1, 100).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			shouldReturn: true,
+		},
+		{
+			name: "Replayed",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE mfa_factors SET last_step").
+					WithArgs(1, 100).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "DB Failure",
+			mockBehaviour: func() {
+				mock.ExpectExec("UPDATE mfa_factors SET last_step").
+					WithArgs(1, 100).WillReturnError(errors.New("internal error"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMFARepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.UseStep(entity.ActorAdmin, 1, 100)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepository_SetPolicy(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM mfa_policies WHERE role = (.+) FOR UPDATE").
+					WithArgs(entity.TrainerRole).
+					WillReturnRows(sqlmock.NewRows([]string{"role", "required", "updated_at"}).
+						AddRow("trainer", false, time.Now()))
+				mock.ExpectExec("UPDATE mfa_policies SET required").
+					WithArgs(entity.TrainerRole, true).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditMFAPolicy, entity.AuditTargetMFAPolicy, 0).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Unknown Role",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM mfa_policies").
+					WithArgs(entity.TrainerRole).
+					WillReturnRows(sqlmock.NewRows([]string{"role", "required", "updated_at"}))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewMFARepository(NewDB(db))
+			test.mockBehaviour()
+
+			err := r.SetPolicy(testActor, &entity.MFAPolicy{Role: entity.TrainerRole, Required: true})
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,16 +2,19 @@ package postgres
 
 import (
 	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"time"
 )
 
 const (
-	adminTable        = "admins"
-	userTable         = "users"
-	workoutsTable     = "workouts"
-	partnershipsTable = "partnerships"
+	uniqueViolation      = "23505"
+	exclusionViolation   = "23P01"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
 )
 
 func InitPostgresDB(cfg *config.Config) (*sqlx.DB, error) {
@@ -29,3 +32,61 @@ func InitPostgresDB(cfg *config.Config) (*sqlx.DB, error) {
 	}
 	return db, nil
 }
+
+// NewDB wraps db for repositories, transactions on it are rerun after serialization
+// failures and deadlocks.
+func NewDB(db *sqlx.DB) *dbtx.DB {
+	return dbtx.New(db, isRetryable)
+}
+
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected)
+}
+
+// dialect locks rows with SELECT FOR UPDATE and rejects overlapping bookings by exclusion constraint.
+type dialect struct{}
+
+func (dialect) ForUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (dialect) TimeOfDay(column string) string {
+	return fmt.Sprintf("to_char(%s, 'HH24:MI')", column)
+}
+
+func (dialect) Date(column string) string {
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", column)
+}
+
+func (dialect) TimeOfDayArg(value string) interface{} {
+	return value
+}
+
+func (dialect) DateArg(t time.Time) interface{} {
+	return t
+}
+
+func (dialect) InIds(column, placeholder string) string {
+	return fmt.Sprintf("%s = ANY(%s)", column, placeholder)
+}
+
+func (dialect) IdsArg(ids []int64) (interface{}, error) {
+	return pq.Array(ids), nil
+}
+
+func (dialect) IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// IsUniqueViolationOn relies on default name of unique constraint, which is <table>_<column>_key.
+func (dialect) IsUniqueViolationOn(err error, table, column string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == table+"_"+column+"_key"
+}
+
+func (dialect) IsOverlap(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == exclusionViolation
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/repository/dbtx"
+	"Fitness_REST_API/internal/repository/sqlrepo"
+)
+
+// Repositories below are the shared sqlrepo ones with PostgreSQL dialect.
+
+func NewAccountRepository(db *dbtx.DB) *sqlrepo.AccountRepository {
+	return sqlrepo.NewAccountRepository(db)
+}
+
+func NewAdminRepository(db *dbtx.DB) *sqlrepo.AdminRepository {
+	return sqlrepo.NewAdminRepository(db)
+}
+
+func NewAuditRepository(db *dbtx.DB) *sqlrepo.AuditRepository {
+	return sqlrepo.NewAuditRepository(db)
+}
+
+func NewCommentRepository(db *dbtx.DB) *sqlrepo.CommentRepository {
+	return sqlrepo.NewCommentRepository(db)
+}
+
+func NewExportRepository(db *dbtx.DB) *sqlrepo.ExportRepository {
+	return sqlrepo.NewExportRepository(db)
+}
+
+func NewIdempotencyRepository(db *dbtx.DB) *sqlrepo.IdempotencyRepository {
+	return sqlrepo.NewIdempotencyRepository(db)
+}
+
+func NewLockoutRepository(db *dbtx.DB) *sqlrepo.LockoutRepository {
+	return sqlrepo.NewLockoutRepository(db, dialect{})
+}
+
+func NewMessageRepository(db *dbtx.DB) *sqlrepo.MessageRepository {
+	return sqlrepo.NewMessageRepository(db)
+}
+
+func NewApplicationRepository(db *dbtx.DB) *sqlrepo.ApplicationRepository {
+	return sqlrepo.NewApplicationRepository(db, dialect{})
+}
+
+func NewGroupRepository(db *dbtx.DB) *sqlrepo.GroupRepository {
+	return sqlrepo.NewGroupRepository(db, dialect{})
+}
+
+func NewIdentityRepository(db *dbtx.DB) *sqlrepo.IdentityRepository {
+	return sqlrepo.NewIdentityRepository(db, dialect{})
+}
+
+func NewMFARepository(db *dbtx.DB) *sqlrepo.MFARepository {
+	return sqlrepo.NewMFARepository(db, dialect{})
+}
+
+func NewScheduleRepository(db *dbtx.DB) *sqlrepo.ScheduleRepository {
+	return sqlrepo.NewScheduleRepository(db, dialect{})
+}
+
+func NewUserRepository(db *dbtx.DB) *sqlrepo.UserRepository {
+	return sqlrepo.NewUserRepository(db, dialect{})
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestScheduleRepository_CreateAvailabilitySlot(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(slot *entity.AvailabilitySlot)
+
+	table := []struct {
+		name          string
+		slot          entity.AvailabilitySlot
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name: "Ok",
+			slot: entity.AvailabilitySlot{TrainerId: 1, Weekday: 1, StartTime: "09:00", EndTime: "10:00",
+				TimeZone: "Europe/Minsk"},
+			mockBehaviour: func(slot *entity.AvailabilitySlot) {
+				row := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO availability_slots").
+					WithArgs(slot.TrainerId, slot.Weekday, slot.StartTime, slot.EndTime, slot.TimeZone).
+					WillReturnRows(row)
+			},
+			shouldReturn: 1,
+		},
+		{
+			name: "Insert error",
+			slot: entity.AvailabilitySlot{TrainerId: 1, Weekday: 1, StartTime: "10:00", EndTime: "09:00",
+				TimeZone: "UTC"},
+			mockBehaviour: func(slot *entity.AvailabilitySlot) {
+				mock.ExpectQuery("INSERT INTO availability_slots").
+					WithArgs(slot.TrainerId, slot.Weekday, slot.StartTime, slot.EndTime, slot.TimeZone).
+					WillReturnError(errors.New("check constraint violation"))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewScheduleRepository(NewDB(db))
+			test.mockBehaviour(&test.slot)
+
+			got, err := r.CreateAvailabilitySlot(&test.slot)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, got, test.shouldReturn)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduleRepository_DeleteAvailabilitySlot(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(trainerId, slotId int64)
+
+	table := []struct {
+		name          string
+		trainerId     int64
+		slotId        int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name:      "Ok",
+			trainerId: 1,
+			slotId:    2,
+			mockBehaviour: func(trainerId, slotId int64) {
+				mock.ExpectExec("DELETE FROM availability_slots").
+					WithArgs(trainerId, slotId).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:      "Slot of another trainer",
+			trainerId: 1,
+			slotId:    3,
+			mockBehaviour: func(trainerId, slotId int64) {
+				mock.ExpectExec("DELETE FROM availability_slots").
+					WithArgs(trainerId, slotId).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewScheduleRepository(NewDB(db))
+			test.mockBehaviour(test.trainerId, test.slotId)
+
+			err := r.DeleteAvailabilitySlot(test.trainerId, test.slotId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduleRepository_CreateBooking(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	startsAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	booking := entity.Booking{TrainerId: 1, UserId: 2, StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)}
+	workout := entity.Workout{Title: "test", UserId: 2, TrainerId: sql.NullInt64{Int64: 1, Valid: true},
+		Date: startsAt}
+
+	type mockBehaviour func()
+
+	table := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(booking.TrainerId, booking.UserId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+				mock.ExpectQuery("INSERT INTO workouts").
+					WithArgs(workout.Title, workout.TrainerId, workout.UserId, workout.Description, workout.Date).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("INSERT INTO bookings").
+					WithArgs(booking.TrainerId, booking.UserId, 5, booking.StartsAt, booking.EndsAt,
+						entity.BookingStatusBooked).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 3,
+		},
+		{
+			name: "No approved partnership",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(booking.TrainerId, booking.UserId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusEndedByUser))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name: "Slot already booked",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(booking.TrainerId, booking.UserId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+				mock.ExpectQuery("INSERT INTO workouts").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("INSERT INTO bookings").
+					WillReturnError(&pq.Error{Code: exclusionViolation})
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
+		},
+		{
+			name: "Insert error",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT status FROM partnerships").
+					WithArgs(booking.TrainerId, booking.UserId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(entity.StatusApproved))
+				mock.ExpectQuery("INSERT INTO workouts").
+					WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail:   true,
+			shouldReturn: 0,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewScheduleRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.CreateBooking(&booking, &workout)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, got, test.shouldReturn)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduleRepository_CancelBooking(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(bookingId, userId int64)
+
+	table := []struct {
+		name          string
+		bookingId     int64
+		userId        int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name:      "Ok",
+			bookingId: 1,
+			userId:    2,
+			mockBehaviour: func(bookingId, userId int64) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bookings").
+					WithArgs(entity.BookingStatusCancelled, userId, bookingId, entity.BookingStatusBooked).
+					WillReturnRows(sqlmock.NewRows([]string{"workout_id"}).AddRow(7))
+				mock.ExpectExec("DELETE FROM workouts").
+					WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:      "No booking to cancel",
+			bookingId: 1,
+			userId:    2,
+			mockBehaviour: func(bookingId, userId int64) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bookings").
+					WithArgs(entity.BookingStatusCancelled, userId, bookingId, entity.BookingStatusBooked).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewScheduleRepository(NewDB(db))
+			test.mockBehaviour(test.bookingId, test.userId)
+
+			err := r.CancelBooking(test.bookingId, test.userId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
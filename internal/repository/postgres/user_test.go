@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 	"testing"
@@ -26,7 +27,7 @@ func TestUserRepository_Authorize(t *testing.T) {
 		password string
 	}
 
-	r := NewUserRepository(db)
+	r := NewUserRepository(NewDB(db))
 
 	table := []struct {
 		name         string
@@ -123,7 +124,7 @@ func TestUserRepository_IsTrainer(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got := r.IsTrainer(test.userId)
 
 			assert.Equal(t, got, test.shouldReturn)
@@ -171,7 +172,7 @@ func TestUserRepository_IsUser(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got := r.IsUser(test.userId)
 
 			assert.Equal(t, got, test.shouldReturn)
@@ -200,8 +201,12 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			inputUser: entity.User{Email: "testEmail", PasswordHash: "testPassword", Name: "testName", Surname: "testSurname"},
 			mockBehaviour: func() {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(int64(1))
+				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO users").WithArgs(
-					"testEmail", "testPassword", "testName", "testSurname").WillReturnRows(rows)
+					"testEmail", "testPassword", "testName", "testSurname", sql.NullTime{}).WillReturnRows(rows)
+				expectAudit(mock, entity.AuditUserCreate, entity.AuditTargetUser, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail:   false,
 			shouldReturn: int64(1),
@@ -210,9 +215,11 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			name:      "Email is reserved",
 			inputUser: entity.User{Email: "testEmail", PasswordHash: "testPassword", Name: "testName", Surname: "testSurname"},
 			mockBehaviour: func() {
-				rowsSelect := sqlmock.NewRows([]string{"id", "email", "password_hash", "name", "surname"}).
-					AddRow(int64(1), "testEmail", "testPassword", "testName", "testSurname")
-				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("testEmail").WillReturnRows(rowsSelect)
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO users").WithArgs(
+					"testEmail", "testPassword", "testName", "testSurname", sql.NullTime{}).
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "users_email_key"})
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: int64(-1),
@@ -221,8 +228,10 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			name:      "Internal error",
 			inputUser: entity.User{Email: "testEmail", PasswordHash: "testPassword", Name: "testName", Surname: "testSurname"},
 			mockBehaviour: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO users").WithArgs(
-					"testEmail", "testPassword", "testName", "testSurname").WillReturnError(errors.New("error"))
+					"testEmail", "testPassword", "testName", "testSurname", sql.NullTime{}).WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: int64(0),
@@ -232,7 +241,9 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			inputUser: entity.User{},
 			mockBehaviour: func() {
 				rows := sqlmock.NewRows([]string{"id"})
+				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO users").WithArgs().WillReturnRows(rows)
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: int64(0),
@@ -241,9 +252,9 @@ func TestUserRepository_CreateUser(t *testing.T) {
 
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour()
-			got, err := r.CreateUser(&test.inputUser, entity.UserRole)
+			got, err := r.CreateUser(testActor, &test.inputUser, entity.UserRole)
 
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -256,53 +267,6 @@ func TestUserRepository_CreateUser(t *testing.T) {
 	}
 }
 
-func TestUserRepository_HasEmail(t *testing.T) {
-
-	db, mock, err := sqlmock.Newx()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer db.Close()
-
-	type mockBehaviour func()
-
-	table := []struct {
-		name          string
-		email         string
-		mockBehaviour mockBehaviour
-		shouldReturn  bool
-	}{
-		{
-			name:  "No email",
-			email: "test",
-			mockBehaviour: func() {
-				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("test").WillReturnError(errors.New("no rows selected"))
-			},
-			shouldReturn: false,
-		},
-		{
-			name:  "Email found",
-			email: "test",
-			mockBehaviour: func() {
-				row := sqlmock.NewRows([]string{"id"}).AddRow(int64(1))
-				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs("test").WillReturnRows(row)
-			},
-			shouldReturn: true,
-		},
-	}
-
-	for _, test := range table {
-		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
-			test.mockBehaviour()
-
-			got := r.HasEmail(test.email)
-
-			assert.Equal(t, got, test.shouldReturn)
-		})
-	}
-}
-
 func TestUserRepository_GetUserInfoById(t *testing.T) {
 
 	db, mock, err := sqlmock.Newx()
@@ -346,7 +310,7 @@ func TestUserRepository_GetUserInfoById(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.userId)
 
 			got, err := r.GetUserInfoById(test.userId)
@@ -392,11 +356,9 @@ func TestUserRepository_CreateWorkoutAsUser(t *testing.T) {
 				row := sqlmock.NewRows([]string{"id"}).AddRow(workout.Id)
 				rowTrainer := sqlmock.NewRows([]string{"id", "role"}).AddRow(workout.TrainerId, entity.TrainerRole)
 				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rowTrainer)
-				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO workouts").
 					WithArgs(workout.Title, workout.UserId, workout.TrainerId, workout.Description, workout.Date).
 					WillReturnRows(row)
-				mock.ExpectCommit()
 			},
 			shouldFail:   false,
 			shouldReturn: int64(1),
@@ -414,11 +376,9 @@ func TestUserRepository_CreateWorkoutAsUser(t *testing.T) {
 			mockBehaviour: func(workout *entity.Workout) {
 				rowTrainer := sqlmock.NewRows([]string{"id", "role"}).AddRow(workout.TrainerId, entity.TrainerRole)
 				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rowTrainer)
-				mock.ExpectBegin()
 				mock.ExpectQuery("INSERT INTO workouts").
 					WithArgs(workout.Title, workout.UserId, workout.TrainerId, workout.Description, workout.Date).
 					WillReturnError(errors.New("title must be not null"))
-				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: int64(-1),
@@ -459,7 +419,7 @@ func TestUserRepository_CreateWorkoutAsUser(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(&test.workout)
 
 			got, err := r.CreateWorkoutAsUser(&test.workout)
@@ -550,7 +510,7 @@ func TestUserRepository_CheckAccessToWorkout(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.workoutId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			err = r.CheckAccessToWorkout(test.workoutId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -621,9 +581,9 @@ func TestUserRepository_GetAllUserWorkouts(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 
-			got, err := r.GetUserWorkouts(test.userId)
+			got, err := r.GetUserWorkouts(test.userId, time.Time{}, time.Time{})
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -634,6 +594,28 @@ func TestUserRepository_GetAllUserWorkouts(t *testing.T) {
 	}
 }
 
+func TestUserRepository_GetUserWorkoutsOfDay(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	from := time.Date(2026, 10, 24, 22, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 25, 23, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "title", "date"}).
+		AddRow(int64(1), int64(1), "test", from.Add(time.Hour))
+	mock.ExpectQuery("SELECT (.+) FROM workouts WHERE user_id = (.+) AND date >= (.+) AND date < (.+) ORDER BY date").
+		WithArgs(int64(1), from, to).WillReturnRows(rows)
+
+	r := NewUserRepository(NewDB(db))
+	got, err := r.GetUserWorkouts(1, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUserRepository_GetWorkoutById(t *testing.T) {
 
 	db, mock, err := sqlmock.Newx()
@@ -712,7 +694,7 @@ func TestUserRepository_GetWorkoutById(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.workoutId, test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 
 			got, err := r.GetWorkoutById(test.workoutId, test.userId)
 			if test.shouldFail {
@@ -740,6 +722,7 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 		userId        int64
 		workoutId     int64
 		update        *entity.UpdateWorkout
+		version       int64
 		mockBehaviour mockBehaviour
 		shouldFail    bool
 	}{
@@ -750,9 +733,9 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 			update:    &entity.UpdateWorkout{Title: "newTitle", Description: "newDesc", Date: time.Now()},
 			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
 				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rowsSelect)
-				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE workouts SET (.+) WHERE (.+)").
 					WithArgs(update.Title, update.Description, update.Date, workoutId).WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit()
@@ -766,9 +749,9 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 			update:    &entity.UpdateWorkout{},
 			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
 				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rowsSelect)
-				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE workouts SET (.+) WHERE (.+)").
 					WithArgs(update.Title, update.Description, update.Date, workoutId).WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit()
@@ -782,9 +765,9 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 			update:    &entity.UpdateWorkout{},
 			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
 				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rowsSelect)
-				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE workouts SET (.+) WHERE (.+)").
 					WithArgs(update.Title, update.Description, update.Date, workoutId).WillReturnError(errors.New("internal error"))
 				mock.ExpectRollback()
@@ -798,8 +781,10 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 			update:    &entity.UpdateWorkout{},
 			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
 				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"})
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectRollback()
 			},
 			shouldFail: true,
 		},
@@ -810,8 +795,28 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 			update:    &entity.UpdateWorkout{},
 			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
 				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(100), int64(101))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM workouts").
+					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:      "Version mismatch",
+			userId:    1,
+			workoutId: 1,
+			update:    &entity.UpdateWorkout{Title: "newTitle"},
+			version:   2,
+			mockBehaviour: func(workoutId, userId int64, update *entity.UpdateWorkout) {
+				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectExec("UPDATE workouts SET (.+) AND version = ").
+					WithArgs(update.Title, update.Description, update.Date, workoutId, int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			shouldFail: true,
 		},
@@ -820,14 +825,102 @@ func TestUserRepository_UpdateWorkout(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.workoutId, test.userId, test.update)
 
-			r := NewUserRepository(db)
-			err = r.UpdateWorkout(test.workoutId, test.userId, test.update)
+			r := NewUserRepository(NewDB(db))
+			err = r.UpdateWorkout(test.workoutId, test.userId, test.update, test.version)
+
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserRepository_PatchWorkout(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(workoutId, userId int64, patch *entity.WorkoutPatch)
+
+	table := []struct {
+		name          string
+		userId        int64
+		workoutId     int64
+		patch         *entity.WorkoutPatch
+		version       int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name:      "Ok",
+			userId:    1,
+			workoutId: 1,
+			patch:     &entity.WorkoutPatch{Description: entity.Patch[string]{Set: true, Null: true}},
+			mockBehaviour: func(workoutId, userId int64, patch *entity.WorkoutPatch) {
+				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				rowsUpdate := sqlmock.NewRows([]string{"version"}).AddRow(int64(3))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM workouts").
+					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectQuery(`UPDATE workouts SET description = \$1, version = version \+ 1 WHERE id = \$2 `+
+					"RETURNING version").WithArgs("", workoutId).WillReturnRows(rowsUpdate)
+				mock.ExpectCommit()
+			},
+			shouldFail:   false,
+			shouldReturn: 3,
+		},
+		{
+			name:      "Version mismatch",
+			userId:    1,
+			workoutId: 1,
+			patch:     &entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Value: "newTitle"}},
+			version:   2,
+			mockBehaviour: func(workoutId, userId int64, patch *entity.WorkoutPatch) {
+				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(1), int64(0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM workouts").
+					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectQuery(`UPDATE workouts SET title = \$1, (.+) AND version = \$3`).
+					WithArgs(patch.Title.Value, workoutId, int64(2)).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:      "No access to workout",
+			userId:    1,
+			workoutId: 1,
+			patch:     &entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Value: "newTitle"}},
+			mockBehaviour: func(workoutId, userId int64, patch *entity.WorkoutPatch) {
+				rowsSelect := sqlmock.NewRows([]string{"user_id", "trainer_id"}).AddRow(int64(100), int64(101))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM workouts").
+					WithArgs(workoutId).WillReturnRows(rowsSelect)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour(test.workoutId, test.userId, test.patch)
+
+			r := NewUserRepository(NewDB(db))
+			got, err := r.PatchWorkout(test.workoutId, test.userId, test.patch, test.version)
 
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, test.shouldReturn, got)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -846,6 +939,7 @@ func TestUserRepository_DeleteWorkout(t *testing.T) {
 		name          string
 		userId        int64
 		workoutId     int64
+		version       int64
 		mockBehaviour mockBehaviour
 		shouldFail    bool
 	}{
@@ -854,10 +948,12 @@ func TestUserRepository_DeleteWorkout(t *testing.T) {
 			userId: 1,
 			mockBehaviour: func(workoutId, userId int64) {
 				rows := sqlmock.NewRows([]string{"trainer_id", "user_id"}).AddRow(int64(0), int64(1))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT user_id, trainer_id FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rows)
 				mock.ExpectExec("DELETE FROM workouts").
 					WithArgs(workoutId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail: false,
 		},
@@ -865,8 +961,10 @@ func TestUserRepository_DeleteWorkout(t *testing.T) {
 			name:   "No workout",
 			userId: 1,
 			mockBehaviour: func(workoutId, userId int64) {
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT user_id, trainer_id FROM workouts").
 					WithArgs(workoutId).WillReturnError(errors.New("sql: no rows in result set"))
+				mock.ExpectRollback()
 			},
 			shouldFail: true,
 		},
@@ -875,10 +973,27 @@ func TestUserRepository_DeleteWorkout(t *testing.T) {
 			userId: 1,
 			mockBehaviour: func(workoutId, userId int64) {
 				rows := sqlmock.NewRows([]string{"trainer_id", "user_id"}).AddRow(int64(0), int64(1))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT user_id, trainer_id FROM workouts").
 					WithArgs(workoutId).WillReturnRows(rows)
 				mock.ExpectExec("DELETE FROM workouts").
 					WithArgs(workoutId).WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:    "Version mismatch",
+			userId:  1,
+			version: 2,
+			mockBehaviour: func(workoutId, userId int64) {
+				rows := sqlmock.NewRows([]string{"trainer_id", "user_id"}).AddRow(int64(0), int64(1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT user_id, trainer_id FROM workouts").
+					WithArgs(workoutId).WillReturnRows(rows)
+				mock.ExpectExec("DELETE FROM workouts WHERE id = (.+) AND version = ").
+					WithArgs(workoutId, int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			shouldFail: true,
 		},
@@ -887,9 +1002,9 @@ func TestUserRepository_DeleteWorkout(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.workoutId, test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 
-			err = r.DeleteWorkout(test.workoutId, test.userId)
+			err = r.DeleteWorkout(test.workoutId, test.userId, test.version)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -953,7 +1068,7 @@ func TestUserRepository_GetAllTrainers(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour()
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainers()
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1017,7 +1132,7 @@ func TestUserRepository_GetTrainerById(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.trainerId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainerById(test.trainerId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1090,7 +1205,7 @@ func TestUserRepository_GetUserPartnerships(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetUserPartnerships(test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1147,7 +1262,7 @@ func TestUserRepository_GetPartnership(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.trainerId, test.userId)
 
 			got, err := r.GetPartnership(test.trainerId, test.userId)
@@ -1186,14 +1301,13 @@ func TestUserRepository_SendRequestToTrainer(t *testing.T) {
 			mockBehaviour: func(trainerId, userId int64) {
 				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
 					AddRow(trainerId, entity.TrainerRole)
-				idRow := sqlmock.NewRows([]string{"id"}).
-					AddRow(int64(1))
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(trainerId).WillReturnRows(trainerRow)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnError(errors.New("sql: no rows in result set"))
-				mock.ExpectQuery("INSERT INTO partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(idRow)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+					AddRow(int64(1), entity.StatusRequest))
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
@@ -1205,34 +1319,47 @@ func TestUserRepository_SendRequestToTrainer(t *testing.T) {
 			mockBehaviour: func(trainerId, userId int64) {
 				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
 					AddRow(trainerId, entity.TrainerRole)
-				partnershipRow := sqlmock.NewRows([]string{"id", "trainer_id", "user_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusEndedByUser)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(trainerId).WillReturnRows(trainerRow)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
-				mock.ExpectExec("UPDATE partnerships SET (.+)").
-					WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
 		},
 		{
-			name:      "Ok Update Request",
+			name:      "Ok Open Request",
 			userId:    1,
 			trainerId: 2,
 			mockBehaviour: func(trainerId, userId int64) {
 				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
 					AddRow(trainerId, entity.TrainerRole)
-				partnershipRow := sqlmock.NewRows([]string{"id", "trainer_id", "user_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusRequest)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(trainerId).WillReturnRows(trainerRow)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+					AddRow(int64(3), entity.StatusRequest))
 			},
 			shouldFail:   false,
-			shouldReturn: 1,
+			shouldReturn: 3,
+		},
+		{
+			name:      "Opened concurrently",
+			userId:    1,
+			trainerId: 2,
+			mockBehaviour: func(trainerId, userId int64) {
+				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
+					AddRow(trainerId, entity.TrainerRole)
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(trainerId).WillReturnRows(trainerRow)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "partnerships_open_idx"})
+			},
+			shouldFail:   true,
+			shouldReturn: -1,
 		},
 		{
 			name:      "Not a trainer",
@@ -1252,29 +1379,30 @@ func TestUserRepository_SendRequestToTrainer(t *testing.T) {
 			mockBehaviour: func(trainerId, userId int64) {
 				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
 					AddRow(trainerId, entity.TrainerRole)
-				partnershipRow := sqlmock.NewRows([]string{"id", "trainer_id", "user_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(trainerId).WillReturnRows(trainerRow)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+					AddRow(int64(1), entity.StatusApproved))
 			},
 			shouldFail:   true,
 			shouldReturn: -1,
 		},
 		{
-			name:      "Undefined status",
+			name:      "DB Failure",
 			userId:    1,
 			trainerId: 2,
 			mockBehaviour: func(trainerId, userId int64) {
 				trainerRow := sqlmock.NewRows([]string{"id", "role"}).
 					AddRow(trainerId, entity.TrainerRole)
-				partnershipRow := sqlmock.NewRows([]string{"id", "trainer_id", "user_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), "?????")
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(trainerId).WillReturnRows(trainerRow)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnError(errors.New("internal error"))
 			},
 			shouldFail:   true,
 			shouldReturn: 0,
@@ -1284,7 +1412,7 @@ func TestUserRepository_SendRequestToTrainer(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.trainerId, test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.SendRequestToTrainer(test.trainerId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1323,8 +1451,12 @@ func TestUserRepository_EndPartnershipWithTrainer(t *testing.T) {
 					AddRow(int64(1), int64(1), int64(2), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM partnerships").
 					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE partnerships SET (.+)").
 					WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditPartnershipEnd, entity.AuditTargetPartnership, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
@@ -1362,8 +1494,10 @@ func TestUserRepository_EndPartnershipWithTrainer(t *testing.T) {
 					AddRow(int64(1), int64(1), int64(2), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM partnerships").
 					WithArgs(trainerId, userId).WillReturnRows(partnershipRow)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE partnerships SET (.+)").
 					WithArgs(int64(1)).WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: 0,
@@ -1373,8 +1507,8 @@ func TestUserRepository_EndPartnershipWithTrainer(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehaviour(test.trainerId, test.userId)
 
-			r := NewUserRepository(db)
-			got, err := r.EndPartnershipWithTrainer(test.trainerId, test.userId)
+			r := NewUserRepository(NewDB(db))
+			got, err := r.EndPartnershipWithTrainer(testActor, test.trainerId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -1467,7 +1601,7 @@ func TestUserRepository_GetTrainerUsers(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainerUsers(test.trainerId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1561,7 +1695,7 @@ func TestUserRepository_GetTrainerRequests(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainerRequests(test.trainerId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1668,7 +1802,7 @@ func TestUserRepository_GetTrainerUserById(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainerUserById(test.trainerId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1764,7 +1898,7 @@ func TestUserRepository_GetTrainerRequestById(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.requestId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.GetTrainerRequestById(test.trainerId, test.requestId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1800,30 +1934,12 @@ func TestUserRepository_InitPartnershipWithUser(t *testing.T) {
 			userId:    2,
 			mockBehaviour: func(trainerId, userId int64) {
 				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"})
-				rowsResult := sqlmock.NewRows([]string{"id"}).AddRow(int64(1))
-				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs(userId).WillReturnRows(rowsUser)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
-				mock.ExpectQuery("INSERT INTO partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsResult)
-			},
-			shouldFail:   false,
-			shouldReturn: 1,
-		},
-		{
-			name:      "Ok (Approved)",
-			trainerId: 1,
-			userId:    2,
-			mockBehaviour: func(trainerId, userId int64) {
-				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowsUser)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
@@ -1834,67 +1950,58 @@ func TestUserRepository_InitPartnershipWithUser(t *testing.T) {
 			userId:    2,
 			mockBehaviour: func(trainerId, userId int64) {
 				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusEndedByTrainer)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowsUser)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
-				mock.ExpectExec("UPDATE partnerships SET status").
-					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
 		},
 		{
-			name:      "Ok (request)",
+			name:      "Bad request (Ended by User)",
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(trainerId, userId int64) {
 				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusRequest)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowsUser)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("INSERT INTO partnerships (.+) ON CONFLICT").
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id"}))
 				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
-				mock.ExpectExec("UPDATE partnerships SET status").
-					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+					WithArgs(trainerId, userId).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+					AddRow(int64(1), entity.StatusEndedByUser))
 			},
-			shouldFail:   false,
+			shouldFail:   true,
 			shouldReturn: 1,
 		},
 		{
-			name:      "Bad request (Ended by User)",
+			name:      "Opened concurrently",
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(trainerId, userId int64) {
 				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), entity.StatusEndedByUser)
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowsUser)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
+				mock.ExpectQuery("UPDATE partnerships SET (.+) RETURNING id").
+					WithArgs(trainerId, userId).
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "partnerships_open_idx"})
 			},
 			shouldFail:   true,
-			shouldReturn: 1,
+			shouldReturn: -1,
 		},
 		{
-			name:      "Bad request (Undefined status)",
+			name:      "Bad request (Invalid user)",
 			trainerId: 1,
 			userId:    2,
 			mockBehaviour: func(trainerId, userId int64) {
-				rowsUser := sqlmock.NewRows([]string{"id"}).AddRow(int64(2))
-				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
-					AddRow(int64(1), int64(2), int64(1), "")
 				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs(userId).WillReturnRows(rowsUser)
-				mock.ExpectQuery("SELECT (.+) FROM partnerships").
-					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
+					WithArgs(userId).WillReturnError(errors.New("sql: no rows in result set"))
 			},
 			shouldFail:   true,
-			shouldReturn: 0,
+			shouldReturn: -1,
 		},
 	}
 	for _, test := range table {
@@ -1904,7 +2011,7 @@ func TestUserRepository_InitPartnershipWithUser(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.userId)
 
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			got, err := r.InitPartnershipWithUser(test.trainerId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
@@ -1943,8 +2050,12 @@ func TestUserRepository_EndPartnershipWithUser(t *testing.T) {
 					AddRow(int64(1), int64(2), int64(1), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM partnerships").
 					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE partnerships SET").
 					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditPartnershipEnd, entity.AuditTargetPartnership, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
@@ -1983,8 +2094,10 @@ func TestUserRepository_EndPartnershipWithUser(t *testing.T) {
 					AddRow(int64(1), int64(2), int64(1), entity.StatusApproved)
 				mock.ExpectQuery("SELECT (.+) FROM partnerships").
 					WithArgs(trainerId, userId).WillReturnRows(rowsPartnership)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE partnerships SET").
 					WithArgs(1).WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: 0,
@@ -1997,8 +2110,8 @@ func TestUserRepository_EndPartnershipWithUser(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.userId)
 
-			r := NewUserRepository(db)
-			got, err := r.EndPartnershipWithUser(test.trainerId, test.userId)
+			r := NewUserRepository(NewDB(db))
+			got, err := r.EndPartnershipWithUser(testActor, test.trainerId, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -2032,10 +2145,14 @@ func TestUserRepository_AcceptRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 1,
 			mockBehaviour: func(trainerId, requestId int64) {
-				rowsPartnership := sqlmock.NewRows([]string{"id"}).
-					AddRow(int64(1))
+				rowsPartnership := sqlmock.NewRows([]string{"id", "user_id", "trainer_id", "status"}).
+					AddRow(int64(1), int64(2), trainerId, entity.StatusApproved)
+				mock.ExpectBegin()
 				mock.ExpectQuery("UPDATE partnerships SET").
 					WithArgs(trainerId, requestId).WillReturnRows(rowsPartnership)
+				expectAudit(mock, entity.AuditRequestAccept, entity.AuditTargetPartnership, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail:   false,
 			shouldReturn: 1,
@@ -2045,8 +2162,10 @@ func TestUserRepository_AcceptRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(trainerId, requestId int64) {
+				mock.ExpectBegin()
 				mock.ExpectQuery("UPDATE partnerships SET").
 					WithArgs(trainerId, requestId).WillReturnError(errors.New("no rows"))
+				mock.ExpectRollback()
 			},
 			shouldFail:   true,
 			shouldReturn: -1,
@@ -2059,8 +2178,8 @@ func TestUserRepository_AcceptRequest(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.requestId)
 
-			r := NewUserRepository(db)
-			got, err := r.AcceptRequest(test.trainerId, test.requestId)
+			r := NewUserRepository(NewDB(db))
+			got, err := r.AcceptRequest(testActor, test.trainerId, test.requestId)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -2093,8 +2212,13 @@ func TestUserRepository_DenyRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 1,
 			mockBehaviour: func(trainerId, requestId int64) {
-				mock.ExpectExec("DELETE FROM partnerships").
-					WithArgs(trainerId, requestId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE partnerships SET status = (.+), ended_at = NOW()").
+					WithArgs(trainerId, requestId, entity.StatusEndedByTrainer).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditRequestDeny, entity.AuditTargetPartnership, requestId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail: false,
 		},
@@ -2103,8 +2227,11 @@ func TestUserRepository_DenyRequest(t *testing.T) {
 			trainerId: 1,
 			requestId: 2,
 			mockBehaviour: func(trainerId, requestId int64) {
-				mock.ExpectExec("DELETE FROM partnerships").
-					WithArgs(trainerId, requestId).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE partnerships SET status").
+					WithArgs(trainerId, requestId, entity.StatusEndedByTrainer).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			shouldFail: true,
 		},
@@ -2116,8 +2243,8 @@ func TestUserRepository_DenyRequest(t *testing.T) {
 
 			test.mockBehaviour(test.trainerId, test.requestId)
 
-			r := NewUserRepository(db)
-			err := r.DenyRequest(test.trainerId, test.requestId)
+			r := NewUserRepository(NewDB(db))
+			err := r.DenyRequest(testActor, test.trainerId, test.requestId)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -2202,7 +2329,7 @@ func TestUserRepository_CreateWorkoutAsTrainer(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(&test.workout)
 
 			got, err := r.CreateWorkoutAsTrainer(&test.workout)
@@ -2275,7 +2402,7 @@ func TestUserRepository_GetTrainerWorkouts(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.trainerId)
 
 			got, err := r.GetTrainerWorkouts(test.trainerId)
@@ -2350,7 +2477,7 @@ func TestUserRepository_GetTrainerWorkoutsWithUser(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.trainerId, test.userId)
 
 			got, err := r.GetTrainerWorkoutsWithUser(test.trainerId, test.userId)
@@ -2425,7 +2552,7 @@ func TestUserRepository_GetUsersId(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour()
 
 			got, err := r.GetUsersId(test.role)
@@ -2528,7 +2655,7 @@ func TestUserRepository_GetUserFullInfoById(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.userId)
 
 			got, err := r.GetUserFullInfoById(test.userId)
@@ -2574,19 +2701,64 @@ func TestUserRepository_UpdateUser(t *testing.T) {
 					AddRow(int64(1), "testOld", "testOld", "testOld", entity.UserRole, time.Unix(0, 1))
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowUser)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE users SET").
 					WithArgs(update.Email, update.Password, update.Role, update.Name, update.Surname, userId).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditUserUpdate, entity.AuditTargetUser, userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			shouldFail: false,
 		},
+		{
+			name:   "Email is reserved",
+			userId: 1,
+			update: &entity.UserUpdate{
+				Email:    "taken",
+				Password: "test",
+				Role:     entity.UserRole,
+				Name:     "test",
+				Surname:  "test",
+			},
+			mockBehaviour: func(userId int64, update *entity.UserUpdate) {
+				rowUser := sqlmock.NewRows([]string{"id", "email", "name", "surname", "role", "created_at"}).
+					AddRow(int64(1), "testOld", "testOld", "testOld", entity.UserRole, time.Unix(0, 1))
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(userId).WillReturnRows(rowUser)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET").
+					WithArgs(update.Email, update.Password, update.Role, update.Name, update.Surname, userId).
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "users_email_key"})
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "Role is changed",
+			userId: 1,
+			update: &entity.UserUpdate{
+				Email:    "testOld",
+				Password: "test",
+				Role:     entity.TrainerRole,
+				Name:     "test",
+				Surname:  "test",
+			},
+			mockBehaviour: func(userId int64, update *entity.UserUpdate) {
+				rowUser := sqlmock.NewRows([]string{"id", "email", "name", "surname", "role", "created_at"}).
+					AddRow(int64(1), "testOld", "testOld", "testOld", entity.UserRole, time.Unix(0, 1))
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(userId).WillReturnRows(rowUser)
+			},
+			shouldFail: true,
+		},
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.userId, test.update)
 
-			err = r.UpdateUser(test.userId, test.update)
+			err = r.UpdateUser(testActor, test.userId, test.update)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
@@ -2606,6 +2778,8 @@ func TestUserRepository_DeleteUser(t *testing.T) {
 
 	type mockBehaviour func(userId int64)
 
+	userColumns := []string{"id", "email", "password_hash", "name", "surname", "role", "created_at"}
+
 	table := []struct {
 		name          string
 		userId        int64
@@ -2613,40 +2787,74 @@ func TestUserRepository_DeleteUser(t *testing.T) {
 		shouldFail    bool
 	}{
 		{
-			name:   "Ok",
+			name:   "Ok user",
 			userId: 1,
 			mockBehaviour: func(userId int64) {
-				rowUser := sqlmock.NewRows([]string{"id"}).
-					AddRow(int64(1))
-				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs(userId).WillReturnRows(rowUser)
+				rowUser := sqlmock.NewRows(userColumns).
+					AddRow(userId, "test", "test", "test", "test", entity.UserRole, time.Unix(0, 1))
 				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM partnerships").
-					WithArgs(userId).
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE id = (.+) AND deleted_at IS NULL FOR UPDATE").
+					WithArgs(userId).WillReturnRows(rowUser)
+				mock.ExpectExec("UPDATE partnerships SET status (.+) WHERE user_id").
+					WithArgs(userId, entity.StatusEndedByUser).
 					WillReturnResult(sqlmock.NewResult(0, 2))
-				mock.ExpectExec("DELETE FROM workouts").
+				mock.ExpectExec("UPDATE users SET deleted_at = NOW()").
 					WithArgs(userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditUserDelete, entity.AuditTargetUser, userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:   "Ok trainer",
+			userId: 2,
+			mockBehaviour: func(userId int64) {
+				rowUser := sqlmock.NewRows(userColumns).
+					AddRow(userId, "test", "test", "test", "test", entity.TrainerRole, time.Unix(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(userId).WillReturnRows(rowUser)
+				mock.ExpectExec("UPDATE partnerships SET status (.+) WHERE trainer_id").
+					WithArgs(userId, entity.StatusEndedByTrainer).
 					WillReturnResult(sqlmock.NewResult(0, 2))
-				mock.ExpectExec("DELETE FROM users").
+				mock.ExpectExec("UPDATE workouts SET trainer_id = NULL").
+					WithArgs(userId).
+					WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec("UPDATE users SET deleted_at = NOW()").
 					WithArgs(userId).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAudit(mock, entity.AuditUserDelete, entity.AuditTargetUser, userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
 			shouldFail: false,
 		},
+		{
+			name:   "No user",
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(userId).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
 		{
 			name:   "Rollback",
 			userId: 1,
 			mockBehaviour: func(userId int64) {
-				rowUser := sqlmock.NewRows([]string{"id"}).
-					AddRow(int64(1))
+				rowUser := sqlmock.NewRows(userColumns).
+					AddRow(userId, "test", "test", "test", "test", entity.UserRole, time.Unix(0, 1))
+				mock.ExpectBegin()
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WithArgs(userId).WillReturnRows(rowUser)
-				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM partnerships").
-					WithArgs(userId).
+				mock.ExpectExec("UPDATE partnerships SET status").
+					WithArgs(userId, entity.StatusEndedByUser).
 					WillReturnResult(sqlmock.NewResult(0, 2))
-				mock.ExpectExec("DELETE FROM workouts").
+				mock.ExpectExec("UPDATE users SET deleted_at = NOW()").
 					WithArgs(userId).
 					WillReturnError(errors.New("internal error"))
 				mock.ExpectRollback()
@@ -2656,15 +2864,317 @@ func TestUserRepository_DeleteUser(t *testing.T) {
 	}
 	for _, test := range table {
 		t.Run(test.name, func(t *testing.T) {
-			r := NewUserRepository(db)
+			r := NewUserRepository(NewDB(db))
 			test.mockBehaviour(test.userId)
 
-			err := r.DeleteUser(test.userId)
+			err := r.DeleteUser(testActor, test.userId)
 			if test.shouldFail {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestUserRepository_RestoreUser(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type mockBehaviour func(userId int64)
+
+	table := []struct {
+		name          string
+		userId        int64
+		mockBehaviour mockBehaviour
+		shouldFail    bool
+	}{
+		{
+			name:   "Ok",
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				rowUser := sqlmock.NewRows([]string{"id", "email", "name", "surname", "role"}).
+					AddRow(userId, "test", "test", "test", entity.UserRole)
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET deleted_at = NULL").
+					WithArgs(userId).WillReturnRows(rowUser)
+				expectAudit(mock, entity.AuditUserRestore, entity.AuditTargetUser, userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			shouldFail: false,
+		},
+		{
+			name:   "Not deleted",
+			userId: 1,
+			mockBehaviour: func(userId int64) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET deleted_at = NULL").
+					WithArgs(userId).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewUserRepository(NewDB(db))
+			test.mockBehaviour(test.userId)
+
+			err := r.RestoreUser(testActor, test.userId)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_PurgeDeletedUsers(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewUserRepository(NewDB(db))
+	deletedBefore := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM users WHERE deleted_at IS NOT NULL").
+		WithArgs(deletedBefore).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	got, err := r.PurgeDeletedUsers(deletedBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, got, int64(3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_CancelDeletion(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	table := []struct {
+		name         string
+		affected     int64
+		shouldReturn bool
+	}{
+		{name: "Scheduled", affected: 1, shouldReturn: true},
+		{name: "Not scheduled", affected: 0, shouldReturn: false},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewUserRepository(NewDB(db))
+			mock.ExpectExec("UPDATE users SET deletion_scheduled_at = NULL").
+				WithArgs(1).WillReturnResult(sqlmock.NewResult(0, test.affected))
+
+			got, err := r.CancelDeletion(1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_AnonymizeScheduledUsers(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dueBefore := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		name          string
+		mockBehaviour func()
+		shouldFail    bool
+		shouldReturn  int64
+	}{
+		{
+			name: "Ok",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET email = 'deleted-' (.+) WHERE deletion_scheduled_at <= (.+)").
+					WithArgs(dueBefore).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec("UPDATE partnerships SET status (.+) WHERE user_id = ANY").
+					WithArgs(sqlmock.AnyArg(), entity.StatusEndedByUser).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE partnerships SET status (.+) WHERE trainer_id = ANY").
+					WithArgs(sqlmock.AnyArg(), entity.StatusEndedByTrainer).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			shouldReturn: 2,
+		},
+		{
+			name: "Nothing due",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET email").
+					WithArgs(dueBefore).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			shouldReturn: 0,
+		},
+		{
+			name: "Internal error",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE users SET email").
+					WithArgs(dueBefore).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("UPDATE partnerships SET status").
+					WithArgs(sqlmock.AnyArg(), entity.StatusEndedByUser).WillReturnError(errors.New("internal error"))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewUserRepository(NewDB(db))
+			test.mockBehaviour()
+
+			got, err := r.AnonymizeScheduledUsers(dueBefore)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.shouldReturn, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_UpdateProfile(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectEmail := func(email string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT email FROM users (.+) FOR UPDATE").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow(email))
+	}
+
+	table := []struct {
+		name          string
+		update        entity.ProfileUpdate
+		version       int64
+		mockBehaviour func()
+		shouldFail    bool
+	}{
+		{
+			name: "Ok",
+			update: entity.ProfileUpdate{Email: "new@mail.com", Name: "newName", Surname: "surname",
+				TimeZone: "Europe/Berlin"},
+			mockBehaviour: func() {
+				expectEmail("old@mail.com")
+				mock.ExpectExec("UPDATE users SET email").
+					WithArgs("new@mail.com", "newName", "surname", int64(1), "Europe/Berlin").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE user_tokens SET used_at").WithArgs(int64(1), entity.TokenVerifyEmail).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Same email",
+			update: entity.ProfileUpdate{Email: "old@mail.com", Name: "newName", Surname: "surname", TimeZone: "UTC"},
+			mockBehaviour: func() {
+				expectEmail("old@mail.com")
+				mock.ExpectExec("UPDATE users SET email").
+					WithArgs("old@mail.com", "newName", "surname", int64(1), "UTC").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Email is reserved",
+			update: entity.ProfileUpdate{Email: "taken@mail.com", Name: "name", Surname: "surname", TimeZone: "UTC"},
+			mockBehaviour: func() {
+				expectEmail("old@mail.com")
+				mock.ExpectExec("UPDATE users SET email").
+					WithArgs("taken@mail.com", "name", "surname", int64(1), "UTC").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "users_email_key"})
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "No user",
+			update: entity.ProfileUpdate{Email: "old@mail.com", Name: "name", Surname: "surname", TimeZone: "UTC"},
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+		{
+			name:    "Version mismatch",
+			update:  entity.ProfileUpdate{Email: "old@mail.com", Name: "name", Surname: "surname", TimeZone: "UTC"},
+			version: 3,
+			mockBehaviour: func() {
+				expectEmail("old@mail.com")
+				mock.ExpectExec("UPDATE users SET (.+) AND version = ").
+					WithArgs("old@mail.com", "name", "surname", int64(1), "UTC", int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			shouldFail: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehaviour()
+			r := NewUserRepository(NewDB(db))
+
+			err := r.UpdateProfile(1, &test.update, test.version)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_ChangePassword(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("UPDATE users SET password_hash").WithArgs(int64(1), "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(int64(3)))
+
+	r := NewUserRepository(NewDB(db))
+	version, err := r.ChangePassword(1, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	mock.ExpectQuery("SELECT token_version FROM users").WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(int64(3)))
+
+	version, err = r.GetTokenVersion(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,51 +2,205 @@ package repository
 
 import (
 	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"Fitness_REST_API/internal/repository/memory"
 	"Fitness_REST_API/internal/repository/postgres"
+	"Fitness_REST_API/internal/repository/sqlite"
+	"database/sql"
 	"github.com/jmoiron/sqlx"
+	"time"
 )
 
 type Repository struct {
+	TxManager
 	Admin
 	User
+	Schedule
+	Message
+	Comment
+	Group
+	Audit
+	Export
+	Account
+	Application
+	MFA
+	Identity
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
+	return newPostgresRepository(postgres.NewDB(db))
+}
+
+func newPostgresRepository(db *dbtx.DB) *Repository {
 	return &Repository{
-		Admin: postgres.NewAdminRepository(db),
-		User:  postgres.NewUserRepository(db),
+		TxManager:   &txManager{db: db, repos: newPostgresRepository},
+		Admin:       postgres.NewAdminRepository(db),
+		User:        postgres.NewUserRepository(db),
+		Schedule:    postgres.NewScheduleRepository(db),
+		Message:     postgres.NewMessageRepository(db),
+		Comment:     postgres.NewCommentRepository(db),
+		Group:       postgres.NewGroupRepository(db),
+		Audit:       postgres.NewAuditRepository(db),
+		Export:      postgres.NewExportRepository(db),
+		Account:     postgres.NewAccountRepository(db),
+		Application: postgres.NewApplicationRepository(db),
+		MFA:         postgres.NewMFARepository(db),
+		Identity:    postgres.NewIdentityRepository(db),
 	}
 }
 
+// NewSQLiteRepository serves all repositories from SQLite database of single node.
+func NewSQLiteRepository(db *sqlx.DB) *Repository {
+	return newSQLiteRepository(sqlite.NewDB(db))
+}
+
+func newSQLiteRepository(db *dbtx.DB) *Repository {
+	return &Repository{
+		TxManager:   &txManager{db: db, repos: newSQLiteRepository},
+		Admin:       sqlite.NewAdminRepository(db),
+		User:        sqlite.NewUserRepository(db),
+		Schedule:    sqlite.NewScheduleRepository(db),
+		Message:     sqlite.NewMessageRepository(db),
+		Comment:     sqlite.NewCommentRepository(db),
+		Group:       sqlite.NewGroupRepository(db),
+		Audit:       sqlite.NewAuditRepository(db),
+		Export:      sqlite.NewExportRepository(db),
+		Account:     sqlite.NewAccountRepository(db),
+		Application: sqlite.NewApplicationRepository(db),
+		MFA:         sqlite.NewMFARepository(db),
+		Identity:    sqlite.NewIdentityRepository(db),
+	}
+}
+
+// NewMemoryRepository serves all repositories from store kept in memory of the process, data is lost on restart.
+func NewMemoryRepository(store *memory.Store) *Repository {
+	repos := &Repository{
+		Admin:       memory.NewAdminRepository(store),
+		User:        memory.NewUserRepository(store),
+		Schedule:    memory.NewScheduleRepository(store),
+		Message:     memory.NewMessageRepository(store),
+		Comment:     memory.NewCommentRepository(store),
+		Group:       memory.NewGroupRepository(store),
+		Audit:       memory.NewAuditRepository(store),
+		Export:      memory.NewExportRepository(store),
+		Account:     memory.NewAccountRepository(store),
+		Application: memory.NewApplicationRepository(store),
+		MFA:         memory.NewMFARepository(store),
+		Identity:    memory.NewIdentityRepository(store),
+	}
+	repos.TxManager = memoryTxManager{repos: repos}
+	return repos
+}
+
+// TxOptions configures transaction of TxManager.
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Retries is how many times transaction is rerun after serialization failure or deadlock
+	Retries int
+}
+
+// TxManager runs several repository calls atomically. Repositories passed to fn use the
+// transaction, their own transactions become savepoints of it, and WithinTx called on
+// them joins it. fn may be run several times and must not have effects outside of repositories.
+type TxManager interface {
+	WithinTx(opts TxOptions, fn func(repos *Repository) error) error
+	// Savepoint runs fn so that its failure rolls back only changes made by fn and the transaction
+	// of WithinTx it is called in can go on.
+	Savepoint(fn func(repos *Repository) error) error
+	// Retryable reports whether err fails the transaction so that WithinTx reruns it. Such error
+	// returned by Savepoint has to be returned by fn too, the transaction can't go on after it.
+	Retryable(err error) bool
+}
+
+type txManager struct {
+	db    *dbtx.DB
+	repos func(db *dbtx.DB) *Repository
+}
+
+func (m *txManager) WithinTx(opts TxOptions, fn func(repos *Repository) error) error {
+	return m.db.WithinTx(&sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}, opts.Retries,
+		func(db *dbtx.DB) error {
+			return fn(m.repos(db))
+		})
+}
+
+func (m *txManager) Savepoint(fn func(repos *Repository) error) error {
+	return m.db.Savepoint(func(db *dbtx.DB) error {
+		return fn(m.repos(db))
+	})
+}
+
+func (m *txManager) Retryable(err error) bool {
+	return m.db.Retryable(err)
+}
+
+// memoryTxManager runs fn on the store as is, it has no transactions to roll back and keeps
+// each repository call atomic only.
+type memoryTxManager struct {
+	repos *Repository
+}
+
+func (m memoryTxManager) WithinTx(_ TxOptions, fn func(repos *Repository) error) error {
+	return fn(m.repos)
+}
+
+func (m memoryTxManager) Savepoint(fn func(repos *Repository) error) error {
+	return fn(m.repos)
+}
+
+func (m memoryTxManager) Retryable(error) bool {
+	return false
+}
+
 type Admin interface {
-	Authorize(login, passwordHash string) error
+	Authorize(login, passwordHash string) (int64, error)
+	GetLogin(adminId int64) (string, error)
 }
 
 type User interface { //nolint
 	Authorize(email, passwordHash string, role entity.Role) (int64, error)
-	CreateUser(user *entity.User, status entity.Role) (int64, error)
-	UpdateUser(userId int64, update *entity.UserUpdate) error
-	DeleteUser(userId int64) error
+	CreateUser(actor *entity.Actor, user *entity.User, status entity.Role) (int64, error)
+	UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error
+	DeleteUser(actor *entity.Actor, userId int64) error
+	RestoreUser(actor *entity.Actor, userId int64) error
+	PurgeDeletedUsers(deletedBefore time.Time) (int64, error)
+	ScheduleDeletion(userId int64, at time.Time) error
+	CancelDeletion(userId int64) (bool, error)
+	AnonymizeScheduledUsers(dueBefore time.Time) (int64, error)
 	GetUserInfoById(id int64) (*entity.User, error)
+	GetUserByEmail(email string) (*entity.User, error)
+	IsVerified(userId int64) (bool, error)
+	GetTimeZone(userId int64) (string, error)
+	GetTokenVersion(userId int64) (int64, error)
+	// UpdateProfile, UpdateWorkout, DeleteWorkout and patches apply only to the given version, zero version
+	// applies to any. Patches write only columns set in them.
+	UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) error
+	PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) error
+	ChangePassword(userId int64, passwordHash string) (int64, error)
 	CreateWorkoutAsUser(*entity.Workout) (int64, error)
-	UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout) error
-	GetUserWorkouts(id int64) ([]*entity.Workout, error)
+	UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error
+	PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch, version int64) (int64, error)
+	GetUserWorkouts(id int64, from, to time.Time) ([]*entity.Workout, error)
 	GetWorkoutById(workoutId, userId int64) (*entity.Workout, error)
-	DeleteWorkout(workoutId, userId int64) error
+	DeleteWorkout(workoutId, userId, version int64) error
+	CheckAccessToWorkout(workoutId, userId int64) error
 	GetTrainers() ([]*entity.User, error)
 	GetTrainerById(id int64) (*entity.User, error)
 	SendRequestToTrainer(trainerId, userId int64) (int64, error)
-	EndPartnershipWithTrainer(trainerId, userId int64) (int64, error)
+	EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error)
 	GetUserPartnerships(userId int64) ([]*entity.Partnership, error)
+	GetPartnership(trainerId, userId int64) (*entity.Partnership, error)
+	GetPartnershipById(partnershipId int64) (*entity.Partnership, error)
 
 	GetTrainerUsers(trainerId int64) ([]*entity.User, error)
 	GetTrainerRequests(trainerId int64) ([]*entity.Request, error)
 	GetTrainerUserById(trainerId, userId int64) (*entity.User, error)
 	GetTrainerRequestById(trainerId, requestId int64) (*entity.Request, error)
 	InitPartnershipWithUser(trainerId, userId int64) (int64, error)
-	EndPartnershipWithUser(trainerId, userId int64) (int64, error)
-	AcceptRequest(trainerId, requestId int64) (int64, error)
-	DenyRequest(trainerId, requestId int64) error
+	EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error)
+	AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error)
+	DenyRequest(actor *entity.Actor, trainerId, requestId int64) error
 	CreateWorkoutAsTrainer(workout *entity.Workout) (int64, error)
 	GetTrainerWorkouts(trainerId int64) ([]*entity.Workout, error)
 	GetTrainerWorkoutsWithUser(trainerId, userId int64) ([]*entity.Workout, error)
@@ -54,3 +208,103 @@ type User interface { //nolint
 	GetUsersId(role entity.Role) ([]int64, error)
 	GetUserFullInfoById(userId int64) (*entity.UserInfo, error)
 }
+
+type Schedule interface {
+	CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error)
+	GetAvailabilitySlots(trainerId int64) ([]*entity.AvailabilitySlot, error)
+	DeleteAvailabilitySlot(trainerId, slotId int64) error
+	CreateAvailabilityException(exception *entity.AvailabilityException) (int64, error)
+	GetAvailabilityExceptions(trainerId int64, from, to time.Time) ([]*entity.AvailabilityException, error)
+	DeleteAvailabilityException(trainerId, exceptionId int64) error
+	GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error)
+	GetUserBookings(userId int64) ([]*entity.Booking, error)
+	GetBookingById(bookingId int64) (*entity.Booking, error)
+	CreateBooking(booking *entity.Booking, workout *entity.Workout) (int64, error)
+	CancelBooking(bookingId, cancelledBy int64) error
+}
+
+type Message interface {
+	GetMessages(partnershipId, beforeId int64, limit int) ([]*entity.Message, error)
+	CreateMessage(message *entity.Message) (int64, error)
+	MarkMessagesRead(partnershipId, readerId int64) (int64, error)
+}
+
+type Comment interface {
+	GetWorkoutComments(workoutId int64) ([]*entity.Comment, error)
+	GetCommentById(workoutId, commentId int64) (*entity.Comment, error)
+	CreateComment(comment *entity.Comment) (int64, error)
+	UpdateComment(commentId, authorId int64, body string) error
+	SetCommentResolved(commentId int64, resolved bool) error
+	DeleteComment(commentId, authorId int64) error
+}
+
+type Group interface {
+	CreateGroup(group *entity.ClientGroup) (int64, error)
+	GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error)
+	GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error)
+	DeleteGroup(trainerId, groupId int64) error
+	AddGroupMember(trainerId, groupId, userId int64) error
+	RemoveGroupMember(trainerId, groupId, userId int64) error
+	CreateGroupWorkout(workout *entity.GroupWorkout, userIds []int64) (int64, error)
+	GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error)
+	GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error)
+	DeleteGroupWorkout(trainerId, groupWorkoutId int64) error
+	AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error
+	RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error
+	SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error
+}
+
+type Audit interface {
+	WriteAudit(actor *entity.Actor, action entity.AuditAction, targetType string, targetId int64,
+		before, after map[string]interface{}) error
+	GetAuditLog(filter *entity.AuditFilter) ([]*entity.AuditEntry, error)
+}
+
+type Export interface {
+	CreateExport(actor *entity.Actor, userId int64, staleBefore time.Time) (int64, error)
+	GetExport(userId, exportId int64) (*entity.DataExport, error)
+	GetLatestExport(userId int64) (*entity.DataExport, error)
+	GetExportArchive(token string) (*entity.DataExport, error)
+	CompleteExport(exportId int64, archive []byte, token string, expiresAt time.Time) error
+	FailExport(exportId int64, reason string) error
+	FailStaleExports(createdBefore time.Time) (int64, error)
+	PurgeExpiredExports(expiredBefore time.Time) (int64, error)
+	GetExportData(userId int64) (*entity.ExportData, error)
+}
+
+type Account interface {
+	CreateToken(token *entity.AccountToken) error
+	VerifyEmail(tokenId string, userId int64) error
+	ResetPassword(tokenId string, userId int64, passwordHash string) error
+}
+
+type Application interface {
+	CreateApplication(actor *entity.Actor, app *entity.TrainerApplication) (int64, error)
+	GetUserApplications(userId int64) ([]*entity.TrainerApplication, error)
+	UpdateApplication(actor *entity.Actor, userId, appId int64, input *entity.TrainerApplicationInput) error
+	AddCertificate(actor *entity.Actor, userId int64, cert *entity.Certificate) (int64, error)
+	GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error)
+	GetApplicationById(appId int64) (*entity.TrainerApplication, error)
+	GetCertificate(appId, certificateId int64) (*entity.Certificate, error)
+	ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus, note string) error
+}
+
+type MFA interface {
+	GetFactor(subject entity.ActorType, subjectId int64) (*entity.MFAFactor, error)
+	SaveFactor(subject entity.ActorType, subjectId int64, secret string) error
+	ConfirmFactor(subject entity.ActorType, subjectId, step int64, codeHashes []string) error
+	UseStep(subject entity.ActorType, subjectId, step int64) (bool, error)
+	UseRecoveryCode(subject entity.ActorType, subjectId int64, codeHash string) (bool, error)
+	DeleteFactor(subject entity.ActorType, subjectId int64) error
+	GetPolicies() ([]*entity.MFAPolicy, error)
+	IsRequired(role entity.Role) (bool, error)
+	SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error
+}
+
+type Identity interface {
+	GetIdentityUser(provider, subject string) (*entity.User, error)
+	CreateIdentityUser(user *entity.User, identity *entity.ExternalIdentity) (int64, error)
+	LinkIdentity(userId int64, identity *entity.ExternalIdentity, takeOver bool) error
+	GetIdentities(userId int64) ([]*entity.ExternalIdentity, error)
+	UnlinkIdentity(userId int64, provider string) error
+}
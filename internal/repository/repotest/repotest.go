@@ -0,0 +1,695 @@
+// Package repotest holds contract tests which every storage backend of repositories has to pass.
+package repotest
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is a clean storage, AddAdmin seeds admin account which can't be created through repositories.
+type Backend struct {
+	Admin    repository.Admin
+	User     repository.User
+	Account  repository.Account
+	Audit    repository.Audit
+	MFA      repository.MFA
+	Identity repository.Identity
+	AddAdmin func(t *testing.T, login, passwordHash string) int64
+}
+
+// RunContract runs the suite, newBackend is called for every test and has to return empty storage.
+func RunContract(t *testing.T, newBackend func(t *testing.T) *Backend) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, b *Backend)
+	}{
+		{"Admin", testAdmin},
+		{"Accounts", testAccounts},
+		{"Profile", testProfile},
+		{"Deletion", testDeletion},
+		{"Trainers", testTrainers},
+		{"Request", testRequest},
+		{"Partnership", testPartnership},
+		{"Workouts", testWorkouts},
+		{"Patch", testPatch},
+		{"Audit", testAudit},
+		{"Tokens", testTokens},
+		{"MFA", testMFA},
+		{"MFAPolicy", testMFAPolicy},
+		{"Identity", testIdentity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newBackend(t))
+		})
+	}
+}
+
+func testAdmin(t *testing.T, b *Backend) {
+	id := b.AddAdmin(t, "admin", "hash")
+
+	got, err := b.Admin.Authorize("admin", "hash")
+	require.NoError(t, err)
+	require.Equal(t, id, got)
+
+	_, err = b.Admin.Authorize("admin", "wrong")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	login, err := b.Admin.GetLogin(id)
+	require.NoError(t, err)
+	require.Equal(t, "admin", login)
+
+	_, err = b.Admin.GetLogin(id + 1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testAccounts(t *testing.T, b *Backend) {
+	id := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+
+	got, err := b.User.Authorize("user@mail.com", "hash", entity.UserRole)
+	require.NoError(t, err)
+	require.Equal(t, id, got)
+
+	_, err = b.User.Authorize("user@mail.com", "hash", entity.TrainerRole)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = b.User.CreateUser(nil, &entity.User{Email: "user@mail.com", PasswordHash: "hash", Name: "Other",
+		Surname: "Other"}, entity.UserRole)
+	require.Error(t, err)
+
+	user, err := b.User.GetUserInfoById(id)
+	require.NoError(t, err)
+	require.Equal(t, "user@mail.com", user.Email)
+	require.Equal(t, entity.UserRole, user.Role)
+	require.Equal(t, "UTC", user.TimeZone)
+
+	byEmail, err := b.User.GetUserByEmail("user@mail.com")
+	require.NoError(t, err)
+	require.Equal(t, id, byEmail.Id)
+
+	_, err = b.User.GetUserInfoById(id + 100)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testProfile(t *testing.T, b *Backend) {
+	id, err := b.User.CreateUser(nil, &entity.User{Email: "user@mail.com", PasswordHash: "hash", Name: "John",
+		Surname: "Smith", VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, entity.UserRole)
+	require.NoError(t, err)
+	createUser(t, b, "taken@mail.com", "Brown", entity.UserRole)
+
+	verified, err := b.User.IsVerified(id)
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	err = b.User.UpdateProfile(id, &entity.ProfileUpdate{Email: "taken@mail.com", Name: "John", Surname: "Smith",
+		TimeZone: "UTC"}, 0)
+	require.Error(t, err)
+
+	user, err := b.User.GetUserInfoById(id)
+	require.NoError(t, err)
+	err = b.User.UpdateProfile(id, &entity.ProfileUpdate{Email: "new@mail.com", Name: "John", Surname: "Smith",
+		TimeZone: "Europe/Berlin"}, user.Version)
+	require.NoError(t, err)
+	err = b.User.UpdateProfile(id, &entity.ProfileUpdate{Email: "new@mail.com", Name: "Stale", Surname: "Smith",
+		TimeZone: "UTC"}, user.Version)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+	updated, err := b.User.GetUserInfoById(id)
+	require.NoError(t, err)
+	require.Equal(t, user.Version+1, updated.Version)
+	require.Equal(t, "John", updated.Name)
+
+	verified, err = b.User.IsVerified(id)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	tz, err := b.User.GetTimeZone(id)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", tz)
+
+	version, err := b.User.ChangePassword(id, "new hash")
+	require.NoError(t, err)
+	current, err := b.User.GetTokenVersion(id)
+	require.NoError(t, err)
+	require.Equal(t, version, current)
+
+	_, err = b.User.Authorize("new@mail.com", "new hash", entity.UserRole)
+	require.NoError(t, err)
+}
+
+func testDeletion(t *testing.T, b *Backend) {
+	trainerId := createUser(t, b, "trainer@mail.com", "Brown", entity.TrainerRole)
+	userId := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+	_, err := b.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	workoutId, err := b.User.CreateWorkoutAsTrainer(&entity.Workout{Title: "Run", UserId: userId,
+		TrainerId: sql.NullInt64{Int64: trainerId, Valid: true}, Date: time.Now()})
+	require.NoError(t, err)
+
+	require.NoError(t, b.User.DeleteUser(nil, trainerId))
+	require.Error(t, b.User.DeleteUser(nil, trainerId))
+
+	_, err = b.User.GetUserInfoById(trainerId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = b.User.Authorize("trainer@mail.com", "hash", entity.TrainerRole)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	p, err := b.User.GetPartnership(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusEndedByTrainer, p.Status)
+	require.True(t, p.EndedAt.Valid)
+
+	workout, err := b.User.GetWorkoutById(workoutId, userId)
+	require.NoError(t, err)
+	require.False(t, workout.TrainerId.Valid)
+
+	_, err = b.User.CreateUser(nil, &entity.User{Email: "trainer@mail.com", PasswordHash: "hash", Name: "New",
+		Surname: "Brown"}, entity.UserRole)
+	require.Error(t, err)
+
+	require.NoError(t, b.User.RestoreUser(nil, trainerId))
+	require.Error(t, b.User.RestoreUser(nil, trainerId))
+	_, err = b.User.GetUserInfoById(trainerId)
+	require.NoError(t, err)
+
+	require.NoError(t, b.User.DeleteUser(nil, userId))
+	purged, err := b.User.PurgeDeletedUsers(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	_, err = b.User.GetPartnership(trainerId, userId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = b.User.GetWorkoutById(workoutId, trainerId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testTrainers(t *testing.T, b *Backend) {
+	second := createUser(t, b, "second@mail.com", "Brown", entity.TrainerRole)
+	first := createUser(t, b, "first@mail.com", "Adams", entity.TrainerRole)
+	userId := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+
+	trainers, err := b.User.GetTrainers()
+	require.NoError(t, err)
+	require.Equal(t, []int64{first, second}, userIds(trainers))
+
+	_, err = b.User.GetTrainerById(userId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	ids, err := b.User.GetUsersId(entity.TrainerRole)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{second, first}, ids)
+
+	_, err = b.User.GetTrainerUsers(userId)
+	require.Error(t, err)
+}
+
+func testRequest(t *testing.T, b *Backend) {
+	trainerId := createUser(t, b, "trainer@mail.com", "Brown", entity.TrainerRole)
+	otherTrainerId := createUser(t, b, "other@mail.com", "Adams", entity.TrainerRole)
+	firstId := createUser(t, b, "first@mail.com", "Smith", entity.UserRole)
+	secondId := createUser(t, b, "second@mail.com", "Jones", entity.UserRole)
+
+	id, err := b.User.SendRequestToTrainer(firstId, secondId)
+	require.Error(t, err)
+	require.Equal(t, int64(-1), id)
+
+	firstRequest, err := b.User.SendRequestToTrainer(trainerId, firstId)
+	require.NoError(t, err)
+	again, err := b.User.SendRequestToTrainer(trainerId, firstId)
+	require.NoError(t, err)
+	require.Equal(t, firstRequest, again)
+	secondRequest, err := b.User.SendRequestToTrainer(trainerId, secondId)
+	require.NoError(t, err)
+
+	requests, err := b.User.GetTrainerRequests(trainerId)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+
+	_, err = b.User.GetTrainerRequestById(otherTrainerId, firstRequest)
+	require.Error(t, err)
+	request, err := b.User.GetTrainerRequestById(trainerId, firstRequest)
+	require.NoError(t, err)
+	require.Equal(t, firstId, request.UserId)
+
+	_, err = b.User.AcceptRequest(nil, otherTrainerId, firstRequest)
+	require.Error(t, err)
+	accepted, err := b.User.AcceptRequest(nil, trainerId, firstRequest)
+	require.NoError(t, err)
+	require.Equal(t, firstRequest, accepted)
+	_, err = b.User.AcceptRequest(nil, trainerId, firstRequest)
+	require.Error(t, err)
+
+	id, err = b.User.SendRequestToTrainer(trainerId, firstId)
+	require.Error(t, err)
+	require.Equal(t, int64(-1), id)
+
+	require.Error(t, b.User.DenyRequest(nil, otherTrainerId, secondRequest))
+	require.NoError(t, b.User.DenyRequest(nil, trainerId, secondRequest))
+	require.Error(t, b.User.DenyRequest(nil, trainerId, secondRequest))
+	denied, err := b.User.GetPartnershipById(secondRequest)
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusEndedByTrainer, denied.Status)
+
+	users, err := b.User.GetTrainerUsers(trainerId)
+	require.NoError(t, err)
+	require.Equal(t, []int64{firstId}, userIds(users))
+
+	// denied request is reopened by the next one, so messages sent before stay with it
+	again, err = b.User.SendRequestToTrainer(trainerId, secondId)
+	require.NoError(t, err)
+	require.Equal(t, secondRequest, again)
+}
+
+func testPartnership(t *testing.T, b *Backend) {
+	trainerId := createUser(t, b, "trainer@mail.com", "Brown", entity.TrainerRole)
+	userId := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+
+	id, err := b.User.InitPartnershipWithUser(trainerId, userId+100)
+	require.Error(t, err)
+	require.Equal(t, int64(-1), id)
+
+	_, err = b.User.GetPartnership(trainerId, userId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	id, err = b.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	same, err := b.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, id, same)
+
+	user, err := b.User.GetTrainerUserById(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, userId, user.Id)
+
+	ended, err := b.User.EndPartnershipWithTrainer(nil, trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, id, ended)
+	_, err = b.User.EndPartnershipWithTrainer(nil, trainerId, userId)
+	require.Error(t, err)
+	_, err = b.User.GetTrainerUserById(trainerId, userId)
+	require.Error(t, err)
+
+	resumed, err := b.User.InitPartnershipWithUser(trainerId, userId)
+	require.Error(t, err)
+	require.Equal(t, id, resumed)
+
+	requested, err := b.User.SendRequestToTrainer(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, id, requested)
+	p, err := b.User.GetPartnershipById(id)
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusRequest, p.Status)
+
+	_, err = b.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	_, err = b.User.EndPartnershipWithUser(nil, trainerId, userId)
+	require.NoError(t, err)
+	p, err = b.User.GetPartnership(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusEndedByTrainer, p.Status)
+
+	partnerships, err := b.User.GetUserPartnerships(userId)
+	require.NoError(t, err)
+	require.Len(t, partnerships, 1)
+}
+
+func testWorkouts(t *testing.T, b *Backend) {
+	trainerId := createUser(t, b, "trainer@mail.com", "Brown", entity.TrainerRole)
+	userId := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+	otherId := createUser(t, b, "other@mail.com", "Jones", entity.UserRole)
+	day := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	id, err := b.User.CreateWorkoutAsUser(&entity.Workout{Title: "Run", UserId: userId,
+		TrainerId: sql.NullInt64{Int64: otherId, Valid: true}, Date: day})
+	require.Error(t, err)
+	require.Equal(t, int64(-1), id)
+
+	id, err = b.User.CreateWorkoutAsTrainer(&entity.Workout{Title: "Run", UserId: userId,
+		TrainerId: sql.NullInt64{Int64: trainerId, Valid: true}, Date: day})
+	require.Error(t, err)
+	require.Equal(t, int64(-1), id)
+
+	morning, err := b.User.CreateWorkoutAsUser(&entity.Workout{Title: "Morning", UserId: userId,
+		Date: day.Add(8 * time.Hour)})
+	require.NoError(t, err)
+	evening, err := b.User.CreateWorkoutAsUser(&entity.Workout{Title: "Evening", UserId: userId,
+		Date: day.Add(20 * time.Hour)})
+	require.NoError(t, err)
+	_, err = b.User.CreateWorkoutAsUser(&entity.Workout{Title: "Next day", UserId: userId,
+		Date: day.Add(32 * time.Hour)})
+	require.NoError(t, err)
+
+	workouts, err := b.User.GetUserWorkouts(userId, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []int64{evening, morning}, workoutIds(workouts))
+	workouts, err = b.User.GetUserWorkouts(userId, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, workouts, 3)
+
+	require.Error(t, b.User.CheckAccessToWorkout(morning, otherId))
+	_, err = b.User.GetWorkoutById(morning, otherId)
+	require.Error(t, err)
+	require.Error(t, b.User.UpdateWorkout(morning, otherId, &entity.UpdateWorkout{Title: "Hijack", Date: day}, 0))
+	require.Error(t, b.User.DeleteWorkout(morning, otherId, 0))
+
+	workout, err := b.User.GetWorkoutById(morning, userId)
+	require.NoError(t, err)
+	read := workout.Version
+	require.NoError(t, b.User.UpdateWorkout(morning, userId, &entity.UpdateWorkout{Title: "Swim", Date: day}, read))
+	err = b.User.UpdateWorkout(morning, userId, &entity.UpdateWorkout{Title: "Stale", Date: day}, read)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+	require.ErrorIs(t, b.User.DeleteWorkout(morning, userId, read), entity.ErrVersionMismatch)
+	workout, err = b.User.GetWorkoutById(morning, userId)
+	require.NoError(t, err)
+	require.Equal(t, "Swim", workout.Title)
+	require.Equal(t, read+1, workout.Version)
+	require.True(t, day.Equal(workout.Date))
+
+	_, err = b.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	withTrainer, err := b.User.CreateWorkoutAsTrainer(&entity.Workout{Title: "Gym", UserId: userId,
+		TrainerId: sql.NullInt64{Int64: trainerId, Valid: true}, Date: day})
+	require.NoError(t, err)
+	require.NoError(t, b.User.CheckAccessToWorkout(withTrainer, trainerId))
+
+	workouts, err = b.User.GetTrainerWorkoutsWithUser(trainerId, userId)
+	require.NoError(t, err)
+	require.Equal(t, []int64{withTrainer}, workoutIds(workouts))
+
+	info, err := b.User.GetUserFullInfoById(userId)
+	require.NoError(t, err)
+	require.Len(t, info.Workouts, 4)
+	require.Len(t, info.Partnerships, 1)
+
+	require.NoError(t, b.User.DeleteWorkout(morning, userId, workout.Version))
+	err = b.User.CheckAccessToWorkout(morning, userId)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testPatch(t *testing.T, b *Backend) {
+	userId, err := b.User.CreateUser(nil, &entity.User{Email: "user@mail.com", PasswordHash: "hash", Name: "John",
+		Surname: "Smith", VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, entity.UserRole)
+	require.NoError(t, err)
+	otherId := createUser(t, b, "other@mail.com", "Jones", entity.UserRole)
+	day := time.Date(2023, 5, 10, 8, 0, 0, 0, time.UTC)
+
+	user, err := b.User.GetUserInfoById(userId)
+	require.NoError(t, err)
+	require.NoError(t, b.User.PatchProfile(userId, &entity.ProfilePatch{
+		Name: entity.Patch[string]{Set: true, Value: "Jack"}}, user.Version))
+	err = b.User.PatchProfile(userId, &entity.ProfilePatch{
+		Surname: entity.Patch[string]{Set: true, Value: "Stale"}}, user.Version)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+	err = b.User.PatchProfile(userId, &entity.ProfilePatch{
+		Email: entity.Patch[string]{Set: true, Value: "other@mail.com"}}, 0)
+	require.Error(t, err)
+
+	patched, err := b.User.GetUserInfoById(userId)
+	require.NoError(t, err)
+	require.Equal(t, "Jack", patched.Name)
+	require.Equal(t, "Smith", patched.Surname)
+	require.Equal(t, "user@mail.com", patched.Email)
+	require.Equal(t, user.Version+1, patched.Version)
+	verified, err := b.User.IsVerified(userId)
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	require.NoError(t, b.User.PatchProfile(userId, &entity.ProfilePatch{
+		Email: entity.Patch[string]{Set: true, Value: "new@mail.com"}}, 0))
+	verified, err = b.User.IsVerified(userId)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	id, err := b.User.CreateWorkoutAsUser(&entity.Workout{Title: "Run", Description: "Easy pace", UserId: userId,
+		Date: day})
+	require.NoError(t, err)
+	workout, err := b.User.GetWorkoutById(id, userId)
+	require.NoError(t, err)
+
+	_, err = b.User.PatchWorkout(id, otherId, &entity.WorkoutPatch{
+		Title: entity.Patch[string]{Set: true, Value: "Hijack"}}, 0)
+	require.Error(t, err)
+	version, err := b.User.PatchWorkout(id, userId, &entity.WorkoutPatch{
+		Description: entity.Patch[string]{Set: true, Null: true}}, workout.Version)
+	require.NoError(t, err)
+	require.Equal(t, workout.Version+1, version)
+	_, err = b.User.PatchWorkout(id, userId, &entity.WorkoutPatch{
+		Title: entity.Patch[string]{Set: true, Value: "Stale"}}, workout.Version)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+	version, err = b.User.PatchWorkout(id, userId, &entity.WorkoutPatch{
+		Date: entity.Patch[time.Time]{Set: true, Value: day.Add(time.Hour)}}, 0)
+	require.NoError(t, err)
+	require.Equal(t, workout.Version+2, version)
+
+	patchedWorkout, err := b.User.GetWorkoutById(id, userId)
+	require.NoError(t, err)
+	require.Equal(t, "Run", patchedWorkout.Title)
+	require.Equal(t, "", patchedWorkout.Description)
+	require.True(t, day.Add(time.Hour).Equal(patchedWorkout.Date))
+	require.Equal(t, version, patchedWorkout.Version)
+}
+
+func testAudit(t *testing.T, b *Backend) {
+	admin := &entity.Actor{Type: entity.ActorAdmin, Id: b.AddAdmin(t, "admin", "hash"), RequestId: "req",
+		IP: "10.0.0.1"}
+	id, err := b.User.CreateUser(admin, &entity.User{Email: "user@mail.com", PasswordHash: "hash", Name: "John",
+		Surname: "Smith"}, entity.UserRole)
+	require.NoError(t, err)
+	otherId := createUser(t, b, "other@mail.com", "Jones", entity.UserRole)
+	require.NoError(t, b.User.UpdateUser(admin, id, &entity.UserUpdate{Email: "user@mail.com", Password: "hash",
+		Role: entity.UserRole, Name: "Johnny", Surname: "Smith"}))
+	require.NoError(t, b.User.DeleteUser(admin, otherId))
+	require.NoError(t, b.Audit.WriteAudit(admin, entity.AuditSignInUnlock, entity.AuditTargetSignIn, 0, nil, nil))
+	require.NoError(t, b.Audit.WriteAudit(nil, entity.AuditSignInUnlock, entity.AuditTargetSignIn, 0, nil, nil))
+
+	entries, err := b.Audit.GetAuditLog(&entity.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	actions := make([]entity.AuditAction, 0, len(entries))
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	require.Equal(t, []entity.AuditAction{entity.AuditSignInUnlock, entity.AuditUserDelete, entity.AuditUserUpdate,
+		entity.AuditUserCreate}, actions)
+	require.Equal(t, admin.Id, entries[0].ActorId)
+	require.Equal(t, "req", entries[0].RequestId)
+	require.Equal(t, "10.0.0.1", entries[0].IP)
+	require.JSONEq(t, `{"name": "John"}`, string(entries[2].Before))
+	require.JSONEq(t, `{"name": "Johnny"}`, string(entries[2].After))
+	require.Nil(t, entries[3].Before)
+
+	userEntries, err := b.Audit.GetAuditLog(&entity.AuditFilter{TargetType: entity.AuditTargetUser, TargetId: id,
+		Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, userEntries, 1)
+	require.Equal(t, entity.AuditUserUpdate, userEntries[0].Action)
+
+	older, err := b.Audit.GetAuditLog(&entity.AuditFilter{ActorType: entity.ActorAdmin, ActorId: admin.Id,
+		BeforeId: userEntries[0].Id, To: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, older, 1)
+	require.Equal(t, entity.AuditUserCreate, older[0].Action)
+
+	future, err := b.Audit.GetAuditLog(&entity.AuditFilter{From: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, future)
+}
+
+func testTokens(t *testing.T, b *Backend) {
+	id := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+	expiresAt := time.Now().Add(time.Hour)
+	for _, token := range []*entity.AccountToken{
+		{Id: "verify", UserId: id, Purpose: entity.TokenVerifyEmail, ExpiresAt: expiresAt},
+		{Id: "revoked", UserId: id, Purpose: entity.TokenVerifyEmail, ExpiresAt: expiresAt},
+		{Id: "expired", UserId: id, Purpose: entity.TokenVerifyEmail, ExpiresAt: time.Now().Add(-time.Minute)},
+		{Id: "reset", UserId: id, Purpose: entity.TokenResetPassword, ExpiresAt: expiresAt},
+		{Id: "other reset", UserId: id, Purpose: entity.TokenResetPassword, ExpiresAt: expiresAt},
+	} {
+		require.NoError(t, b.Account.CreateToken(token))
+	}
+
+	require.Error(t, b.Account.VerifyEmail("expired", id))
+	require.Error(t, b.Account.VerifyEmail("reset", id))
+	require.Error(t, b.Account.VerifyEmail("verify", id+1))
+	require.NoError(t, b.Account.VerifyEmail("verify", id))
+	require.Error(t, b.Account.VerifyEmail("verify", id))
+	verified, err := b.User.IsVerified(id)
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	require.NoError(t, b.User.UpdateProfile(id, &entity.ProfileUpdate{Email: "new@mail.com", Name: "John",
+		Surname: "Smith", TimeZone: "UTC"}, 0))
+	require.Error(t, b.Account.VerifyEmail("revoked", id))
+
+	version, err := b.User.GetTokenVersion(id)
+	require.NoError(t, err)
+	require.NoError(t, b.Account.ResetPassword("reset", id, "new hash"))
+	require.Error(t, b.Account.ResetPassword("other reset", id, "other hash"))
+	_, err = b.User.Authorize("new@mail.com", "new hash", entity.UserRole)
+	require.NoError(t, err)
+	reset, err := b.User.GetTokenVersion(id)
+	require.NoError(t, err)
+	require.Equal(t, version+1, reset)
+	verified, err = b.User.IsVerified(id)
+	require.NoError(t, err)
+	require.True(t, verified)
+}
+
+func testMFA(t *testing.T, b *Backend) {
+	userId := createUser(t, b, "user@mail.com", "Smith", entity.UserRole)
+	adminId := b.AddAdmin(t, "admin", "hash")
+
+	factor, err := b.MFA.GetFactor(entity.ActorUser, userId)
+	require.NoError(t, err)
+	require.Nil(t, factor)
+	require.Error(t, b.MFA.ConfirmFactor(entity.ActorUser, userId, 1, nil))
+
+	require.NoError(t, b.MFA.SaveFactor(entity.ActorUser, userId, "first"))
+	require.NoError(t, b.MFA.SaveFactor(entity.ActorUser, userId, "second"))
+	ok, err := b.MFA.UseStep(entity.ActorUser, userId, 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, b.MFA.ConfirmFactor(entity.ActorUser, userId, 10, []string{"code1", "code2"}))
+	require.Error(t, b.MFA.SaveFactor(entity.ActorUser, userId, "third"))
+	factor, err = b.MFA.GetFactor(entity.ActorUser, userId)
+	require.NoError(t, err)
+	require.Equal(t, "second", factor.Secret)
+	require.True(t, factor.ConfirmedAt.Valid)
+	require.Equal(t, int64(10), factor.LastStep)
+
+	for step, expected := range map[int64]bool{10: false, 9: false, 11: true} {
+		ok, err = b.MFA.UseStep(entity.ActorUser, userId, step)
+		require.NoError(t, err)
+		require.Equal(t, expected, ok, "step %d", step)
+	}
+
+	ok, err = b.MFA.UseRecoveryCode(entity.ActorUser, userId, "code1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = b.MFA.UseRecoveryCode(entity.ActorUser, userId, "code1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	admin, err := b.MFA.GetFactor(entity.ActorAdmin, adminId)
+	require.NoError(t, err)
+	require.Nil(t, admin)
+	require.NoError(t, b.MFA.SaveFactor(entity.ActorAdmin, adminId, "admin"))
+	ok, err = b.MFA.UseRecoveryCode(entity.ActorAdmin, adminId, "code2")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, b.MFA.DeleteFactor(entity.ActorUser, userId))
+	factor, err = b.MFA.GetFactor(entity.ActorUser, userId)
+	require.NoError(t, err)
+	require.Nil(t, factor)
+	admin, err = b.MFA.GetFactor(entity.ActorAdmin, adminId)
+	require.NoError(t, err)
+	require.Equal(t, "admin", admin.Secret)
+}
+
+func testMFAPolicy(t *testing.T, b *Backend) {
+	actor := &entity.Actor{Type: entity.ActorAdmin, Id: b.AddAdmin(t, "admin", "hash")}
+
+	policies, err := b.MFA.GetPolicies()
+	require.NoError(t, err)
+	roles := make([]entity.Role, 0, len(policies))
+	for _, p := range policies {
+		roles = append(roles, p.Role)
+		require.False(t, p.Required)
+	}
+	require.Equal(t, []entity.Role{entity.AdminRole, entity.TrainerRole, entity.UserRole}, roles)
+
+	require.NoError(t, b.MFA.SetPolicy(actor, &entity.MFAPolicy{Role: entity.TrainerRole, Required: true}))
+	require.Error(t, b.MFA.SetPolicy(actor, &entity.MFAPolicy{Role: "guest", Required: true}))
+	required, err := b.MFA.IsRequired(entity.TrainerRole)
+	require.NoError(t, err)
+	require.True(t, required)
+	required, err = b.MFA.IsRequired(entity.UserRole)
+	require.NoError(t, err)
+	require.False(t, required)
+
+	entries, err := b.Audit.GetAuditLog(&entity.AuditFilter{TargetType: entity.AuditTargetMFAPolicy, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.JSONEq(t, `{"trainer": true}`, string(entries[0].After))
+}
+
+func testIdentity(t *testing.T, b *Backend) {
+	google := &entity.ExternalIdentity{Provider: "google", Subject: "g-1", Email: "new@mail.com"}
+	id, err := b.Identity.CreateIdentityUser(&entity.User{Email: "new@mail.com", Name: "John", Surname: "Smith"},
+		google)
+	require.NoError(t, err)
+	_, err = b.Identity.CreateIdentityUser(&entity.User{Email: "other@mail.com", Name: "John", Surname: "Smith"},
+		&entity.ExternalIdentity{Provider: "google", Subject: "g-1", Email: "other@mail.com"})
+	require.Error(t, err)
+	_, err = b.User.GetUserByEmail("other@mail.com")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	user, err := b.Identity.GetIdentityUser("google", "g-1")
+	require.NoError(t, err)
+	require.Equal(t, id, user.Id)
+	require.Equal(t, entity.UserRole, user.Role)
+	require.True(t, user.VerifiedAt.Valid)
+	missing, err := b.Identity.GetIdentityUser("google", "g-2")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	require.Error(t, b.Identity.UnlinkIdentity(id, "google"))
+
+	passwordId := createUser(t, b, "user@mail.com", "Jones", entity.UserRole)
+	version, err := b.User.GetTokenVersion(passwordId)
+	require.NoError(t, err)
+	require.NoError(t, b.Identity.LinkIdentity(passwordId, &entity.ExternalIdentity{Provider: "google",
+		Subject: "g-2", Email: "user@mail.com"}, true))
+	require.Error(t, b.Identity.LinkIdentity(passwordId, &entity.ExternalIdentity{Provider: "google",
+		Subject: "g-3", Email: "user@mail.com"}, false))
+	require.Error(t, b.Identity.LinkIdentity(passwordId, &entity.ExternalIdentity{Provider: "google",
+		Subject: "g-1", Email: "user@mail.com"}, false))
+	require.NoError(t, b.Identity.LinkIdentity(passwordId, &entity.ExternalIdentity{Provider: "apple",
+		Subject: "a-1", Email: "user@mail.com"}, false))
+
+	takenOver, err := b.User.GetTokenVersion(passwordId)
+	require.NoError(t, err)
+	require.Equal(t, version+1, takenOver)
+	_, err = b.User.Authorize("user@mail.com", "hash", entity.UserRole)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	identities, err := b.Identity.GetIdentities(passwordId)
+	require.NoError(t, err)
+	require.Len(t, identities, 2)
+	require.Equal(t, "apple", identities[0].Provider)
+	require.Equal(t, "google", identities[1].Provider)
+
+	require.NoError(t, b.Identity.UnlinkIdentity(passwordId, "apple"))
+	require.Error(t, b.Identity.UnlinkIdentity(passwordId, "apple"))
+	require.Error(t, b.Identity.UnlinkIdentity(passwordId, "google"))
+}
+
+func createUser(t *testing.T, b *Backend, email, surname string, role entity.Role) int64 {
+	t.Helper()
+	id, err := b.User.CreateUser(nil, &entity.User{Email: email, PasswordHash: "hash",
+		Name: fmt.Sprintf("%s name", surname), Surname: surname}, role)
+	require.NoError(t, err)
+	return id
+}
+
+func userIds(users []*entity.User) []int64 {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.Id)
+	}
+	return ids
+}
+
+func workoutIds(workouts []*entity.Workout) []int64 {
+	ids := make([]int64, 0, len(workouts))
+	for _, w := range workouts {
+		ids = append(ids, w.Id)
+	}
+	return ids
+}
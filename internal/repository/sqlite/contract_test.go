@@ -0,0 +1,64 @@
+package sqlite_test
+
+import (
+	"Fitness_REST_API/dbschema"
+	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/migrate"
+	"Fitness_REST_API/internal/repository/repotest"
+	"Fitness_REST_API/internal/repository/sqlite"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// newDB opens migrated database in temporary directory of test.
+func newDB(t *testing.T) *sqlx.DB {
+	cfg := &config.Config{SQLiteConfig: config.SQLiteConfig{SQLitePath: filepath.Join(t.TempDir(), "test.db")}}
+	db, err := sqlite.InitSQLiteDB(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := migrate.Load(dbschema.SQLiteFS)
+	require.NoError(t, err)
+	_, err = migrate.NewSQLite(db.DB, migrations).Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrations_Down(t *testing.T) {
+	db := newDB(t)
+	migrations, err := migrate.Load(dbschema.SQLiteFS)
+	require.NoError(t, err)
+	migrator := migrate.NewSQLite(db.DB, migrations)
+
+	reverted, err := migrator.Down(context.Background(), len(migrations))
+	require.NoError(t, err)
+	require.Equal(t, len(migrations), reverted)
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(migrations), applied)
+}
+
+func TestContract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) *repotest.Backend {
+		db := newDB(t)
+		return &repotest.Backend{
+			Admin:    sqlite.NewAdminRepository(sqlite.NewDB(db)),
+			User:     sqlite.NewUserRepository(sqlite.NewDB(db)),
+			Account:  sqlite.NewAccountRepository(sqlite.NewDB(db)),
+			Audit:    sqlite.NewAuditRepository(sqlite.NewDB(db)),
+			MFA:      sqlite.NewMFARepository(sqlite.NewDB(db)),
+			Identity: sqlite.NewIdentityRepository(sqlite.NewDB(db)),
+			AddAdmin: func(t *testing.T, login, passwordHash string) int64 {
+				var id int64
+				err := db.Get(&id, "INSERT INTO admins (login, password_hash) values ($1, $2) RETURNING id",
+					login, passwordHash)
+				require.NoError(t, err)
+				return id
+			},
+		}
+	})
+}
@@ -0,0 +1,183 @@
+// Package sqlite implements repositories on SQLite for single node and embedded
+// deployments, repositories are the shared sqlrepo ones with SQLite dialect.
+package sqlite
+
+import (
+	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	driverName = "sqlite-utc"
+
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04:05"
+
+	// timeFormat is the format of _time_format=sqlite, UTC values of it sort as instants
+	timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+	// dsnParams enable foreign keys and make transactions take write lock at BEGIN, so
+	// read-modify-write transactions are serialized like SELECT FOR UPDATE does in postgres
+	dsnParams = "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)" +
+		"&_txlock=immediate&_time_format=sqlite"
+)
+
+func init() {
+	// NOW() keeps queries the same as in postgres
+	sqlitedriver.MustRegisterScalarFunction("now", 0,
+		func(*sqlitedriver.FunctionContext, []driver.Value) (driver.Value, error) {
+			return time.Now().UTC().Format(timeFormat), nil
+		})
+
+	// functions are applied by the driver instance registered by the package
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sql.Register(driverName, utcDriver{db.Driver()})
+}
+
+func InitSQLiteDB(cfg *config.Config) (*sqlx.DB, error) {
+	db, err := sqlx.Open(driverName, "file:"+cfg.SQLitePath+dsnParams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// NewDB wraps db for repositories, SQLite runs transactions one at a time so any isolation
+// level is serializable, transactions on it are rerun when the database stays busy.
+func NewDB(db *sqlx.DB) *dbtx.DB {
+	return dbtx.New(db, isBusy)
+}
+
+// utcDriver stores time values in UTC, SQLite keeps them as text and values written
+// with different offsets wouldn't compare and sort as instants.
+type utcDriver struct {
+	driver.Driver
+}
+
+func (d utcDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return utcConn{c.(conn)}, nil
+}
+
+type conn interface {
+	driver.Conn
+	driver.Pinger
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+}
+
+type utcConn struct {
+	conn
+}
+
+func (c utcConn) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if t, ok := v.(time.Time); ok {
+		v = t.UTC()
+	}
+	nv.Value = v
+	return nil
+}
+
+// isUniqueViolation reports whether err is violation of unique constraint, index or primary key.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// isTriggerAbort reports whether err is RAISE(ABORT) of a trigger.
+func isTriggerAbort(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_TRIGGER
+}
+
+// isBusy reports whether err is SQLITE_BUSY, raised when the write lock isn't taken within busy timeout.
+func isBusy(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+}
+
+// dialect relies on write lock taken at BEGIN instead of SELECT FOR UPDATE and rejects
+// overlapping bookings by trigger.
+type dialect struct{}
+
+func (dialect) ForUpdate() string {
+	return ""
+}
+
+func (dialect) TimeOfDay(column string) string {
+	return fmt.Sprintf("strftime('%%H:%%M', %s)", column)
+}
+
+// Date returns column as is, dates are stored as YYYY-MM-DD text.
+func (dialect) Date(column string) string {
+	return column
+}
+
+// TimeOfDayArg stores time of day as HH:MM:SS, so text values compare in time order.
+func (dialect) TimeOfDayArg(value string) interface{} {
+	for _, layout := range []string{clockLayout, "15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(clockLayout)
+		}
+	}
+	return value
+}
+
+func (dialect) DateArg(t time.Time) interface{} {
+	return t.Format(dateLayout)
+}
+
+// InIds takes ids as JSON array, SQLite has no arrays.
+func (dialect) InIds(column, placeholder string) string {
+	return fmt.Sprintf("%s IN (SELECT value FROM json_each(%s))", column, placeholder)
+}
+
+func (dialect) IdsArg(ids []int64) (interface{}, error) {
+	idsJSON, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	return string(idsJSON), nil
+}
+
+func (dialect) IsUniqueViolation(err error) bool {
+	return isUniqueViolation(err)
+}
+
+// IsUniqueViolationOn checks the message, SQLite names columns of violated constraint instead of the constraint.
+func (dialect) IsUniqueViolationOn(err error, table, column string) bool {
+	return isUniqueViolation(err) && strings.Contains(err.Error(), table+"."+column)
+}
+
+func (dialect) IsOverlap(err error) bool {
+	return isTriggerAbort(err)
+}
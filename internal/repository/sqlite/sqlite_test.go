@@ -0,0 +1,420 @@
+package sqlite_test
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/repository"
+	"Fitness_REST_API/internal/repository/sqlite"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertUser(t *testing.T, db *sqlx.DB, email string, role entity.Role) int64 {
+	var id int64
+	err := db.Get(&id, "INSERT INTO users (email, password_hash, role, name, surname) "+
+		"values ($1, 'hash', $2, 'Name', 'Surname') RETURNING id", email, role)
+	require.NoError(t, err)
+	return id
+}
+
+func insertPartnership(t *testing.T, db *sqlx.DB, trainerId, userId int64, status entity.Status) {
+	_, err := db.Exec("INSERT INTO partnerships (trainer_id, user_id, status) values ($1, $2, $3)",
+		trainerId, userId, status)
+	require.NoError(t, err)
+}
+
+func TestScheduleRepository(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewScheduleRepository(sqlite.NewDB(db))
+	trainerId := insertUser(t, db, "trainer@test.com", entity.TrainerRole)
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+	otherId := insertUser(t, db, "other@test.com", entity.UserRole)
+	insertPartnership(t, db, trainerId, userId, entity.StatusApproved)
+	insertPartnership(t, db, trainerId, otherId, entity.StatusApproved)
+
+	t.Run("Slots", func(t *testing.T) {
+		_, err := repo.CreateAvailabilitySlot(&entity.AvailabilitySlot{TrainerId: trainerId, Weekday: 1,
+			StartTime: "10:00", EndTime: "12:00", TimeZone: "UTC"})
+		require.NoError(t, err)
+		_, err = repo.CreateAvailabilitySlot(&entity.AvailabilitySlot{TrainerId: trainerId, Weekday: 1,
+			StartTime: "9:00", EndTime: "9:30:00", TimeZone: "UTC"})
+		require.NoError(t, err)
+
+		slots, err := repo.GetAvailabilitySlots(trainerId)
+		require.NoError(t, err)
+		require.Len(t, slots, 2)
+		assert.Equal(t, "09:00", slots[0].StartTime)
+		assert.Equal(t, "09:30", slots[0].EndTime)
+		assert.Equal(t, "10:00", slots[1].StartTime)
+	})
+
+	t.Run("Exceptions", func(t *testing.T) {
+		for _, date := range []string{"2023-05-09", "2023-05-10", "2023-05-12"} {
+			_, err := repo.CreateAvailabilityException(&entity.AvailabilityException{TrainerId: trainerId,
+				Date: date, StartTime: "08:00", EndTime: "09:00", TimeZone: "UTC"})
+			require.NoError(t, err)
+		}
+
+		exceptions, err := repo.GetAvailabilityExceptions(trainerId,
+			time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Len(t, exceptions, 1)
+		assert.Equal(t, "2023-05-10", exceptions[0].Date)
+		assert.Equal(t, "08:00", exceptions[0].StartTime)
+	})
+
+	t.Run("Bookings", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		startsAt := time.Date(2023, 5, 10, 10, 0, 0, 0, berlin)
+
+		book := func(userId int64, startsAt time.Time) (int64, error) {
+			return repo.CreateBooking(
+				&entity.Booking{TrainerId: trainerId, UserId: userId, StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)},
+				&entity.Workout{Title: "Session", TrainerId: sql.NullInt64{Int64: trainerId, Valid: true},
+					UserId: userId, Date: startsAt})
+		}
+		id, err := book(userId, startsAt)
+		require.NoError(t, err)
+
+		_, err = book(otherId, startsAt.Add(30*time.Minute).UTC())
+		assert.EqualError(t, err, "slot is already booked")
+
+		bookings, err := repo.GetTrainerBookings(trainerId,
+			time.Date(2023, 5, 10, 8, 30, 0, 0, time.UTC), time.Date(2023, 5, 10, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Len(t, bookings, 1)
+		assert.True(t, startsAt.Equal(bookings[0].StartsAt))
+
+		require.NoError(t, repo.CancelBooking(id, userId))
+		_, err = book(otherId, startsAt)
+		assert.NoError(t, err)
+	})
+}
+
+func TestAuditRepository_AppendOnly(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewAuditRepository(sqlite.NewDB(db))
+
+	err := repo.WriteAudit(&entity.Actor{Type: entity.ActorAdmin, Id: 1}, entity.AuditUserUpdate,
+		entity.AuditTargetUser, 2, map[string]interface{}{"name": "Old"}, map[string]interface{}{"name": "New"})
+	require.NoError(t, err)
+
+	entries, err := repo.GetAuditLog(&entity.AuditFilter{TargetId: 2, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = db.Exec("UPDATE audit_log SET action = 'user.delete'")
+	assert.ErrorContains(t, err, "append-only")
+	_, err = db.Exec("DELETE FROM audit_log")
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestLockoutRepository(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewLockoutRepository(sqlite.NewDB(db))
+	moscow := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, moscow)
+	policy := lockout.Policy{FreeAttempts: 1, MaxFailures: 5, BaseDelay: time.Minute, MaxDelay: time.Hour,
+		LockoutDuration: time.Hour, Window: time.Hour}
+
+	a, wait, err := repo.Attempt("user:a", now, policy)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+	assert.Equal(t, 1, a.Failures)
+
+	a, _, err = repo.Attempt("user:a", now.Add(time.Minute).UTC(), policy)
+	require.NoError(t, err)
+	assert.Equal(t, 2, a.Failures)
+	assert.True(t, now.Add(time.Minute).Equal(a.LastFailure))
+
+	// backoff after the second failure, the attempt isn't counted
+	a, wait, err = repo.Attempt("user:a", now.Add(time.Minute+time.Second), policy)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute-time.Second, wait)
+	assert.Equal(t, 2, a.Failures)
+
+	require.NoError(t, repo.Refund("user:a"))
+	a, err = repo.Get("user:a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.Failures)
+
+	// the window has passed, counting starts again
+	a, _, err = repo.Attempt("user:a", now.Add(2*time.Hour), policy)
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.Failures)
+
+	purged, err := repo.Purge(now.Add(2*time.Hour + time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestLockoutRepository_ConcurrentAttempts(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewLockoutRepository(sqlite.NewDB(db))
+	now := time.Now()
+	policy := lockout.Policy{FreeAttempts: 3, MaxFailures: 10, BaseDelay: time.Minute, MaxDelay: time.Hour,
+		LockoutDuration: time.Hour, Window: time.Hour}
+
+	var wg sync.WaitGroup
+	var allowed int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, wait, err := repo.Attempt("user:a", now, policy)
+			assert.NoError(t, err)
+			if err == nil && wait == 0 {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(policy.FreeAttempts+1), allowed)
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewIdempotencyRepository(sqlite.NewDB(db))
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	record := &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "abc", CreatedAt: now,
+		LockedUntil: now.Add(time.Minute)}
+
+	stored, err := repo.Reserve(record, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// request in progress keeps the key until its lease ends
+	retry := &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "abc", CreatedAt: now.Add(30 * time.Second),
+		LockedUntil: now.Add(90 * time.Second)}
+	stored, err = repo.Reserve(retry, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 0, stored.Status)
+	retry = &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "abc", CreatedAt: now.Add(2 * time.Minute),
+		LockedUntil: now.Add(3 * time.Minute)}
+	stored, err = repo.Reserve(retry, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// request which has lost its reservation neither completes nor releases the new one
+	require.NoError(t, repo.Complete("1:key", now, 201, "application/json", []byte(`{"workout_id":2}`)))
+	require.NoError(t, repo.Release("1:key", now))
+	stored, err = repo.Reserve(record, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 0, stored.Status)
+
+	require.NoError(t, repo.Complete("1:key", retry.CreatedAt, 200, "application/json", []byte(`{"workout_id":1}`)))
+	stored, err = repo.Reserve(record, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 200, stored.Status)
+	assert.Equal(t, []byte(`{"workout_id":1}`), stored.Body)
+
+	// completed key isn't released, expired one is reserved again
+	require.NoError(t, repo.Release("1:key", retry.CreatedAt))
+	later := &entity.IdempotencyRecord{Key: "1:key", Fingerprint: "def", CreatedAt: now.Add(2 * time.Hour)}
+	stored, err = repo.Reserve(later, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	purged, err := repo.Purge(now.Add(3 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestIdentityRepository_Link(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewIdentityRepository(sqlite.NewDB(db))
+	firstId := insertUser(t, db, "first@test.com", entity.UserRole)
+	secondId := insertUser(t, db, "second@test.com", entity.UserRole)
+
+	identity := func(subject string) *entity.ExternalIdentity {
+		return &entity.ExternalIdentity{Provider: "google", Subject: subject, Email: subject + "@test.com"}
+	}
+	require.NoError(t, repo.LinkIdentity(firstId, identity("1"), false))
+
+	err := repo.LinkIdentity(secondId, identity("1"), false)
+	assert.EqualError(t, err, "provider account is already linked to another user")
+
+	err = repo.LinkIdentity(firstId, identity("2"), false)
+	assert.EqualError(t, err, "provider is already linked")
+}
+
+func TestUserRepository_AnonymizeScheduledUsers(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewUserRepository(sqlite.NewDB(db))
+	trainerId := insertUser(t, db, "trainer@test.com", entity.TrainerRole)
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+	insertPartnership(t, db, trainerId, userId, entity.StatusApproved)
+
+	now := time.Now()
+	require.NoError(t, repo.ScheduleDeletion(userId, now.Add(-time.Minute)))
+	require.NoError(t, repo.ScheduleDeletion(trainerId, now.Add(time.Hour)))
+
+	anonymized, err := repo.AnonymizeScheduledUsers(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), anonymized)
+
+	var status entity.Status
+	require.NoError(t, db.Get(&status, "SELECT status FROM partnerships WHERE user_id = $1", userId))
+	assert.Equal(t, entity.StatusEndedByUser, status)
+}
+
+func TestUserRepository_EmailChangeRevokesVerification(t *testing.T) {
+	db := newDB(t)
+	users := sqlite.NewUserRepository(sqlite.NewDB(db))
+	accounts := sqlite.NewAccountRepository(sqlite.NewDB(db))
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+
+	expiresAt := time.Now().Add(time.Hour)
+	for _, id := range []string{"first", "second"} {
+		require.NoError(t, accounts.CreateToken(&entity.AccountToken{Id: id, UserId: userId,
+			Purpose: entity.TokenVerifyEmail, ExpiresAt: expiresAt}))
+	}
+
+	require.NoError(t, users.UpdateProfile(userId, &entity.ProfileUpdate{Email: "user@test.com", Name: "Renamed",
+		Surname: "Smith", TimeZone: "UTC"}, 0))
+	require.NoError(t, accounts.VerifyEmail("first", userId))
+
+	require.NoError(t, users.PatchProfile(userId, &entity.ProfilePatch{
+		Email: entity.Patch[string]{Set: true, Value: "new@test.com"}}, 0))
+	err := accounts.VerifyEmail("second", userId)
+	assert.EqualError(t, err, "token is invalid, expired or already used")
+
+	verified, err := users.IsVerified(userId)
+	require.NoError(t, err)
+	assert.False(t, verified)
+}
+
+func TestExportRepository_StaleExports(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewExportRepository(sqlite.NewDB(db))
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+
+	lostId, err := repo.CreateExport(nil, userId, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = repo.CreateExport(nil, userId, time.Now().Add(-time.Hour))
+	assert.EqualError(t, err, "export is already in progress")
+
+	// build of the first export has been lost, it is failed and a new one is started
+	id, err := repo.CreateExport(nil, userId, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	lost, err := repo.GetExport(userId, lostId)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportFailed, lost.Status)
+
+	err = repo.CompleteExport(lostId, []byte("zip"), "lost", time.Now().Add(time.Hour))
+	assert.EqualError(t, err, "export is no longer pending")
+
+	failed, err := repo.FailStaleExports(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failed)
+	current, err := repo.GetExport(userId, id)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportFailed, current.Status)
+}
+
+func TestGroupRepository_Members(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewGroupRepository(sqlite.NewDB(db))
+	trainerId := insertUser(t, db, "trainer@test.com", entity.TrainerRole)
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+	insertPartnership(t, db, trainerId, userId, entity.StatusApproved)
+
+	groupId, err := repo.CreateGroup(&entity.ClientGroup{TrainerId: trainerId, Name: "Morning"})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddGroupMember(trainerId, groupId, userId))
+	assert.EqualError(t, repo.AddGroupMember(trainerId, groupId, userId), "user is already a member of group")
+	assert.EqualError(t, repo.RemoveGroupMember(userId, groupId, userId), "no member to remove")
+	assert.NoError(t, repo.RemoveGroupMember(trainerId, groupId, userId))
+
+	workoutId, err := repo.CreateGroupWorkout(&entity.GroupWorkout{TrainerId: trainerId, Title: "Run",
+		Date: time.Now(), Capacity: 5}, []int64{userId})
+	require.NoError(t, err)
+	assert.EqualError(t, repo.SetAttendance(userId, workoutId, userId, entity.AttendanceAttended),
+		"no participant with provided id")
+	require.NoError(t, repo.SetAttendance(trainerId, workoutId, userId, entity.AttendanceAttended))
+
+	workout, err := repo.GetGroupWorkoutById(trainerId, workoutId)
+	require.NoError(t, err)
+	require.Len(t, workout.Participants, 1)
+	assert.Equal(t, entity.AttendanceAttended, workout.Participants[0].Status)
+}
+
+func TestUserRepository_Partnership(t *testing.T) {
+	db := newDB(t)
+	repo := sqlite.NewUserRepository(sqlite.NewDB(db))
+	trainerId := insertUser(t, db, "trainer@test.com", entity.TrainerRole)
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+
+	id, err := repo.SendRequestToTrainer(trainerId, userId)
+	require.NoError(t, err)
+	// open request is kept unique by partnerships_open_idx and returned again
+	again, err := repo.SendRequestToTrainer(trainerId, userId)
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	approved, err := repo.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	assert.Equal(t, id, approved)
+	_, err = repo.SendRequestToTrainer(trainerId, userId)
+	assert.EqualError(t, err, "there is already approved partnership with trainer")
+
+	_, err = repo.EndPartnershipWithTrainer(nil, trainerId, userId)
+	require.NoError(t, err)
+	_, err = repo.InitPartnershipWithUser(trainerId, userId)
+	assert.Error(t, err)
+
+	reopened, err := repo.SendRequestToTrainer(trainerId, userId)
+	require.NoError(t, err)
+	assert.Equal(t, id, reopened)
+	p, err := repo.GetPartnership(trainerId, userId)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusRequest, p.Status)
+}
+
+func TestTxManager(t *testing.T) {
+	db := newDB(t)
+	repos := repository.NewSQLiteRepository(db)
+	trainerId := insertUser(t, db, "trainer@test.com", entity.TrainerRole)
+	userId := insertUser(t, db, "user@test.com", entity.UserRole)
+
+	t.Run("Rollback", func(t *testing.T) {
+		err := repos.WithinTx(repository.TxOptions{}, func(repos *repository.Repository) error {
+			_, err := repos.User.SendRequestToTrainer(trainerId, userId)
+			require.NoError(t, err)
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		_, err = repos.User.GetPartnership(trainerId, userId)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Savepoint", func(t *testing.T) {
+		err := repos.WithinTx(repository.TxOptions{Isolation: sql.LevelSerializable},
+			func(repos *repository.Repository) error {
+				// failed transaction of repository is rolled back to its savepoint only
+				assert.EqualError(t, repos.User.DeleteUser(&entity.Actor{Type: entity.ActorAdmin, Id: 1}, 0),
+					"no user to delete")
+				_, err := repos.User.SendRequestToTrainer(trainerId, userId)
+				return err
+			})
+		require.NoError(t, err)
+
+		p, err := repos.User.GetPartnership(trainerId, userId)
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusRequest, p.Status)
+	})
+}
@@ -0,0 +1,97 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+)
+
+type AccountRepository struct {
+	db *dbtx.DB
+}
+
+func NewAccountRepository(db *dbtx.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+func (r *AccountRepository) CreateToken(token *entity.AccountToken) error {
+	query := fmt.Sprintf("INSERT INTO %s (id, user_id, purpose, expires_at) values ($1, $2, $3, $4)", userTokensTable)
+	_, err := r.db.Exec(query, token.Id, token.UserId, token.Purpose, token.ExpiresAt)
+	return err
+}
+
+func (r *AccountRepository) VerifyEmail(tokenId string, userId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err = consumeToken(tx, tokenId, userId, entity.TokenVerifyEmail); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET verified_at = COALESCE(verified_at, NOW()) "+
+		"WHERE id = $1 AND deleted_at IS NULL", userTable)
+	res, err := tx.Exec(query, userId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		_ = tx.Rollback()
+		return errors.New("no user to verify")
+	}
+	return tx.Commit()
+}
+
+// ResetPassword sets new password, revokes other reset tokens and sessions of user. Reset proves
+// ownership of email, so account becomes verified as well.
+func (r *AccountRepository) ResetPassword(tokenId string, userId int64, passwordHash string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err = consumeToken(tx, tokenId, userId, entity.TokenResetPassword); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET password_hash = $2, verified_at = COALESCE(verified_at, NOW()), "+
+		"token_version = token_version + 1 WHERE id = $1 AND deleted_at IS NULL", userTable)
+	res, err := tx.Exec(query, userId, passwordHash)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		_ = tx.Rollback()
+		return errors.New("no user to reset password")
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL",
+		userTokensTable)
+	if _, err = tx.Exec(query, userId, entity.TokenResetPassword); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func consumeToken(tx *dbtx.Tx, tokenId string, userId int64, purpose entity.TokenPurpose) error {
+	query := fmt.Sprintf("UPDATE %s SET used_at = NOW() WHERE id = $1 AND user_id = $2 AND purpose = $3 "+
+		"AND used_at IS NULL AND expires_at > NOW()", userTokensTable)
+	res, err := tx.Exec(query, tokenId, userId, purpose)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("token is invalid, expired or already used")
+	}
+	return nil
+}
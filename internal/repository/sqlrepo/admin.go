@@ -0,0 +1,30 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"fmt"
+)
+
+type AdminRepository struct {
+	db *dbtx.DB
+}
+
+func NewAdminRepository(db *dbtx.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+func (r *AdminRepository) Authorize(login, passwordHash string) (int64, error) {
+	var admin entity.Admin
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE login =$1 AND password_hash = $2", adminTable)
+	err := r.db.Get(&admin, query, login, passwordHash)
+	return admin.Id, err
+}
+
+func (r *AdminRepository) GetLogin(adminId int64) (string, error) {
+	var login string
+	query := fmt.Sprintf("SELECT login FROM %s WHERE id = $1", adminTable)
+	err := r.db.Get(&login, query, adminId)
+	return login, err
+}
@@ -0,0 +1,254 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type ApplicationRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewApplicationRepository(db *dbtx.DB, dialect Dialect) *ApplicationRepository {
+	return &ApplicationRepository{db: db, dialect: dialect}
+}
+
+func (r *ApplicationRepository) CreateApplication(actor *entity.Actor, app *entity.TrainerApplication) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var role entity.Role
+	query := fmt.Sprintf("SELECT role FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	if err = tx.Get(&role, query, app.UserId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if role != entity.UserRole {
+		_ = tx.Rollback()
+		return -1, errors.New("only users can apply to become trainers")
+	}
+
+	var id int64
+	query = fmt.Sprintf("INSERT INTO %s (user_id, status, bio, specialization, experience_years) "+
+		"values ($1, $2, $3, $4, $5) RETURNING id", trainerApplicationsTable)
+	row := tx.QueryRow(query, app.UserId, entity.ApplicationPending, app.Bio, app.Specialization, app.ExperienceYears)
+	if err = row.Scan(&id); err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsUniqueViolation(err) {
+			return -1, errors.New("application is already under consideration")
+		}
+		return 0, err
+	}
+
+	app.Status = entity.ApplicationPending
+	err = writeAudit(tx, actor, entity.AuditApplicationSubmit, entity.AuditTargetApplication, id,
+		nil, applicationAuditState(app))
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *ApplicationRepository) GetUserApplications(userId int64) ([]*entity.TrainerApplication, error) {
+	apps := make([]*entity.TrainerApplication, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY created_at DESC", trainerApplicationsTable)
+	if err := r.db.Select(&apps, query, userId); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// UpdateApplication resubmits application which admin asked to change.
+func (r *ApplicationRepository) UpdateApplication(actor *entity.Actor, userId, appId int64,
+	input *entity.TrainerApplicationInput) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	app, err := r.lockApplication(tx, appId)
+	if err != nil || app.UserId != userId {
+		_ = tx.Rollback()
+		return errors.New("no application with provided id")
+	}
+	if app.Status != entity.ApplicationChangesRequested {
+		_ = tx.Rollback()
+		return errors.New("application can be changed only on reviewer request")
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = $2, bio = $3, specialization = $4, experience_years = $5, "+
+		"updated_at = NOW() WHERE id = $1", trainerApplicationsTable)
+	_, err = tx.Exec(query, appId, entity.ApplicationPending, input.Bio, input.Specialization, input.ExperienceYears)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	after := *app
+	after.Status = entity.ApplicationPending
+	after.Bio, after.Specialization, after.ExperienceYears = input.Bio, input.Specialization, input.ExperienceYears
+	err = writeAudit(tx, actor, entity.AuditApplicationUpdate, entity.AuditTargetApplication, appId,
+		applicationAuditState(app), applicationAuditState(&after))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ApplicationRepository) AddCertificate(actor *entity.Actor, userId int64,
+	cert *entity.Certificate) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	app, err := r.lockApplication(tx, cert.ApplicationId)
+	if err != nil || app.UserId != userId {
+		_ = tx.Rollback()
+		return -1, errors.New("no application with provided id")
+	}
+	if app.Status != entity.ApplicationPending && app.Status != entity.ApplicationChangesRequested {
+		_ = tx.Rollback()
+		return -1, errors.New("application is already reviewed")
+	}
+
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (application_id, file_name, content_type, size, content) "+
+		"values ($1, $2, $3, $4, $5) RETURNING id", certificatesTable)
+	row := tx.QueryRow(query, cert.ApplicationId, cert.FileName, cert.ContentType, cert.Size, cert.Content)
+	if err = row.Scan(&id); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	after := map[string]interface{}{"certificate_id": id, "file_name": cert.FileName}
+	err = writeAudit(tx, actor, entity.AuditApplicationCertificate, entity.AuditTargetApplication,
+		cert.ApplicationId, nil, after)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *ApplicationRepository) GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error) {
+	apps := make([]*entity.TrainerApplication, 0)
+	query := fmt.Sprintf("SELECT a.* FROM %s a JOIN %s u ON u.id = a.user_id "+
+		"WHERE a.status = $1 AND u.deleted_at IS NULL ORDER BY a.updated_at", trainerApplicationsTable, userTable)
+	if err := r.db.Select(&apps, query, status); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+func (r *ApplicationRepository) GetApplicationById(appId int64) (*entity.TrainerApplication, error) {
+	var app entity.TrainerApplication
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", trainerApplicationsTable)
+	if err := r.db.Get(&app, query, appId); err != nil {
+		return nil, err
+	}
+
+	app.Certificates = make([]*entity.Certificate, 0)
+	query = fmt.Sprintf("SELECT id, application_id, file_name, content_type, size, created_at FROM %s "+
+		"WHERE application_id = $1 ORDER BY id", certificatesTable)
+	if err := r.db.Select(&app.Certificates, query, appId); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+func (r *ApplicationRepository) GetCertificate(appId, certificateId int64) (*entity.Certificate, error) {
+	var cert entity.Certificate
+	query := fmt.Sprintf("SELECT * FROM %s WHERE application_id = $1 AND id = $2", certificatesTable)
+	if err := r.db.Get(&cert, query, appId, certificateId); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// ReviewApplication records decision of admin on pending application, approval switches role of user to trainer.
+func (r *ApplicationRepository) ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus,
+	note string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	app, err := r.lockApplication(tx, appId)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.New("no application with provided id")
+	}
+	if app.Status != entity.ApplicationPending {
+		_ = tx.Rollback()
+		return errors.New("only pending application can be reviewed")
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = $2, reviewer_id = $3, reviewer_note = $4, reviewed_at = NOW(), "+
+		"updated_at = NOW() WHERE id = $1", trainerApplicationsTable)
+	reviewerNote := sql.NullString{String: note, Valid: note != ""}
+	var reviewerId sql.NullInt64
+	if actor != nil {
+		reviewerId = sql.NullInt64{Int64: actor.Id, Valid: true}
+	}
+	if _, err = tx.Exec(query, appId, status, reviewerId, reviewerNote); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if status == entity.ApplicationApproved {
+		query = fmt.Sprintf("UPDATE %s SET role = $2, version = version + 1 WHERE id = $1 AND role = $3 "+
+			"AND deleted_at IS NULL", userTable)
+		res, err := tx.Exec(query, app.UserId, entity.TrainerRole, entity.UserRole)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		rows, _ := res.RowsAffected()
+		if rows != 1 {
+			_ = tx.Rollback()
+			return errors.New("applicant is not an active user")
+		}
+
+		err = writeAudit(tx, actor, entity.AuditUserUpdate, entity.AuditTargetUser, app.UserId,
+			map[string]interface{}{"role": string(entity.UserRole)},
+			map[string]interface{}{"role": string(entity.TrainerRole)})
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	after := *app
+	after.Status, after.ReviewerNote = status, reviewerNote
+	err = writeAudit(tx, actor, reviewAuditActions[status], entity.AuditTargetApplication, appId,
+		applicationAuditState(app), applicationAuditState(&after))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+var reviewAuditActions = map[entity.ApplicationStatus]entity.AuditAction{
+	entity.ApplicationApproved:         entity.AuditApplicationApprove,
+	entity.ApplicationRejected:         entity.AuditApplicationReject,
+	entity.ApplicationChangesRequested: entity.AuditApplicationRequestChanges,
+}
+
+func (r *ApplicationRepository) lockApplication(tx *dbtx.Tx, appId int64) (*entity.TrainerApplication, error) {
+	var app entity.TrainerApplication
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1%s", trainerApplicationsTable, r.dialect.ForUpdate())
+	if err := tx.Get(&app, query, appId); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
@@ -0,0 +1,155 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type AuditRepository struct {
+	db *dbtx.DB
+}
+
+func NewAuditRepository(db *dbtx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// WriteAudit records event which is not a part of other mutation.
+func (r *AuditRepository) WriteAudit(actor *entity.Actor, action entity.AuditAction, targetType string, targetId int64,
+	before, after map[string]interface{}) error {
+	return writeAudit(r.db, actor, action, targetType, targetId, before, after)
+}
+
+func (r *AuditRepository) GetAuditLog(filter *entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorType != "" {
+		add("actor_type = $%d", filter.ActorType)
+	}
+	if filter.ActorId > 0 {
+		add("actor_id = $%d", filter.ActorId)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetId > 0 {
+		add("target_id = $%d", filter.TargetId)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+	if filter.BeforeId > 0 {
+		add("id < $%d", filter.BeforeId)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+
+	entries := make([]*entity.AuditEntry, 0)
+	query := fmt.Sprintf("SELECT * FROM %s %s ORDER BY id DESC LIMIT $%d", auditLogTable, where, len(args))
+	err := r.db.Select(&entries, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// writeAudit stores changed fields of target in the transaction of mutation.
+// Nil actor means that mutation is not privileged and is not recorded.
+func writeAudit(tx execer, actor *entity.Actor, action entity.AuditAction, targetType string, targetId int64,
+	before, after map[string]interface{}) error {
+	if actor == nil {
+		return nil
+	}
+
+	before, after = auditDiff(before, after)
+	beforeData, err := marshalAudit(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := marshalAudit(after)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (actor_type, actor_id, action, target_type, target_id, before, after, "+
+		"request_id, ip) values ($1, $2, $3, $4, $5, $6, $7, $8, $9)", auditLogTable)
+	_, err = tx.Exec(query, actor.Type, actor.Id, action, targetType, targetId,
+		beforeData, afterData, actor.RequestId, actor.IP)
+	return err
+}
+
+func auditDiff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+func marshalAudit(data map[string]interface{}) (entity.AuditData, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+func userAuditState(user *entity.User) map[string]interface{} {
+	return map[string]interface{}{
+		"email":   user.Email,
+		"role":    string(user.Role),
+		"name":    user.Name,
+		"surname": user.Surname,
+	}
+}
+
+func partnershipAuditState(p *entity.Partnership) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    p.UserId,
+		"trainer_id": p.TrainerId,
+		"status":     string(p.Status),
+	}
+}
+
+func applicationAuditState(a *entity.TrainerApplication) map[string]interface{} {
+	return map[string]interface{}{
+		"status":           string(a.Status),
+		"bio":              a.Bio,
+		"specialization":   a.Specialization,
+		"experience_years": a.ExperienceYears,
+		"reviewer_note":    a.ReviewerNote.String,
+	}
+}
+
+// mfaPolicyAuditState is keyed by role, so that diff still names the role whose policy is changed.
+func mfaPolicyAuditState(p *entity.MFAPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		string(p.Role): p.Required,
+	}
+}
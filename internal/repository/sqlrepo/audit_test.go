@@ -0,0 +1,30 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+var testActor = &entity.Actor{Type: entity.ActorAdmin, Id: 1, RequestId: "req", IP: "127.0.0.1"}
+
+func TestWriteAudit(t *testing.T) {
+
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	before := map[string]interface{}{"email": "old", "name": "test"}
+	after := map[string]interface{}{"email": "new", "name": "test"}
+
+	mock.ExpectExec("INSERT INTO audit_log").WithArgs("admin", int64(1), "user.update", "user", int64(2),
+		[]byte(`{"email":"old"}`), []byte(`{"email":"new"}`), "req", "127.0.0.1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, writeAudit(db, testActor, entity.AuditUserUpdate, entity.AuditTargetUser, 2, before, after))
+	assert.NoError(t, writeAudit(db, nil, entity.AuditUserUpdate, entity.AuditTargetUser, 2, before, after))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+)
+
+type CommentRepository struct {
+	db *dbtx.DB
+}
+
+func NewCommentRepository(db *dbtx.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) GetWorkoutComments(workoutId int64) ([]*entity.Comment, error) {
+	comments := make([]*entity.Comment, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE workout_id = $1 ORDER BY id", workoutCommentsTable)
+	err := r.db.Select(&comments, query, workoutId)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) GetCommentById(workoutId, commentId int64) (*entity.Comment, error) {
+	var comment entity.Comment
+	query := fmt.Sprintf("SELECT * FROM %s WHERE workout_id = $1 AND id = $2", workoutCommentsTable)
+	err := r.db.Get(&comment, query, workoutId, commentId)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *CommentRepository) CreateComment(comment *entity.Comment) (int64, error) {
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (workout_id, author_id, body) values ($1, $2, $3) RETURNING id",
+		workoutCommentsTable)
+	row := r.db.QueryRow(query, comment.WorkoutId, comment.AuthorId, comment.Body)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *CommentRepository) UpdateComment(commentId, authorId int64, body string) error {
+	query := fmt.Sprintf("UPDATE %s SET body = $1, updated_at = NOW() WHERE id = $2 AND author_id = $3",
+		workoutCommentsTable)
+	res, err := r.db.Exec(query, body, commentId, authorId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no comment to update")
+	}
+	return nil
+}
+
+func (r *CommentRepository) SetCommentResolved(commentId int64, resolved bool) error {
+	query := fmt.Sprintf("UPDATE %s SET resolved = $1 WHERE id = $2", workoutCommentsTable)
+	res, err := r.db.Exec(query, resolved, commentId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no comment to update")
+	}
+	return nil
+}
+
+func (r *CommentRepository) DeleteComment(commentId, authorId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND author_id = $2", workoutCommentsTable)
+	res, err := r.db.Exec(query, commentId, authorId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no comment to delete")
+	}
+	return nil
+}
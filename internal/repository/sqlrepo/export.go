@@ -0,0 +1,181 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	exportColumns = "id, user_id, status, error, created_at, completed_at, expires_at"
+
+	// exportTimedOut is error of pending export whose build has been lost
+	exportTimedOut = "export has timed out"
+)
+
+type ExportRepository struct {
+	db *dbtx.DB
+}
+
+func NewExportRepository(db *dbtx.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// CreateExport registers pending export unless user has one in progress, pending exports created
+// before staleBefore have lost their build and are failed first.
+func (r *ExportRepository) CreateExport(actor *entity.Actor, userId int64, staleBefore time.Time) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = %s, error = $3, completed_at = NOW() "+
+		"WHERE user_id = $1 AND status = %s AND created_at < $2",
+		dataExportsTable, "'"+entity.ExportFailed+"'", "'"+entity.ExportPending+"'")
+	if _, err = tx.Exec(query, userId, staleBefore, exportTimedOut); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	var id int64
+	query = fmt.Sprintf("INSERT INTO %s (user_id) SELECT $1 "+
+		"WHERE NOT EXISTS (SELECT 1 FROM %s WHERE user_id = $1 AND status = %s) RETURNING id",
+		dataExportsTable, dataExportsTable, "'"+entity.ExportPending+"'")
+	if err = tx.QueryRow(query, userId).Scan(&id); err != nil {
+		_ = tx.Rollback()
+		return -1, errors.New("export is already in progress")
+	}
+
+	after := map[string]interface{}{"export_id": id}
+	if err = writeAudit(tx, actor, entity.AuditUserExport, entity.AuditTargetUser, userId, nil, after); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *ExportRepository) GetExport(userId, exportId int64) (*entity.DataExport, error) {
+	var export entity.DataExport
+	query := fmt.Sprintf("SELECT %s, token FROM %s WHERE user_id = $1 AND id = $2", exportColumns, dataExportsTable)
+	err := r.db.Get(&export, query, userId, exportId)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetLatestExport returns the most recent export of user.
+func (r *ExportRepository) GetLatestExport(userId int64) (*entity.DataExport, error) {
+	var export entity.DataExport
+	query := fmt.Sprintf("SELECT %s, token FROM %s WHERE user_id = $1 ORDER BY id DESC LIMIT 1",
+		exportColumns, dataExportsTable)
+	err := r.db.Get(&export, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetExportArchive returns ready export by download token while the link is not expired.
+func (r *ExportRepository) GetExportArchive(token string) (*entity.DataExport, error) {
+	var export entity.DataExport
+	query := fmt.Sprintf("SELECT %s, archive FROM %s WHERE token = $1 AND status = %s AND expires_at > NOW()",
+		exportColumns, dataExportsTable, "'"+entity.ExportReady+"'")
+	err := r.db.Get(&export, query, token)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// CompleteExport stores archive of pending export, export which has timed out meanwhile stays failed.
+func (r *ExportRepository) CompleteExport(exportId int64, archive []byte, token string, expiresAt time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET status = %s, archive = $2, token = $3, expires_at = $4, "+
+		"completed_at = NOW() WHERE id = $1 AND status = %s",
+		dataExportsTable, "'"+entity.ExportReady+"'", "'"+entity.ExportPending+"'")
+	res, err := r.db.Exec(query, exportId, archive, token, expiresAt)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("export is no longer pending")
+	}
+	return nil
+}
+
+func (r *ExportRepository) FailExport(exportId int64, reason string) error {
+	query := fmt.Sprintf("UPDATE %s SET status = %s, error = $2, completed_at = NOW() WHERE id = $1 AND status = %s",
+		dataExportsTable, "'"+entity.ExportFailed+"'", "'"+entity.ExportPending+"'")
+	_, err := r.db.Exec(query, exportId, reason)
+	return err
+}
+
+// FailStaleExports fails pending exports created before createdBefore, their build has been lost.
+func (r *ExportRepository) FailStaleExports(createdBefore time.Time) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET status = %s, error = $2, completed_at = NOW() "+
+		"WHERE status = %s AND created_at < $1",
+		dataExportsTable, "'"+entity.ExportFailed+"'", "'"+entity.ExportPending+"'")
+	res, err := r.db.Exec(query, createdBefore, exportTimedOut)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// PurgeExpiredExports drops archives with expired download links.
+func (r *ExportRepository) PurgeExpiredExports(expiredBefore time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", dataExportsTable)
+	res, err := r.db.Exec(query, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetExportData collects everything stored about user, soft-deleted accounts are included.
+func (r *ExportRepository) GetExportData(userId int64) (*entity.ExportData, error) {
+	data := &entity.ExportData{
+		Profile:      &entity.User{},
+		Workouts:     make([]*entity.Workout, 0),
+		Partnerships: make([]*entity.Partnership, 0),
+		Messages:     make([]*entity.Message, 0),
+		Comments:     make([]*entity.Comment, 0),
+		Bookings:     make([]*entity.Booking, 0),
+	}
+
+	query := fmt.Sprintf("SELECT id, email, name, surname, role, created_at FROM %s WHERE id = $1", userTable)
+	if err := r.db.Get(data.Profile, query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 OR trainer_id = $1 ORDER BY date", workoutsTable)
+	if err := r.db.Select(&data.Workouts, query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 OR trainer_id = $1 ORDER BY created_at",
+		partnershipsTable)
+	if err := r.db.Select(&data.Partnerships, query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("SELECT m.* FROM %s m JOIN %s p ON p.id = m.partnership_id "+
+		"WHERE p.user_id = $1 OR p.trainer_id = $1 ORDER BY m.id", messagesTable, partnershipsTable)
+	if err := r.db.Select(&data.Messages, query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s WHERE author_id = $1 ORDER BY id", workoutCommentsTable)
+	if err := r.db.Select(&data.Comments, query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 OR trainer_id = $1 ORDER BY starts_at", bookingsTable)
+	if err := r.db.Select(&data.Bookings, query, userId); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,274 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+)
+
+type GroupRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewGroupRepository(db *dbtx.DB, dialect Dialect) *GroupRepository {
+	return &GroupRepository{db: db, dialect: dialect}
+}
+
+func (r *GroupRepository) CreateGroup(group *entity.ClientGroup) (int64, error) {
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (trainer_id, name) values ($1, $2) RETURNING id", clientGroupsTable)
+	row := r.db.QueryRow(query, group.TrainerId, group.Name)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *GroupRepository) GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error) {
+	groups := make([]*entity.ClientGroup, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 ORDER BY name", clientGroupsTable)
+	err := r.db.Select(&groups, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *GroupRepository) GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error) {
+	var group entity.ClientGroup
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND id = $2", clientGroupsTable)
+	err := r.db.Get(&group, query, trainerId, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Members = make([]*entity.User, 0)
+	query = fmt.Sprintf("SELECT u.id, u.email, u.name, u.surname FROM %s u JOIN %s m ON m.user_id = u.id "+
+		"WHERE m.group_id = $1 AND u.deleted_at IS NULL ORDER BY u.surname", userTable, clientGroupMembersTable)
+	err = r.db.Select(&group.Members, query, groupId)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *GroupRepository) DeleteGroup(trainerId, groupId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE trainer_id = $1 AND id = $2", clientGroupsTable)
+	res, err := r.db.Exec(query, trainerId, groupId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no group to delete")
+	}
+	return nil
+}
+
+func (r *GroupRepository) AddGroupMember(trainerId, groupId, userId int64) error {
+	query := fmt.Sprintf("INSERT INTO %s (group_id, user_id) "+
+		"SELECT g.id, $3 FROM %s g WHERE g.id = $2 AND g.trainer_id = $1 "+
+		"AND EXISTS (SELECT 1 FROM %s WHERE trainer_id = $1 AND user_id = $3 AND status = %s)",
+		clientGroupMembersTable, clientGroupsTable, partnershipsTable, "'"+entity.StatusApproved+"'")
+	res, err := r.db.Exec(query, trainerId, groupId, userId)
+	if err != nil {
+		if r.dialect.IsUniqueViolation(err) {
+			return errors.New("user is already a member of group")
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no group or approved partnership with user")
+	}
+	return nil
+}
+
+func (r *GroupRepository) RemoveGroupMember(trainerId, groupId, userId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE group_id = (SELECT id FROM %s WHERE trainer_id = $1 AND id = $2) "+
+		"AND user_id = $3", clientGroupMembersTable, clientGroupsTable)
+	res, err := r.db.Exec(query, trainerId, groupId, userId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no member to remove")
+	}
+	return nil
+}
+
+func (r *GroupRepository) CreateGroupWorkout(workout *entity.GroupWorkout, userIds []int64) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (trainer_id, group_id, title, description, date, capacity) values "+
+		"($1, $2, $3, $4, $5, $6) RETURNING id", groupWorkoutsTable)
+	row := tx.QueryRow(query, workout.TrainerId, workout.GroupId, workout.Title, workout.Description,
+		workout.Date, workout.Capacity)
+	if err = row.Scan(&id); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	workout.Id = id
+
+	for _, userId := range userIds {
+		if code, err := r.insertParticipant(tx, workout, userId); err != nil {
+			_ = tx.Rollback()
+			return code, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+func (r *GroupRepository) GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error) {
+	workouts := make([]*entity.GroupWorkout, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 ORDER BY date DESC", groupWorkoutsTable)
+	err := r.db.Select(&workouts, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *GroupRepository) GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error) {
+	var workout entity.GroupWorkout
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND id = $2", groupWorkoutsTable)
+	err := r.db.Get(&workout, query, trainerId, groupWorkoutId)
+	if err != nil {
+		return nil, err
+	}
+
+	workout.Participants = make([]*entity.Participant, 0)
+	query = fmt.Sprintf("SELECT p.*, u.name, u.surname FROM %s p JOIN %s u ON u.id = p.user_id "+
+		"WHERE p.group_workout_id = $1 ORDER BY u.surname", groupWorkoutParticipantsTable, userTable)
+	err = r.db.Select(&workout.Participants, query, groupWorkoutId)
+	if err != nil {
+		return nil, err
+	}
+	return &workout, nil
+}
+
+func (r *GroupRepository) DeleteGroupWorkout(trainerId, groupWorkoutId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id IN (SELECT p.workout_id FROM %s p JOIN %s g "+
+		"ON g.id = p.group_workout_id WHERE g.trainer_id = $1 AND g.id = $2)",
+		workoutsTable, groupWorkoutParticipantsTable, groupWorkoutsTable)
+	if _, err = tx.Exec(query, trainerId, groupWorkoutId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE trainer_id = $1 AND id = $2", groupWorkoutsTable)
+	res, err := tx.Exec(query, trainerId, groupWorkoutId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		_ = tx.Rollback()
+		return errors.New("no group workout to delete")
+	}
+	return tx.Commit()
+}
+
+func (r *GroupRepository) AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var workout entity.GroupWorkout
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND id = $2%s", groupWorkoutsTable,
+		r.dialect.ForUpdate())
+	if err = tx.Get(&workout, query, trainerId, groupWorkoutId); err != nil {
+		_ = tx.Rollback()
+		return errors.New("no group workout with provided id")
+	}
+
+	var participants int
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE group_workout_id = $1", groupWorkoutParticipantsTable)
+	if err = tx.Get(&participants, query, groupWorkoutId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if participants >= workout.Capacity {
+		_ = tx.Rollback()
+		return errors.New("participant cap is reached")
+	}
+
+	if _, err = r.insertParticipant(tx, &workout, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *GroupRepository) RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = (SELECT p.workout_id FROM %s p JOIN %s g "+
+		"ON g.id = p.group_workout_id WHERE g.trainer_id = $1 AND g.id = $2 AND p.user_id = $3)",
+		workoutsTable, groupWorkoutParticipantsTable, groupWorkoutsTable)
+	res, err := r.db.Exec(query, trainerId, groupWorkoutId, userId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no participant to remove")
+	}
+	return nil
+}
+
+func (r *GroupRepository) SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error {
+	query := fmt.Sprintf("UPDATE %s SET status = $4 WHERE group_workout_id = "+
+		"(SELECT id FROM %s WHERE trainer_id = $1 AND id = $2) AND user_id = $3",
+		groupWorkoutParticipantsTable, groupWorkoutsTable)
+	res, err := r.db.Exec(query, trainerId, groupWorkoutId, userId, status)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no participant with provided id")
+	}
+	return nil
+}
+
+func (r *GroupRepository) insertParticipant(tx *dbtx.Tx, workout *entity.GroupWorkout, userId int64) (int64, error) {
+	var status entity.Status
+	query := fmt.Sprintf("SELECT status FROM %s WHERE trainer_id = $1 AND user_id = $2 %s",
+		partnershipsTable, currentPartnership)
+	err := tx.Get(&status, query, workout.TrainerId, userId)
+	if err != nil || status != entity.StatusApproved {
+		return -1, fmt.Errorf("no approved partnership with user %d", userId)
+	}
+
+	var workoutId int64
+	query = fmt.Sprintf("INSERT INTO %s (title, trainer_id, user_id, description, date) values "+
+		"($1, $2, $3, $4, $5) RETURNING id", workoutsTable)
+	row := tx.QueryRow(query, workout.Title, workout.TrainerId, userId, workout.Description, workout.Date)
+	if err = row.Scan(&workoutId); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (group_workout_id, user_id, workout_id, status) values ($1, $2, $3, $4)",
+		groupWorkoutParticipantsTable)
+	_, err = tx.Exec(query, workout.Id, userId, workoutId, entity.AttendanceRegistered)
+	if err != nil {
+		if r.dialect.IsUniqueViolation(err) {
+			return -1, fmt.Errorf("user %d is already a participant", userId)
+		}
+		return 0, err
+	}
+	return workoutId, nil
+}
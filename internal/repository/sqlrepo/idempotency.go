@@ -0,0 +1,76 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotencyRepository stores responses of requests with Idempotency-Key, so that replays are
+// recognized by every replica and after restarts.
+type IdempotencyRepository struct {
+	db *dbtx.DB
+}
+
+func NewIdempotencyRepository(db *dbtx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve inserts record or replaces the expired one or the one in progress past its lease. Key released
+// between insert and select is reserved again.
+func (r *IdempotencyRepository) Reserve(record *entity.IdempotencyRecord,
+	expiredBefore time.Time) (*entity.IdempotencyRecord, error) {
+	insert := fmt.Sprintf("INSERT INTO %[1]s (idempotency_key, fingerprint, status, content_type, body, created_at, "+
+		"locked_until) values ($1, $2, 0, '', NULL, $3, $5) ON CONFLICT (idempotency_key) DO UPDATE SET "+
+		"fingerprint = $2, status = 0, content_type = '', body = NULL, created_at = $3, locked_until = $5 "+
+		"WHERE %[1]s.created_at < $4 OR (%[1]s.status = 0 AND %[1]s.locked_until < $3)", idempotencyKeysTable)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE idempotency_key = $1", idempotencyKeysTable)
+
+	for {
+		res, err := r.db.Exec(insert, record.Key, record.Fingerprint, record.CreatedAt, expiredBefore,
+			record.LockedUntil)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 1 {
+			return nil, err
+		}
+
+		var stored entity.IdempotencyRecord
+		err = r.db.Get(&stored, query, record.Key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &stored, nil
+	}
+}
+
+func (r *IdempotencyRepository) Complete(key string, createdAt time.Time, status int, contentType string,
+	body []byte) error {
+	query := fmt.Sprintf("UPDATE %s SET status = $1, content_type = $2, body = $3 WHERE idempotency_key = $4 "+
+		"AND status = 0 AND created_at = $5", idempotencyKeysTable)
+	_, err := r.db.Exec(query, status, contentType, body, key, createdAt)
+	return err
+}
+
+func (r *IdempotencyRepository) Release(key string, createdAt time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = $1 AND status = 0 AND created_at = $2",
+		idempotencyKeysTable)
+	_, err := r.db.Exec(query, key, createdAt)
+	return err
+}
+
+func (r *IdempotencyRepository) Purge(createdBefore time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE created_at < $1", idempotencyKeysTable)
+	res, err := r.db.Exec(query, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,148 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type IdentityRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewIdentityRepository(db *dbtx.DB, dialect Dialect) *IdentityRepository {
+	return &IdentityRepository{db: db, dialect: dialect}
+}
+
+// GetIdentityUser returns user linked to provider account, nil means that account is not linked.
+func (r *IdentityRepository) GetIdentityUser(provider, subject string) (*entity.User, error) {
+	var user entity.User
+	query := fmt.Sprintf("SELECT u.id, u.email, u.name, u.surname, u.role, u.verified_at FROM %s u "+
+		"JOIN %s i ON i.user_id = u.id WHERE i.provider = $1 AND i.subject = $2 AND u.deleted_at IS NULL",
+		userTable, externalIdentitiesTable)
+	err := r.db.Get(&user, query, provider, subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateIdentityUser creates verified user without password for provider account.
+func (r *IdentityRepository) CreateIdentityUser(user *entity.User, identity *entity.ExternalIdentity) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (email, password_hash, role, name, surname, verified_at) "+
+		"values ($1, '', $2, $3, $4, NOW()) RETURNING id", userTable)
+	err = tx.Get(&id, query, user.Email, entity.UserRole, user.Name, user.Surname)
+	if err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsUniqueViolation(err) {
+			return -1, errors.New("email has already reserved")
+		}
+		return 0, err
+	}
+
+	identity.UserId = id
+	if err = r.insertIdentity(tx, identity); err != nil {
+		_ = tx.Rollback()
+		return -1, err
+	}
+	return id, tx.Commit()
+}
+
+// LinkIdentity links provider account to user. With takeOver password of user is
+// cleared and sessions are revoked, it is used when user had never verified email.
+func (r *IdentityRepository) LinkIdentity(userId int64, identity *entity.ExternalIdentity, takeOver bool) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if takeOver {
+		query := fmt.Sprintf("UPDATE %s SET password_hash = '', verified_at = NOW(), "+
+			"token_version = token_version + 1 WHERE id = $1 AND verified_at IS NULL", userTable)
+		if _, err = tx.Exec(query, userId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	identity.UserId = userId
+	if err = r.insertIdentity(tx, identity); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *IdentityRepository) GetIdentities(userId int64) ([]*entity.ExternalIdentity, error) {
+	identities := make([]*entity.ExternalIdentity, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY provider", externalIdentitiesTable)
+	if err := r.db.Select(&identities, query, userId); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// UnlinkIdentity refuses to remove the only way to sign in of user without password.
+func (r *IdentityRepository) UnlinkIdentity(userId int64, provider string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var passwordHash string
+	query := fmt.Sprintf("SELECT password_hash FROM %s WHERE id = $1 AND deleted_at IS NULL%s", userTable,
+		r.dialect.ForUpdate())
+	if err = tx.Get(&passwordHash, query, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	var linked int
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE user_id = $1", externalIdentitiesTable)
+	if err = tx.Get(&linked, query, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if passwordHash == "" && linked <= 1 {
+		_ = tx.Rollback()
+		return errors.New("reset password before unlinking the only sign in method")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND provider = $2", externalIdentitiesTable)
+	res, err := tx.Exec(query, userId, provider)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		_ = tx.Rollback()
+		return errors.New("provider is not linked")
+	}
+	return tx.Commit()
+}
+
+func (r *IdentityRepository) insertIdentity(tx *dbtx.Tx, identity *entity.ExternalIdentity) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, provider, subject, email) values ($1, $2, $3, $4)",
+		externalIdentitiesTable)
+	_, err := tx.Exec(query, identity.UserId, identity.Provider, identity.Subject, identity.Email)
+
+	if r.dialect.IsUniqueViolation(err) {
+		if r.dialect.IsUniqueViolationOn(err, externalIdentitiesTable, "subject") {
+			return errors.New("provider account is already linked to another user")
+		}
+		return errors.New("provider is already linked")
+	}
+	return err
+}
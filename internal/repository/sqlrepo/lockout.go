@@ -0,0 +1,99 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LockoutRepository stores failed sign in attempts, so that limits are shared between replicas and survive restarts.
+type LockoutRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewLockoutRepository(db *dbtx.DB, dialect Dialect) *LockoutRepository {
+	return &LockoutRepository{db: db, dialect: dialect}
+}
+
+// Attempt locks row of key, which is created with no failures for the first attempt, so that
+// concurrent attempts are checked against the policy one by one.
+func (r *LockoutRepository) Attempt(key string, now time.Time, policy lockout.Policy) (*entity.SignInAttempts,
+	time.Duration, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (attempt_key, failures, last_failure) values ($1, 0, $2) "+
+		"ON CONFLICT (attempt_key) DO NOTHING", signInAttemptsTable)
+	if _, err = tx.Exec(query, key, now); err != nil {
+		_ = tx.Rollback()
+		return nil, 0, err
+	}
+
+	var a entity.SignInAttempts
+	query = fmt.Sprintf("SELECT * FROM %s WHERE attempt_key = $1%s", signInAttemptsTable, r.dialect.ForUpdate())
+	if err = tx.Get(&a, query, key); err != nil {
+		_ = tx.Rollback()
+		return nil, 0, err
+	}
+	if wait, _ := policy.Wait(&a, now); wait > 0 {
+		_ = tx.Rollback()
+		return &a, wait, nil
+	}
+
+	if a.LastFailure.Before(now.Add(-policy.Window)) {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailure = now
+	query = fmt.Sprintf("UPDATE %s SET failures = $2, last_failure = $3 WHERE attempt_key = $1", signInAttemptsTable)
+	if _, err = tx.Exec(query, key, a.Failures, a.LastFailure); err != nil {
+		_ = tx.Rollback()
+		return nil, 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+	return &a, 0, nil
+}
+
+func (r *LockoutRepository) Refund(key string) error {
+	query := fmt.Sprintf("UPDATE %s SET failures = failures - 1 WHERE attempt_key = $1 AND failures > 0",
+		signInAttemptsTable)
+	_, err := r.db.Exec(query, key)
+	return err
+}
+
+func (r *LockoutRepository) Get(key string) (*entity.SignInAttempts, error) {
+	var a entity.SignInAttempts
+	query := fmt.Sprintf("SELECT * FROM %s WHERE attempt_key = $1", signInAttemptsTable)
+	err := r.db.Get(&a, query, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *LockoutRepository) Reset(key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE attempt_key = $1", signInAttemptsTable)
+	_, err := r.db.Exec(query, key)
+	return err
+}
+
+func (r *LockoutRepository) Purge(lastFailureBefore time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE last_failure < $1", signInAttemptsTable)
+	res, err := r.db.Exec(query, lastFailureBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,52 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+)
+
+type MessageRepository struct {
+	db *dbtx.DB
+}
+
+func NewMessageRepository(db *dbtx.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func (r *MessageRepository) GetMessages(partnershipId, beforeId int64, limit int) ([]*entity.Message, error) {
+	messages := make([]*entity.Message, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE partnership_id = $1 AND ($2 = 0 OR id < $2) "+
+		"ORDER BY id DESC LIMIT $3", messagesTable)
+	err := r.db.Select(&messages, query, partnershipId, beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) CreateMessage(message *entity.Message) (int64, error) {
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (partnership_id, sender_id, body) "+
+		"SELECT $1, $2, $3 WHERE EXISTS (SELECT 1 FROM %s WHERE id = $1 AND status = %s) RETURNING id",
+		messagesTable, partnershipsTable, "'"+entity.StatusApproved+"'")
+	row := r.db.QueryRow(query, message.PartnershipId, message.SenderId, message.Body)
+	if err := row.Scan(&id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return -1, errors.New("partnership is not active, conversation is read-only")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *MessageRepository) MarkMessagesRead(partnershipId, readerId int64) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET read_at = NOW() "+
+		"WHERE partnership_id = $1 AND sender_id <> $2 AND read_at IS NULL", messagesTable)
+	res, err := r.db.Exec(query, partnershipId, readerId)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,168 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type MFARepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewMFARepository(db *dbtx.DB, dialect Dialect) *MFARepository {
+	return &MFARepository{db: db, dialect: dialect}
+}
+
+func (r *MFARepository) GetFactor(subject entity.ActorType, subjectId int64) (*entity.MFAFactor, error) {
+	var factor entity.MFAFactor
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = $1", mfaFactorsTable, subjectColumn(subject))
+	err := r.db.Get(&factor, query, subjectId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// SaveFactor stores secret of pending enrollment, secret of confirmed factor can't be replaced.
+func (r *MFARepository) SaveFactor(subject entity.ActorType, subjectId int64, secret string) error {
+	query := fmt.Sprintf("INSERT INTO %[1]s (%[2]s, secret) values ($1, $2) ON CONFLICT (%[2]s) "+
+		"DO UPDATE SET secret = $2, last_step = 0, created_at = NOW() WHERE %[1]s.confirmed_at IS NULL",
+		mfaFactorsTable, subjectColumn(subject))
+	res, err := r.db.Exec(query, subjectId, secret)
+	if err != nil {
+		return err
+	}
+	saved, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if saved == 0 {
+		return errors.New("two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// ConfirmFactor activates pending factor and replaces its recovery codes.
+func (r *MFARepository) ConfirmFactor(subject entity.ActorType, subjectId, step int64, codeHashes []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var factorId int64
+	query := fmt.Sprintf("UPDATE %s SET confirmed_at = NOW(), last_step = $2 WHERE %s = $1 "+
+		"AND confirmed_at IS NULL RETURNING id", mfaFactorsTable, subjectColumn(subject))
+	if err = tx.Get(&factorId, query, subjectId, step); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("two-factor enrollment is not started")
+		}
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE factor_id = $1", mfaRecoveryCodesTable)
+	if _, err = tx.Exec(query, factorId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (factor_id, code_hash) values ($1, $2)", mfaRecoveryCodesTable)
+	for _, hash := range codeHashes {
+		if _, err = tx.Exec(query, factorId, hash); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UseStep consumes time step of accepted code, so that the code can't be replayed.
+func (r *MFARepository) UseStep(subject entity.ActorType, subjectId, step int64) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET last_step = $2 WHERE %s = $1 AND confirmed_at IS NOT NULL "+
+		"AND last_step < $2", mfaFactorsTable, subjectColumn(subject))
+	return affected(r.db.Exec(query, subjectId, step))
+}
+
+func (r *MFARepository) UseRecoveryCode(subject entity.ActorType, subjectId int64, codeHash string) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET used_at = NOW() WHERE code_hash = $2 AND used_at IS NULL "+
+		"AND factor_id = (SELECT id FROM %s WHERE %s = $1 AND confirmed_at IS NOT NULL)",
+		mfaRecoveryCodesTable, mfaFactorsTable, subjectColumn(subject))
+	return affected(r.db.Exec(query, subjectId, codeHash))
+}
+
+func (r *MFARepository) DeleteFactor(subject entity.ActorType, subjectId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", mfaFactorsTable, subjectColumn(subject))
+	_, err := r.db.Exec(query, subjectId)
+	return err
+}
+
+func (r *MFARepository) GetPolicies() ([]*entity.MFAPolicy, error) {
+	policies := make([]*entity.MFAPolicy, 0)
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY role", mfaPoliciesTable)
+	if err := r.db.Select(&policies, query); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *MFARepository) IsRequired(role entity.Role) (bool, error) {
+	var required bool
+	query := fmt.Sprintf("SELECT required FROM %s WHERE role = $1", mfaPoliciesTable)
+	err := r.db.Get(&required, query, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return required, err
+}
+
+func (r *MFARepository) SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var before entity.MFAPolicy
+	query := fmt.Sprintf("SELECT * FROM %s WHERE role = $1%s", mfaPoliciesTable, r.dialect.ForUpdate())
+	if err = tx.Get(&before, query, policy.Role); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("no policy for provided role")
+		}
+		return err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET required = $2, updated_at = NOW() WHERE role = $1", mfaPoliciesTable)
+	if _, err = tx.Exec(query, policy.Role, policy.Required); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = writeAudit(tx, actor, entity.AuditMFAPolicy, entity.AuditTargetMFAPolicy, 0,
+		mfaPolicyAuditState(&before), mfaPolicyAuditState(policy))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func subjectColumn(subject entity.ActorType) string {
+	if subject == entity.ActorAdmin {
+		return "admin_id"
+	}
+	return "user_id"
+}
+
+func affected(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,197 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ScheduleRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+
+	slotColumns      string
+	exceptionColumns string
+}
+
+func NewScheduleRepository(db *dbtx.DB, dialect Dialect) *ScheduleRepository {
+	return &ScheduleRepository{
+		db:      db,
+		dialect: dialect,
+		slotColumns: fmt.Sprintf("id, trainer_id, weekday, %s AS start_time, %s AS end_time, time_zone",
+			dialect.TimeOfDay("start_time"), dialect.TimeOfDay("end_time")),
+		exceptionColumns: fmt.Sprintf("id, trainer_id, %s AS date, %s AS start_time, %s AS end_time, available, "+
+			"time_zone", dialect.Date("date"), dialect.TimeOfDay("start_time"), dialect.TimeOfDay("end_time")),
+	}
+}
+
+func (r *ScheduleRepository) CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error) {
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (trainer_id, weekday, start_time, end_time, time_zone) "+
+		"values ($1, $2, $3, $4, $5) RETURNING id", availabilitySlotsTable)
+	row := r.db.QueryRow(query, slot.TrainerId, slot.Weekday, r.dialect.TimeOfDayArg(slot.StartTime),
+		r.dialect.TimeOfDayArg(slot.EndTime), slot.TimeZone)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *ScheduleRepository) GetAvailabilitySlots(trainerId int64) ([]*entity.AvailabilitySlot, error) {
+	slots := make([]*entity.AvailabilitySlot, 0)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE trainer_id = $1 ORDER BY weekday, start_time",
+		r.slotColumns, availabilitySlotsTable)
+	err := r.db.Select(&slots, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (r *ScheduleRepository) DeleteAvailabilitySlot(trainerId, slotId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE trainer_id = $1 AND id = $2", availabilitySlotsTable)
+	res, err := r.db.Exec(query, trainerId, slotId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no slot to delete")
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) CreateAvailabilityException(e *entity.AvailabilityException) (int64, error) {
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (trainer_id, date, start_time, end_time, available, time_zone) "+
+		"values ($1, $2, $3, $4, $5, $6) RETURNING id", availabilityExceptionsTable)
+	row := r.db.QueryRow(query, e.TrainerId, e.Date, r.dialect.TimeOfDayArg(e.StartTime),
+		r.dialect.TimeOfDayArg(e.EndTime), e.Available, e.TimeZone)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *ScheduleRepository) GetAvailabilityExceptions(trainerId int64, from, to time.Time) (
+	[]*entity.AvailabilityException, error) {
+	exceptions := make([]*entity.AvailabilityException, 0)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE trainer_id = $1 AND date BETWEEN $2 AND $3 "+
+		"ORDER BY date, start_time", r.exceptionColumns, availabilityExceptionsTable)
+	err := r.db.Select(&exceptions, query, trainerId, r.dialect.DateArg(from), r.dialect.DateArg(to))
+	if err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+func (r *ScheduleRepository) DeleteAvailabilityException(trainerId, exceptionId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE trainer_id = $1 AND id = $2", availabilityExceptionsTable)
+	res, err := r.db.Exec(query, trainerId, exceptionId)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no exception to delete")
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error) {
+	bookings := make([]*entity.Booking, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND status = $2 "+
+		"AND starts_at < $4 AND ends_at > $3 ORDER BY starts_at", bookingsTable)
+	err := r.db.Select(&bookings, query, trainerId, entity.BookingStatusBooked, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *ScheduleRepository) GetUserBookings(userId int64) ([]*entity.Booking, error) {
+	bookings := make([]*entity.Booking, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE user_id = $1 ORDER BY starts_at DESC", bookingsTable)
+	err := r.db.Select(&bookings, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *ScheduleRepository) GetBookingById(bookingId int64) (*entity.Booking, error) {
+	var booking entity.Booking
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", bookingsTable)
+	err := r.db.Get(&booking, query, bookingId)
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (r *ScheduleRepository) CreateBooking(booking *entity.Booking, workout *entity.Workout) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var status entity.Status
+	query := fmt.Sprintf("SELECT status FROM %s WHERE trainer_id = $1 AND user_id = $2 %s",
+		partnershipsTable, currentPartnership)
+	err = tx.Get(&status, query, booking.TrainerId, booking.UserId)
+	if err != nil || status != entity.StatusApproved {
+		_ = tx.Rollback()
+		return -1, errors.New("no approved partnership with trainer")
+	}
+
+	var workoutId int64
+	query = fmt.Sprintf("INSERT INTO %s (title, trainer_id, user_id, description, date) values "+
+		"($1, $2, $3, $4, $5) RETURNING id", workoutsTable)
+	row := tx.QueryRow(query, workout.Title, workout.TrainerId, workout.UserId, workout.Description, workout.Date)
+	if err = row.Scan(&workoutId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	workout.Id = workoutId
+
+	var id int64
+	query = fmt.Sprintf("INSERT INTO %s (trainer_id, user_id, workout_id, starts_at, ends_at, status) values "+
+		"($1, $2, $3, $4, $5, $6) RETURNING id", bookingsTable)
+	row = tx.QueryRow(query, booking.TrainerId, booking.UserId, workoutId,
+		booking.StartsAt, booking.EndsAt, entity.BookingStatusBooked)
+	if err = row.Scan(&id); err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsOverlap(err) {
+			return -1, errors.New("slot is already booked")
+		}
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *ScheduleRepository) CancelBooking(bookingId, cancelledBy int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var workoutId *int64
+	query := fmt.Sprintf("UPDATE %s SET status = $1, cancelled_at = NOW(), cancelled_by = $2 "+
+		"WHERE id = $3 AND status = $4 RETURNING workout_id", bookingsTable)
+	row := tx.QueryRow(query, entity.BookingStatusCancelled, cancelledBy, bookingId, entity.BookingStatusBooked)
+	if err = row.Scan(&workoutId); err != nil {
+		_ = tx.Rollback()
+		return errors.New("no booking to cancel")
+	}
+
+	if workoutId != nil {
+		query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", workoutsTable)
+		if _, err = tx.Exec(query, *workoutId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,73 @@
+// Package sqlrepo implements repositories shared by SQL backends, the SQL that differs
+// between database engines is taken from Dialect of the backend.
+package sqlrepo
+
+import "time"
+
+const (
+	adminTable        = "admins"
+	userTable         = "users"
+	workoutsTable     = "workouts"
+	partnershipsTable = "partnerships"
+
+	availabilitySlotsTable      = "availability_slots"
+	availabilityExceptionsTable = "availability_exceptions"
+	bookingsTable               = "bookings"
+
+	messagesTable = "messages"
+
+	workoutCommentsTable = "workout_comments"
+
+	clientGroupsTable             = "client_groups"
+	clientGroupMembersTable       = "client_group_members"
+	groupWorkoutsTable            = "group_workouts"
+	groupWorkoutParticipantsTable = "group_workout_participants"
+
+	auditLogTable = "audit_log"
+
+	dataExportsTable = "data_exports"
+
+	userTokensTable = "user_tokens"
+
+	trainerApplicationsTable = "trainer_applications"
+	certificatesTable        = "trainer_application_certificates"
+
+	signInAttemptsTable  = "sign_in_attempts"
+	idempotencyKeysTable = "idempotency_keys"
+
+	mfaFactorsTable       = "mfa_factors"
+	mfaRecoveryCodesTable = "mfa_recovery_codes"
+	mfaPoliciesTable      = "mfa_policies"
+
+	externalIdentitiesTable = "external_identities"
+
+	unreadMessagesQuery = "(SELECT COUNT(*) FROM " + messagesTable + " m " +
+		"WHERE m.partnership_id = " + partnershipsTable + ".id AND m.sender_id <> $1 AND m.read_at IS NULL)"
+
+	// currentPartnership picks the open partnership of pair, ended ones are kept as history
+	currentPartnership = "ORDER BY status IN ('request', 'approved') DESC, id DESC LIMIT 1"
+)
+
+// Dialect is the part of SQL and driver errors that differs between database engines.
+type Dialect interface {
+	// ForUpdate is appended to SELECT to lock selected rows until the end of transaction.
+	ForUpdate() string
+	// TimeOfDay formats time column as HH:MM.
+	TimeOfDay(column string) string
+	// Date formats date column as YYYY-MM-DD.
+	Date(column string) string
+	// TimeOfDayArg converts HH:MM or HH:MM:SS to value stored in time column.
+	TimeOfDayArg(value string) interface{}
+	// DateArg converts t to value compared with date column.
+	DateArg(t time.Time) interface{}
+	// InIds reports whether column is one of ids passed in placeholder.
+	InIds(column, placeholder string) string
+	// IdsArg converts ids to value of InIds placeholder.
+	IdsArg(ids []int64) (interface{}, error)
+	// IsUniqueViolation reports whether err is violation of unique constraint, index or primary key.
+	IsUniqueViolation(err error) bool
+	// IsUniqueViolationOn reports whether err is violation of unique constraint on column of table.
+	IsUniqueViolationOn(err error, table, column string) bool
+	// IsOverlap reports whether err is raised for booking that overlaps another one.
+	IsOverlap(err error) bool
+}
@@ -0,0 +1,1046 @@
+package sqlrepo
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository/dbtx"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type UserRepository struct {
+	db      *dbtx.DB
+	dialect Dialect
+}
+
+func NewUserRepository(db *dbtx.DB, dialect Dialect) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect}
+}
+
+func (r *UserRepository) Authorize(email, passwordHash string, role entity.Role) (int64, error) {
+	var user entity.User
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE email = $1 AND password_hash = $2 AND role = $3 "+
+		"AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, email, passwordHash, role)
+	return user.Id, err
+}
+
+func (r *UserRepository) IsTrainer(userId int64) bool {
+	var user entity.User
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, userId)
+	if err != nil {
+		return false
+	}
+	return user.Role == entity.TrainerRole
+}
+
+func (r *UserRepository) IsUser(id int64) bool {
+	var user entity.User
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, id)
+	return err == nil
+}
+
+func (r *UserRepository) CreateUser(actor *entity.Actor, user *entity.User, role entity.Role) (int64, error) {
+	var id int64
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (email, password_hash, role, name, surname, verified_at)"+
+		" values ($1, $2, '%s', $3, $4, $5) RETURNING id",
+		userTable, role)
+	row := tx.QueryRow(query, user.Email, user.PasswordHash, user.Name, user.Surname, user.VerifiedAt)
+
+	logrus.Debugf("creating user query: %s\nargs: %s, %s, %s, %s",
+		query, user.Email, user.PasswordHash, user.Name, user.Surname)
+
+	if err = row.Scan(&id); err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsUniqueViolation(err) {
+			return -1, errors.New("email has already reserved")
+		}
+		return 0, err
+	}
+
+	after := userAuditState(user)
+	after["role"] = string(role)
+	if err = writeAudit(tx, actor, entity.AuditUserCreate, entity.AuditTargetUser, id, nil, after); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *UserRepository) GetUserInfoById(id int64) (*entity.User, error) {
+	var user entity.User
+	query := fmt.Sprintf("SELECT id, email, password_hash, name, surname, role, created_at, time_zone, version "+
+		"FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, id)
+	return &user, err
+}
+
+func (r *UserRepository) GetUserByEmail(email string) (*entity.User, error) {
+	var user entity.User
+	query := fmt.Sprintf("SELECT id, email, name, surname, role, verified_at "+
+		"FROM %s WHERE email = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, email)
+	return &user, err
+}
+
+func (r *UserRepository) IsVerified(userId int64) (bool, error) {
+	var verified bool
+	query := fmt.Sprintf("SELECT verified_at IS NOT NULL FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&verified, query, userId)
+	return verified, err
+}
+
+func (r *UserRepository) GetTimeZone(userId int64) (string, error) {
+	var timeZone string
+	query := fmt.Sprintf("SELECT time_zone FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&timeZone, query, userId)
+	return timeZone, err
+}
+
+func (r *UserRepository) GetTokenVersion(userId int64) (int64, error) {
+	var version int64
+	query := fmt.Sprintf("SELECT token_version FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&version, query, userId)
+	return version, err
+}
+
+// UpdateProfile updates personal info of user, changed email has to be verified again.
+func (r *UserRepository) UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) error {
+	args := []interface{}{update.Email, update.Name, update.Surname, userId, update.TimeZone}
+	query := fmt.Sprintf("UPDATE %s SET email = $1, name = $2, surname = $3, time_zone = $5, version = version + 1, "+
+		"verified_at = CASE WHEN email = $1 THEN verified_at END WHERE id = $4 AND deleted_at IS NULL", userTable)
+	if version != 0 {
+		args = append(args, version)
+		query += " AND version = $6"
+	}
+	return r.writeProfile(userId, &update.Email, version, query, args)
+}
+
+// PatchProfile writes columns set in patch, changed email has to be verified again.
+func (r *UserRepository) PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) error {
+	columns, args := patch.Changes()
+	set := setClause(columns)
+	for i, column := range columns {
+		if column == "email" {
+			set += fmt.Sprintf(", verified_at = CASE WHEN email = $%d THEN verified_at END", i+1)
+		}
+	}
+
+	args = append(args, userId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND deleted_at IS NULL", userTable, set, len(args))
+	if version != 0 {
+		args = append(args, version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+	var email *string
+	if patch.Email.Set {
+		email = &patch.Email.Value
+	}
+	return r.writeProfile(userId, email, version, query, args)
+}
+
+// writeProfile runs profile update in transaction. When email is changed, verification tokens sent
+// to the old address are revoked, so they can't verify the new one.
+func (r *UserRepository) writeProfile(userId int64, email *string, version int64, query string,
+	args []interface{}) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var current string
+	if email != nil {
+		emailQuery := fmt.Sprintf("SELECT email FROM %s WHERE id = $1 AND deleted_at IS NULL%s",
+			userTable, r.dialect.ForUpdate())
+		if err = tx.Get(&current, emailQuery, userId); err != nil {
+			_ = tx.Rollback()
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.New("invalid userId")
+			}
+			return err
+		}
+	}
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsUniqueViolation(err) {
+			return errors.New("provided email has already been reserved")
+		}
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		_ = tx.Rollback()
+		if version != 0 {
+			return entity.ErrVersionMismatch
+		}
+		return errors.New("invalid userId")
+	}
+
+	if email != nil && *email != current {
+		revokeQuery := fmt.Sprintf("UPDATE %s SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 "+
+			"AND used_at IS NULL", userTokensTable)
+		if _, err = tx.Exec(revokeQuery, userId, entity.TokenVerifyEmail); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// setClause assigns placeholders from $1 to columns and bumps version of the row.
+func setClause(columns []string) string {
+	assignments := make([]string, 0, len(columns)+1)
+	for i, column := range columns {
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", column, i+1))
+	}
+	return strings.Join(append(assignments, "version = version + 1"), ", ")
+}
+
+// ChangePassword sets password and bumps token version, so tokens issued before are rejected.
+func (r *UserRepository) ChangePassword(userId int64, passwordHash string) (int64, error) {
+	var version int64
+	query := fmt.Sprintf("UPDATE %s SET password_hash = $2, token_version = token_version + 1 "+
+		"WHERE id = $1 AND deleted_at IS NULL RETURNING token_version", userTable)
+	err := r.db.Get(&version, query, userId, passwordHash)
+	return version, err
+}
+
+func (r *UserRepository) CreateWorkoutAsUser(workout *entity.Workout) (int64, error) {
+	if workout.TrainerId.Int64 > 0 && !r.IsTrainer(workout.TrainerId.Int64) {
+		return -1, errors.New("can't set common user as a trainer")
+	}
+
+	var id int64
+	addQuery := fmt.Sprintf("INSERT INTO %s (title, user_id, trainer_id, description, date) "+
+		"values ($1, $2, $3, $4, $5) RETURNING id", workoutsTable)
+	row := r.db.QueryRow(addQuery, workout.Title, workout.UserId, workout.TrainerId, workout.Description, workout.Date)
+	if err := row.Scan(&id); err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (r *UserRepository) CheckAccessToWorkout(workoutId, userId int64) error {
+	return checkAccessToWorkout(r.db, workoutId, userId)
+}
+
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkAccessToWorkout is run in transaction of the operation it guards.
+func checkAccessToWorkout(q rowQueryer, workoutId, userId int64) error {
+	var inputId struct {
+		user    int64         `db:"user_id"`
+		trainer sql.NullInt64 `db:"trainer_id"`
+	}
+	query := fmt.Sprintf("SELECT user_id, trainer_id FROM %s WHERE id = $1", workoutsTable)
+	row := q.QueryRow(query, workoutId)
+	if err := row.Scan(&inputId.user, &inputId.trainer); err != nil {
+		return err
+	}
+	if inputId.user != userId && (!inputId.trainer.Valid || inputId.trainer.Int64 != userId) {
+		return entity.ErrNoWorkoutAccess
+	}
+	return nil
+}
+
+// GetUserWorkouts returns workouts of user which start in [from, to), zero bound is not applied.
+func (r *UserRepository) GetUserWorkouts(userid int64, from, to time.Time) ([]*entity.Workout, error) {
+	args := []interface{}{userid}
+	where := "user_id = $1"
+	if !from.IsZero() {
+		args = append(args, from)
+		where += fmt.Sprintf(" AND date >= $%d", len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		where += fmt.Sprintf(" AND date < $%d", len(args))
+	}
+
+	workouts := make([]*entity.Workout, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY date DESC", workoutsTable, where)
+	err := r.db.Select(&workouts, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *UserRepository) GetWorkoutById(workoutId, userId int64) (*entity.Workout, error) {
+	err := r.CheckAccessToWorkout(workoutId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var workout entity.Workout
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", workoutsTable)
+	err = r.db.Get(&workout, query, workoutId)
+	if err != nil {
+		return nil, err
+	}
+	return &workout, nil
+}
+
+func (r *UserRepository) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	if err = checkAccessToWorkout(tx, workoutId, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	args := []interface{}{update.Title, update.Description, update.Date, workoutId}
+	querySample := "UPDATE %s SET %s = $1, %s = $2, %s = $3, version = version + 1 WHERE id = $4"
+	query := fmt.Sprintf(querySample, workoutsTable, "title", "description", "date")
+	if version != 0 {
+		args = append(args, version)
+		query += " AND version = $5"
+	}
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows != 1 && version != 0 {
+		_ = tx.Rollback()
+		return entity.ErrVersionMismatch
+	}
+	return tx.Commit()
+}
+
+// PatchWorkout writes columns set in patch and returns the new version of workout.
+func (r *UserRepository) PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch,
+	version int64) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	if err = checkAccessToWorkout(tx, workoutId, userId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	columns, args := patch.Changes()
+	args = append(args, workoutId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", workoutsTable, setClause(columns), len(args))
+	if version != 0 {
+		args = append(args, version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	var newVersion int64
+	if err = tx.Get(&newVersion, query+" RETURNING version", args...); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) && version != 0 {
+			return 0, entity.ErrVersionMismatch
+		}
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+func (r *UserRepository) DeleteWorkout(workoutId, userId, version int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	if err = checkAccessToWorkout(tx, workoutId, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	args := []interface{}{workoutId}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", workoutsTable)
+	if version != 0 {
+		args = append(args, version)
+		query += " AND version = $2"
+	}
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows != 1 && version != 0 {
+		_ = tx.Rollback()
+		return entity.ErrVersionMismatch
+	}
+	return tx.Commit()
+}
+
+func (r *UserRepository) GetTrainers() ([]*entity.User, error) {
+	trainers := make([]*entity.User, 0)
+	query := fmt.Sprintf("SELECT id, email, name, surname FROM %s WHERE role = $1 AND deleted_at IS NULL "+
+		"ORDER BY surname", userTable)
+	err := r.db.Select(&trainers, query, entity.TrainerRole)
+	if err != nil {
+		return nil, err
+	}
+	return trainers, nil
+}
+
+func (r *UserRepository) GetTrainerById(id int64) (*entity.User, error) {
+	var trainer entity.User
+	query := fmt.Sprintf("SELECT id, email, name, surname FROM %s WHERE role = 'trainer' AND id = $1 "+
+		"AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&trainer, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return &trainer, nil
+}
+
+func (r *UserRepository) GetUserPartnerships(userId int64) ([]*entity.Partnership, error) {
+	partnerships := make([]*entity.Partnership, 0)
+	query := fmt.Sprintf("SELECT *, %s AS unread_messages FROM %s WHERE user_id = $1 ORDER BY created_at DESC",
+		unreadMessagesQuery, partnershipsTable)
+	err := r.db.Select(&partnerships, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return partnerships, nil
+}
+
+func (r *UserRepository) GetPartnershipById(partnershipId int64) (*entity.Partnership, error) {
+	var p entity.Partnership
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", partnershipsTable)
+	err := r.db.Get(&p, query, partnershipId)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *UserRepository) GetPartnership(trainerId, userId int64) (*entity.Partnership, error) {
+	var p entity.Partnership
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND user_id = $2 %s",
+		partnershipsTable, currentPartnership)
+	err := r.db.Get(&p, query, trainerId, userId)
+	return &p, err
+}
+
+// SendRequestToTrainer reopens ended partnership or opens a new one. Open partnership of the pair is kept
+// unique by partnerships_open_idx, so request which is already open is returned instead of a new one.
+func (r *UserRepository) SendRequestToTrainer(trainerId, userId int64) (int64, error) {
+	if !r.IsTrainer(trainerId) {
+		return -1, errors.New("can't send request not to trainer")
+	}
+
+	request, approved := "'"+entity.StatusRequest+"'", "'"+entity.StatusApproved+"'"
+	endedByTrainer, endedByUser := "'"+entity.StatusEndedByTrainer+"'", "'"+entity.StatusEndedByUser+"'"
+
+	var id int64
+	query := fmt.Sprintf("UPDATE %[1]s SET status = %[2]s WHERE id = (SELECT id FROM %[1]s "+
+		"WHERE trainer_id = $1 AND user_id = $2 ORDER BY id DESC LIMIT 1) AND status IN (%[3]s, %[4]s) RETURNING id",
+		partnershipsTable, request, endedByTrainer, endedByUser)
+	err := r.db.Get(&id, query, trainerId, userId)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return r.partnershipConflict(err)
+	}
+
+	var p entity.Partnership
+	query = fmt.Sprintf("INSERT INTO %[1]s (trainer_id, user_id, status) values ($1, $2, %[2]s) "+
+		"ON CONFLICT (trainer_id, user_id) WHERE status IN (%[2]s, %[3]s) DO UPDATE SET status = %[1]s.status "+
+		"RETURNING id, status", partnershipsTable, request, approved)
+	if err = r.db.Get(&p, query, trainerId, userId); err != nil {
+		return r.partnershipConflict(err)
+	}
+	if p.Status == entity.StatusApproved {
+		return -1, errors.New("there is already approved partnership with trainer")
+	}
+	return p.Id, nil
+}
+
+// partnershipConflict reports partnership which was opened for the same pair concurrently.
+func (r *UserRepository) partnershipConflict(err error) (int64, error) {
+	if r.dialect.IsUniqueViolation(err) {
+		return -1, errors.New("there is already open partnership with provided user")
+	}
+	return 0, err
+}
+
+func (r *UserRepository) EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	p, err := r.GetPartnership(trainerId, userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return -1, err
+		} else {
+			return 0, err
+		}
+	}
+	if !hasApprovedPartnership(p) {
+		return -1, errors.New("no approved partnership to end")
+	}
+
+	if err = r.endPartnership(actor, p, entity.StatusEndedByUser); err != nil {
+		return 0, err
+	}
+	return p.Id, nil
+}
+
+func (r *UserRepository) GetTrainerPartnerships(userId int64) ([]*entity.Partnership, error) {
+	partnerships := make([]*entity.Partnership, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 ORDER BY created_at DESC", partnershipsTable)
+	err := r.db.Select(&partnerships, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return partnerships, nil
+}
+
+func (r *UserRepository) GetTrainerUsers(trainerId int64) ([]*entity.User, error) {
+	if !r.IsTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+
+	users := make([]*entity.User, 0)
+
+	query := fmt.Sprintf("SELECT %s.id, email, name, surname, %s.created_at, %s AS unread_messages "+
+		"FROM %s "+
+		"JOIN %s "+
+		"ON %s.id = %s.user_id "+
+		"WHERE %s.trainer_id =$1 "+
+		"AND status = %s "+
+		"AND deleted_at IS NULL "+
+		"ORDER BY surname;",
+		userTable, partnershipsTable, unreadMessagesQuery,
+		userTable, partnershipsTable, userTable,
+		partnershipsTable, partnershipsTable,
+		"'"+entity.StatusApproved+"'")
+	err := r.db.Select(&users, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) GetTrainerRequests(trainerId int64) ([]*entity.Request, error) {
+	if !r.IsTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+
+	requests := make([]*entity.Request, 0)
+
+	query := fmt.Sprintf("SELECT %s.id AS user_id, %s.id AS request_id, email, name, surname, %s.created_at AS send_at "+
+		"FROM %s "+
+		"JOIN %s "+
+		"ON %s.id = %s.user_id "+
+		"WHERE %s.trainer_id =$1 "+
+		"AND status = %s "+
+		"AND deleted_at IS NULL "+
+		"ORDER BY send_at DESC;",
+		userTable, partnershipsTable, partnershipsTable,
+		userTable, partnershipsTable, userTable,
+		partnershipsTable, partnershipsTable,
+		"'"+entity.StatusRequest+"'")
+	err := r.db.Select(&requests, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *UserRepository) GetTrainerUserById(trainerId, userId int64) (*entity.User, error) {
+	if !r.IsTrainer(trainerId) {
+		return nil, errors.New("not a trainer was provided")
+	}
+
+	p, _ := r.GetPartnership(trainerId, userId)
+	if !hasApprovedPartnership(p) {
+		return nil, errors.New("approved partnership with user was not found")
+	}
+
+	var user entity.User
+	query := fmt.Sprintf("SELECT id, email, name, surname FROM %s WHERE id = $1 AND deleted_at IS NULL", userTable)
+	err := r.db.Get(&user, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) GetTrainerRequestById(trainerId, requestId int64) (*entity.Request, error) {
+	var p entity.Partnership
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1", partnershipsTable)
+	err := r.db.Get(&p, query, requestId)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRequestOnPartnership(&p) {
+		return nil, errors.New("no request for you on that id")
+	}
+	if p.TrainerId != trainerId {
+		return nil, errors.New("no access to request")
+	}
+
+	var req entity.Request
+	query = fmt.Sprintf("SELECT %s.id AS user_id, %s.id AS request_id, email, name, surname, %s.created_at AS send_at "+
+		"FROM %s "+
+		"JOIN %s "+
+		"ON %s.id = %s.user_id "+
+		"WHERE %s.id = $1;",
+		userTable, partnershipsTable, partnershipsTable,
+		userTable, partnershipsTable, userTable,
+		partnershipsTable, partnershipsTable)
+	err = r.db.Get(&req, query, requestId)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// InitPartnershipWithUser approves partnership, request of user is approved and partnership ended
+// by trainer is resumed. Open partnership of the pair is kept unique by partnerships_open_idx.
+func (r *UserRepository) InitPartnershipWithUser(trainerId, userId int64) (int64, error) {
+	if !r.IsUser(userId) {
+		return -1, errors.New("invalid userId")
+	}
+
+	request, approved := "'"+entity.StatusRequest+"'", "'"+entity.StatusApproved+"'"
+	endedByTrainer, endedByUser := "'"+entity.StatusEndedByTrainer+"'", "'"+entity.StatusEndedByUser+"'"
+
+	var id int64
+	query := fmt.Sprintf("UPDATE %[1]s SET status = %[2]s, ended_at = null WHERE id = (SELECT id FROM %[1]s "+
+		"WHERE trainer_id = $1 AND user_id = $2 ORDER BY id DESC LIMIT 1) AND status = %[3]s RETURNING id",
+		partnershipsTable, approved, endedByTrainer)
+	err := r.db.Get(&id, query, trainerId, userId)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return r.partnershipConflict(err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %[1]s (trainer_id, user_id, status) SELECT $1, $2, %[2]s "+
+		"WHERE NOT EXISTS (SELECT 1 FROM %[1]s WHERE trainer_id = $1 AND user_id = $2 AND status = %[4]s) "+
+		"ON CONFLICT (trainer_id, user_id) WHERE status IN (%[3]s, %[2]s) DO UPDATE SET status = %[2]s, "+
+		"ended_at = null RETURNING id", partnershipsTable, approved, request, endedByUser)
+	err = r.db.Get(&id, query, trainerId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		p, err := r.GetPartnership(trainerId, userId)
+		if err != nil {
+			return 0, err
+		}
+		return p.Id, errors.New("partnership was ended by user, it can be resumed only by request from user")
+	}
+	if err != nil {
+		return r.partnershipConflict(err)
+	}
+	return id, nil
+}
+
+func (r *UserRepository) EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	p, err := r.GetPartnership(trainerId, userId)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return -1, err
+		} else {
+			return 0, err
+		}
+	}
+	if !hasApprovedPartnership(p) {
+		return -1, errors.New("no approved partnership to end")
+	}
+
+	if err = r.endPartnership(actor, p, entity.StatusEndedByTrainer); err != nil {
+		return 0, err
+	}
+	return p.Id, nil
+}
+
+func (r *UserRepository) AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var p entity.Partnership
+	query := fmt.Sprintf("UPDATE %s SET status = %s WHERE status = %s AND trainer_id = $1 AND id = $2 "+
+		"RETURNING id, user_id, trainer_id, status",
+		partnershipsTable, "'"+entity.StatusApproved+"'", "'"+entity.StatusRequest+"'")
+	if err = tx.Get(&p, query, trainerId, requestId); err != nil {
+		_ = tx.Rollback()
+		return -1, errors.New("no request to accept")
+	}
+
+	before := partnershipAuditState(&p)
+	before["status"] = string(entity.StatusRequest)
+	err = writeAudit(tx, actor, entity.AuditRequestAccept, entity.AuditTargetPartnership, p.Id,
+		before, partnershipAuditState(&p))
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return p.Id, tx.Commit()
+}
+
+// DenyRequest ends the request instead of deleting it, request reopens ended partnership and messages
+// of the earlier one stay with it.
+func (r *UserRepository) DenyRequest(actor *entity.Actor, trainerId, requestId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = $3, ended_at = NOW() WHERE trainer_id = $1 AND id = $2 "+
+		"AND status = %s", partnershipsTable, "'"+entity.StatusRequest+"'")
+	res, err := tx.Exec(query, trainerId, requestId, entity.StatusEndedByTrainer)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		_ = tx.Rollback()
+		return errors.New("no request to deny")
+	}
+
+	err = writeAudit(tx, actor, entity.AuditRequestDeny, entity.AuditTargetPartnership, requestId,
+		map[string]interface{}{"status": string(entity.StatusRequest)},
+		map[string]interface{}{"status": string(entity.StatusEndedByTrainer)})
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *UserRepository) endPartnership(actor *entity.Actor, p *entity.Partnership, status entity.Status) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = %s, ended_at = NOW() WHERE id = $1",
+		partnershipsTable, "'"+status+"'")
+	if _, err = tx.Exec(query, p.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	after := partnershipAuditState(p)
+	after["status"] = string(status)
+	err = writeAudit(tx, actor, entity.AuditPartnershipEnd, entity.AuditTargetPartnership, p.Id,
+		partnershipAuditState(p), after)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *UserRepository) CreateWorkoutAsTrainer(workout *entity.Workout) (int64, error) {
+	p, err := r.GetPartnership(workout.TrainerId.Int64, workout.UserId)
+	if err != nil || !hasApprovedPartnership(p) {
+		return -1, entity.ErrNoWorkoutRights
+	}
+
+	var id int64
+	query := fmt.Sprintf("INSERT INTO %s (title, trainer_id, user_id, description, date) values "+
+		"($1, $2, $3 ,$4, $5) RETURNING id", workoutsTable)
+	row := r.db.QueryRow(query, workout.Title, workout.TrainerId, workout.UserId, workout.Description, workout.Date)
+	if err = row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *UserRepository) GetTrainerWorkouts(trainerId int64) ([]*entity.Workout, error) {
+	workouts := make([]*entity.Workout, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 ORDER BY date DESC", workoutsTable)
+	err := r.db.Select(&workouts, query, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *UserRepository) GetTrainerWorkoutsWithUser(trainerId, userId int64) ([]*entity.Workout, error) {
+	workouts := make([]*entity.Workout, 0)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE trainer_id = $1 AND user_id = $2 ORDER BY date DESC",
+		workoutsTable)
+	err := r.db.Select(&workouts, query, trainerId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *UserRepository) GetUsersId(role entity.Role) ([]int64, error) {
+	idSlice := make([]int64, 0)
+	query := fmt.Sprintf("SELECT id FROM %s WHERE role = '%s' AND deleted_at IS NULL", userTable, role)
+	err := r.db.Select(&idSlice, query)
+	if err != nil {
+		return nil, err
+	}
+	return idSlice, nil
+}
+
+func (r *UserRepository) GetUserFullInfoById(userId int64) (*entity.UserInfo, error) {
+	user, err := r.GetUserInfoById(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var userInfo entity.UserInfo
+	userInfo.Id = user.Id
+	userInfo.Email = user.Email
+	userInfo.Role = user.Role
+	userInfo.Name = user.Name
+	userInfo.Surname = user.Surname
+	userInfo.CreatedAt = user.CreatedAt
+
+	switch user.Role {
+	case entity.UserRole:
+		partnerships, err := r.GetUserPartnerships(userId)
+		if err != nil {
+			return nil, err
+		}
+		userInfo.Partnerships = partnerships
+		workouts, err := r.GetUserWorkouts(userId, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		userInfo.Workouts = workouts
+	case entity.TrainerRole:
+		partnerships, err := r.GetTrainerPartnerships(userId)
+		if err != nil {
+			return nil, err
+		}
+		userInfo.Partnerships = partnerships
+		workouts, err := r.GetTrainerWorkouts(userId)
+		if err != nil {
+			return nil, err
+		}
+		userInfo.Workouts = workouts
+	default:
+		return nil, errors.New("undefined user role")
+	}
+	return &userInfo, nil
+}
+
+func (r *UserRepository) UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error {
+	user, err := r.GetUserInfoById(userId)
+	if err != nil {
+		return errors.New("invalid userId")
+	}
+	if update.Role != user.Role {
+		return errors.New("role can be changed only by approving trainer application")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET email = $1, password_hash = $2, role = $3, version = version + 1, "+
+		"name = $4, surname = $5 WHERE id = $6",
+		userTable)
+	_, err = tx.Exec(query, update.Email, update.Password, update.Role, update.Name, update.Surname, userId)
+	if err != nil {
+		_ = tx.Rollback()
+		if r.dialect.IsUniqueViolation(err) {
+			return errors.New("provided email has already been reserved")
+		}
+		return err
+	}
+
+	after := userAuditState(&entity.User{Email: update.Email, Role: update.Role, Name: update.Name,
+		Surname: update.Surname})
+	if update.Password != user.PasswordHash {
+		after["password"] = "changed"
+	}
+	err = writeAudit(tx, actor, entity.AuditUserUpdate, entity.AuditTargetUser, userId, userAuditState(user), after)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteUser marks user as deleted, the account is removed physically by PurgeDeletedUsers
+// after retention period. Active partnerships are ended, trainer is also detached from workouts.
+func (r *UserRepository) DeleteUser(actor *entity.Actor, userId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var user entity.User
+	query := fmt.Sprintf("SELECT id, email, password_hash, name, surname, role, created_at, time_zone "+
+		"FROM %s WHERE id = $1 AND deleted_at IS NULL%s", userTable, r.dialect.ForUpdate())
+	if err = tx.Get(&user, query, userId); err != nil {
+		_ = tx.Rollback()
+		return errors.New("no user to delete")
+	}
+
+	column, status := "user_id", entity.StatusEndedByUser
+	if user.Role == entity.TrainerRole {
+		column, status = "trainer_id", entity.StatusEndedByTrainer
+	}
+	query = fmt.Sprintf("UPDATE %s SET status = $2, ended_at = NOW() WHERE %s = $1 AND status IN (%s, %s)",
+		partnershipsTable, column, "'"+entity.StatusApproved+"'", "'"+entity.StatusRequest+"'")
+	_, err = tx.Exec(query, userId, status)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if user.Role == entity.TrainerRole {
+		query = fmt.Sprintf("UPDATE %s SET trainer_id = NULL, version = version + 1 WHERE trainer_id = $1", workoutsTable)
+		_, err = tx.Exec(query, userId)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	query = fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE id = $1", userTable)
+	_, err = tx.Exec(query, userId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	err = writeAudit(tx, actor, entity.AuditUserDelete, entity.AuditTargetUser, userId, userAuditState(&user), nil)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *UserRepository) RestoreUser(actor *entity.Actor, userId int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var user entity.User
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL "+
+		"AND anonymized_at IS NULL RETURNING id, email, name, surname, role", userTable)
+	if err = tx.Get(&user, query, userId); err != nil {
+		_ = tx.Rollback()
+		return errors.New("no deleted user to restore")
+	}
+	err = writeAudit(tx, actor, entity.AuditUserRestore, entity.AuditTargetUser, userId, nil, userAuditState(&user))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeDeletedUsers physically removes users soft-deleted before provided time,
+// dependent rows are removed by foreign keys. Anonymized accounts are kept for trainer history.
+func (r *UserRepository) PurgeDeletedUsers(deletedBefore time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < $1 "+
+		"AND anonymized_at IS NULL", userTable)
+	res, err := r.db.Exec(query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *UserRepository) ScheduleDeletion(userId int64, at time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET deletion_scheduled_at = $2 WHERE id = $1 AND deleted_at IS NULL", userTable)
+	res, err := r.db.Exec(query, userId, at)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows != 1 {
+		return errors.New("no user to delete")
+	}
+	return nil
+}
+
+// CancelDeletion drops scheduled self-deletion, it reports whether deletion was scheduled.
+func (r *UserRepository) CancelDeletion(userId int64) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET deletion_scheduled_at = NULL "+
+		"WHERE id = $1 AND deletion_scheduled_at IS NOT NULL", userTable)
+	res, err := r.db.Exec(query, userId)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// AnonymizeScheduledUsers scrubs personal data of users whose deletion is due and frees their emails.
+// Accounts stay soft-deleted so that workouts are kept for trainer history.
+func (r *UserRepository) AnonymizeScheduledUsers(dueBefore time.Time) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, 0)
+	query := fmt.Sprintf("UPDATE %s SET email = 'deleted-' || id || '@anonymized.invalid', password_hash = '', "+
+		"name = 'Deleted', surname = 'User', deleted_at = NOW(), anonymized_at = NOW(), "+
+		"deletion_scheduled_at = NULL WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL RETURNING id",
+		userTable)
+	if err = tx.Select(&ids, query, dueBefore); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, tx.Rollback()
+	}
+
+	ended := []struct {
+		column string
+		status entity.Status
+	}{
+		{"user_id", entity.StatusEndedByUser},
+		{"trainer_id", entity.StatusEndedByTrainer},
+	}
+	idsArg, err := r.dialect.IdsArg(ids)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	for _, e := range ended {
+		query = fmt.Sprintf("UPDATE %s SET status = $2, ended_at = NOW() WHERE %s AND status IN (%s, %s)",
+			partnershipsTable, r.dialect.InIds(e.column, "$1"),
+			"'"+entity.StatusApproved+"'", "'"+entity.StatusRequest+"'")
+		if _, err = tx.Exec(query, idsArg, e.status); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+	return int64(len(ids)), tx.Commit()
+}
+
+func hasApprovedPartnership(p *entity.Partnership) bool {
+	if p == nil || p.Status != entity.StatusApproved {
+		return false
+	}
+	return true
+}
+
+func hasRequestOnPartnership(p *entity.Partnership) bool {
+	if p == nil || p.Status != entity.StatusRequest {
+		return false
+	}
+	return true
+}
@@ -2,10 +2,14 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"time"
 )
 
+type connCtxKey struct{}
+
 type Server struct {
 	httpServer *http.Server
 }
@@ -17,6 +21,9 @@ func (s *Server) Run(port string, handler http.Handler) error { //nolint
 		MaxHeaderBytes: 1 << 20,
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connCtxKey{}, c)
+		},
 	}
 	err := s.httpServer.ListenAndServe()
 	return err
@@ -25,3 +32,12 @@ func (s *Server) Run(port string, handler http.Handler) error { //nolint
 func (s *Server) ShutDown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
+
+// SetWriteDeadline overrides server write timeout for long-lived responses such as event streams.
+func SetWriteDeadline(ctx context.Context, t time.Time) error {
+	conn, ok := ctx.Value(connCtxKey{}).(net.Conn)
+	if !ok {
+		return errors.New("no connection in request context")
+	}
+	return conn.SetWriteDeadline(t)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/mail"
+	"Fitness_REST_API/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+var errInvalidAccountToken = errors.New("token is invalid or expired")
+
+type accountTokenClaims struct {
+	jwt.StandardClaims
+	UserId  int64               `json:"user_id"`
+	Purpose entity.TokenPurpose `json:"purpose"`
+}
+
+type AccountService struct {
+	repo            repository.Account
+	userRepo        repository.User
+	mailer          mail.Mailer
+	passwordHash    func(password string) string
+	appURL          string
+	verificationTTL time.Duration
+	resetTTL        time.Duration
+	signingKey      []byte
+}
+
+func NewAccountService(repo repository.Account, userRepo repository.User, mailer mail.Mailer,
+	passwordHash func(password string) string, appURL string, verificationTTL, resetTTL time.Duration,
+	signingKey string) *AccountService {
+	return &AccountService{repo: repo, userRepo: userRepo, mailer: mailer, passwordHash: passwordHash,
+		appURL: appURL, verificationTTL: verificationTTL, resetTTL: resetTTL, signingKey: []byte(signingKey)}
+}
+
+func (s *AccountService) SendVerification(userId int64) error {
+	user, err := s.userRepo.GetUserInfoById(userId)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.issueToken(userId, entity.TokenVerifyEmail, s.verificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf("Hello, %s!\n\nFollow the link to confirm your email:\n%s/auth/verify?token=%s\n\n"+
+			"The link is valid for %s.", user.Name, s.appURL, token, s.verificationTTL),
+	})
+}
+
+func (s *AccountService) VerifyEmail(token string) error {
+	claims, err := s.parseToken(token, entity.TokenVerifyEmail)
+	if err != nil {
+		return err
+	}
+	return s.repo.VerifyEmail(claims.Id, claims.UserId)
+}
+
+// ForgotPassword mails reset token if account with email exists, it never reports
+// whether it does to not disclose registered emails.
+func (s *AccountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		logrus.Infof("password reset is requested for unknown email")
+		return nil
+	}
+
+	token, err := s.issueToken(user.Id, entity.TokenResetPassword, s.resetTTL)
+	if err != nil {
+		return err
+	}
+	err = s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf("Hello, %s!\n\nUse the token to reset your password:\n%s\n\n"+
+			"The token is valid for %s. If you didn't request reset, ignore this message.", user.Name, token, s.resetTTL),
+	})
+	if err != nil {
+		logrus.Errorf("can't send password reset mail to user %d: %s", user.Id, err.Error())
+	}
+	return nil
+}
+
+func (s *AccountService) ResetPassword(token, password string) error {
+	claims, err := s.parseToken(token, entity.TokenResetPassword)
+	if err != nil {
+		return err
+	}
+	return s.repo.ResetPassword(claims.Id, claims.UserId, s.passwordHash(password))
+}
+
+func (s *AccountService) issueToken(userId int64, purpose entity.TokenPurpose, ttl time.Duration) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	record := &entity.AccountToken{
+		Id:        hex.EncodeToString(b),
+		UserId:    userId,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateToken(record); err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &accountTokenClaims{
+		jwt.StandardClaims{
+			Id:        record.Id,
+			ExpiresAt: record.ExpiresAt.Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		userId,
+		purpose,
+	})
+	return token.SignedString(s.signingKey)
+}
+
+func (s *AccountService) parseToken(token string, purpose entity.TokenPurpose) (*accountTokenClaims, error) {
+	t, err := jwt.ParseWithClaims(token, &accountTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.signingKey, nil
+	})
+	if err != nil {
+		return nil, errInvalidAccountToken
+	}
+
+	claims, ok := t.Claims.(*accountTokenClaims)
+	if !ok || claims.Purpose != purpose || claims.Id == "" {
+		return nil, errInvalidAccountToken
+	}
+	return claims, nil
+}
@@ -4,42 +4,95 @@ import (
 	"Fitness_REST_API/internal/entity"
 	"Fitness_REST_API/internal/repository"
 	"crypto/sha1"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"time"
 )
 
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
 type AdminService struct {
 	adminRepo  repository.Admin
 	userRepo   repository.User
+	auditRepo  repository.Audit
 	hashSalt   string
 	signingKey []byte
+	mfa        *MFAService
+}
+
+type adminTokenClaims struct {
+	jwt.StandardClaims
+	ID int64 `json:"id"`
 }
 
 func NewAdminService(
 	adminRepo repository.Admin,
 	userRepo repository.User,
+	auditRepo repository.Audit,
+	mfa *MFAService,
 	hashSalt string,
 	signingKey string) *AdminService {
-	return &AdminService{adminRepo: adminRepo, userRepo: userRepo, hashSalt: hashSalt, signingKey: []byte(signingKey)}
+	return &AdminService{adminRepo: adminRepo, userRepo: userRepo, auditRepo: auditRepo, mfa: mfa,
+		hashSalt: hashSalt, signingKey: []byte(signingKey)}
+}
+
+func (s *AdminService) SignIn(login, password string) (*entity.SignInResult, error) {
+	id, err := s.adminRepo.Authorize(login, s.getPasswordHash(password))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.mfa.challenge(entity.ActorAdmin, id, entity.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+	return s.newToken(id)
 }
 
-func (s *AdminService) SignIn(login, password string) (string, error) {
-	err := s.adminRepo.Authorize(login, s.getPasswordHash(password))
+// VerifyMFA finishes sign in which was challenged for two-factor code.
+func (s *AdminService) VerifyMFA(mfaToken, code string) (*entity.SignInResult, error) {
+	claims, recoveryCodes, err := s.mfa.verifyChallenge(entity.ActorAdmin, mfaToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.newToken(claims.ID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	result.RecoveryCodes = recoveryCodes
+	return result, nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(tokenTTL).Unix(),
-		IssuedAt:  time.Now().Unix(),
+func (s *AdminService) newToken(id int64) (*entity.SignInResult, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &adminTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		ID: id,
 	})
 
-	return token.SignedString(s.signingKey)
+	signed, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SignInResult{Token: signed}, nil
 }
 
-func (s *AdminService) ParseToken(token string) error {
-	t, err := jwt.Parse(token, func(token *jwt.Token) (i interface{}, err error) { //nolint
+func (s *AdminService) ParseToken(token string) (int64, error) {
+	t, err := jwt.ParseWithClaims(token, &adminTokenClaims{}, func(token *jwt.Token) (i interface{}, err error) { //nolint
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
@@ -47,15 +100,15 @@ func (s *AdminService) ParseToken(token string) error {
 	})
 
 	if err != nil {
-		return err
+		return -1, err
 	}
 
-	_, ok := t.Claims.(jwt.MapClaims)
+	claims, ok := t.Claims.(*adminTokenClaims)
 	if !ok {
-		return fmt.Errorf("error get user claims from token")
+		return -1, fmt.Errorf("error get user claims from token")
 	}
 
-	return nil
+	return claims.ID, nil
 }
 
 func (s *AdminService) getPasswordHash(password string) string {
@@ -73,13 +126,46 @@ func (s *AdminService) GetUserFullInfoById(userId int64) (*entity.UserInfo, erro
 	return s.userRepo.GetUserFullInfoById(userId)
 }
 
-func (s *AdminService) CreateUser(user *entity.User) (int64, error) {
-	return s.userRepo.CreateUser(user, user.Role)
+// CreateUser creates verified account, admin is trusted to provide valid email.
+func (s *AdminService) CreateUser(actor *entity.Actor, user *entity.User) (int64, error) {
+	user.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return s.userRepo.CreateUser(actor, user, user.Role)
+}
+
+func (s *AdminService) UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error {
+	return s.userRepo.UpdateUser(actor, userId, update)
+}
+func (s *AdminService) DeleteUser(actor *entity.Actor, userId int64) error {
+	return s.userRepo.DeleteUser(actor, userId)
+}
+
+func (s *AdminService) RestoreUser(actor *entity.Actor, userId int64) error {
+	return s.userRepo.RestoreUser(actor, userId)
 }
 
-func (s *AdminService) UpdateUser(userId int64, update *entity.UserUpdate) error {
-	return s.userRepo.UpdateUser(userId, update)
+func (s *AdminService) PurgeDeletedUsers(deletedBefore time.Time) (int64, error) {
+	return s.userRepo.PurgeDeletedUsers(deletedBefore)
 }
-func (s *AdminService) DeleteUser(userId int64) error {
-	return s.userRepo.DeleteUser(userId)
+
+func (s *AdminService) GetAuditLog(filter *entity.AuditFilter) (*entity.AuditPage, error) {
+	if filter.Limit < 1 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	entries, err := s.auditRepo.GetAuditLog(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.AuditPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.HasMore = true
+	}
+	return page, nil
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository"
+	"errors"
+	"net/http"
+)
+
+// MaxCertificateSize limits size of single uploaded certificate.
+const MaxCertificateSize = 5 << 20
+
+var certificateContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+type ApplicationService struct {
+	repo repository.Application
+}
+
+func NewApplicationService(repo repository.Application) *ApplicationService {
+	return &ApplicationService{repo: repo}
+}
+
+func (s *ApplicationService) SubmitApplication(actor *entity.Actor, userId int64,
+	input *entity.TrainerApplicationInput) (int64, error) {
+	return s.repo.CreateApplication(actor, &entity.TrainerApplication{
+		UserId:          userId,
+		Bio:             input.Bio,
+		Specialization:  input.Specialization,
+		ExperienceYears: input.ExperienceYears,
+	})
+}
+
+func (s *ApplicationService) GetUserApplications(userId int64) ([]*entity.TrainerApplication, error) {
+	return s.repo.GetUserApplications(userId)
+}
+
+func (s *ApplicationService) UpdateApplication(actor *entity.Actor, userId, appId int64,
+	input *entity.TrainerApplicationInput) error {
+	return s.repo.UpdateApplication(actor, userId, appId, input)
+}
+
+// AddCertificate stores certificate file, content type is detected from content and not trusted from client.
+func (s *ApplicationService) AddCertificate(actor *entity.Actor, userId int64, cert *entity.Certificate) (int64, error) {
+	if len(cert.Content) == 0 {
+		return -1, errors.New("certificate file is empty")
+	}
+	if len(cert.Content) > MaxCertificateSize {
+		return -1, errors.New("certificate file is too large")
+	}
+	contentType := http.DetectContentType(cert.Content)
+	if !certificateContentTypes[contentType] {
+		return -1, errors.New("certificate must be a pdf, png or jpeg file")
+	}
+
+	cert.ContentType = contentType
+	cert.Size = int64(len(cert.Content))
+	return s.repo.AddCertificate(actor, userId, cert)
+}
+
+func (s *ApplicationService) GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error) {
+	if status == "" {
+		status = entity.ApplicationPending
+	}
+	switch status {
+	case entity.ApplicationPending, entity.ApplicationChangesRequested,
+		entity.ApplicationApproved, entity.ApplicationRejected:
+	default:
+		return nil, errors.New("invalid application status")
+	}
+	return s.repo.GetApplications(status)
+}
+
+func (s *ApplicationService) GetApplicationById(appId int64) (*entity.TrainerApplication, error) {
+	app, err := s.repo.GetApplicationById(appId)
+	if err != nil {
+		return nil, errors.New("no application with provided id")
+	}
+	return app, nil
+}
+
+func (s *ApplicationService) GetCertificate(appId, certificateId int64) (*entity.Certificate, error) {
+	cert, err := s.repo.GetCertificate(appId, certificateId)
+	if err != nil {
+		return nil, errors.New("no certificate with provided id")
+	}
+	return cert, nil
+}
+
+// ReviewApplication records admin decision, reviewer has to explain rejection and requested changes.
+func (s *ApplicationService) ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus,
+	note string) error {
+	switch status {
+	case entity.ApplicationApproved:
+	case entity.ApplicationRejected, entity.ApplicationChangesRequested:
+		if note == "" {
+			return errors.New("reviewer note is required")
+		}
+	default:
+		return errors.New("invalid review decision")
+	}
+	return s.repo.ReviewApplication(actor, appId, status, note)
+}
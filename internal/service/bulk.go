@@ -0,0 +1,233 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// maxBulkItems is the most workouts one bulk operation changes, inputs are limited by binding too.
+const maxBulkItems = 100
+
+var (
+	// ErrInvalidRange and ErrRangeTooLarge reject shift input, other errors of bulk operations are internal.
+	ErrInvalidRange  = errors.New("from must be before to")
+	ErrRangeTooLarge = fmt.Errorf("range has more than %d workouts, shift it in parts", maxBulkItems)
+
+	// errBulkAborted rolls back transaction of atomic bulk operation which has failed items.
+	errBulkAborted = errors.New("bulk operation is aborted")
+	// errWorkoutNotFound and errItemFailed are item errors shown instead of errors of the database.
+	errWorkoutNotFound = errors.New("workout not found")
+	errItemFailed      = errors.New("workout can't be changed, try again later")
+)
+
+// bulkItem applies one item in the transaction of bulk operation and returns event to publish after commit.
+type bulkItem func(repos *repository.Repository, result *entity.BulkItemResult) (*entity.Event, error)
+
+// BulkService changes many workouts of trainer in one transaction. Every item goes through the same
+// repository calls, and so the same access checks, as the single workout endpoints.
+// Memory storage has no transactions, there atomic operation can be left applied in part.
+type BulkService struct {
+	tx     repository.TxManager
+	events event.Publisher
+}
+
+func NewBulkService(tx repository.TxManager, events event.Publisher) *BulkService {
+	return &BulkService{tx: tx, events: events}
+}
+
+func (s *BulkService) CreateWorkouts(trainerId int64, input *entity.BulkCreateInput) (*entity.BulkResult, error) {
+	items := make([]bulkItem, 0, len(input.Workouts))
+	for _, workout := range input.Workouts {
+		workout := workout
+		items = append(items, func(repos *repository.Repository, result *entity.BulkItemResult) (*entity.Event, error) {
+			if workout.Date.IsZero() {
+				return nil, validationError{errors.New("date can't be empty")}
+			}
+			created := *workout
+			created.TrainerId = sql.NullInt64{Int64: trainerId, Valid: true}
+			created.Date = workout.Date.UTC()
+
+			id, err := repos.User.CreateWorkoutAsTrainer(&created)
+			if err != nil {
+				return nil, err
+			}
+			created.Id = id
+			result.WorkoutId = id
+			return workoutEvent(entity.EventWorkoutCreated, &created), nil
+		})
+	}
+	return s.run(input.Mode, func(*repository.Repository) ([]bulkItem, error) {
+		return items, nil
+	})
+}
+
+func (s *BulkService) PatchWorkouts(trainerId int64, input *entity.BulkPatchInput) (*entity.BulkResult, error) {
+	items := make([]bulkItem, 0, len(input.Workouts))
+	for _, patch := range input.Workouts {
+		patch := patch
+		items = append(items, func(repos *repository.Repository, result *entity.BulkItemResult) (*entity.Event, error) {
+			result.WorkoutId = patch.Id
+			return patchWorkoutItem(repos, trainerId, patch.Id, &patch.WorkoutPatch, patch.Version, result)
+		})
+	}
+	return s.run(input.Mode, func(*repository.Repository) ([]bulkItem, error) {
+		return items, nil
+	})
+}
+
+func (s *BulkService) DeleteWorkouts(trainerId int64, input *entity.BulkDeleteInput) (*entity.BulkResult, error) {
+	items := make([]bulkItem, 0, len(input.Workouts))
+	for _, ref := range input.Workouts {
+		ref := ref
+		items = append(items, func(repos *repository.Repository, result *entity.BulkItemResult) (*entity.Event, error) {
+			result.WorkoutId = ref.Id
+			return nil, repos.User.DeleteWorkout(ref.Id, trainerId, ref.Version)
+		})
+	}
+	return s.run(input.Mode, func(*repository.Repository) ([]bulkItem, error) {
+		return items, nil
+	})
+}
+
+// ShiftWorkouts moves workouts of trainer with user, every workout in range is an item and is
+// changed only over the version it has been selected at.
+func (s *BulkService) ShiftWorkouts(trainerId int64, input *entity.BulkShiftInput) (*entity.BulkResult, error) {
+	if !input.From.Before(input.To) {
+		return nil, ErrInvalidRange
+	}
+
+	return s.run(input.Mode, func(repos *repository.Repository) ([]bulkItem, error) {
+		timeZone, err := repos.User.GetTimeZone(input.UserId)
+		if err != nil {
+			return nil, err
+		}
+		loc, err := loadTimeZone(timeZone)
+		if err != nil {
+			return nil, err
+		}
+		workouts, err := repos.User.GetTrainerWorkoutsWithUser(trainerId, input.UserId)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]bulkItem, 0)
+		for _, workout := range workouts {
+			if workout.Date.Before(input.From) || !workout.Date.Before(input.To) {
+				continue
+			}
+			workout := workout
+			items = append(items, func(repos *repository.Repository,
+				result *entity.BulkItemResult) (*entity.Event, error) {
+				result.WorkoutId = workout.Id
+				patch := &entity.WorkoutPatch{Date: entity.Patch[time.Time]{Set: true,
+					Value: workout.Date.In(loc).AddDate(0, 0, input.Days)}}
+				return patchWorkoutItem(repos, trainerId, workout.Id, patch, workout.Version, result)
+			})
+		}
+		if len(items) > maxBulkItems {
+			return nil, ErrRangeTooLarge
+		}
+		return items, nil
+	})
+}
+
+// run applies items in one transaction, each of them in a savepoint, so failed item is rolled back alone.
+// Atomic operation with failed items is rolled back as a whole, best effort one commits the others.
+// Item failed by serialization failure or deadlock fails the transaction, which is then rerun.
+// Result of the last attempt of transaction is returned and events are published only after commit.
+func (s *BulkService) run(mode entity.BulkMode,
+	prepare func(repos *repository.Repository) ([]bulkItem, error)) (*entity.BulkResult, error) {
+	var result *entity.BulkResult
+	var events []*entity.Event
+	err := s.tx.WithinTx(serializableTx, func(repos *repository.Repository) error {
+		items, err := prepare(repos)
+		if err != nil {
+			return err
+		}
+
+		result = &entity.BulkResult{Items: make([]*entity.BulkItemResult, 0, len(items))}
+		events = events[:0]
+		for i, apply := range items {
+			item := &entity.BulkItemResult{Index: i}
+			result.Items = append(result.Items, item)
+
+			var e *entity.Event
+			err = repos.Savepoint(func(repos *repository.Repository) (err error) {
+				e, err = apply(repos, item)
+				return err
+			})
+			if err != nil {
+				if s.tx.Retryable(err) {
+					return err
+				}
+				item.Error = itemError(err)
+				result.Failed++
+				continue
+			}
+			result.Succeeded++
+			if e != nil {
+				events = append(events, e)
+			}
+		}
+
+		if result.Failed > 0 && mode != entity.BulkBestEffort {
+			return errBulkAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkAborted) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Committed = true
+	for _, e := range events {
+		s.events.Publish(e)
+	}
+	return result, nil
+}
+
+// itemError is message of failed item, the same the single workout endpoints answer with. Errors
+// of the database are logged and not shown to client.
+func itemError(err error) string {
+	var invalid validationError
+	switch {
+	case errors.As(err, &invalid), errors.Is(err, entity.ErrVersionMismatch),
+		errors.Is(err, entity.ErrNoWorkoutAccess), errors.Is(err, entity.ErrNoWorkoutRights):
+		return err.Error()
+	case errors.Is(err, sql.ErrNoRows):
+		return errWorkoutNotFound.Error()
+	}
+	logrus.Errorf("bulk item failed: %s", err.Error())
+	return errItemFailed.Error()
+}
+
+func patchWorkoutItem(repos *repository.Repository, trainerId, workoutId int64, patch *entity.WorkoutPatch,
+	version int64, result *entity.BulkItemResult) (*entity.Event, error) {
+	version, changed, err := patchWorkout(repos.User, workoutId, trainerId, patch, version)
+	if err != nil {
+		return nil, err
+	}
+	result.Version = version
+	if !changed {
+		return nil, nil
+	}
+
+	workout, err := repos.User.GetWorkoutById(workoutId, trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return workoutEvent(entity.EventWorkoutUpdated, workout), nil
+}
+
+func workoutEvent(eventType entity.EventType, workout *entity.Workout) *entity.Event {
+	return entity.NewEvent(eventType, workout, workoutParticipants(workout)...)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"Fitness_REST_API/dbschema"
+	"Fitness_REST_API/internal/config"
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/migrate"
+	"Fitness_REST_API/internal/repository"
+	"Fitness_REST_API/internal/repository/sqlite"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBulkService runs bulk operations on migrated SQLite database, so that rollback is real.
+func newBulkService(t *testing.T) (*BulkService, *repository.Repository) {
+	cfg := &config.Config{SQLiteConfig: config.SQLiteConfig{SQLitePath: filepath.Join(t.TempDir(), "test.db")}}
+	db, err := sqlite.InitSQLiteDB(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := migrate.Load(dbschema.SQLiteFS)
+	require.NoError(t, err)
+	_, err = migrate.NewSQLite(db.DB, migrations).Up(context.Background())
+	require.NoError(t, err)
+
+	repos := repository.NewSQLiteRepository(db)
+	return NewBulkService(repos.TxManager, event.NewMemoryBus()), repos
+}
+
+func TestBulkService(t *testing.T) {
+	s, repos := newBulkService(t)
+	trainerId, err := repos.User.CreateUser(nil, &entity.User{Email: "trainer@mail.com", PasswordHash: "hash",
+		Name: "Bob", Surname: "Brown"}, entity.TrainerRole)
+	require.NoError(t, err)
+	userId, err := repos.User.CreateUser(nil, &entity.User{Email: "user@mail.com", PasswordHash: "hash",
+		Name: "John", Surname: "Smith"}, entity.UserRole)
+	require.NoError(t, err)
+	strangerId, err := repos.User.CreateUser(nil, &entity.User{Email: "stranger@mail.com", PasswordHash: "hash",
+		Name: "Jim", Surname: "Jones"}, entity.UserRole)
+	require.NoError(t, err)
+	_, err = repos.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+	day := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Atomic create is rolled back", func(t *testing.T) {
+		result, err := s.CreateWorkouts(trainerId, &entity.BulkCreateInput{Workouts: []*entity.Workout{
+			{Title: "Run", UserId: userId, Date: day},
+			{Title: "Swim", UserId: strangerId, Date: day},
+		}})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+		assert.Empty(t, result.Items[0].Error)
+		assert.Equal(t, "no rights to create workout with this user", result.Items[1].Error)
+
+		workouts, err := repos.User.GetTrainerWorkoutsWithUser(trainerId, userId)
+		require.NoError(t, err)
+		assert.Empty(t, workouts)
+	})
+
+	var created []int64
+	t.Run("Best effort create", func(t *testing.T) {
+		result, err := s.CreateWorkouts(trainerId, &entity.BulkCreateInput{Mode: entity.BulkBestEffort,
+			Workouts: []*entity.Workout{
+				{Title: "Run", UserId: userId, Date: day},
+				{Title: "Swim", UserId: strangerId, Date: day},
+				{Title: "Gym", UserId: userId, Date: day.AddDate(0, 0, 2)},
+			}})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.NotEmpty(t, result.Items[1].Error)
+		created = []int64{result.Items[0].WorkoutId, result.Items[2].WorkoutId}
+
+		workouts, err := repos.User.GetTrainerWorkoutsWithUser(trainerId, userId)
+		require.NoError(t, err)
+		assert.Len(t, workouts, 2)
+	})
+
+	t.Run("Patch checks access and version", func(t *testing.T) {
+		result, err := s.PatchWorkouts(trainerId, &entity.BulkPatchInput{Mode: entity.BulkBestEffort,
+			Workouts: []*entity.BulkWorkoutPatch{
+				{Id: created[0], Version: 1, WorkoutPatch: entity.WorkoutPatch{
+					Description: entity.Patch[string]{Set: true, Value: "Easy pace"}}},
+				{Id: created[1], Version: 5, WorkoutPatch: entity.WorkoutPatch{
+					Title: entity.Patch[string]{Set: true, Value: "Stale"}}},
+			}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Items[0].Version)
+		assert.Equal(t, entity.ErrVersionMismatch.Error(), result.Items[1].Error)
+
+		result, err = s.PatchWorkouts(strangerId, &entity.BulkPatchInput{Workouts: []*entity.BulkWorkoutPatch{
+			{Id: created[0], WorkoutPatch: entity.WorkoutPatch{
+				Title: entity.Patch[string]{Set: true, Value: "Hijack"}}},
+		}})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, "no access to this workout", result.Items[0].Error)
+		workout, err := repos.User.GetWorkoutById(created[0], trainerId)
+		require.NoError(t, err)
+		assert.Equal(t, "Run", workout.Title)
+		assert.Equal(t, "Easy pace", workout.Description)
+	})
+
+	t.Run("Shift", func(t *testing.T) {
+		result, err := s.ShiftWorkouts(trainerId, &entity.BulkShiftInput{UserId: userId, From: day,
+			To: day.AddDate(0, 0, 1), Days: 7})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, created[0], result.Items[0].WorkoutId)
+
+		workout, err := repos.User.GetWorkoutById(created[0], trainerId)
+		require.NoError(t, err)
+		assert.True(t, day.AddDate(0, 0, 7).Equal(workout.Date))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		result, err := s.DeleteWorkouts(trainerId, &entity.BulkDeleteInput{Workouts: []*entity.WorkoutRef{
+			{Id: created[0]}, {Id: created[1]},
+		}})
+		require.NoError(t, err)
+		assert.True(t, result.Committed)
+
+		_, err = repos.User.GetWorkoutById(created[0], trainerId)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		result, err = s.DeleteWorkouts(trainerId, &entity.BulkDeleteInput{Workouts: []*entity.WorkoutRef{
+			{Id: created[0]},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "workout not found", result.Items[0].Error)
+	})
+}
+
+var errConflict = errors.New("conflict")
+
+// conflictingTx fails the first savepoint with retryable error, like serialization failure does, and
+// reruns transactions failed with it.
+type conflictingTx struct {
+	repository.TxManager
+	attempts int
+	failed   bool
+}
+
+func (m *conflictingTx) WithinTx(opts repository.TxOptions, fn func(repos *repository.Repository) error) error {
+	for {
+		m.attempts++
+		err := m.TxManager.WithinTx(opts, func(repos *repository.Repository) error {
+			conflicting := *repos
+			conflicting.TxManager = &savepointConflict{TxManager: repos.TxManager, tx: m}
+			return fn(&conflicting)
+		})
+		if !errors.Is(err, errConflict) {
+			return err
+		}
+	}
+}
+
+func (m *conflictingTx) Retryable(err error) bool {
+	return errors.Is(err, errConflict)
+}
+
+type savepointConflict struct {
+	repository.TxManager
+	tx *conflictingTx
+}
+
+func (m *savepointConflict) Savepoint(fn func(repos *repository.Repository) error) error {
+	if !m.tx.failed {
+		m.tx.failed = true
+		return errConflict
+	}
+	return m.TxManager.Savepoint(fn)
+}
+
+func TestBulkService_Retry(t *testing.T) {
+	_, repos := newBulkService(t)
+	tx := &conflictingTx{TxManager: repos.TxManager}
+	s := NewBulkService(tx, event.NewMemoryBus())
+	trainerId, err := repos.User.CreateUser(nil, &entity.User{Email: "trainer@mail.com", PasswordHash: "hash",
+		Name: "Bob", Surname: "Brown"}, entity.TrainerRole)
+	require.NoError(t, err)
+	userId, err := repos.User.CreateUser(nil, &entity.User{Email: "user@mail.com", PasswordHash: "hash",
+		Name: "John", Surname: "Smith"}, entity.UserRole)
+	require.NoError(t, err)
+	_, err = repos.User.InitPartnershipWithUser(trainerId, userId)
+	require.NoError(t, err)
+
+	result, err := s.CreateWorkouts(trainerId, &entity.BulkCreateInput{Workouts: []*entity.Workout{
+		{Title: "Run", UserId: userId, Date: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, tx.attempts)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Empty(t, result.Items[0].Error)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/repository"
+	"errors"
+	"time"
+)
+
+type CommentService struct {
+	repo     repository.Comment
+	userRepo repository.User
+	events   event.Publisher
+}
+
+func NewCommentService(repo repository.Comment, userRepo repository.User, events event.Publisher) *CommentService {
+	return &CommentService{repo: repo, userRepo: userRepo, events: events}
+}
+
+func (s *CommentService) GetComments(workoutId, userId int64) ([]*entity.Comment, error) {
+	if err := s.userRepo.CheckAccessToWorkout(workoutId, userId); err != nil {
+		return nil, entity.ErrNoWorkoutAccess
+	}
+	return s.repo.GetWorkoutComments(workoutId)
+}
+
+func (s *CommentService) CreateComment(workoutId, userId int64, body string) (int64, error) {
+	workout, err := s.userRepo.GetWorkoutById(workoutId, userId)
+	if err != nil {
+		return -1, entity.ErrNoWorkoutAccess
+	}
+
+	comment := &entity.Comment{
+		WorkoutId: workoutId,
+		AuthorId:  userId,
+		Body:      body,
+	}
+	id, err := s.repo.CreateComment(comment)
+	if err != nil {
+		return id, err
+	}
+
+	comment.Id = id
+	comment.CreatedAt = time.Now().UTC()
+	s.events.Publish(entity.NewEvent(entity.EventCommentCreated, comment, workoutParticipants(workout)...))
+	return id, nil
+}
+
+func (s *CommentService) UpdateComment(workoutId, commentId, userId int64, body string) error {
+	comment, err := s.getComment(workoutId, commentId, userId)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorId != userId {
+		return errors.New("only author can edit comment")
+	}
+	return s.repo.UpdateComment(commentId, userId, body)
+}
+
+func (s *CommentService) ResolveComment(workoutId, commentId, userId int64, resolved bool) error {
+	if _, err := s.getComment(workoutId, commentId, userId); err != nil {
+		return err
+	}
+	return s.repo.SetCommentResolved(commentId, resolved)
+}
+
+func (s *CommentService) DeleteComment(workoutId, commentId, userId int64) error {
+	comment, err := s.getComment(workoutId, commentId, userId)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorId != userId {
+		return errors.New("only author can delete comment")
+	}
+	return s.repo.DeleteComment(commentId, userId)
+}
+
+func (s *CommentService) getComment(workoutId, commentId, userId int64) (*entity.Comment, error) {
+	if err := s.userRepo.CheckAccessToWorkout(workoutId, userId); err != nil {
+		return nil, entity.ErrNoWorkoutAccess
+	}
+	comment, err := s.repo.GetCommentById(workoutId, commentId)
+	if err != nil {
+		return nil, errors.New("no comment with provided id")
+	}
+	return comment, nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+)
+
+type EventService struct {
+	bus event.Subscriber
+}
+
+func NewEventService(bus event.Subscriber) *EventService {
+	return &EventService{bus: bus}
+}
+
+func (s *EventService) Subscribe(userId int64) (<-chan *entity.Event, func()) {
+	return s.bus.Subscribe(userId)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/export"
+	"Fitness_REST_API/internal/repository"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const exportDownloadPath = "/export/"
+
+// ExportService builds archives in background, export which is pending longer than buildTimeout
+// is considered lost, e.g. with restart of the server, and is failed.
+type ExportService struct {
+	repo         repository.Export
+	userRepo     repository.User
+	linkTTL      time.Duration
+	buildTimeout time.Duration
+}
+
+func NewExportService(repo repository.Export, userRepo repository.User,
+	linkTTL, buildTimeout time.Duration) *ExportService {
+	return &ExportService{repo: repo, userRepo: userRepo, linkTTL: linkTTL, buildTimeout: buildTimeout}
+}
+
+// RequestExport registers export of user data, archive is built in background.
+func (s *ExportService) RequestExport(actor *entity.Actor, userId int64) (int64, error) {
+	if _, err := s.userRepo.GetUserInfoById(userId); err != nil {
+		return -1, errors.New("invalid userId")
+	}
+
+	id, err := s.repo.CreateExport(actor, userId, time.Now().Add(-s.buildTimeout))
+	if err != nil {
+		return id, err
+	}
+	go s.build(id, userId)
+	return id, nil
+}
+
+func (s *ExportService) GetExport(userId, exportId int64) (*entity.DataExport, error) {
+	e, err := s.repo.GetExport(userId, exportId)
+	if err != nil {
+		return nil, errors.New("export not found")
+	}
+	if linkValid(e) {
+		e.DownloadURL = exportDownloadPath + e.Token.String
+	}
+	return e, nil
+}
+
+// GetCurrentExport returns the latest export of user, new export is started when there is none
+// or the latest one has failed, timed out or its link has expired.
+func (s *ExportService) GetCurrentExport(userId int64) (*entity.DataExport, error) {
+	e, err := s.repo.GetLatestExport(userId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if e == nil || e.Status == entity.ExportFailed || (e.Status == entity.ExportReady && !linkValid(e)) ||
+		(e.Status == entity.ExportPending && e.CreatedAt.Before(time.Now().Add(-s.buildTimeout))) {
+		id, err := s.RequestExport(nil, userId)
+		if err != nil && id != -1 {
+			return nil, err
+		}
+		// export started by concurrent request is returned as well
+		if e, err = s.repo.GetLatestExport(userId); err != nil {
+			return nil, err
+		}
+	}
+	if linkValid(e) {
+		e.DownloadURL = exportDownloadPath + e.Token.String
+	}
+	return e, nil
+}
+
+func (s *ExportService) GetExportArchive(token string) (*entity.DataExport, error) {
+	e, err := s.repo.GetExportArchive(token)
+	if err != nil {
+		return nil, errors.New("export link is invalid or expired")
+	}
+	return e, nil
+}
+
+func (s *ExportService) PurgeExpiredExports(expiredBefore time.Time) (int64, error) {
+	return s.repo.PurgeExpiredExports(expiredBefore)
+}
+
+func (s *ExportService) FailStaleExports(createdBefore time.Time) (int64, error) {
+	return s.repo.FailStaleExports(createdBefore)
+}
+
+func (s *ExportService) build(exportId, userId int64) {
+	data, err := s.repo.GetExportData(userId)
+	if err != nil {
+		s.fail(exportId, "can't collect user data", err)
+		return
+	}
+	archive, err := export.Build(data)
+	if err != nil {
+		s.fail(exportId, "can't build archive", err)
+		return
+	}
+
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		s.fail(exportId, "can't generate download link", err)
+		return
+	}
+	err = s.repo.CompleteExport(exportId, archive, hex.EncodeToString(b), time.Now().Add(s.linkTTL))
+	if err != nil {
+		s.fail(exportId, "can't store archive", err)
+	}
+}
+
+func linkValid(e *entity.DataExport) bool {
+	return e.Status == entity.ExportReady && e.ExpiresAt.Time.After(time.Now())
+}
+
+func (s *ExportService) fail(exportId int64, reason string, err error) {
+	logrus.Errorf("export %d failed, %s: %s", exportId, reason, err.Error())
+	if err = s.repo.FailExport(exportId, reason); err != nil {
+		logrus.Errorf("can't mark export %d as failed: %s", exportId, err.Error())
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type GroupService struct {
+	repo   repository.Group
+	events event.Publisher
+}
+
+func NewGroupService(repo repository.Group, events event.Publisher) *GroupService {
+	return &GroupService{repo: repo, events: events}
+}
+
+func (s *GroupService) CreateGroup(group *entity.ClientGroup) (int64, error) {
+	return s.repo.CreateGroup(group)
+}
+
+func (s *GroupService) GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error) {
+	return s.repo.GetTrainerGroups(trainerId)
+}
+
+func (s *GroupService) GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error) {
+	group, err := s.repo.GetGroupById(trainerId, groupId)
+	if err != nil {
+		return nil, errors.New("no group with provided id")
+	}
+	return group, nil
+}
+
+func (s *GroupService) DeleteGroup(trainerId, groupId int64) error {
+	return s.repo.DeleteGroup(trainerId, groupId)
+}
+
+func (s *GroupService) AddGroupMember(trainerId, groupId, userId int64) error {
+	return s.repo.AddGroupMember(trainerId, groupId, userId)
+}
+
+func (s *GroupService) RemoveGroupMember(trainerId, groupId, userId int64) error {
+	return s.repo.RemoveGroupMember(trainerId, groupId, userId)
+}
+
+func (s *GroupService) CreateGroupWorkout(trainerId int64, input *entity.GroupWorkoutInput) (int64, error) {
+	userIds := make([]int64, 0, len(input.UserIds))
+	seen := make(map[int64]bool)
+	add := func(userId int64) {
+		if !seen[userId] {
+			seen[userId] = true
+			userIds = append(userIds, userId)
+		}
+	}
+
+	workout := &entity.GroupWorkout{
+		TrainerId:   trainerId,
+		Title:       input.Title,
+		Description: input.Description,
+		Date:        input.Date,
+		Capacity:    input.Capacity,
+	}
+
+	if input.GroupId != 0 {
+		group, err := s.GetGroupById(trainerId, input.GroupId)
+		if err != nil {
+			return -1, err
+		}
+		workout.GroupId = sql.NullInt64{Int64: group.Id, Valid: true}
+		for _, member := range group.Members {
+			add(member.Id)
+		}
+	}
+	for _, userId := range input.UserIds {
+		if userId < 1 {
+			return -1, errors.New("invalid user_ids")
+		}
+		add(userId)
+	}
+
+	if len(userIds) > workout.Capacity {
+		return -1, fmt.Errorf("group workout has %d participants, cap is %d", len(userIds), workout.Capacity)
+	}
+
+	id, err := s.repo.CreateGroupWorkout(workout, userIds)
+	if err != nil {
+		return id, err
+	}
+	if len(userIds) > 0 {
+		s.events.Publish(entity.NewEvent(entity.EventWorkoutCreated, workout, userIds...))
+	}
+	return id, nil
+}
+
+func (s *GroupService) GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error) {
+	return s.repo.GetTrainerGroupWorkouts(trainerId)
+}
+
+func (s *GroupService) GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error) {
+	workout, err := s.repo.GetGroupWorkoutById(trainerId, groupWorkoutId)
+	if err != nil {
+		return nil, errors.New("no group workout with provided id")
+	}
+	return workout, nil
+}
+
+func (s *GroupService) DeleteGroupWorkout(trainerId, groupWorkoutId int64) error {
+	return s.repo.DeleteGroupWorkout(trainerId, groupWorkoutId)
+}
+
+func (s *GroupService) AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	return s.repo.AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId)
+}
+
+func (s *GroupService) RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	return s.repo.RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId)
+}
+
+func (s *GroupService) SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error {
+	return s.repo.SetAttendance(trainerId, groupWorkoutId, userId, status)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/idempotency"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("idempotency key has been used for another request")
+	ErrIdempotencyKeyInFlight = errors.New("request with the idempotency key is in progress")
+)
+
+// IdempotencyService keeps responses of requests with Idempotency-Key for ttl, keys are scoped by user.
+// Request is in progress for lease at most, key of request its process has died with is reserved again after it.
+type IdempotencyService struct {
+	store idempotency.Store
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(store idempotency.Store, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{store: store, ttl: ttl, lease: lease}
+}
+
+// BeginRequest reserves key for request with fingerprint. It returns stored response of completed request,
+// otherwise the reservation, request has to be handled and then completed or released with it.
+func (s *IdempotencyService) BeginRequest(userId int64, key, fingerprint string) (
+	reservation, stored *entity.IdempotencyRecord, err error) {
+	// databases keep microseconds, reservation is matched by its creation time
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	reservation = &entity.IdempotencyRecord{
+		Key:         scopedKey(userId, key),
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		LockedUntil: now.Add(s.lease),
+	}
+	stored, err = s.store.Reserve(reservation, now.Add(-s.ttl))
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored == nil {
+		return reservation, nil, nil
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, nil, ErrIdempotencyKeyReused
+	}
+	if stored.Status == 0 {
+		return nil, nil, ErrIdempotencyKeyInFlight
+	}
+	return nil, stored, nil
+}
+
+// CompleteRequest stores response of request, unless its reservation has been taken over after the lease.
+func (s *IdempotencyService) CompleteRequest(reservation *entity.IdempotencyRecord, status int, contentType string,
+	body []byte) error {
+	return s.store.Complete(reservation.Key, reservation.CreatedAt, status, contentType, body)
+}
+
+// ReleaseRequest forgets key of failed request, so that client can retry it with the same key.
+func (s *IdempotencyService) ReleaseRequest(reservation *entity.IdempotencyRecord) error {
+	return s.store.Release(reservation.Key, reservation.CreatedAt)
+}
+
+func (s *IdempotencyService) PurgeKeys(createdBefore time.Time) (int64, error) {
+	return s.store.Purge(createdBefore)
+}
+
+func scopedKey(userId int64, key string) string {
+	return fmt.Sprintf("%d:%s", userId, key)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/oidc"
+	"Fitness_REST_API/internal/repository"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// IdentityService signs users in with ID tokens of OIDC providers and manages linked providers.
+type IdentityService struct {
+	repo         repository.Identity
+	userRepo     repository.User
+	verifier     oidc.Verifier
+	issueSession func(id int64, role entity.Role) (*entity.SignInResult, error)
+}
+
+func NewIdentityService(repo repository.Identity, userRepo repository.User, verifier oidc.Verifier,
+	issueSession func(id int64, role entity.Role) (*entity.SignInResult, error)) *IdentityService {
+	return &IdentityService{repo: repo, userRepo: userRepo, verifier: verifier, issueSession: issueSession}
+}
+
+// SignIn finds user linked to provider account. Unknown account is linked to user
+// with the same verified email or a new user is created.
+func (s *IdentityService) SignIn(provider string, input *entity.OIDCInput) (*entity.SignInResult, error) {
+	identity, err := s.verifier.Verify(provider, input.IdToken, input.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetIdentityUser(provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = s.linkByEmail(identity); err != nil {
+			return nil, err
+		}
+	}
+	return s.issueSession(user.Id, user.Role)
+}
+
+func (s *IdentityService) Link(userId int64, provider string, input *entity.OIDCInput) error {
+	identity, err := s.verifier.Verify(provider, input.IdToken, input.Nonce)
+	if err != nil {
+		return err
+	}
+	return s.repo.LinkIdentity(userId, externalIdentity(identity), false)
+}
+
+func (s *IdentityService) Unlink(userId int64, provider string) error {
+	return s.repo.UnlinkIdentity(userId, provider)
+}
+
+func (s *IdentityService) GetIdentities(userId int64) ([]*entity.ExternalIdentity, error) {
+	return s.repo.GetIdentities(userId)
+}
+
+// linkByEmail trusts only emails verified by provider. Password of existing user who
+// never verified the email is dropped, it could be set by someone else.
+func (s *IdentityService) linkByEmail(identity *oidc.Identity) (*entity.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.New("email of provider account is not verified")
+	}
+
+	user, err := s.userRepo.GetUserByEmail(identity.Email)
+	if err == nil {
+		err = s.repo.LinkIdentity(user.Id, externalIdentity(identity), !user.VerifiedAt.Valid)
+		return user, err
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user = &entity.User{
+		Email:   identity.Email,
+		Role:    entity.UserRole,
+		Name:    identity.GivenName,
+		Surname: identity.FamilyName,
+	}
+	if user.Name == "" {
+		user.Name = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+	user.Id, err = s.repo.CreateIdentityUser(user, externalIdentity(identity))
+	return user, err
+}
+
+func externalIdentity(identity *oidc.Identity) *entity.ExternalIdentity {
+	return &entity.ExternalIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+}
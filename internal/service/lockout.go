@@ -0,0 +1,131 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/repository"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// LockoutService throttles sign in attempts per account and per client IP.
+type LockoutService struct {
+	store   lockout.Store
+	audit   repository.Audit
+	account lockout.Policy
+	ip      lockout.Policy
+}
+
+func NewLockoutService(store lockout.Store, audit repository.Audit, account, ip lockout.Policy) *LockoutService {
+	return &LockoutService{store: store, audit: audit, account: account, ip: ip}
+}
+
+// BeginSignIn counts attempt as failed for account and client IP before credentials are checked, so that
+// concurrent attempts can't exceed the limits. Non-zero wait means attempt isn't allowed and nothing is counted,
+// otherwise the attempt has to be ended by FailSignIn, SucceedSignIn or CancelSignIn.
+func (s *LockoutService) BeginSignIn(actor *entity.Actor, scope entity.ActorType, login string) (time.Duration,
+	error) {
+	now := time.Now()
+	key := accountKey(scope, login)
+
+	_, wait, err := s.store.Attempt(key, now, s.account)
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	_, wait, err = s.store.Attempt(ipKey(actor.IP), now, s.ip)
+	if err != nil || wait > 0 {
+		if refundErr := s.store.Refund(key); refundErr != nil {
+			logrus.Errorf("can't refund sign in attempt: %s", refundErr.Error())
+		}
+	}
+	return wait, err
+}
+
+// FailSignIn keeps the attempt counted by BeginSignIn and reports lockout it has caused.
+func (s *LockoutService) FailSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	if err := s.auditLockout(actor, accountKey(scope, login), s.account); err != nil {
+		return err
+	}
+	return s.auditLockout(actor, ipKey(actor.IP), s.ip)
+}
+
+// SucceedSignIn forgets failures of account and takes back the attempt of IP, other failures of IP
+// are kept so that one known password doesn't let to brute-force other accounts.
+func (s *LockoutService) SucceedSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	if err := s.store.Reset(accountKey(scope, login)); err != nil {
+		return err
+	}
+	return s.store.Refund(ipKey(actor.IP))
+}
+
+// CancelSignIn takes back the attempt, when sign in is rejected for other reason than wrong credentials.
+func (s *LockoutService) CancelSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	if err := s.store.Refund(accountKey(scope, login)); err != nil {
+		return err
+	}
+	return s.store.Refund(ipKey(actor.IP))
+}
+
+func (s *LockoutService) Unlock(actor *entity.Actor, input *entity.UnlockInput) error {
+	if input.Login == "" && input.IP == "" {
+		return errors.New("login or ip is required")
+	}
+
+	keys := make([]string, 0, 2)
+	if input.Login != "" {
+		scope := input.Scope
+		if scope == "" {
+			scope = entity.ActorUser
+		}
+		keys = append(keys, accountKey(scope, input.Login))
+	}
+	if input.IP != "" {
+		keys = append(keys, ipKey(input.IP))
+	}
+
+	for _, key := range keys {
+		if err := s.store.Reset(key); err != nil {
+			return err
+		}
+		err := s.audit.WriteAudit(actor, entity.AuditSignInUnlock, entity.AuditTargetSignIn, 0,
+			map[string]interface{}{"key": key}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LockoutService) PurgeAttempts(lastFailureBefore time.Time) (int64, error) {
+	return s.store.Purge(lastFailureBefore)
+}
+
+func (s *LockoutService) auditLockout(actor *entity.Actor, key string, policy lockout.Policy) error {
+	a, err := s.store.Get(key)
+	if err != nil {
+		return err
+	}
+	if a == nil || !policy.LocksOut(a) {
+		return nil
+	}
+
+	logrus.Warnf("sign in for %s is locked out after %d failures", key, a.Failures)
+	return s.audit.WriteAudit(actor, entity.AuditSignInLockout, entity.AuditTargetSignIn, 0, nil,
+		map[string]interface{}{
+			"key":          key,
+			"failures":     a.Failures,
+			"locked_until": a.LastFailure.Add(policy.LockoutDuration),
+		})
+}
+
+// accountKey is shared by user and trainer sign in, they authorize the same accounts.
+func accountKey(scope entity.ActorType, login string) string {
+	return string(scope) + ":" + strings.ToLower(strings.TrimSpace(login))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/repository"
+	"errors"
+	"time"
+)
+
+const (
+	defaultMessagesLimit = 50
+	maxMessagesLimit     = 100
+)
+
+type MessageService struct {
+	repo     repository.Message
+	userRepo repository.User
+	events   event.Publisher
+}
+
+func NewMessageService(repo repository.Message, userRepo repository.User, events event.Publisher) *MessageService {
+	return &MessageService{repo: repo, userRepo: userRepo, events: events}
+}
+
+func (s *MessageService) GetUserConversation(userId, partnershipId int64) (*entity.Partnership, error) {
+	p, err := s.userRepo.GetPartnershipById(partnershipId)
+	if err != nil || p.UserId != userId {
+		return nil, errors.New("no access to conversation")
+	}
+	return p, nil
+}
+
+func (s *MessageService) GetTrainerConversation(trainerId, userId int64) (*entity.Partnership, error) {
+	p, err := s.userRepo.GetPartnership(trainerId, userId)
+	if err != nil || p.TrainerId != trainerId {
+		return nil, errors.New("no partnership with user")
+	}
+	return p, nil
+}
+
+func (s *MessageService) GetMessages(partnershipId, beforeId int64, limit int) (*entity.MessagePage, error) {
+	if limit < 1 {
+		limit = defaultMessagesLimit
+	}
+	if limit > maxMessagesLimit {
+		limit = maxMessagesLimit
+	}
+
+	messages, err := s.repo.GetMessages(partnershipId, beforeId, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+func (s *MessageService) SendMessage(partnership *entity.Partnership, senderId int64, body string) (int64, error) {
+	if partnership.Status != entity.StatusApproved {
+		return -1, errors.New("partnership is not active, conversation is read-only")
+	}
+	message := &entity.Message{
+		PartnershipId: partnership.Id,
+		SenderId:      senderId,
+		Body:          body,
+	}
+	id, err := s.repo.CreateMessage(message)
+	if err != nil {
+		return id, err
+	}
+
+	message.Id = id
+	message.CreatedAt = time.Now().UTC()
+	s.events.Publish(entity.NewEvent(entity.EventMessageCreated, message, partnership.UserId, partnership.TrainerId))
+	return id, nil
+}
+
+func (s *MessageService) MarkMessagesRead(partnershipId, readerId int64) (int64, error) {
+	return s.repo.MarkMessagesRead(partnershipId, readerId)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/repository"
+	"Fitness_REST_API/internal/totp"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	mfaIssuer         = "Fitness"
+	mfaChallengeTTL   = 5 * time.Minute
+	mfaSkew           = 1
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidMFACode  = errors.New("invalid two-factor code")
+	ErrTooManyAttempts = errors.New("too many failed attempts, try again later")
+)
+
+// MFAService manages TOTP factors and verifies the second step of sign in.
+type MFAService struct {
+	repo       repository.MFA
+	userRepo   repository.User
+	adminRepo  repository.Admin
+	attempts   lockout.Store
+	throttle   lockout.Policy
+	signingKey []byte
+}
+
+// mfaClaims identify account which passed password check, Enroll is set when
+// policy requires MFA but account has no confirmed factor yet.
+type mfaClaims struct {
+	jwt.StandardClaims
+	Scope  entity.ActorType `json:"scope"`
+	ID     int64            `json:"id"`
+	Role   entity.Role      `json:"role"`
+	Enroll bool             `json:"enroll,omitempty"`
+}
+
+func NewMFAService(repo repository.MFA, userRepo repository.User, adminRepo repository.Admin,
+	attempts lockout.Store, throttle lockout.Policy, signingKey string) *MFAService {
+	return &MFAService{repo: repo, userRepo: userRepo, adminRepo: adminRepo, attempts: attempts,
+		throttle: throttle, signingKey: []byte(signingKey)}
+}
+
+// Enroll starts enrollment with new secret, pending secret is replaced on repeated call.
+func (s *MFAService) Enroll(subject entity.ActorType, subjectId int64) (*entity.MFAEnrollment, error) {
+	account, err := s.accountName(subject, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.SaveFactor(subject, subjectId, secret); err != nil {
+		return nil, err
+	}
+	return &entity.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(mfaIssuer, account, secret),
+	}, nil
+}
+
+// EnrollChallenge starts enrollment for account which can't sign in until MFA is set up.
+func (s *MFAService) EnrollChallenge(mfaToken string) (*entity.MFAEnrollment, error) {
+	claims, err := s.parseChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.Enroll {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	return s.Enroll(claims.Scope, claims.ID)
+}
+
+// Confirm activates pending factor with code from authenticator app and returns recovery codes.
+func (s *MFAService) Confirm(subject entity.ActorType, subjectId int64, code string) ([]string, error) {
+	factor, err := s.repo.GetFactor(subject, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	if factor == nil || factor.ConfirmedAt.Valid {
+		return nil, errors.New("two-factor enrollment is not started")
+	}
+
+	var step int64
+	err = s.throttled(subject, subjectId, func() (bool, error) {
+		matched, ok, err := totp.Match(factor.Secret, code, time.Now(), mfaSkew)
+		step = matched
+		return ok, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.ConfirmFactor(subject, subjectId, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable removes factor after one more code check, unless policy of account role requires MFA.
+func (s *MFAService) Disable(subject entity.ActorType, subjectId int64, code string) error {
+	role, err := s.role(subject, subjectId)
+	if err != nil {
+		return err
+	}
+	required, err := s.repo.IsRequired(role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return fmt.Errorf("two-factor authentication is required for %s accounts", role)
+	}
+
+	if err = s.throttled(subject, subjectId, func() (bool, error) {
+		return s.matchCode(subject, subjectId, code)
+	}); err != nil {
+		return err
+	}
+	return s.repo.DeleteFactor(subject, subjectId)
+}
+
+func (s *MFAService) GetPolicies() ([]*entity.MFAPolicy, error) {
+	return s.repo.GetPolicies()
+}
+
+func (s *MFAService) SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error {
+	return s.repo.SetPolicy(actor, policy)
+}
+
+// challenge returns MFA challenge when account has confirmed factor or its role requires one,
+// nil result means that password is enough to sign in.
+func (s *MFAService) challenge(subject entity.ActorType, subjectId int64, role entity.Role) (*entity.SignInResult, error) {
+	factor, err := s.repo.GetFactor(subject, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := factor != nil && factor.ConfirmedAt.Valid
+	if !enrolled {
+		required, err := s.repo.IsRequired(role)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &mfaClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(mfaChallengeTTL).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		Scope:  subject,
+		ID:     subjectId,
+		Role:   role,
+		Enroll: !enrolled,
+	})
+	mfaToken, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SignInResult{MFAToken: mfaToken, MFAEnrollmentRequired: !enrolled}, nil
+}
+
+// verifyChallenge checks code for challenge issued to subject, enrollment started
+// by the challenge is confirmed by the same code and recovery codes are returned.
+func (s *MFAService) verifyChallenge(subject entity.ActorType, mfaToken, code string) (*mfaClaims, []string, error) {
+	claims, err := s.parseChallenge(mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.Scope != subject {
+		return nil, nil, errors.New("invalid two-factor token")
+	}
+
+	if claims.Enroll {
+		codes, err := s.Confirm(subject, claims.ID, code)
+		return claims, codes, err
+	}
+	err = s.throttled(subject, claims.ID, func() (bool, error) {
+		return s.matchCode(subject, claims.ID, code)
+	})
+	return claims, nil, err
+}
+
+func (s *MFAService) parseChallenge(mfaToken string) (*mfaClaims, error) {
+	t, err := jwt.ParseWithClaims(mfaToken, &mfaClaims{}, func(token *jwt.Token) (i interface{}, err error) { //nolint
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.signingKey, nil
+	})
+	if err != nil {
+		return nil, errors.New("invalid or expired two-factor token")
+	}
+
+	claims, ok := t.Claims.(*mfaClaims)
+	if !ok {
+		return nil, errors.New("invalid two-factor token")
+	}
+	return claims, nil
+}
+
+// matchCode accepts TOTP code once per time step or unused recovery code.
+func (s *MFAService) matchCode(subject entity.ActorType, subjectId int64, code string) (bool, error) {
+	factor, err := s.repo.GetFactor(subject, subjectId)
+	if err != nil {
+		return false, err
+	}
+	if factor == nil || !factor.ConfirmedAt.Valid {
+		return false, errors.New("two-factor authentication is not enabled")
+	}
+
+	step, ok, err := totp.Match(factor.Secret, code, time.Now(), mfaSkew)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return s.repo.UseStep(subject, subjectId, step)
+	}
+	return s.repo.UseRecoveryCode(subject, subjectId, hashRecoveryCode(code))
+}
+
+// throttled runs check unless subject has too many failed codes recently,
+// six digit code would be guessed quickly without backoff. The attempt is counted as failed
+// before check, so that parallel requests can't try more codes than allowed.
+func (s *MFAService) throttled(subject entity.ActorType, subjectId int64, check func() (bool, error)) error {
+	key := fmt.Sprintf("mfa:%s:%d", subject, subjectId)
+
+	_, wait, err := s.attempts.Attempt(key, time.Now(), s.throttle)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return ErrTooManyAttempts
+	}
+
+	ok, err := check()
+	if err != nil {
+		if refundErr := s.attempts.Refund(key); refundErr != nil {
+			logrus.Errorf("can't refund mfa attempt: %s", refundErr.Error())
+		}
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return s.attempts.Reset(key)
+}
+
+func (s *MFAService) accountName(subject entity.ActorType, subjectId int64) (string, error) {
+	if subject == entity.ActorAdmin {
+		return s.adminRepo.GetLogin(subjectId)
+	}
+	user, err := s.userRepo.GetUserInfoById(subjectId)
+	if err != nil {
+		return "", err
+	}
+	return user.Email, nil
+}
+
+func (s *MFAService) role(subject entity.ActorType, subjectId int64) (entity.Role, error) {
+	if subject == entity.ActorAdmin {
+		return entity.AdminRole, nil
+	}
+	user, err := s.userRepo.GetUserInfoById(subjectId)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// newRecoveryCodes returns codes formatted as xxxxx-xxxxx and their hashes to be stored.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashRecoveryCode(code)
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
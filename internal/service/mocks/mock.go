@@ -7,6 +7,7 @@ package mock_service
 import (
 	entity "Fitness_REST_API/internal/entity"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,32 +36,47 @@ func (m *MockAdmin) EXPECT() *MockAdminMockRecorder {
 }
 
 // CreateUser mocks base method.
-func (m *MockAdmin) CreateUser(user *entity.User) (int64, error) {
+func (m *MockAdmin) CreateUser(actor *entity.Actor, user *entity.User) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret := m.ctrl.Call(m, "CreateUser", actor, user)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockAdminMockRecorder) CreateUser(user interface{}) *gomock.Call {
+func (mr *MockAdminMockRecorder) CreateUser(actor, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAdmin)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAdmin)(nil).CreateUser), actor, user)
 }
 
 // DeleteUser mocks base method.
-func (m *MockAdmin) DeleteUser(userId int64) error {
+func (m *MockAdmin) DeleteUser(actor *entity.Actor, userId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", userId)
+	ret := m.ctrl.Call(m, "DeleteUser", actor, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockAdminMockRecorder) DeleteUser(userId interface{}) *gomock.Call {
+func (mr *MockAdminMockRecorder) DeleteUser(actor, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAdmin)(nil).DeleteUser), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAdmin)(nil).DeleteUser), actor, userId)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockAdmin) GetAuditLog(filter *entity.AuditFilter) (*entity.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", filter)
+	ret0, _ := ret[0].(*entity.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockAdminMockRecorder) GetAuditLog(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockAdmin)(nil).GetAuditLog), filter)
 }
 
 // GetUserFullInfoById mocks base method.
@@ -94,11 +110,12 @@ func (mr *MockAdminMockRecorder) GetUsersId(role interface{}) *gomock.Call {
 }
 
 // ParseToken mocks base method.
-func (m *MockAdmin) ParseToken(token string) error {
+func (m *MockAdmin) ParseToken(token string) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ParseToken", token)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
@@ -107,11 +124,40 @@ func (mr *MockAdminMockRecorder) ParseToken(token interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAdmin)(nil).ParseToken), token)
 }
 
+// PurgeDeletedUsers mocks base method.
+func (m *MockAdmin) PurgeDeletedUsers(deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockAdminMockRecorder) PurgeDeletedUsers(deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockAdmin)(nil).PurgeDeletedUsers), deletedBefore)
+}
+
+// RestoreUser mocks base method.
+func (m *MockAdmin) RestoreUser(actor *entity.Actor, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", actor, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockAdminMockRecorder) RestoreUser(actor, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockAdmin)(nil).RestoreUser), actor, userId)
+}
+
 // SignIn mocks base method.
-func (m *MockAdmin) SignIn(login, passwordHash string) (string, error) {
+func (m *MockAdmin) SignIn(login, passwordHash string) (*entity.SignInResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignIn", login, passwordHash)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*entity.SignInResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -123,17 +169,32 @@ func (mr *MockAdminMockRecorder) SignIn(login, passwordHash interface{}) *gomock
 }
 
 // UpdateUser mocks base method.
-func (m *MockAdmin) UpdateUser(userId int64, update *entity.UserUpdate) error {
+func (m *MockAdmin) UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", userId, update)
+	ret := m.ctrl.Call(m, "UpdateUser", actor, userId, update)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockAdminMockRecorder) UpdateUser(userId, update interface{}) *gomock.Call {
+func (mr *MockAdminMockRecorder) UpdateUser(actor, userId, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAdmin)(nil).UpdateUser), actor, userId, update)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockAdmin) VerifyMFA(mfaToken, code string) (*entity.SignInResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", mfaToken, code)
+	ret0, _ := ret[0].(*entity.SignInResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockAdminMockRecorder) VerifyMFA(mfaToken, code interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAdmin)(nil).UpdateUser), userId, update)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockAdmin)(nil).VerifyMFA), mfaToken, code)
 }
 
 // MockUser is a mock of User interface.
@@ -160,18 +221,48 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 }
 
 // AcceptRequest mocks base method.
-func (m *MockUser) AcceptRequest(trainerId, requestId int64) (int64, error) {
+func (m *MockUser) AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptRequest", trainerId, requestId)
+	ret := m.ctrl.Call(m, "AcceptRequest", actor, trainerId, requestId)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AcceptRequest indicates an expected call of AcceptRequest.
-func (mr *MockUserMockRecorder) AcceptRequest(trainerId, requestId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) AcceptRequest(actor, trainerId, requestId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptRequest", reflect.TypeOf((*MockUser)(nil).AcceptRequest), actor, trainerId, requestId)
+}
+
+// AnonymizeScheduledUsers mocks base method.
+func (m *MockUser) AnonymizeScheduledUsers(dueBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeScheduledUsers", dueBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeScheduledUsers indicates an expected call of AnonymizeScheduledUsers.
+func (mr *MockUserMockRecorder) AnonymizeScheduledUsers(dueBefore interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptRequest", reflect.TypeOf((*MockUser)(nil).AcceptRequest), trainerId, requestId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeScheduledUsers", reflect.TypeOf((*MockUser)(nil).AnonymizeScheduledUsers), dueBefore)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUser) ChangePassword(userId int64, currentPassword, newPassword string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userId, currentPassword, newPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserMockRecorder) ChangePassword(userId, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUser)(nil).ChangePassword), userId, currentPassword, newPassword)
 }
 
 // CreateWorkoutAsTrainer mocks base method.
@@ -204,76 +295,92 @@ func (mr *MockUserMockRecorder) CreateWorkoutAsUser(workout interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkoutAsUser", reflect.TypeOf((*MockUser)(nil).CreateWorkoutAsUser), workout)
 }
 
+// DeleteAccount mocks base method.
+func (m *MockUser) DeleteAccount(userId int64, password string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", userId, password)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUserMockRecorder) DeleteAccount(userId, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUser)(nil).DeleteAccount), userId, password)
+}
+
 // DeleteWorkout mocks base method.
-func (m *MockUser) DeleteWorkout(workoutId, userId int64) error {
+func (m *MockUser) DeleteWorkout(workoutId, userId, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWorkout", workoutId, userId)
+	ret := m.ctrl.Call(m, "DeleteWorkout", workoutId, userId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteWorkout indicates an expected call of DeleteWorkout.
-func (mr *MockUserMockRecorder) DeleteWorkout(workoutId, userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) DeleteWorkout(workoutId, userId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkout", reflect.TypeOf((*MockUser)(nil).DeleteWorkout), workoutId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkout", reflect.TypeOf((*MockUser)(nil).DeleteWorkout), workoutId, userId, version)
 }
 
 // DenyRequest mocks base method.
-func (m *MockUser) DenyRequest(trainerId, requestId int64) error {
+func (m *MockUser) DenyRequest(actor *entity.Actor, trainerId, requestId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DenyRequest", trainerId, requestId)
+	ret := m.ctrl.Call(m, "DenyRequest", actor, trainerId, requestId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DenyRequest indicates an expected call of DenyRequest.
-func (mr *MockUserMockRecorder) DenyRequest(trainerId, requestId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) DenyRequest(actor, trainerId, requestId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DenyRequest", reflect.TypeOf((*MockUser)(nil).DenyRequest), trainerId, requestId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DenyRequest", reflect.TypeOf((*MockUser)(nil).DenyRequest), actor, trainerId, requestId)
 }
 
 // EndPartnershipWithTrainer mocks base method.
-func (m *MockUser) EndPartnershipWithTrainer(trainerId, userId int64) (int64, error) {
+func (m *MockUser) EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EndPartnershipWithTrainer", trainerId, userId)
+	ret := m.ctrl.Call(m, "EndPartnershipWithTrainer", actor, trainerId, userId)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EndPartnershipWithTrainer indicates an expected call of EndPartnershipWithTrainer.
-func (mr *MockUserMockRecorder) EndPartnershipWithTrainer(trainerId, userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) EndPartnershipWithTrainer(actor, trainerId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndPartnershipWithTrainer", reflect.TypeOf((*MockUser)(nil).EndPartnershipWithTrainer), trainerId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndPartnershipWithTrainer", reflect.TypeOf((*MockUser)(nil).EndPartnershipWithTrainer), actor, trainerId, userId)
 }
 
 // EndPartnershipWithUser mocks base method.
-func (m *MockUser) EndPartnershipWithUser(trainerId, userId int64) (int64, error) {
+func (m *MockUser) EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EndPartnershipWithUser", trainerId, userId)
+	ret := m.ctrl.Call(m, "EndPartnershipWithUser", actor, trainerId, userId)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EndPartnershipWithUser indicates an expected call of EndPartnershipWithUser.
-func (mr *MockUserMockRecorder) EndPartnershipWithUser(trainerId, userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) EndPartnershipWithUser(actor, trainerId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndPartnershipWithUser", reflect.TypeOf((*MockUser)(nil).EndPartnershipWithUser), trainerId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndPartnershipWithUser", reflect.TypeOf((*MockUser)(nil).EndPartnershipWithUser), actor, trainerId, userId)
 }
 
 // FormatUpdateWorkout mocks base method.
-func (m *MockUser) FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId, userId int64) error {
+func (m *MockUser) FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId, userId, version int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FormatUpdateWorkout", input, workoutId, userId)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FormatUpdateWorkout", input, workoutId, userId, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FormatUpdateWorkout indicates an expected call of FormatUpdateWorkout.
-func (mr *MockUserMockRecorder) FormatUpdateWorkout(input, workoutId, userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) FormatUpdateWorkout(input, workoutId, userId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FormatUpdateWorkout", reflect.TypeOf((*MockUser)(nil).FormatUpdateWorkout), input, workoutId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FormatUpdateWorkout", reflect.TypeOf((*MockUser)(nil).FormatUpdateWorkout), input, workoutId, userId, version)
 }
 
 // GetPasswordHash mocks base method.
@@ -441,18 +548,18 @@ func (mr *MockUserMockRecorder) GetUserPartnerships(userId interface{}) *gomock.
 }
 
 // GetUserWorkouts mocks base method.
-func (m *MockUser) GetUserWorkouts(id int64) ([]*entity.Workout, error) {
+func (m *MockUser) GetUserWorkouts(id int64, date time.Time) ([]*entity.Workout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserWorkouts", id)
+	ret := m.ctrl.Call(m, "GetUserWorkouts", id, date)
 	ret0, _ := ret[0].([]*entity.Workout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserWorkouts indicates an expected call of GetUserWorkouts.
-func (mr *MockUserMockRecorder) GetUserWorkouts(id interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) GetUserWorkouts(id, date interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkouts", reflect.TypeOf((*MockUser)(nil).GetUserWorkouts), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkouts", reflect.TypeOf((*MockUser)(nil).GetUserWorkouts), id, date)
 }
 
 // GetWorkoutById mocks base method.
@@ -515,6 +622,36 @@ func (mr *MockUserMockRecorder) ParseToken(token interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockUser)(nil).ParseToken), token)
 }
 
+// PatchProfile mocks base method.
+func (m *MockUser) PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchProfile", userId, patch, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchProfile indicates an expected call of PatchProfile.
+func (mr *MockUserMockRecorder) PatchProfile(userId, patch, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchProfile", reflect.TypeOf((*MockUser)(nil).PatchProfile), userId, patch, version)
+}
+
+// PatchWorkout mocks base method.
+func (m *MockUser) PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchWorkout", workoutId, userId, patch, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchWorkout indicates an expected call of PatchWorkout.
+func (mr *MockUserMockRecorder) PatchWorkout(workoutId, userId, patch, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchWorkout", reflect.TypeOf((*MockUser)(nil).PatchWorkout), workoutId, userId, patch, version)
+}
+
 // SendRequestToTrainer mocks base method.
 func (m *MockUser) SendRequestToTrainer(trainerId, userId int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -531,10 +668,10 @@ func (mr *MockUserMockRecorder) SendRequestToTrainer(trainerId, userId interface
 }
 
 // SignIn mocks base method.
-func (m *MockUser) SignIn(email, passwordHash string, role entity.Role) (string, error) {
+func (m *MockUser) SignIn(email, passwordHash string, role entity.Role) (*entity.SignInResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignIn", email, passwordHash, role)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*entity.SignInResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -560,16 +697,1457 @@ func (mr *MockUserMockRecorder) SignUp(user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockUser)(nil).SignUp), user)
 }
 
+// UpdateProfile mocks base method.
+func (m *MockUser) UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", userId, update, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserMockRecorder) UpdateProfile(userId, update, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUser)(nil).UpdateProfile), userId, update, version)
+}
+
 // UpdateWorkout mocks base method.
-func (m *MockUser) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout) error {
+func (m *MockUser) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWorkout", workoutId, userId, update)
+	ret := m.ctrl.Call(m, "UpdateWorkout", workoutId, userId, update, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateWorkout indicates an expected call of UpdateWorkout.
-func (mr *MockUserMockRecorder) UpdateWorkout(workoutId, userId, update interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) UpdateWorkout(workoutId, userId, update, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkout", reflect.TypeOf((*MockUser)(nil).UpdateWorkout), workoutId, userId, update, version)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockUser) VerifyMFA(mfaToken, code string) (*entity.SignInResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", mfaToken, code)
+	ret0, _ := ret[0].(*entity.SignInResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockUserMockRecorder) VerifyMFA(mfaToken, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockUser)(nil).VerifyMFA), mfaToken, code)
+}
+
+// MockBulk is a mock of Bulk interface.
+type MockBulk struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkMockRecorder
+}
+
+// MockBulkMockRecorder is the mock recorder for MockBulk.
+type MockBulkMockRecorder struct {
+	mock *MockBulk
+}
+
+// NewMockBulk creates a new mock instance.
+func NewMockBulk(ctrl *gomock.Controller) *MockBulk {
+	mock := &MockBulk{ctrl: ctrl}
+	mock.recorder = &MockBulkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulk) EXPECT() *MockBulkMockRecorder {
+	return m.recorder
+}
+
+// CreateWorkouts mocks base method.
+func (m *MockBulk) CreateWorkouts(trainerId int64, input *entity.BulkCreateInput) (*entity.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkouts", trainerId, input)
+	ret0, _ := ret[0].(*entity.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkouts indicates an expected call of CreateWorkouts.
+func (mr *MockBulkMockRecorder) CreateWorkouts(trainerId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkouts", reflect.TypeOf((*MockBulk)(nil).CreateWorkouts), trainerId, input)
+}
+
+// DeleteWorkouts mocks base method.
+func (m *MockBulk) DeleteWorkouts(trainerId int64, input *entity.BulkDeleteInput) (*entity.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkouts", trainerId, input)
+	ret0, _ := ret[0].(*entity.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWorkouts indicates an expected call of DeleteWorkouts.
+func (mr *MockBulkMockRecorder) DeleteWorkouts(trainerId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkouts", reflect.TypeOf((*MockBulk)(nil).DeleteWorkouts), trainerId, input)
+}
+
+// PatchWorkouts mocks base method.
+func (m *MockBulk) PatchWorkouts(trainerId int64, input *entity.BulkPatchInput) (*entity.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchWorkouts", trainerId, input)
+	ret0, _ := ret[0].(*entity.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchWorkouts indicates an expected call of PatchWorkouts.
+func (mr *MockBulkMockRecorder) PatchWorkouts(trainerId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchWorkouts", reflect.TypeOf((*MockBulk)(nil).PatchWorkouts), trainerId, input)
+}
+
+// ShiftWorkouts mocks base method.
+func (m *MockBulk) ShiftWorkouts(trainerId int64, input *entity.BulkShiftInput) (*entity.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShiftWorkouts", trainerId, input)
+	ret0, _ := ret[0].(*entity.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShiftWorkouts indicates an expected call of ShiftWorkouts.
+func (mr *MockBulkMockRecorder) ShiftWorkouts(trainerId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShiftWorkouts", reflect.TypeOf((*MockBulk)(nil).ShiftWorkouts), trainerId, input)
+}
+
+// MockSchedule is a mock of Schedule interface.
+type MockSchedule struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleMockRecorder
+}
+
+// MockScheduleMockRecorder is the mock recorder for MockSchedule.
+type MockScheduleMockRecorder struct {
+	mock *MockSchedule
+}
+
+// NewMockSchedule creates a new mock instance.
+func NewMockSchedule(ctrl *gomock.Controller) *MockSchedule {
+	mock := &MockSchedule{ctrl: ctrl}
+	mock.recorder = &MockScheduleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedule) EXPECT() *MockScheduleMockRecorder {
+	return m.recorder
+}
+
+// BookSlot mocks base method.
+func (m *MockSchedule) BookSlot(userId int64, input *entity.BookingInput) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookSlot", userId, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookSlot indicates an expected call of BookSlot.
+func (mr *MockScheduleMockRecorder) BookSlot(userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookSlot", reflect.TypeOf((*MockSchedule)(nil).BookSlot), userId, input)
+}
+
+// CancelBooking mocks base method.
+func (m *MockSchedule) CancelBooking(bookingId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBooking", bookingId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelBooking indicates an expected call of CancelBooking.
+func (mr *MockScheduleMockRecorder) CancelBooking(bookingId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBooking", reflect.TypeOf((*MockSchedule)(nil).CancelBooking), bookingId, userId)
+}
+
+// CreateAvailabilityException mocks base method.
+func (m *MockSchedule) CreateAvailabilityException(exception *entity.AvailabilityException) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAvailabilityException", exception)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAvailabilityException indicates an expected call of CreateAvailabilityException.
+func (mr *MockScheduleMockRecorder) CreateAvailabilityException(exception interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAvailabilityException", reflect.TypeOf((*MockSchedule)(nil).CreateAvailabilityException), exception)
+}
+
+// CreateAvailabilitySlot mocks base method.
+func (m *MockSchedule) CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAvailabilitySlot", slot)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAvailabilitySlot indicates an expected call of CreateAvailabilitySlot.
+func (mr *MockScheduleMockRecorder) CreateAvailabilitySlot(slot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAvailabilitySlot", reflect.TypeOf((*MockSchedule)(nil).CreateAvailabilitySlot), slot)
+}
+
+// DeleteAvailabilityException mocks base method.
+func (m *MockSchedule) DeleteAvailabilityException(trainerId, exceptionId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilityException", trainerId, exceptionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailabilityException indicates an expected call of DeleteAvailabilityException.
+func (mr *MockScheduleMockRecorder) DeleteAvailabilityException(trainerId, exceptionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilityException", reflect.TypeOf((*MockSchedule)(nil).DeleteAvailabilityException), trainerId, exceptionId)
+}
+
+// DeleteAvailabilitySlot mocks base method.
+func (m *MockSchedule) DeleteAvailabilitySlot(trainerId, slotId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilitySlot", trainerId, slotId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailabilitySlot indicates an expected call of DeleteAvailabilitySlot.
+func (mr *MockScheduleMockRecorder) DeleteAvailabilitySlot(trainerId, slotId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilitySlot", reflect.TypeOf((*MockSchedule)(nil).DeleteAvailabilitySlot), trainerId, slotId)
+}
+
+// GetAvailability mocks base method.
+func (m *MockSchedule) GetAvailability(trainerId int64) (*entity.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailability", trainerId)
+	ret0, _ := ret[0].(*entity.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailability indicates an expected call of GetAvailability.
+func (mr *MockScheduleMockRecorder) GetAvailability(trainerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailability", reflect.TypeOf((*MockSchedule)(nil).GetAvailability), trainerId)
+}
+
+// GetFreeSlots mocks base method.
+func (m *MockSchedule) GetFreeSlots(trainerId int64, from, to time.Time) ([]*entity.FreeSlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeSlots", trainerId, from, to)
+	ret0, _ := ret[0].([]*entity.FreeSlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeSlots indicates an expected call of GetFreeSlots.
+func (mr *MockScheduleMockRecorder) GetFreeSlots(trainerId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeSlots", reflect.TypeOf((*MockSchedule)(nil).GetFreeSlots), trainerId, from, to)
+}
+
+// GetTrainerBookings mocks base method.
+func (m *MockSchedule) GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrainerBookings", trainerId, from, to)
+	ret0, _ := ret[0].([]*entity.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrainerBookings indicates an expected call of GetTrainerBookings.
+func (mr *MockScheduleMockRecorder) GetTrainerBookings(trainerId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrainerBookings", reflect.TypeOf((*MockSchedule)(nil).GetTrainerBookings), trainerId, from, to)
+}
+
+// GetUserBookings mocks base method.
+func (m *MockSchedule) GetUserBookings(userId int64) ([]*entity.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBookings", userId)
+	ret0, _ := ret[0].([]*entity.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBookings indicates an expected call of GetUserBookings.
+func (mr *MockScheduleMockRecorder) GetUserBookings(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBookings", reflect.TypeOf((*MockSchedule)(nil).GetUserBookings), userId)
+}
+
+// MockMessage is a mock of Message interface.
+type MockMessage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageMockRecorder
+}
+
+// MockMessageMockRecorder is the mock recorder for MockMessage.
+type MockMessageMockRecorder struct {
+	mock *MockMessage
+}
+
+// NewMockMessage creates a new mock instance.
+func NewMockMessage(ctrl *gomock.Controller) *MockMessage {
+	mock := &MockMessage{ctrl: ctrl}
+	mock.recorder = &MockMessageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessage) EXPECT() *MockMessageMockRecorder {
+	return m.recorder
+}
+
+// GetMessages mocks base method.
+func (m *MockMessage) GetMessages(partnershipId, beforeId int64, limit int) (*entity.MessagePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", partnershipId, beforeId, limit)
+	ret0, _ := ret[0].(*entity.MessagePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockMessageMockRecorder) GetMessages(partnershipId, beforeId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockMessage)(nil).GetMessages), partnershipId, beforeId, limit)
+}
+
+// GetTrainerConversation mocks base method.
+func (m *MockMessage) GetTrainerConversation(trainerId, userId int64) (*entity.Partnership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrainerConversation", trainerId, userId)
+	ret0, _ := ret[0].(*entity.Partnership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrainerConversation indicates an expected call of GetTrainerConversation.
+func (mr *MockMessageMockRecorder) GetTrainerConversation(trainerId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrainerConversation", reflect.TypeOf((*MockMessage)(nil).GetTrainerConversation), trainerId, userId)
+}
+
+// GetUserConversation mocks base method.
+func (m *MockMessage) GetUserConversation(userId, partnershipId int64) (*entity.Partnership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserConversation", userId, partnershipId)
+	ret0, _ := ret[0].(*entity.Partnership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserConversation indicates an expected call of GetUserConversation.
+func (mr *MockMessageMockRecorder) GetUserConversation(userId, partnershipId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserConversation", reflect.TypeOf((*MockMessage)(nil).GetUserConversation), userId, partnershipId)
+}
+
+// MarkMessagesRead mocks base method.
+func (m *MockMessage) MarkMessagesRead(partnershipId, readerId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMessagesRead", partnershipId, readerId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkMessagesRead indicates an expected call of MarkMessagesRead.
+func (mr *MockMessageMockRecorder) MarkMessagesRead(partnershipId, readerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessagesRead", reflect.TypeOf((*MockMessage)(nil).MarkMessagesRead), partnershipId, readerId)
+}
+
+// SendMessage mocks base method.
+func (m *MockMessage) SendMessage(partnership *entity.Partnership, senderId int64, body string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", partnership, senderId, body)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockMessageMockRecorder) SendMessage(partnership, senderId, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessage)(nil).SendMessage), partnership, senderId, body)
+}
+
+// MockComment is a mock of Comment interface.
+type MockComment struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentMockRecorder
+}
+
+// MockCommentMockRecorder is the mock recorder for MockComment.
+type MockCommentMockRecorder struct {
+	mock *MockComment
+}
+
+// NewMockComment creates a new mock instance.
+func NewMockComment(ctrl *gomock.Controller) *MockComment {
+	mock := &MockComment{ctrl: ctrl}
+	mock.recorder = &MockCommentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockComment) EXPECT() *MockCommentMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockComment) CreateComment(workoutId, userId int64, body string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", workoutId, userId, body)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentMockRecorder) CreateComment(workoutId, userId, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockComment)(nil).CreateComment), workoutId, userId, body)
+}
+
+// DeleteComment mocks base method.
+func (m *MockComment) DeleteComment(workoutId, commentId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", workoutId, commentId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentMockRecorder) DeleteComment(workoutId, commentId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockComment)(nil).DeleteComment), workoutId, commentId, userId)
+}
+
+// GetComments mocks base method.
+func (m *MockComment) GetComments(workoutId, userId int64) ([]*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", workoutId, userId)
+	ret0, _ := ret[0].([]*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockCommentMockRecorder) GetComments(workoutId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockComment)(nil).GetComments), workoutId, userId)
+}
+
+// ResolveComment mocks base method.
+func (m *MockComment) ResolveComment(workoutId, commentId, userId int64, resolved bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveComment", workoutId, commentId, userId, resolved)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveComment indicates an expected call of ResolveComment.
+func (mr *MockCommentMockRecorder) ResolveComment(workoutId, commentId, userId, resolved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveComment", reflect.TypeOf((*MockComment)(nil).ResolveComment), workoutId, commentId, userId, resolved)
+}
+
+// UpdateComment mocks base method.
+func (m *MockComment) UpdateComment(workoutId, commentId, userId int64, body string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", workoutId, commentId, userId, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockCommentMockRecorder) UpdateComment(workoutId, commentId, userId, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockComment)(nil).UpdateComment), workoutId, commentId, userId, body)
+}
+
+// MockGroup is a mock of Group interface.
+type MockGroup struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupMockRecorder
+}
+
+// MockGroupMockRecorder is the mock recorder for MockGroup.
+type MockGroupMockRecorder struct {
+	mock *MockGroup
+}
+
+// NewMockGroup creates a new mock instance.
+func NewMockGroup(ctrl *gomock.Controller) *MockGroup {
+	mock := &MockGroup{ctrl: ctrl}
+	mock.recorder = &MockGroupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroup) EXPECT() *MockGroupMockRecorder {
+	return m.recorder
+}
+
+// AddGroupMember mocks base method.
+func (m *MockGroup) AddGroupMember(trainerId, groupId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupMember", trainerId, groupId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGroupMember indicates an expected call of AddGroupMember.
+func (mr *MockGroupMockRecorder) AddGroupMember(trainerId, groupId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupMember", reflect.TypeOf((*MockGroup)(nil).AddGroupMember), trainerId, groupId, userId)
+}
+
+// AddGroupWorkoutParticipant mocks base method.
+func (m *MockGroup) AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupWorkoutParticipant", trainerId, groupWorkoutId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGroupWorkoutParticipant indicates an expected call of AddGroupWorkoutParticipant.
+func (mr *MockGroupMockRecorder) AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupWorkoutParticipant", reflect.TypeOf((*MockGroup)(nil).AddGroupWorkoutParticipant), trainerId, groupWorkoutId, userId)
+}
+
+// CreateGroup mocks base method.
+func (m *MockGroup) CreateGroup(group *entity.ClientGroup) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", group)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockGroupMockRecorder) CreateGroup(group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockGroup)(nil).CreateGroup), group)
+}
+
+// CreateGroupWorkout mocks base method.
+func (m *MockGroup) CreateGroupWorkout(trainerId int64, input *entity.GroupWorkoutInput) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupWorkout", trainerId, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroupWorkout indicates an expected call of CreateGroupWorkout.
+func (mr *MockGroupMockRecorder) CreateGroupWorkout(trainerId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupWorkout", reflect.TypeOf((*MockGroup)(nil).CreateGroupWorkout), trainerId, input)
+}
+
+// DeleteGroup mocks base method.
+func (m *MockGroup) DeleteGroup(trainerId, groupId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroup", trainerId, groupId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockGroupMockRecorder) DeleteGroup(trainerId, groupId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockGroup)(nil).DeleteGroup), trainerId, groupId)
+}
+
+// DeleteGroupWorkout mocks base method.
+func (m *MockGroup) DeleteGroupWorkout(trainerId, groupWorkoutId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupWorkout", trainerId, groupWorkoutId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupWorkout indicates an expected call of DeleteGroupWorkout.
+func (mr *MockGroupMockRecorder) DeleteGroupWorkout(trainerId, groupWorkoutId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupWorkout", reflect.TypeOf((*MockGroup)(nil).DeleteGroupWorkout), trainerId, groupWorkoutId)
+}
+
+// GetGroupById mocks base method.
+func (m *MockGroup) GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupById", trainerId, groupId)
+	ret0, _ := ret[0].(*entity.ClientGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupById indicates an expected call of GetGroupById.
+func (mr *MockGroupMockRecorder) GetGroupById(trainerId, groupId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupById", reflect.TypeOf((*MockGroup)(nil).GetGroupById), trainerId, groupId)
+}
+
+// GetGroupWorkoutById mocks base method.
+func (m *MockGroup) GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupWorkoutById", trainerId, groupWorkoutId)
+	ret0, _ := ret[0].(*entity.GroupWorkout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupWorkoutById indicates an expected call of GetGroupWorkoutById.
+func (mr *MockGroupMockRecorder) GetGroupWorkoutById(trainerId, groupWorkoutId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupWorkoutById", reflect.TypeOf((*MockGroup)(nil).GetGroupWorkoutById), trainerId, groupWorkoutId)
+}
+
+// GetTrainerGroupWorkouts mocks base method.
+func (m *MockGroup) GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrainerGroupWorkouts", trainerId)
+	ret0, _ := ret[0].([]*entity.GroupWorkout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrainerGroupWorkouts indicates an expected call of GetTrainerGroupWorkouts.
+func (mr *MockGroupMockRecorder) GetTrainerGroupWorkouts(trainerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrainerGroupWorkouts", reflect.TypeOf((*MockGroup)(nil).GetTrainerGroupWorkouts), trainerId)
+}
+
+// GetTrainerGroups mocks base method.
+func (m *MockGroup) GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrainerGroups", trainerId)
+	ret0, _ := ret[0].([]*entity.ClientGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrainerGroups indicates an expected call of GetTrainerGroups.
+func (mr *MockGroupMockRecorder) GetTrainerGroups(trainerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrainerGroups", reflect.TypeOf((*MockGroup)(nil).GetTrainerGroups), trainerId)
+}
+
+// RemoveGroupMember mocks base method.
+func (m *MockGroup) RemoveGroupMember(trainerId, groupId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveGroupMember", trainerId, groupId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveGroupMember indicates an expected call of RemoveGroupMember.
+func (mr *MockGroupMockRecorder) RemoveGroupMember(trainerId, groupId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroupMember", reflect.TypeOf((*MockGroup)(nil).RemoveGroupMember), trainerId, groupId, userId)
+}
+
+// RemoveGroupWorkoutParticipant mocks base method.
+func (m *MockGroup) RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveGroupWorkoutParticipant", trainerId, groupWorkoutId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveGroupWorkoutParticipant indicates an expected call of RemoveGroupWorkoutParticipant.
+func (mr *MockGroupMockRecorder) RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroupWorkoutParticipant", reflect.TypeOf((*MockGroup)(nil).RemoveGroupWorkoutParticipant), trainerId, groupWorkoutId, userId)
+}
+
+// SetAttendance mocks base method.
+func (m *MockGroup) SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAttendance", trainerId, groupWorkoutId, userId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAttendance indicates an expected call of SetAttendance.
+func (mr *MockGroupMockRecorder) SetAttendance(trainerId, groupWorkoutId, userId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAttendance", reflect.TypeOf((*MockGroup)(nil).SetAttendance), trainerId, groupWorkoutId, userId, status)
+}
+
+// MockEvent is a mock of Event interface.
+type MockEvent struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventMockRecorder
+}
+
+// MockEventMockRecorder is the mock recorder for MockEvent.
+type MockEventMockRecorder struct {
+	mock *MockEvent
+}
+
+// NewMockEvent creates a new mock instance.
+func NewMockEvent(ctrl *gomock.Controller) *MockEvent {
+	mock := &MockEvent{ctrl: ctrl}
+	mock.recorder = &MockEventMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvent) EXPECT() *MockEventMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEvent) Subscribe(userId int64) (<-chan *entity.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userId)
+	ret0, _ := ret[0].(<-chan *entity.Event)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventMockRecorder) Subscribe(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEvent)(nil).Subscribe), userId)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// FailStaleExports mocks base method.
+func (m *MockExport) FailStaleExports(createdBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleExports", createdBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleExports indicates an expected call of FailStaleExports.
+func (mr *MockExportMockRecorder) FailStaleExports(createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleExports", reflect.TypeOf((*MockExport)(nil).FailStaleExports), createdBefore)
+}
+
+// GetCurrentExport mocks base method.
+func (m *MockExport) GetCurrentExport(userId int64) (*entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentExport", userId)
+	ret0, _ := ret[0].(*entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentExport indicates an expected call of GetCurrentExport.
+func (mr *MockExportMockRecorder) GetCurrentExport(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExport", reflect.TypeOf((*MockExport)(nil).GetCurrentExport), userId)
+}
+
+// GetExport mocks base method.
+func (m *MockExport) GetExport(userId, exportId int64) (*entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", userId, exportId)
+	ret0, _ := ret[0].(*entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockExportMockRecorder) GetExport(userId, exportId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockExport)(nil).GetExport), userId, exportId)
+}
+
+// GetExportArchive mocks base method.
+func (m *MockExport) GetExportArchive(token string) (*entity.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportArchive", token)
+	ret0, _ := ret[0].(*entity.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportArchive indicates an expected call of GetExportArchive.
+func (mr *MockExportMockRecorder) GetExportArchive(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportArchive", reflect.TypeOf((*MockExport)(nil).GetExportArchive), token)
+}
+
+// PurgeExpiredExports mocks base method.
+func (m *MockExport) PurgeExpiredExports(expiredBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredExports", expiredBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredExports indicates an expected call of PurgeExpiredExports.
+func (mr *MockExportMockRecorder) PurgeExpiredExports(expiredBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredExports", reflect.TypeOf((*MockExport)(nil).PurgeExpiredExports), expiredBefore)
+}
+
+// RequestExport mocks base method.
+func (m *MockExport) RequestExport(actor *entity.Actor, userId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", actor, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockExportMockRecorder) RequestExport(actor, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockExport)(nil).RequestExport), actor, userId)
+}
+
+// MockAccount is a mock of Account interface.
+type MockAccount struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountMockRecorder
+}
+
+// MockAccountMockRecorder is the mock recorder for MockAccount.
+type MockAccountMockRecorder struct {
+	mock *MockAccount
+}
+
+// NewMockAccount creates a new mock instance.
+func NewMockAccount(ctrl *gomock.Controller) *MockAccount {
+	mock := &MockAccount{ctrl: ctrl}
+	mock.recorder = &MockAccountMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccount) EXPECT() *MockAccountMockRecorder {
+	return m.recorder
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAccount) ForgotPassword(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAccountMockRecorder) ForgotPassword(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAccount)(nil).ForgotPassword), email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAccount) ResetPassword(token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAccountMockRecorder) ResetPassword(token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAccount)(nil).ResetPassword), token, password)
+}
+
+// SendVerification mocks base method.
+func (m *MockAccount) SendVerification(userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockAccountMockRecorder) SendVerification(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockAccount)(nil).SendVerification), userId)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAccount) VerifyEmail(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAccountMockRecorder) VerifyEmail(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAccount)(nil).VerifyEmail), token)
+}
+
+// MockApplication is a mock of Application interface.
+type MockApplication struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationMockRecorder
+}
+
+// MockApplicationMockRecorder is the mock recorder for MockApplication.
+type MockApplicationMockRecorder struct {
+	mock *MockApplication
+}
+
+// NewMockApplication creates a new mock instance.
+func NewMockApplication(ctrl *gomock.Controller) *MockApplication {
+	mock := &MockApplication{ctrl: ctrl}
+	mock.recorder = &MockApplicationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplication) EXPECT() *MockApplicationMockRecorder {
+	return m.recorder
+}
+
+// AddCertificate mocks base method.
+func (m *MockApplication) AddCertificate(actor *entity.Actor, userId int64, cert *entity.Certificate) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCertificate", actor, userId, cert)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCertificate indicates an expected call of AddCertificate.
+func (mr *MockApplicationMockRecorder) AddCertificate(actor, userId, cert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCertificate", reflect.TypeOf((*MockApplication)(nil).AddCertificate), actor, userId, cert)
+}
+
+// GetApplicationById mocks base method.
+func (m *MockApplication) GetApplicationById(appId int64) (*entity.TrainerApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationById", appId)
+	ret0, _ := ret[0].(*entity.TrainerApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationById indicates an expected call of GetApplicationById.
+func (mr *MockApplicationMockRecorder) GetApplicationById(appId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationById", reflect.TypeOf((*MockApplication)(nil).GetApplicationById), appId)
+}
+
+// GetApplications mocks base method.
+func (m *MockApplication) GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplications", status)
+	ret0, _ := ret[0].([]*entity.TrainerApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplications indicates an expected call of GetApplications.
+func (mr *MockApplicationMockRecorder) GetApplications(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplications", reflect.TypeOf((*MockApplication)(nil).GetApplications), status)
+}
+
+// GetCertificate mocks base method.
+func (m *MockApplication) GetCertificate(appId, certificateId int64) (*entity.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertificate", appId, certificateId)
+	ret0, _ := ret[0].(*entity.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCertificate indicates an expected call of GetCertificate.
+func (mr *MockApplicationMockRecorder) GetCertificate(appId, certificateId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertificate", reflect.TypeOf((*MockApplication)(nil).GetCertificate), appId, certificateId)
+}
+
+// GetUserApplications mocks base method.
+func (m *MockApplication) GetUserApplications(userId int64) ([]*entity.TrainerApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserApplications", userId)
+	ret0, _ := ret[0].([]*entity.TrainerApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserApplications indicates an expected call of GetUserApplications.
+func (mr *MockApplicationMockRecorder) GetUserApplications(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserApplications", reflect.TypeOf((*MockApplication)(nil).GetUserApplications), userId)
+}
+
+// ReviewApplication mocks base method.
+func (m *MockApplication) ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus, note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewApplication", actor, appId, status, note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewApplication indicates an expected call of ReviewApplication.
+func (mr *MockApplicationMockRecorder) ReviewApplication(actor, appId, status, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewApplication", reflect.TypeOf((*MockApplication)(nil).ReviewApplication), actor, appId, status, note)
+}
+
+// SubmitApplication mocks base method.
+func (m *MockApplication) SubmitApplication(actor *entity.Actor, userId int64, input *entity.TrainerApplicationInput) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitApplication", actor, userId, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitApplication indicates an expected call of SubmitApplication.
+func (mr *MockApplicationMockRecorder) SubmitApplication(actor, userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitApplication", reflect.TypeOf((*MockApplication)(nil).SubmitApplication), actor, userId, input)
+}
+
+// UpdateApplication mocks base method.
+func (m *MockApplication) UpdateApplication(actor *entity.Actor, userId, appId int64, input *entity.TrainerApplicationInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApplication", actor, userId, appId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApplication indicates an expected call of UpdateApplication.
+func (mr *MockApplicationMockRecorder) UpdateApplication(actor, userId, appId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplication", reflect.TypeOf((*MockApplication)(nil).UpdateApplication), actor, userId, appId, input)
+}
+
+// MockLockout is a mock of Lockout interface.
+type MockLockout struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutMockRecorder
+}
+
+// MockLockoutMockRecorder is the mock recorder for MockLockout.
+type MockLockoutMockRecorder struct {
+	mock *MockLockout
+}
+
+// NewMockLockout creates a new mock instance.
+func NewMockLockout(ctrl *gomock.Controller) *MockLockout {
+	mock := &MockLockout{ctrl: ctrl}
+	mock.recorder = &MockLockoutMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockout) EXPECT() *MockLockoutMockRecorder {
+	return m.recorder
+}
+
+// BeginSignIn mocks base method.
+func (m *MockLockout) BeginSignIn(actor *entity.Actor, scope entity.ActorType, login string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginSignIn", actor, scope, login)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginSignIn indicates an expected call of BeginSignIn.
+func (mr *MockLockoutMockRecorder) BeginSignIn(actor, scope, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginSignIn", reflect.TypeOf((*MockLockout)(nil).BeginSignIn), actor, scope, login)
+}
+
+// CancelSignIn mocks base method.
+func (m *MockLockout) CancelSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSignIn", actor, scope, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSignIn indicates an expected call of CancelSignIn.
+func (mr *MockLockoutMockRecorder) CancelSignIn(actor, scope, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSignIn", reflect.TypeOf((*MockLockout)(nil).CancelSignIn), actor, scope, login)
+}
+
+// FailSignIn mocks base method.
+func (m *MockLockout) FailSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailSignIn", actor, scope, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailSignIn indicates an expected call of FailSignIn.
+func (mr *MockLockoutMockRecorder) FailSignIn(actor, scope, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailSignIn", reflect.TypeOf((*MockLockout)(nil).FailSignIn), actor, scope, login)
+}
+
+// PurgeAttempts mocks base method.
+func (m *MockLockout) PurgeAttempts(lastFailureBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeAttempts", lastFailureBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeAttempts indicates an expected call of PurgeAttempts.
+func (mr *MockLockoutMockRecorder) PurgeAttempts(lastFailureBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAttempts", reflect.TypeOf((*MockLockout)(nil).PurgeAttempts), lastFailureBefore)
+}
+
+// SucceedSignIn mocks base method.
+func (m *MockLockout) SucceedSignIn(actor *entity.Actor, scope entity.ActorType, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SucceedSignIn", actor, scope, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SucceedSignIn indicates an expected call of SucceedSignIn.
+func (mr *MockLockoutMockRecorder) SucceedSignIn(actor, scope, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SucceedSignIn", reflect.TypeOf((*MockLockout)(nil).SucceedSignIn), actor, scope, login)
+}
+
+// Unlock mocks base method.
+func (m *MockLockout) Unlock(actor *entity.Actor, input *entity.UnlockInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", actor, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLockoutMockRecorder) Unlock(actor, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLockout)(nil).Unlock), actor, input)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// BeginRequest mocks base method.
+func (m *MockIdempotency) BeginRequest(userId int64, key, fingerprint string) (*entity.IdempotencyRecord, *entity.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRequest", userId, key, fingerprint)
+	ret0, _ := ret[0].(*entity.IdempotencyRecord)
+	ret1, _ := ret[1].(*entity.IdempotencyRecord)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BeginRequest indicates an expected call of BeginRequest.
+func (mr *MockIdempotencyMockRecorder) BeginRequest(userId, key, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRequest", reflect.TypeOf((*MockIdempotency)(nil).BeginRequest), userId, key, fingerprint)
+}
+
+// CompleteRequest mocks base method.
+func (m *MockIdempotency) CompleteRequest(reservation *entity.IdempotencyRecord, status int, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRequest", reservation, status, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRequest indicates an expected call of CompleteRequest.
+func (mr *MockIdempotencyMockRecorder) CompleteRequest(reservation, status, contentType, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRequest", reflect.TypeOf((*MockIdempotency)(nil).CompleteRequest), reservation, status, contentType, body)
+}
+
+// PurgeKeys mocks base method.
+func (m *MockIdempotency) PurgeKeys(createdBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeKeys", createdBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeKeys indicates an expected call of PurgeKeys.
+func (mr *MockIdempotencyMockRecorder) PurgeKeys(createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeKeys", reflect.TypeOf((*MockIdempotency)(nil).PurgeKeys), createdBefore)
+}
+
+// ReleaseRequest mocks base method.
+func (m *MockIdempotency) ReleaseRequest(reservation *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRequest", reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRequest indicates an expected call of ReleaseRequest.
+func (mr *MockIdempotencyMockRecorder) ReleaseRequest(reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRequest", reflect.TypeOf((*MockIdempotency)(nil).ReleaseRequest), reservation)
+}
+
+// MockMFA is a mock of MFA interface.
+type MockMFA struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAMockRecorder
+}
+
+// MockMFAMockRecorder is the mock recorder for MockMFA.
+type MockMFAMockRecorder struct {
+	mock *MockMFA
+}
+
+// NewMockMFA creates a new mock instance.
+func NewMockMFA(ctrl *gomock.Controller) *MockMFA {
+	mock := &MockMFA{ctrl: ctrl}
+	mock.recorder = &MockMFAMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFA) EXPECT() *MockMFAMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockMFA) Confirm(subject entity.ActorType, subjectId int64, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", subject, subjectId, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockMFAMockRecorder) Confirm(subject, subjectId, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockMFA)(nil).Confirm), subject, subjectId, code)
+}
+
+// Disable mocks base method.
+func (m *MockMFA) Disable(subject entity.ActorType, subjectId int64, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", subject, subjectId, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockMFAMockRecorder) Disable(subject, subjectId, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockMFA)(nil).Disable), subject, subjectId, code)
+}
+
+// Enroll mocks base method.
+func (m *MockMFA) Enroll(subject entity.ActorType, subjectId int64) (*entity.MFAEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", subject, subjectId)
+	ret0, _ := ret[0].(*entity.MFAEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockMFAMockRecorder) Enroll(subject, subjectId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockMFA)(nil).Enroll), subject, subjectId)
+}
+
+// EnrollChallenge mocks base method.
+func (m *MockMFA) EnrollChallenge(mfaToken string) (*entity.MFAEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollChallenge", mfaToken)
+	ret0, _ := ret[0].(*entity.MFAEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollChallenge indicates an expected call of EnrollChallenge.
+func (mr *MockMFAMockRecorder) EnrollChallenge(mfaToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollChallenge", reflect.TypeOf((*MockMFA)(nil).EnrollChallenge), mfaToken)
+}
+
+// GetPolicies mocks base method.
+func (m *MockMFA) GetPolicies() ([]*entity.MFAPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicies")
+	ret0, _ := ret[0].([]*entity.MFAPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicies indicates an expected call of GetPolicies.
+func (mr *MockMFAMockRecorder) GetPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicies", reflect.TypeOf((*MockMFA)(nil).GetPolicies))
+}
+
+// SetPolicy mocks base method.
+func (m *MockMFA) SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPolicy", actor, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPolicy indicates an expected call of SetPolicy.
+func (mr *MockMFAMockRecorder) SetPolicy(actor, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPolicy", reflect.TypeOf((*MockMFA)(nil).SetPolicy), actor, policy)
+}
+
+// MockIdentity is a mock of Identity interface.
+type MockIdentity struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityMockRecorder
+}
+
+// MockIdentityMockRecorder is the mock recorder for MockIdentity.
+type MockIdentityMockRecorder struct {
+	mock *MockIdentity
+}
+
+// NewMockIdentity creates a new mock instance.
+func NewMockIdentity(ctrl *gomock.Controller) *MockIdentity {
+	mock := &MockIdentity{ctrl: ctrl}
+	mock.recorder = &MockIdentityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentity) EXPECT() *MockIdentityMockRecorder {
+	return m.recorder
+}
+
+// GetIdentities mocks base method.
+func (m *MockIdentity) GetIdentities(userId int64) ([]*entity.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentities", userId)
+	ret0, _ := ret[0].([]*entity.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentities indicates an expected call of GetIdentities.
+func (mr *MockIdentityMockRecorder) GetIdentities(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentities", reflect.TypeOf((*MockIdentity)(nil).GetIdentities), userId)
+}
+
+// Link mocks base method.
+func (m *MockIdentity) Link(userId int64, provider string, input *entity.OIDCInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", userId, provider, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockIdentityMockRecorder) Link(userId, provider, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockIdentity)(nil).Link), userId, provider, input)
+}
+
+// SignIn mocks base method.
+func (m *MockIdentity) SignIn(provider string, input *entity.OIDCInput) (*entity.SignInResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", provider, input)
+	ret0, _ := ret[0].(*entity.SignInResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIn indicates an expected call of SignIn.
+func (mr *MockIdentityMockRecorder) SignIn(provider, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockIdentity)(nil).SignIn), provider, input)
+}
+
+// Unlink mocks base method.
+func (m *MockIdentity) Unlink(userId int64, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlink", userId, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlink indicates an expected call of Unlink.
+func (mr *MockIdentityMockRecorder) Unlink(userId, provider interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkout", reflect.TypeOf((*MockUser)(nil).UpdateWorkout), workoutId, userId, update)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlink", reflect.TypeOf((*MockIdentity)(nil).Unlink), userId, provider)
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/repository"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// maxTextLength is size of varchar columns which patched text is written to.
+const maxTextLength = 255
+
+// patchWorkout validates and writes patch, empty patch only checks access and version of workout.
+// It returns version of workout and reports whether it is changed.
+func patchWorkout(repo repository.User, workoutId, userId int64, patch *entity.WorkoutPatch,
+	version int64) (int64, bool, error) {
+	if err := validateWorkoutPatch(patch); err != nil {
+		return 0, false, err
+	}
+	if columns, _ := patch.Changes(); len(columns) == 0 {
+		workout, err := repo.GetWorkoutById(workoutId, userId)
+		if err != nil {
+			return 0, false, err
+		}
+		if version != 0 && workout.Version != version {
+			return 0, false, entity.ErrVersionMismatch
+		}
+		return workout.Version, false, nil
+	}
+
+	version, err := repo.PatchWorkout(workoutId, userId, patch, version)
+	if err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
+
+// validateWorkoutPatch checks members present in patch, null description is replaced with empty one.
+func validateWorkoutPatch(patch *entity.WorkoutPatch) error {
+	if patch.Title.Set {
+		if err := validateText("title", patch.Title); err != nil {
+			return validationError{err}
+		}
+	}
+	if patch.Description.Set {
+		if len(patch.Description.Value) > maxTextLength {
+			return validationError{fmt.Errorf("description is longer than %d characters", maxTextLength)}
+		}
+	}
+	if patch.Date.Set {
+		if patch.Date.Null || patch.Date.Value.IsZero() {
+			return validationError{errors.New("date can't be empty")}
+		}
+		patch.Date.Value = patch.Date.Value.UTC()
+	}
+	return nil
+}
+
+// validateProfilePatch checks members present in patch, null time zone is replaced with UTC.
+func validateProfilePatch(patch *entity.ProfilePatch) error {
+	if patch.Email.Set {
+		if err := validateText("email", patch.Email); err != nil {
+			return err
+		}
+		if address, err := mail.ParseAddress(patch.Email.Value); err != nil || address.Address != patch.Email.Value {
+			return errors.New("invalid email")
+		}
+	}
+	if patch.Name.Set {
+		if err := validateText("name", patch.Name); err != nil {
+			return err
+		}
+	}
+	if patch.Surname.Set {
+		if err := validateText("surname", patch.Surname); err != nil {
+			return err
+		}
+	}
+	if patch.TimeZone.Set {
+		if patch.TimeZone.Null {
+			patch.TimeZone.Value = "UTC"
+		} else if _, err := loadTimeZone(patch.TimeZone.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateText checks member of required text column, it can be changed but not removed.
+func validateText(name string, member entity.Patch[string]) error {
+	if member.Null || strings.TrimSpace(member.Value) == "" {
+		return fmt.Errorf("%s can't be empty", name)
+	}
+	if len(member.Value) > maxTextLength {
+		return fmt.Errorf("%s is longer than %d characters", name, maxTextLength)
+	}
+	return nil
+}
+
+// validationError is error of invalid input, its message is meant for client.
+type validationError struct {
+	error
+}
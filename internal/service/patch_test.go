@@ -0,0 +1,106 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateWorkoutPatch(t *testing.T) {
+	table := []struct {
+		name       string
+		patch      entity.WorkoutPatch
+		shouldFail bool
+	}{
+		{
+			name:       "Empty patch",
+			patch:      entity.WorkoutPatch{},
+			shouldFail: false,
+		},
+		{
+			name:       "Null description",
+			patch:      entity.WorkoutPatch{Description: entity.Patch[string]{Set: true, Null: true}},
+			shouldFail: false,
+		},
+		{
+			name:       "Null title",
+			patch:      entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Null: true}},
+			shouldFail: true,
+		},
+		{
+			name:       "Blank title",
+			patch:      entity.WorkoutPatch{Title: entity.Patch[string]{Set: true, Value: "  "}},
+			shouldFail: true,
+		},
+		{
+			name:       "Long description",
+			patch:      entity.WorkoutPatch{Description: entity.Patch[string]{Set: true, Value: strings.Repeat("a", 256)}},
+			shouldFail: true,
+		},
+		{
+			name:       "Null date",
+			patch:      entity.WorkoutPatch{Date: entity.Patch[time.Time]{Set: true, Null: true}},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateWorkoutPatch(&test.patch)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateProfilePatch(t *testing.T) {
+	table := []struct {
+		name         string
+		patch        entity.ProfilePatch
+		wantTimeZone string
+		shouldFail   bool
+	}{
+		{
+			name:         "Null time zone",
+			patch:        entity.ProfilePatch{TimeZone: entity.Patch[string]{Set: true, Null: true}},
+			wantTimeZone: "UTC",
+			shouldFail:   false,
+		},
+		{
+			name:         "Time zone",
+			patch:        entity.ProfilePatch{TimeZone: entity.Patch[string]{Set: true, Value: "Europe/Berlin"}},
+			wantTimeZone: "Europe/Berlin",
+			shouldFail:   false,
+		},
+		{
+			name:       "Unknown time zone",
+			patch:      entity.ProfilePatch{TimeZone: entity.Patch[string]{Set: true, Value: "Mars/Olympus"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Invalid email",
+			patch:      entity.ProfilePatch{Email: entity.Patch[string]{Set: true, Value: "John <john@mail.com>"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Null name",
+			patch:      entity.ProfilePatch{Name: entity.Patch[string]{Set: true, Null: true}},
+			shouldFail: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateProfilePatch(&test.patch)
+			if test.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantTimeZone, test.patch.TimeZone.Value)
+			}
+		})
+	}
+}
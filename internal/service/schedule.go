@@ -0,0 +1,291 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBookingTitle = "Session with trainer"
+	dateLayout          = "2006-01-02"
+)
+
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+func (i interval) overlaps(start, end time.Time) bool {
+	return i.start.Before(end) && start.Before(i.end)
+}
+
+type ScheduleService struct {
+	repo               repository.Schedule
+	events             event.Publisher
+	cancellationCutoff time.Duration
+}
+
+func NewScheduleService(repo repository.Schedule, events event.Publisher,
+	cancellationCutoff time.Duration) *ScheduleService {
+	return &ScheduleService{repo: repo, events: events, cancellationCutoff: cancellationCutoff}
+}
+
+func (s *ScheduleService) CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error) {
+	if err := validateWindow(slot.StartTime, slot.EndTime, &slot.TimeZone); err != nil {
+		return -1, err
+	}
+	return s.repo.CreateAvailabilitySlot(slot)
+}
+
+func (s *ScheduleService) DeleteAvailabilitySlot(trainerId, slotId int64) error {
+	return s.repo.DeleteAvailabilitySlot(trainerId, slotId)
+}
+
+func (s *ScheduleService) CreateAvailabilityException(exception *entity.AvailabilityException) (int64, error) {
+	if _, err := time.Parse(dateLayout, exception.Date); err != nil {
+		return -1, errors.New("invalid date, expected format is YYYY-MM-DD")
+	}
+	if err := validateWindow(exception.StartTime, exception.EndTime, &exception.TimeZone); err != nil {
+		return -1, err
+	}
+	return s.repo.CreateAvailabilityException(exception)
+}
+
+func (s *ScheduleService) DeleteAvailabilityException(trainerId, exceptionId int64) error {
+	return s.repo.DeleteAvailabilityException(trainerId, exceptionId)
+}
+
+func (s *ScheduleService) GetAvailability(trainerId int64) (*entity.Availability, error) {
+	slots, err := s.repo.GetAvailabilitySlots(trainerId)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	exceptions, err := s.repo.GetAvailabilityExceptions(trainerId, today.AddDate(0, 0, -1), today.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Availability{Slots: slots, Exceptions: exceptions}, nil
+}
+
+func (s *ScheduleService) GetFreeSlots(trainerId int64, from, to time.Time) ([]*entity.FreeSlot, error) {
+	slots, err := s.repo.GetAvailabilitySlots(trainerId)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := s.repo.GetAvailabilityExceptions(trainerId, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.repo.GetTrainerBookings(trainerId, from.AddDate(0, 0, -1), to.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]interval, 0)
+	blocked := make([]interval, 0, len(bookings))
+	for _, b := range bookings {
+		blocked = append(blocked, interval{start: b.StartsAt, end: b.EndsAt})
+	}
+
+	for _, e := range exceptions {
+		day, err := time.Parse(dateLayout, e.Date)
+		if err != nil {
+			return nil, err
+		}
+		window, err := resolveWindow(day, e.StartTime, e.EndTime, e.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		if !e.Available {
+			blocked = append(blocked, window)
+			continue
+		}
+		if !day.Before(from) && !day.After(to) {
+			candidates = append(candidates, window)
+		}
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, slot := range slots {
+			if int(day.Weekday()) != slot.Weekday {
+				continue
+			}
+			window, err := resolveWindow(day, slot.StartTime, slot.EndTime, slot.TimeZone)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, window)
+		}
+	}
+
+	return filterFreeSlots(candidates, blocked, time.Now()), nil
+}
+
+func (s *ScheduleService) BookSlot(userId int64, input *entity.BookingInput) (int64, error) {
+	day := input.StartsAt.UTC().Truncate(24 * time.Hour)
+	free, err := s.GetFreeSlots(input.TrainerId, day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+
+	var slot *entity.FreeSlot
+	for _, f := range free {
+		if f.StartsAt.Equal(input.StartsAt) {
+			slot = f
+			break
+		}
+	}
+	if slot == nil {
+		return -1, errors.New("trainer is not available at provided time")
+	}
+
+	if input.Title == "" {
+		input.Title = defaultBookingTitle
+	}
+	workout := &entity.Workout{
+		Title:       input.Title,
+		UserId:      userId,
+		TrainerId:   sql.NullInt64{Int64: input.TrainerId, Valid: true},
+		Description: input.Description,
+		Date:        slot.StartsAt,
+	}
+	booking := &entity.Booking{
+		TrainerId: input.TrainerId,
+		UserId:    userId,
+		StartsAt:  slot.StartsAt,
+		EndsAt:    slot.EndsAt,
+	}
+	id, err := s.repo.CreateBooking(booking, workout)
+	if err != nil {
+		return id, err
+	}
+	s.events.Publish(entity.NewEvent(entity.EventWorkoutCreated, workout, userId, input.TrainerId))
+	return id, nil
+}
+
+func (s *ScheduleService) GetUserBookings(userId int64) ([]*entity.Booking, error) {
+	return s.repo.GetUserBookings(userId)
+}
+
+func (s *ScheduleService) GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error) {
+	return s.repo.GetTrainerBookings(trainerId, from, to)
+}
+
+func (s *ScheduleService) CancelBooking(bookingId, userId int64) error {
+	booking, err := s.repo.GetBookingById(bookingId)
+	if err != nil {
+		return errors.New("no booking to cancel")
+	}
+	if booking.Status != entity.BookingStatusBooked {
+		return errors.New("booking has already been cancelled")
+	}
+
+	now := time.Now()
+	switch userId {
+	case booking.TrainerId:
+		if !now.Before(booking.StartsAt) {
+			return errors.New("booking has already started")
+		}
+	case booking.UserId:
+		if booking.StartsAt.Sub(now) < s.cancellationCutoff {
+			return fmt.Errorf("booking can be cancelled not later than %s before start", s.cancellationCutoff)
+		}
+	default:
+		return errors.New("no access to booking")
+	}
+	return s.repo.CancelBooking(bookingId, userId)
+}
+
+func validateWindow(start, end string, timeZone *string) error {
+	if *timeZone == "" {
+		*timeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(*timeZone); err != nil {
+		return errors.New("invalid time_zone")
+	}
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return err
+	}
+	if startMinutes >= endMinutes {
+		return errors.New("start_time must be before end_time")
+	}
+	return nil
+}
+
+func resolveWindow(day time.Time, start, end, timeZone string) (interval, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return interval{}, err
+	}
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return interval{}, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return interval{}, err
+	}
+	y, m, d := day.Date()
+	return interval{
+		start: time.Date(y, m, d, startMinutes/60, startMinutes%60, 0, 0, loc),
+		end:   time.Date(y, m, d, endMinutes/60, endMinutes%60, 0, 0, loc),
+	}, nil
+}
+
+func parseClock(clock string) (int, error) {
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q, expected format is HH:MM", clock)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected format is HH:MM", clock)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected format is HH:MM", clock)
+	}
+	total := hours*60 + minutes
+	if hours < 0 || minutes < 0 || minutes > 59 || total > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, expected format is HH:MM", clock)
+	}
+	return total, nil
+}
+
+func filterFreeSlots(candidates, blocked []interval, now time.Time) []*entity.FreeSlot {
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].start.Before(candidates[j].start)
+	})
+
+	free := make([]*entity.FreeSlot, 0, len(candidates))
+	for i, c := range candidates {
+		if !c.start.After(now) || (i > 0 && c.start.Equal(candidates[i-1].start)) {
+			continue
+		}
+		available := true
+		for _, b := range blocked {
+			if b.overlaps(c.start, c.end) {
+				available = false
+				break
+			}
+		}
+		if available {
+			free = append(free, &entity.FreeSlot{StartsAt: c.start.UTC(), EndsAt: c.end.UTC()})
+		}
+	}
+	return free
+}
@@ -2,7 +2,13 @@ package service
 
 import (
 	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
+	"Fitness_REST_API/internal/idempotency"
+	"Fitness_REST_API/internal/lockout"
+	"Fitness_REST_API/internal/mail"
+	"Fitness_REST_API/internal/oidc"
 	"Fitness_REST_API/internal/repository"
+	"errors"
 	"github.com/dgrijalva/jwt-go"
 	"time"
 )
@@ -13,30 +19,44 @@ const (
 	tokenTTL = 150000 * 60 * time.Second
 )
 
+// ErrInvalidCredentials is returned by sign in when login and password don't match.
+var ErrInvalidCredentials = errors.New("invalid login or password")
+
 type Admin interface {
-	SignIn(login, passwordHash string) (string, error)
-	ParseToken(token string) error
+	SignIn(login, passwordHash string) (*entity.SignInResult, error)
+	VerifyMFA(mfaToken, code string) (*entity.SignInResult, error)
+	ParseToken(token string) (int64, error)
 	GetUsersId(role entity.Role) ([]int64, error)
 	GetUserFullInfoById(userId int64) (*entity.UserInfo, error)
-	CreateUser(user *entity.User) (int64, error)
-	UpdateUser(userId int64, update *entity.UserUpdate) error
-	DeleteUser(userId int64) error
+	CreateUser(actor *entity.Actor, user *entity.User) (int64, error)
+	UpdateUser(actor *entity.Actor, userId int64, update *entity.UserUpdate) error
+	DeleteUser(actor *entity.Actor, userId int64) error
+	RestoreUser(actor *entity.Actor, userId int64) error
+	PurgeDeletedUsers(deletedBefore time.Time) (int64, error)
+	GetAuditLog(filter *entity.AuditFilter) (*entity.AuditPage, error)
 }
 
 type User interface { //nolint
-	SignIn(email, passwordHash string, role entity.Role) (string, error)
+	SignIn(email, passwordHash string, role entity.Role) (*entity.SignInResult, error)
+	VerifyMFA(mfaToken, code string) (*entity.SignInResult, error)
 	SignUp(user *entity.User) (int64, error)
 	ParseToken(token string) (int64, entity.Role, error)
+	DeleteAccount(userId int64, password string) (time.Time, error)
+	UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) (bool, error)
+	PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) (bool, error)
+	ChangePassword(userId int64, currentPassword, newPassword string) (string, error)
+	AnonymizeScheduledUsers(dueBefore time.Time) (int64, error)
 	GetUserInfoById(id int64) (*entity.User, error)
 	CreateWorkoutAsUser(workout *entity.Workout) (int64, error)
-	UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout) error
-	GetUserWorkouts(id int64) ([]*entity.Workout, error)
+	UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error
+	PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch, version int64) (int64, error)
+	GetUserWorkouts(id int64, date time.Time) ([]*entity.Workout, error)
 	GetWorkoutById(workoutId, userId int64) (*entity.Workout, error)
-	DeleteWorkout(workoutId, userId int64) error
+	DeleteWorkout(workoutId, userId, version int64) error
 	GetTrainers() ([]*entity.User, error)
 	GetTrainerById(id int64) (*entity.User, error)
 	SendRequestToTrainer(trainerId, userId int64) (int64, error)
-	EndPartnershipWithTrainer(trainerId, userId int64) (int64, error)
+	EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error)
 	GetUserPartnerships(userId int64) ([]*entity.Partnership, error)
 
 	GetTrainerUsers(trainerId int64) ([]*entity.User, error)
@@ -44,35 +64,199 @@ type User interface { //nolint
 	GetTrainerUserById(trainerId, userId int64) (*entity.User, error)
 	GetTrainerRequestById(trainerId, requestId int64) (*entity.Request, error)
 	InitPartnershipWithUser(trainerId, userId int64) (int64, error)
-	EndPartnershipWithUser(trainerId, userId int64) (int64, error)
-	AcceptRequest(trainerId, requestId int64) (int64, error)
-	DenyRequest(trainerId, requestId int64) error
+	EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error)
+	AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error)
+	DenyRequest(actor *entity.Actor, trainerId, requestId int64) error
 	CreateWorkoutAsTrainer(workout *entity.Workout) (int64, error)
 	GetTrainerWorkouts(trainerId int64) ([]*entity.Workout, error)
 	GetTrainerWorkoutsWithUser(trainerId, userId int64) ([]*entity.Workout, error)
 
 	GetPasswordHash(password string) string
 	InitUpdateUser(userId int64, update *entity.UserUpdate) error
-	FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId, userId int64) error
+	FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId, userId, version int64) (int64, error)
+}
+
+type Bulk interface {
+	CreateWorkouts(trainerId int64, input *entity.BulkCreateInput) (*entity.BulkResult, error)
+	PatchWorkouts(trainerId int64, input *entity.BulkPatchInput) (*entity.BulkResult, error)
+	DeleteWorkouts(trainerId int64, input *entity.BulkDeleteInput) (*entity.BulkResult, error)
+	ShiftWorkouts(trainerId int64, input *entity.BulkShiftInput) (*entity.BulkResult, error)
+}
+
+type Schedule interface {
+	CreateAvailabilitySlot(slot *entity.AvailabilitySlot) (int64, error)
+	DeleteAvailabilitySlot(trainerId, slotId int64) error
+	CreateAvailabilityException(exception *entity.AvailabilityException) (int64, error)
+	DeleteAvailabilityException(trainerId, exceptionId int64) error
+	GetAvailability(trainerId int64) (*entity.Availability, error)
+	GetFreeSlots(trainerId int64, from, to time.Time) ([]*entity.FreeSlot, error)
+	BookSlot(userId int64, input *entity.BookingInput) (int64, error)
+	GetUserBookings(userId int64) ([]*entity.Booking, error)
+	GetTrainerBookings(trainerId int64, from, to time.Time) ([]*entity.Booking, error)
+	CancelBooking(bookingId, userId int64) error
+}
+
+type Message interface {
+	GetUserConversation(userId, partnershipId int64) (*entity.Partnership, error)
+	GetTrainerConversation(trainerId, userId int64) (*entity.Partnership, error)
+	GetMessages(partnershipId, beforeId int64, limit int) (*entity.MessagePage, error)
+	SendMessage(partnership *entity.Partnership, senderId int64, body string) (int64, error)
+	MarkMessagesRead(partnershipId, readerId int64) (int64, error)
+}
+
+type Comment interface {
+	GetComments(workoutId, userId int64) ([]*entity.Comment, error)
+	CreateComment(workoutId, userId int64, body string) (int64, error)
+	UpdateComment(workoutId, commentId, userId int64, body string) error
+	ResolveComment(workoutId, commentId, userId int64, resolved bool) error
+	DeleteComment(workoutId, commentId, userId int64) error
+}
+
+type Group interface {
+	CreateGroup(group *entity.ClientGroup) (int64, error)
+	GetTrainerGroups(trainerId int64) ([]*entity.ClientGroup, error)
+	GetGroupById(trainerId, groupId int64) (*entity.ClientGroup, error)
+	DeleteGroup(trainerId, groupId int64) error
+	AddGroupMember(trainerId, groupId, userId int64) error
+	RemoveGroupMember(trainerId, groupId, userId int64) error
+	CreateGroupWorkout(trainerId int64, input *entity.GroupWorkoutInput) (int64, error)
+	GetTrainerGroupWorkouts(trainerId int64) ([]*entity.GroupWorkout, error)
+	GetGroupWorkoutById(trainerId, groupWorkoutId int64) (*entity.GroupWorkout, error)
+	DeleteGroupWorkout(trainerId, groupWorkoutId int64) error
+	AddGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error
+	RemoveGroupWorkoutParticipant(trainerId, groupWorkoutId, userId int64) error
+	SetAttendance(trainerId, groupWorkoutId, userId int64, status entity.AttendanceStatus) error
+}
+
+type Event interface {
+	Subscribe(userId int64) (<-chan *entity.Event, func())
+}
+
+type Export interface {
+	RequestExport(actor *entity.Actor, userId int64) (int64, error)
+	GetExport(userId, exportId int64) (*entity.DataExport, error)
+	GetCurrentExport(userId int64) (*entity.DataExport, error)
+	GetExportArchive(token string) (*entity.DataExport, error)
+	PurgeExpiredExports(expiredBefore time.Time) (int64, error)
+	FailStaleExports(createdBefore time.Time) (int64, error)
+}
+
+type Account interface {
+	SendVerification(userId int64) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+}
+
+type Application interface {
+	SubmitApplication(actor *entity.Actor, userId int64, input *entity.TrainerApplicationInput) (int64, error)
+	GetUserApplications(userId int64) ([]*entity.TrainerApplication, error)
+	UpdateApplication(actor *entity.Actor, userId, appId int64, input *entity.TrainerApplicationInput) error
+	AddCertificate(actor *entity.Actor, userId int64, cert *entity.Certificate) (int64, error)
+	GetApplications(status entity.ApplicationStatus) ([]*entity.TrainerApplication, error)
+	GetApplicationById(appId int64) (*entity.TrainerApplication, error)
+	GetCertificate(appId, certificateId int64) (*entity.Certificate, error)
+	ReviewApplication(actor *entity.Actor, appId int64, status entity.ApplicationStatus, note string) error
+}
+
+type Lockout interface {
+	BeginSignIn(actor *entity.Actor, scope entity.ActorType, login string) (time.Duration, error)
+	FailSignIn(actor *entity.Actor, scope entity.ActorType, login string) error
+	SucceedSignIn(actor *entity.Actor, scope entity.ActorType, login string) error
+	CancelSignIn(actor *entity.Actor, scope entity.ActorType, login string) error
+	Unlock(actor *entity.Actor, input *entity.UnlockInput) error
+	PurgeAttempts(lastFailureBefore time.Time) (int64, error)
+}
+
+type Idempotency interface {
+	BeginRequest(userId int64, key, fingerprint string) (reservation, stored *entity.IdempotencyRecord, err error)
+	CompleteRequest(reservation *entity.IdempotencyRecord, status int, contentType string, body []byte) error
+	ReleaseRequest(reservation *entity.IdempotencyRecord) error
+	PurgeKeys(createdBefore time.Time) (int64, error)
+}
+
+type MFA interface {
+	Enroll(subject entity.ActorType, subjectId int64) (*entity.MFAEnrollment, error)
+	EnrollChallenge(mfaToken string) (*entity.MFAEnrollment, error)
+	Confirm(subject entity.ActorType, subjectId int64, code string) ([]string, error)
+	Disable(subject entity.ActorType, subjectId int64, code string) error
+	GetPolicies() ([]*entity.MFAPolicy, error)
+	SetPolicy(actor *entity.Actor, policy *entity.MFAPolicy) error
+}
+
+type Identity interface {
+	SignIn(provider string, input *entity.OIDCInput) (*entity.SignInResult, error)
+	Link(userId int64, provider string, input *entity.OIDCInput) error
+	Unlink(userId int64, provider string) error
+	GetIdentities(userId int64) ([]*entity.ExternalIdentity, error)
 }
 
 type Services struct {
 	User
+	Bulk
 	Admin
+	Schedule
+	Message
+	Comment
+	Group
+	Event
+	Export
+	Account
+	Application
+	Lockout
+	Idempotency
+	MFA
+	Identity
 }
 
 type Dependencies struct {
+	CancellationCutoff  time.Duration
+	ExportLinkTTL       time.Duration
+	ExportBuildTimeout  time.Duration
+	DeletionGrace       time.Duration
+	RequireVerification bool
+	VerificationTTL     time.Duration
+	ResetTTL            time.Duration
+	AppURL              string
+	Bus                 event.Bus
+	Mailer              mail.Mailer
+	SignInAttempts      lockout.Store
+	AccountLockout      lockout.Policy
+	IPLockout           lockout.Policy
+	IdempotencyKeys     idempotency.Store
+	IdempotencyTTL      time.Duration
+	IdempotencyLease    time.Duration
+	OIDC                oidc.Verifier
 }
 
 type tokenClaims struct {
 	jwt.StandardClaims
-	ID   int64       `json:"id"`
-	Role entity.Role `json:"role"`
+	ID      int64       `json:"id"`
+	Role    entity.Role `json:"role"`
+	Version int64       `json:"ver,omitempty"`
 }
 
-func NewService(repos *repository.Repository) *Services {
+func NewService(repos *repository.Repository, deps *Dependencies) *Services {
+	mfa := NewMFAService(repos.MFA, repos.User, repos.Admin, deps.SignInAttempts, deps.AccountLockout,
+		"zmxncbvlaksjdhg")
+	user := NewUserService(repos.User, repos.TxManager, deps.Bus, mfa, deps.DeletionGrace, deps.RequireVerification,
+		"ergeringeriger", "etiwepirefbjsd")
 	return &Services{
-		Admin: NewAdminService(repos.Admin, repos.User, "ergeringeriger", "psgvjviops"),
-		User:  NewUserService(repos.User, "ergeringeriger", "etiwepirefbjsd"),
+		Admin:    NewAdminService(repos.Admin, repos.User, repos.Audit, mfa, "ergeringeriger", "psgvjviops"),
+		User:     user,
+		Bulk:     NewBulkService(repos.TxManager, deps.Bus),
+		Schedule: NewScheduleService(repos.Schedule, deps.Bus, deps.CancellationCutoff),
+		Message:  NewMessageService(repos.Message, repos.User, deps.Bus),
+		Comment:  NewCommentService(repos.Comment, repos.User, deps.Bus),
+		Group:    NewGroupService(repos.Group, deps.Bus),
+		Event:    NewEventService(deps.Bus),
+		Export:   NewExportService(repos.Export, repos.User, deps.ExportLinkTTL, deps.ExportBuildTimeout),
+		Account: NewAccountService(repos.Account, repos.User, deps.Mailer, user.GetPasswordHash, deps.AppURL,
+			deps.VerificationTTL, deps.ResetTTL, "wqpoeirusdkfjhv"),
+		Application: NewApplicationService(repos.Application),
+		Lockout:     NewLockoutService(deps.SignInAttempts, repos.Audit, deps.AccountLockout, deps.IPLockout),
+		Idempotency: NewIdempotencyService(deps.IdempotencyKeys, deps.IdempotencyTTL, deps.IdempotencyLease),
+		MFA:         mfa,
+		Identity:    NewIdentityService(repos.Identity, repos.User, deps.OIDC, user.issueSession),
 	}
 }
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"errors"
+	"time"
+)
+
+// loadTimeZone resolves IANA name, Local is rejected since it depends on the server.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("invalid time_zone")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("invalid time_zone")
+	}
+	return loc, nil
+}
+
+// localDay returns UTC bounds of calendar day of date in loc, the day is 23 or 25 hours long on DST change.
+func localDay(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	y, m, d := date.Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	to := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	return from.UTC(), to.UTC()
+}
+
+// localizeWorkouts keeps Date in UTC and sets LocalDate in time zone of viewer.
+func localizeWorkouts(loc *time.Location, workouts ...*entity.Workout) {
+	for _, w := range workouts {
+		w.Date = w.Date.UTC()
+		local := w.Date.In(loc)
+		w.LocalDate = &local
+		w.TimeZone = loc.String()
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"Fitness_REST_API/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLocalDay(t *testing.T) {
+	berlin, err := loadTimeZone("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := loadTimeZone("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		name     string
+		date     time.Time
+		loc      *time.Location
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			name:     "Regular day",
+			date:     time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			loc:      berlin,
+			wantFrom: time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 10, 20, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "DST ends, day is 25 hours",
+			date:     time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+			loc:      berlin,
+			wantFrom: time.Date(2026, 10, 24, 22, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 10, 25, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "DST starts, day is 23 hours",
+			date:     time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+			loc:      newYork,
+			wantFrom: time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "UTC",
+			date:     time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			wantFrom: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			from, to := localDay(test.date, test.loc)
+			assert.Equal(t, test.wantFrom, from)
+			assert.Equal(t, test.wantTo, to)
+		})
+	}
+}
+
+func TestLocalizeWorkouts(t *testing.T) {
+	berlin, err := loadTimeZone("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 00:30 and 01:30 UTC are both 02:30 local time around the end of DST
+	before := &entity.Workout{Date: time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC)}
+	after := &entity.Workout{Date: time.Date(2026, 10, 25, 1, 30, 0, 0, time.FixedZone("", 0))}
+	localizeWorkouts(berlin, before, after)
+
+	assert.Equal(t, "2026-10-25T02:30:00+02:00", before.LocalDate.Format(time.RFC3339))
+	assert.Equal(t, "2026-10-25T02:30:00+01:00", after.LocalDate.Format(time.RFC3339))
+	assert.Equal(t, time.UTC, after.Date.Location())
+	assert.Equal(t, "Europe/Berlin", after.TimeZone)
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	for _, name := range []string{"", "Local", "Mars/Olympus"} {
+		_, err := loadTimeZone(name)
+		assert.Error(t, err, name)
+	}
+	loc, err := loadTimeZone("Asia/Tokyo")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+}
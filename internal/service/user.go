@@ -2,29 +2,107 @@ package service
 
 import (
 	"Fitness_REST_API/internal/entity"
+	"Fitness_REST_API/internal/event"
 	"Fitness_REST_API/internal/repository"
 	"crypto/sha1"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
 	"time"
 )
 
+// serializableTx runs read-then-write of repositories, checks like no open partnership of the
+// pair or trainer role of workout trainer hold until commit only in serializable isolation.
+var serializableTx = repository.TxOptions{Isolation: sql.LevelSerializable, Retries: 3}
+
 type UserService struct {
-	repo       repository.User
-	hashSalt   string
-	signingKey []byte
+	repo                repository.User
+	tx                  repository.TxManager
+	events              event.Publisher
+	deletionGrace       time.Duration
+	requireVerification bool
+	hashSalt            string
+	signingKey          []byte
+	mfa                 *MFAService
 }
 
-func NewUserService(repos repository.User, hashSalt string, signingKey string) *UserService {
-	return &UserService{repo: repos, hashSalt: hashSalt, signingKey: []byte(signingKey)}
+func NewUserService(repos repository.User, tx repository.TxManager, events event.Publisher, mfa *MFAService,
+	deletionGrace time.Duration, requireVerification bool, hashSalt string, signingKey string) *UserService {
+	return &UserService{repo: repos, tx: tx, events: events, mfa: mfa, deletionGrace: deletionGrace,
+		requireVerification: requireVerification, hashSalt: hashSalt, signingKey: []byte(signingKey)}
 }
 
-func (s *UserService) SignIn(email, password string, role entity.Role) (string, error) {
+func (s *UserService) SignIn(email, password string, role entity.Role) (*entity.SignInResult, error) {
 	id, err := s.repo.Authorize(email, s.GetPasswordHash(password), role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if s.requireVerification {
+		verified, err := s.repo.IsVerified(id)
+		if err != nil {
+			return nil, err
+		}
+		if !verified {
+			return nil, errors.New("email is not verified")
+		}
+	}
+
+	return s.issueSession(id, role)
+}
+
+// issueSession challenges for second factor when it is needed, otherwise signs user in.
+func (s *UserService) issueSession(id int64, role entity.Role) (*entity.SignInResult, error) {
+	challenge, err := s.mfa.challenge(entity.ActorUser, id, role)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+	return s.completeSignIn(id, role)
+}
+
+// VerifyMFA finishes sign in which was challenged for two-factor code.
+func (s *UserService) VerifyMFA(mfaToken, code string) (*entity.SignInResult, error) {
+	claims, recoveryCodes, err := s.mfa.verifyChallenge(entity.ActorUser, mfaToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.completeSignIn(claims.ID, claims.Role)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = recoveryCodes
+	return result, nil
+}
+
+func (s *UserService) completeSignIn(id int64, role entity.Role) (*entity.SignInResult, error) {
+	cancelled, err := s.repo.CancelDeletion(id)
+	if err != nil {
+		logrus.Errorf("can't cancel scheduled deletion of user %d: %s", id, err.Error())
+	} else if cancelled {
+		logrus.Infof("scheduled deletion of user %d is cancelled by sign in", id)
+	}
+
+	version, err := s.repo.GetTokenVersion(id)
+	if err != nil {
+		return nil, err
 	}
+	token, err := s.newToken(id, role, version)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SignInResult{Token: token}, nil
+}
 
+func (s *UserService) newToken(id int64, role entity.Role, version int64) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
 		jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
@@ -32,6 +110,7 @@ func (s *UserService) SignIn(email, password string, role entity.Role) (string,
 		},
 		id,
 		role,
+		version,
 	})
 
 	return token.SignedString(s.signingKey)
@@ -39,7 +118,7 @@ func (s *UserService) SignIn(email, password string, role entity.Role) (string,
 
 func (s *UserService) SignUp(user *entity.User) (int64, error) {
 	user.PasswordHash = s.GetPasswordHash(user.PasswordHash)
-	return s.repo.CreateUser(user, entity.UserRole)
+	return s.repo.CreateUser(nil, user, entity.UserRole)
 }
 
 func (s *UserService) ParseToken(token string) (int64, entity.Role, error) {
@@ -59,9 +138,108 @@ func (s *UserService) ParseToken(token string) (int64, entity.Role, error) {
 		return -1, "", fmt.Errorf("error get user claims from token")
 	}
 
+	version, err := s.repo.GetTokenVersion(claims.ID)
+	if err != nil || version != claims.Version {
+		return -1, "", errors.New("session is expired, sign in again")
+	}
+
 	return claims.ID, claims.Role, nil
 }
 
+// UpdateProfile fills empty fields of update from the stored profile, non-zero version has to match it, and
+// writes merged profile only over the version it was read at. It reports whether email is changed and has
+// to be verified.
+func (s *UserService) UpdateProfile(userId int64, update *entity.ProfileUpdate, version int64) (bool, error) {
+	user, err := s.repo.GetUserInfoById(userId)
+	if err != nil {
+		return false, err
+	}
+	if version != 0 && user.Version != version {
+		return false, entity.ErrVersionMismatch
+	}
+
+	if update.Email == "" {
+		update.Email = user.Email
+	}
+	if update.Name == "" {
+		update.Name = user.Name
+	}
+	if update.Surname == "" {
+		update.Surname = user.Surname
+	}
+	if update.TimeZone == "" {
+		update.TimeZone = user.TimeZone
+	} else if _, err = loadTimeZone(update.TimeZone); err != nil {
+		return false, err
+	}
+
+	if err = s.repo.UpdateProfile(userId, update, user.Version); err != nil {
+		return false, err
+	}
+	return update.Email != user.Email, nil
+}
+
+// PatchProfile applies JSON Merge Patch to profile, it reports whether email is changed and has to be verified.
+// Unlike UpdateProfile it writes only members present in patch, so it isn't merged with the stored profile.
+func (s *UserService) PatchProfile(userId int64, patch *entity.ProfilePatch, version int64) (bool, error) {
+	if err := validateProfilePatch(patch); err != nil {
+		return false, err
+	}
+	user, err := s.repo.GetUserInfoById(userId)
+	if err != nil {
+		return false, err
+	}
+	if version != 0 && user.Version != version {
+		return false, entity.ErrVersionMismatch
+	}
+	if columns, _ := patch.Changes(); len(columns) == 0 {
+		return false, nil
+	}
+
+	if err = s.repo.PatchProfile(userId, patch, version); err != nil {
+		return false, err
+	}
+	return patch.Email.Set && patch.Email.Value != user.Email, nil
+}
+
+// ChangePassword sets new password and returns fresh token, tokens issued before are revoked.
+func (s *UserService) ChangePassword(userId int64, currentPassword, newPassword string) (string, error) {
+	user, err := s.repo.GetUserInfoById(userId)
+	if err != nil {
+		return "", err
+	}
+	if user.PasswordHash != s.GetPasswordHash(currentPassword) {
+		return "", errors.New("invalid password")
+	}
+
+	version, err := s.repo.ChangePassword(userId, s.GetPasswordHash(newPassword))
+	if err != nil {
+		return "", err
+	}
+	return s.newToken(userId, user.Role, version)
+}
+
+// DeleteAccount schedules anonymization of user after grace period, signing in cancels it.
+func (s *UserService) DeleteAccount(userId int64, password string) (time.Time, error) {
+	user, err := s.repo.GetUserInfoById(userId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.PasswordHash != s.GetPasswordHash(password) {
+		return time.Time{}, errors.New("invalid password")
+	}
+
+	at := time.Now().Add(s.deletionGrace)
+	if err = s.repo.ScheduleDeletion(userId, at); err != nil {
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+func (s *UserService) AnonymizeScheduledUsers(dueBefore time.Time) (int64, error) {
+	return s.repo.AnonymizeScheduledUsers(dueBefore)
+}
+
 func (s *UserService) InitUpdateUser(userId int64, update *entity.UserUpdate) error {
 	user, err := s.GetUserInfoById(userId)
 	if err != nil {
@@ -88,10 +266,16 @@ func (s *UserService) InitUpdateUser(userId int64, update *entity.UserUpdate) er
 	return nil
 }
 
-func (s *UserService) FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId, userId int64) error {
+// FormatUpdateWorkout fills empty fields from the stored workout, non-zero version has to match it.
+// It returns version the input is merged with, update has to be applied to it only.
+func (s *UserService) FormatUpdateWorkout(input *entity.UpdateWorkout,
+	workoutId, userId, version int64) (int64, error) {
 	workout, err := s.GetWorkoutById(workoutId, userId)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if version != 0 && workout.Version != version {
+		return 0, entity.ErrVersionMismatch
 	}
 
 	if input.Title == "" {
@@ -101,7 +285,7 @@ func (s *UserService) FormatUpdateWorkout(input *entity.UpdateWorkout, workoutId
 	if input.Date.IsZero() {
 		input.Date = workout.Date
 	}
-	return nil
+	return workout.Version, nil
 }
 
 func (s *UserService) GetUserInfoById(id int64) (*entity.User, error) {
@@ -109,23 +293,84 @@ func (s *UserService) GetUserInfoById(id int64) (*entity.User, error) {
 }
 
 func (s *UserService) CreateWorkoutAsUser(workout *entity.Workout) (int64, error) {
-	return s.repo.CreateWorkoutAsUser(workout)
+	workout.Date = workout.Date.UTC()
+	var id int64
+	err := s.tx.WithinTx(serializableTx, func(repos *repository.Repository) (err error) {
+		id, err = repos.User.CreateWorkoutAsUser(workout)
+		return err
+	})
+	if err != nil {
+		return id, err
+	}
+	s.publishWorkout(entity.EventWorkoutCreated, id, workout)
+	return id, nil
 }
 
-func (s *UserService) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout) error {
-	return s.repo.UpdateWorkout(workoutId, userId, update)
+func (s *UserService) UpdateWorkout(workoutId, userId int64, update *entity.UpdateWorkout, version int64) error {
+	update.Date = update.Date.UTC()
+	err := s.repo.UpdateWorkout(workoutId, userId, update, version)
+	if err != nil {
+		return err
+	}
+
+	workout, err := s.repo.GetWorkoutById(workoutId, userId)
+	if err != nil {
+		logrus.Errorf("can't load updated workout %d for event: %s", workoutId, err.Error())
+		return nil
+	}
+	s.publishWorkout(entity.EventWorkoutUpdated, workoutId, workout)
+	return nil
 }
 
-func (s *UserService) GetUserWorkouts(id int64) ([]*entity.Workout, error) {
-	return s.repo.GetUserWorkouts(id)
+// PatchWorkout applies JSON Merge Patch to workout and returns its new version, empty patch changes nothing.
+func (s *UserService) PatchWorkout(workoutId, userId int64, patch *entity.WorkoutPatch,
+	version int64) (int64, error) {
+	version, changed, err := patchWorkout(s.repo, workoutId, userId, patch, version)
+	if err != nil || !changed {
+		return version, err
+	}
+
+	workout, err := s.repo.GetWorkoutById(workoutId, userId)
+	if err != nil {
+		logrus.Errorf("can't load patched workout %d for event: %s", workoutId, err.Error())
+		return version, nil
+	}
+	s.publishWorkout(entity.EventWorkoutUpdated, workoutId, workout)
+	return version, nil
+}
+
+// GetUserWorkouts returns workouts of user, non-zero date selects the calendar day in time zone of user.
+func (s *UserService) GetUserWorkouts(id int64, date time.Time) ([]*entity.Workout, error) {
+	loc, err := s.viewerLocation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to time.Time
+	if !date.IsZero() {
+		from, to = localDay(date, loc)
+	}
+	workouts, err := s.repo.GetUserWorkouts(id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	localizeWorkouts(loc, workouts...)
+	return workouts, nil
 }
 
 func (s *UserService) GetWorkoutById(workoutId, userId int64) (*entity.Workout, error) {
-	return s.repo.GetWorkoutById(workoutId, userId)
+	workout, err := s.repo.GetWorkoutById(workoutId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.localizeFor(userId, []*entity.Workout{workout}); err != nil {
+		return nil, err
+	}
+	return workout, nil
 }
 
-func (s *UserService) DeleteWorkout(workoutId, userId int64) error {
-	return s.repo.DeleteWorkout(workoutId, userId)
+func (s *UserService) DeleteWorkout(workoutId, userId, version int64) error {
+	return s.repo.DeleteWorkout(workoutId, userId, version)
 }
 
 func (s *UserService) GetTrainers() ([]*entity.User, error) {
@@ -137,11 +382,24 @@ func (s *UserService) GetTrainerById(id int64) (*entity.User, error) {
 }
 
 func (s *UserService) SendRequestToTrainer(trainerId, userId int64) (int64, error) {
-	return s.repo.SendRequestToTrainer(trainerId, userId)
+	var id int64
+	err := s.tx.WithinTx(serializableTx, func(repos *repository.Repository) (err error) {
+		id, err = repos.User.SendRequestToTrainer(trainerId, userId)
+		return err
+	})
+	if err != nil {
+		return id, err
+	}
+	s.events.Publish(entity.NewEvent(entity.EventPartnershipRequested, &entity.PartnershipEvent{
+		PartnershipId: id,
+		UserId:        userId,
+		TrainerId:     trainerId,
+	}, trainerId))
+	return id, nil
 }
 
-func (s *UserService) EndPartnershipWithTrainer(trainerId, userId int64) (int64, error) {
-	return s.repo.EndPartnershipWithTrainer(trainerId, userId)
+func (s *UserService) EndPartnershipWithTrainer(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	return s.repo.EndPartnershipWithTrainer(actor, trainerId, userId)
 }
 
 func (s *UserService) GetUserPartnerships(userId int64) ([]*entity.Partnership, error) {
@@ -165,31 +423,83 @@ func (s *UserService) GetTrainerRequestById(trainerId, requestId int64) (*entity
 }
 
 func (s *UserService) InitPartnershipWithUser(trainerId, userId int64) (int64, error) {
-	return s.repo.InitPartnershipWithUser(trainerId, userId)
+	var id int64
+	err := s.tx.WithinTx(serializableTx, func(repos *repository.Repository) (err error) {
+		id, err = repos.User.InitPartnershipWithUser(trainerId, userId)
+		return err
+	})
+	if err != nil {
+		return id, err
+	}
+	s.publishPartnershipAccepted(id, trainerId, userId)
+	return id, nil
 }
 
-func (s *UserService) EndPartnershipWithUser(trainerId, userId int64) (int64, error) {
-	return s.repo.EndPartnershipWithUser(trainerId, userId)
+func (s *UserService) EndPartnershipWithUser(actor *entity.Actor, trainerId, userId int64) (int64, error) {
+	return s.repo.EndPartnershipWithUser(actor, trainerId, userId)
 }
 
-func (s *UserService) AcceptRequest(trainerId, requestId int64) (int64, error) {
-	return s.repo.AcceptRequest(trainerId, requestId)
+func (s *UserService) AcceptRequest(actor *entity.Actor, trainerId, requestId int64) (int64, error) {
+	id, err := s.repo.AcceptRequest(actor, trainerId, requestId)
+	if err != nil {
+		return id, err
+	}
+
+	p, err := s.repo.GetPartnershipById(id)
+	if err != nil {
+		logrus.Errorf("can't load accepted partnership %d for event: %s", id, err.Error())
+		return id, nil
+	}
+	s.publishPartnershipAccepted(id, trainerId, p.UserId)
+	return id, nil
 }
 
-func (s *UserService) DenyRequest(trainerId, requestId int64) error {
-	return s.repo.DenyRequest(trainerId, requestId)
+func (s *UserService) DenyRequest(actor *entity.Actor, trainerId, requestId int64) error {
+	return s.repo.DenyRequest(actor, trainerId, requestId)
 }
 
 func (s *UserService) GetTrainerWorkouts(trainerId int64) ([]*entity.Workout, error) {
-	return s.repo.GetTrainerWorkouts(trainerId)
+	workouts, err := s.repo.GetTrainerWorkouts(trainerId)
+	if err != nil {
+		return nil, err
+	}
+	return s.localizeFor(trainerId, workouts)
 }
 
 func (s *UserService) CreateWorkoutAsTrainer(workout *entity.Workout) (int64, error) {
-	return s.repo.CreateWorkoutAsTrainer(workout)
+	workout.Date = workout.Date.UTC()
+	id, err := s.repo.CreateWorkoutAsTrainer(workout)
+	if err != nil {
+		return id, err
+	}
+	s.publishWorkout(entity.EventWorkoutCreated, id, workout)
+	return id, nil
 }
 
 func (s *UserService) GetTrainerWorkoutsWithUser(trainerId, userId int64) ([]*entity.Workout, error) {
-	return s.repo.GetTrainerWorkoutsWithUser(trainerId, userId)
+	workouts, err := s.repo.GetTrainerWorkoutsWithUser(trainerId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.localizeFor(trainerId, workouts)
+}
+
+// localizeFor sets local time of workouts in time zone of viewer.
+func (s *UserService) localizeFor(viewerId int64, workouts []*entity.Workout) ([]*entity.Workout, error) {
+	loc, err := s.viewerLocation(viewerId)
+	if err != nil {
+		return nil, err
+	}
+	localizeWorkouts(loc, workouts...)
+	return workouts, nil
+}
+
+func (s *UserService) viewerLocation(userId int64) (*time.Location, error) {
+	timeZone, err := s.repo.GetTimeZone(userId)
+	if err != nil {
+		return nil, err
+	}
+	return loadTimeZone(timeZone)
 }
 
 func (s *UserService) GetPasswordHash(password string) string {
@@ -198,3 +508,25 @@ func (s *UserService) GetPasswordHash(password string) string {
 
 	return fmt.Sprintf("%x", sha1.Sum([]byte(password)))
 }
+
+func (s *UserService) publishWorkout(eventType entity.EventType, workoutId int64, workout *entity.Workout) {
+	payload := *workout
+	payload.Id = workoutId
+	s.events.Publish(entity.NewEvent(eventType, &payload, workoutParticipants(&payload)...))
+}
+
+func (s *UserService) publishPartnershipAccepted(partnershipId, trainerId, userId int64) {
+	s.events.Publish(entity.NewEvent(entity.EventPartnershipAccepted, &entity.PartnershipEvent{
+		PartnershipId: partnershipId,
+		UserId:        userId,
+		TrainerId:     trainerId,
+	}, userId, trainerId))
+}
+
+func workoutParticipants(workout *entity.Workout) []int64 {
+	participants := []int64{workout.UserId}
+	if workout.TrainerId.Valid && workout.TrainerId.Int64 != workout.UserId {
+		participants = append(participants, workout.TrainerId.Int64)
+	}
+	return participants
+}
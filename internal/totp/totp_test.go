@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// rfcSecret is base32 of "12345678901234567890", the SHA1 key of RFC 6238 test vectors.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	table := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+	for _, test := range table {
+		code, err := Code(rfcSecret, Step(time.Unix(test.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, test.code, code)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	step, ok, err := Match(rfcSecret, "081804", now, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	step, ok, err = Match(rfcSecret, "081804", now.Add(Period), 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	_, ok, err = Match(rfcSecret, "081804", now.Add(2*Period), 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = Match(rfcSecret, "81804", now, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = Match("not base32!", "081804", now, 1)
+	assert.Error(t, err)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Fitness", "user@mail.com", rfcSecret)
+	assert.Equal(t, "otpauth://totp/Fitness:user@mail.com?algorithm=SHA1&digits=6&issuer=Fitness&period=30&"+
+		"secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri)
+}